@@ -2,10 +2,15 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"maps"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/n0madic/mysql-diff/pkg/alter"
 	"github.com/n0madic/mysql-diff/pkg/diff"
@@ -13,37 +18,100 @@ import (
 	"github.com/n0madic/mysql-diff/pkg/parser"
 )
 
+// profiler times the stages of a diff run when --profile is set
+var profiler = output.NewProfiler(io.Discard, false)
+
+// exit prints the profile of the stages run so far and exits with code; deferred calls, including
+// the profile report of a normal return, do not run on os.Exit
+func exit(code int) {
+	profiler.Report()
+	os.Exit(code)
+}
+
 func main() {
+	// Subcommands
+	if len(os.Args) > 1 && os.Args[1] == "stats" {
+		runStatsCommand(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrateCommand(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "watch" {
+		runWatchCommand(os.Args[2:])
+		return
+	}
+
 	// Define command line flags
 	verbose := flag.Bool("v", false, "Show verbose output with analysis details")
 	verboseLong := flag.Bool("verbose", false, "Show verbose output with analysis details")
 	includeDrops := flag.Bool("include-drops", false, "Include DROP TABLE statements for removed tables")
-	includeCreates := flag.Bool("include-creates", false, "Include CREATE TABLE statements for new tables (as comments)")
+	includeCreates := flag.Bool("include-creates", false, "Include CREATE TABLE statements for new tables")
+	additiveOnly := flag.Bool("additive-only", false, "Generate only ADD statements for new tables, columns, indexes, foreign keys and checks, skipping drops and modifications (implies --include-creates)")
 
 	// New flags for enhanced functionality
 	tableName := flag.String("table", "", "Compare only specific table")
+	ignoreTables := flag.String("ignore-tables", "", "Comma-separated list of tables to exclude from the diff (glob patterns allowed, e.g. tmp_*)")
+	comparison := addComparisonFlags(flag.CommandLine)
+	tablesOnly := flag.Bool("tables-only", false, "Report only added and removed tables, skipping the comparison of table structures")
 	detailedMode := flag.Bool("detailed", false, "Output detailed diff report")
 	jsonMode := flag.Bool("json", false, "Output results in JSON format")
+	jsonCompact := flag.Bool("json-compact", false, "Output results in JSON format on a single line")
+	onlyModifiedColumns := flag.Bool("only-modified-columns", false, "List only modified columns in the --detailed report, leaving out added and removed ones")
+	explainMode := flag.Bool("explain", false, "Describe each change in plain English")
+	markdownMode := flag.Bool("markdown", false, "Output a Markdown report for pull request descriptions")
+	formatName := flag.String("format", "", "Output the diff with a registered formatter: "+strings.Join(diff.FormatterNames(), ", "))
 	rollbackMode := flag.Bool("rollback", false, "Generate rollback statements (reverse the comparison)")
 	color := flag.Bool("color", false, "Colored output")
+	pretty := flag.Bool("pretty", false, "Align clauses of generated multi-clause ALTER statements")
+	ddlOnly := flag.Bool("diff-only-ddl-generating", false, "Skip generating statements for comment-only changes (still reported by --detailed/--json)")
+	explicitFKIndexes := flag.Bool("explicit-fk-indexes", false, "Add an index for new foreign key columns that are not indexed instead of letting MySQL create one")
+	onlineDDL := flag.Bool("online", false, "Append ALGORITHM=INPLACE, LOCK=NONE to ALTER statements (LOCK=SHARED where a change blocks writes, ALGORITHM=COPY where it needs a table copy)")
+	mysql8 := flag.Bool("mysql8", false, "Target MySQL 8.0 syntax (e.g. RENAME COLUMN for pure column renames)")
+	validateCharset := flag.String("validate-charset", "off", "Check that generated table options pair a collation with its character set: off, warn (comment in the output) or error (exit with an error)")
+	omitDefaultFKActions := flag.Bool("omit-default-fk-actions", false, "Omit ON DELETE/ON UPDATE RESTRICT and NO ACTION from generated foreign keys")
+	encoding := flag.String("encoding", "utf8", "Encoding of the schema files: utf8, latin1 (cp1252), iso-8859-1 or utf-16")
+	maxErrors := flag.Int("max-errors", 0, "Stop parsing after N unparseable CREATE TABLE statements (0 = no limit)")
+	migrationDir := flag.String("migration-dir", "", "Write numbered NNNN_up.sql and NNNN_down.sql migration files to directory")
+	migrationFormat := flag.String("migration-format", "default", "Migration file layout for --migration-dir: default, golang-migrate (NNNNNN_name.up.sql), flyway (V<n>__name.sql) or atlas (<timestamp>_name.sql + atlas.sum)")
+	migrationName := flag.String("migration-name", "schema_diff", "Migration name used in golang-migrate, flyway and atlas file names")
+	outputPath := flag.String("output", "", "Write the statements or report to file instead of stdout (created empty when there are no changes)")
+	impact := flag.Bool("impact", false, "Dry run: precede each statement with its estimated impact (metadata-only, in-place or rewrite) and summarize")
+	profile := flag.Bool("profile", false, "Print stage timings and memory statistics to stderr")
 
 	// Custom usage message
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "MySQL Schema Diff Tool - Compare MySQL schemas and generate migration statements\n\n")
 		fmt.Fprintf(os.Stderr, "Usage:\n")
-		fmt.Fprintf(os.Stderr, "  %s [OPTIONS] old_schema.sql new_schema.sql\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s [OPTIONS] old_schema.sql new_schema.sql\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s stats [--json] schema.sql\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s migrate [OPTIONS] v1.sql v2.sql [v3.sql ...]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s watch [OPTIONS] dsn target.sql\n\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "Options:\n")
-		flag.PrintDefaults()
+		printDefaultsExcept("profile")
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
 		fmt.Fprintf(os.Stderr, "  %s old_schema.sql new_schema.sql                    # Generate ALTER statements\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --table users old_schema.sql new_schema.sql      # Compare only 'users' table\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --ignore-tables 'tmp_*,cache' old.sql new.sql    # Exclude tables from the diff\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --table-rename-map users:accounts old.sql new.sql # Declare a table rename\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --detailed old_schema.sql new_schema.sql         # Show detailed diff report\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --json old_schema.sql new_schema.sql             # Output JSON format\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --explain old_schema.sql new_schema.sql          # Describe changes in plain English\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --markdown old_schema.sql new_schema.sql > pr.md # Markdown report for a pull request\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --tables-only old_schema.sql new_schema.sql      # List added and removed tables\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --rollback old_schema.sql new_schema.sql         # Generate rollback statements\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --pretty old_schema.sql new_schema.sql           # Align ALTER clauses for review\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --migration-dir migrations old.sql new.sql       # Write up/down migration files\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --output migration.sql old.sql new.sql           # Write ALTER statements to a file\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "Output modes:\n")
 		fmt.Fprintf(os.Stderr, "  default:           Generate ALTER statements for migration\n")
 		fmt.Fprintf(os.Stderr, "  --detailed:        Human-readable diff report\n")
 		fmt.Fprintf(os.Stderr, "  --json:            Structured JSON output for programmatic use\n")
+		fmt.Fprintf(os.Stderr, "  --json-compact:    The JSON output on a single line, for log ingestion\n")
+		fmt.Fprintf(os.Stderr, "  --explain:         Plain English description of each change\n")
+		fmt.Fprintf(os.Stderr, "  --markdown:        Markdown tables of the changes for pull request descriptions\n")
+		fmt.Fprintf(os.Stderr, "  --format NAME:     Any registered formatter (%s)\n", strings.Join(diff.FormatterNames(), ", "))
 	}
 
 	flag.Parse()
@@ -52,6 +120,10 @@ func main() {
 	if *color {
 		output.SetColorsEnabled(true)
 	}
+	if *markdownMode || *formatName == "markdown" {
+		// Markdown is pasted into pull requests, where escape codes would show up as text
+		output.SetColorsEnabled(false)
+	}
 
 	// Combine verbose flags
 	isVerbose := *verbose || *verboseLong
@@ -64,20 +136,85 @@ func main() {
 	if *jsonMode {
 		modeCount++
 	}
+	if *jsonCompact {
+		modeCount++
+	}
+	if *explainMode {
+		modeCount++
+	}
+	if *markdownMode {
+		modeCount++
+	}
+	if *formatName != "" {
+		modeCount++
+	}
 
 	if modeCount > 1 {
-		fmt.Fprintf(os.Stderr, "Error: Only one output mode can be specified (--detailed, or --json)\n\n")
+		fmt.Fprintf(os.Stderr, "Error: Only one output mode can be specified (--detailed, --json, --json-compact, --explain, --markdown or --format)\n\n")
+		flag.Usage()
+		exit(1)
+	}
+
+	// The output mode flags are shorthands for the built-in formatters
+	format := *formatName
+	switch {
+	case *detailedMode:
+		format = "detailed"
+	case *jsonMode:
+		format = "json"
+	case *jsonCompact:
+		format = "json-compact"
+	case *explainMode:
+		format = "explain"
+	case *markdownMode:
+		format = "markdown"
+	}
+	if format != "" && !slices.Contains(diff.FormatterNames(), format) {
+		fmt.Fprintf(os.Stderr, "Error: Unknown format '%s' (expected %s)\n", format, strings.Join(diff.FormatterNames(), ", "))
+		exit(1)
+	}
+
+	if *onlyModifiedColumns && format != "detailed" {
+		fmt.Fprintf(os.Stderr, "Error: --only-modified-columns requires --detailed\n\n")
+		flag.Usage()
+		exit(1)
+	}
+
+	if !slices.Contains([]string{"off", "warn", "error"}, *validateCharset) {
+		fmt.Fprintf(os.Stderr, "Error: Unknown --validate-charset mode '%s' (expected off, warn or error)\n", *validateCharset)
+		exit(1)
+	}
+
+	if *additiveOnly && *includeDrops {
+		fmt.Fprintf(os.Stderr, "Error: --additive-only cannot be combined with --include-drops\n\n")
+		flag.Usage()
+		exit(1)
+	}
+	if *additiveOnly {
+		*includeCreates = true // new tables are the most additive change of all
+	}
+
+	if *outputPath != "" && *migrationDir != "" {
+		fmt.Fprintf(os.Stderr, "Error: --output cannot be combined with --migration-dir\n\n")
 		flag.Usage()
-		os.Exit(1)
+		exit(1)
+	}
+	// The output modes and --tables-only write their report instead of the migration files
+	if *migrationDir != "" && (format != "" || *tablesOnly) {
+		fmt.Fprintf(os.Stderr, "Error: --migration-dir cannot be combined with --tables-only or an output mode (--detailed, --json, --json-compact, --explain, --markdown or --format)\n\n")
+		flag.Usage()
+		exit(1)
 	}
 
 	// Check arguments
 	if flag.NArg() != 2 {
 		fmt.Fprintf(os.Stderr, "Error: Expected 2 arguments, got %d\n\n", flag.NArg())
 		flag.Usage()
-		os.Exit(1)
+		exit(1)
 	}
 
+	analyzer, matchTables := comparison.configure(*rollbackMode)
+
 	firstSchemaPath := flag.Arg(0)
 	secondSchemaPath := flag.Arg(1)
 
@@ -91,31 +228,24 @@ func main() {
 		newSchemaPath = secondSchemaPath
 	}
 
+	profiler = output.NewProfiler(os.Stderr, *profile)
+	defer profiler.Report()
+
 	// Read and parse old schema
-	oldSQL, err := os.ReadFile(oldSchemaPath)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Schema file '%s' not found\n", oldSchemaPath)
-		os.Exit(1)
-	}
+	oldSQL := readSchemaFile(oldSchemaPath, *encoding)
 
-	oldTables, err := parser.ParseSQLDump(string(oldSQL))
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing schema: %v\n", err)
-		os.Exit(1)
-	}
+	endParseOld := profiler.Stage("parse " + oldSchemaPath)
+	oldTables := parseSchema(oldSchemaPath, oldSQL, *maxErrors)
+	endParseOld()
 
 	// Read and parse new schema
-	newSQL, err := os.ReadFile(newSchemaPath)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Schema file '%s' not found\n", newSchemaPath)
-		os.Exit(1)
-	}
+	newSQL := readSchemaFile(newSchemaPath, *encoding)
 
-	newTables, err := parser.ParseSQLDump(string(newSQL))
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing schema: %v\n", err)
-		os.Exit(1)
-	}
+	endParseNew := profiler.Stage("parse " + newSchemaPath)
+	newTables := parseSchema(newSchemaPath, newSQL, *maxErrors)
+	endParseNew()
+
+	comparison.normalizeTables(oldTables, newTables)
 
 	if isVerbose {
 		if *rollbackMode {
@@ -128,6 +258,9 @@ func main() {
 		}
 	}
 
+	// Warn about circular foreign key dependencies in the target schema
+	warnDependencyCycles(newTables)
+
 	// Filter tables by name if specified
 	if *tableName != "" {
 		oldTables = filterTablesByName(oldTables, *tableName)
@@ -135,27 +268,74 @@ func main() {
 
 		if len(oldTables) == 0 && len(newTables) == 0 {
 			fmt.Fprintf(os.Stderr, "Error: Table '%s' not found in either schema\n", *tableName)
-			os.Exit(1)
+			exit(1)
 		}
 	}
 
-	// Match tables by name
-	tableMatches := alter.MatchTablesByName(oldTables, newTables)
+	// Exclude ignored tables from both schemas
+	if *ignoreTables != "" {
+		patterns := splitList(*ignoreTables)
+		oldTables = alter.FilterIgnoredTables(oldTables, patterns)
+		newTables = alter.FilterIgnoredTables(newTables, patterns)
+	}
+
+	// Match tables by name, pairing declared renames
+	endMatch := profiler.Stage("match tables")
+	tableMatches := matchTables(oldTables, newTables)
+	endMatch()
+
+	// Statements and reports go to stdout, or to the --output file, which is created even when
+	// there are no changes so that pipelines can rely on it existing
+	var out io.Writer = os.Stdout
+	if *outputPath != "" {
+		file, err := os.Create(*outputPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --output: %v\n", err)
+			exit(1)
+		}
+		defer closeOutputFile(file)
+		out = file
+		// Escape codes would end up in the file
+		output.SetColorsEnabled(false)
+	}
+
+	if *tablesOnly {
+		defer profiler.Stage("list tables")()
+		handleTablesOnlyOutput(tableMatches, format, out)
+		return
+	}
+
+	// Comparison and output happen together in the output handlers below
+	defer profiler.Stage("compare")()
+
+	// Statements are generated by default and also included in the JSON output
+	generator := alter.NewStatementGenerator()
+	generator.Pretty = *pretty
+	generator.OmitDefaultFKActions = *omitDefaultFKActions
+	generator.MySQL8 = *mysql8
+	generator.OnlineDDL = *onlineDDL
+	generator.ExplicitFKIndexes = *explicitFKIndexes
+	generator.SkipCommentOnlyChanges = *ddlOnly
+	generator.AdditiveOnly = *additiveOnly
+	generator.ValidateCharset = *validateCharset == "warn"
+
+	// Invalid table options fail every output mode, before anything is written
+	if *validateCharset == "error" {
+		validateTableCharsets(tableMatches, analyzer)
+	}
 
 	// Process based on output mode
-	if *jsonMode {
-		handleJSONOutput(tableMatches, isVerbose)
+	if format != "" {
+		handleFormatOutput(tableMatches, analyzer, generator, format, *onlyModifiedColumns, isVerbose, out)
 		return
 	}
 
-	if *detailedMode {
-		handleDetailedOutput(tableMatches, isVerbose)
+	if *migrationDir != "" {
+		handleMigrationOutput(generator, analyzer, tableMatches, *migrationDir, *migrationFormat, *migrationName, isVerbose)
 		return
 	}
 
-	// Default: Generate ALTER statements
-	generator := alter.NewStatementGenerator()
-	allStatements := []string{}
+	allStatements := []alter.StatementImpact{}
 
 	// Process table drops first (if requested)
 	if *includeDrops {
@@ -167,21 +347,25 @@ func main() {
 		for _, table := range newTables {
 			newNames[table.TableName] = true
 		}
+		for _, match := range tableMatches {
+			if match.Old != nil && match.New != nil {
+				newNames[match.Old.TableName] = true // renamed tables are not dropped
+			}
+		}
 		dropStatements := alter.GenerateDropTableStatements(oldTables, newNames)
-		allStatements = append(allStatements, dropStatements...)
+		allStatements = append(allStatements, withImpact(dropStatements, alter.ImpactMetadata)...)
 	}
 
 	// Process existing tables with changes
-	analyzer := diff.NewTableDiffAnalyzer()
-	for tableName, match := range tableMatches {
-		if match.Old != nil && match.New != nil {
+	for _, tableName := range slices.Sorted(maps.Keys(tableMatches)) {
+		if match := tableMatches[tableName]; match.Old != nil && match.New != nil {
 			// Table exists in both schemas, check for differences
 			tableDiff := analyzer.CompareTables(match.Old, match.New)
 			if tableDiff.HasChanges() {
 				if isVerbose {
 					fmt.Fprintf(os.Stderr, "-- Processing changes for table: %s\n", tableName)
 				}
-				statements := generator.GenerateAlterStatements(tableDiff)
+				statements := generator.GenerateAlterStatementsWithImpact(tableDiff)
 				allStatements = append(allStatements, statements...)
 			}
 		}
@@ -193,8 +377,13 @@ func main() {
 		for _, table := range oldTables {
 			oldNames[table.TableName] = true
 		}
+		for _, match := range tableMatches {
+			if match.Old != nil && match.New != nil {
+				oldNames[match.New.TableName] = true // renamed tables are not created
+			}
+		}
 		createStatements := alter.GenerateCreateTableStatements(newTables, oldNames)
-		allStatements = append(allStatements, createStatements...)
+		allStatements = append(allStatements, withImpact(createStatements, alter.ImpactMetadata)...)
 	}
 
 	// Output results
@@ -202,17 +391,293 @@ func main() {
 		if isVerbose {
 			fmt.Fprintf(os.Stderr, "-- No differences found between schemas\n")
 		}
-		os.Exit(0)
+		return
 	}
 
 	// Print all ALTER statements with syntax highlighting
 	for _, statement := range allStatements {
-		fmt.Println(output.ColorizeSQLStatement(statement))
+		if *impact && statement.Impact != "" {
+			fmt.Fprintln(out, output.ColorizeSQLStatement("-- Impact: "+string(statement.Impact)))
+		}
+		fmt.Fprintln(out, output.ColorizeSQLStatement(statement.SQL))
+	}
+	if *impact {
+		fmt.Fprintln(out, output.ColorizeSQLStatement("-- "+alter.SummarizeImpact(allStatements)))
 	}
 
 	if isVerbose {
-		fmt.Fprintf(os.Stderr, "-- Generated %d statements\n", len(allStatements))
+		count := 0
+		for _, statement := range allStatements {
+			if statement.Impact != "" { // comments have no impact
+				count++
+			}
+		}
+		fmt.Fprintf(os.Stderr, "-- Generated %d statements\n", count)
+	}
+}
+
+// runStatsCommand prints table, column, index and foreign key statistics for a single schema file,
+// warning about redundant indexes on stderr
+func runStatsCommand(args []string) {
+	statsFlags := flag.NewFlagSet("stats", flag.ExitOnError)
+	jsonMode := statsFlags.Bool("json", false, "Output statistics in JSON format")
+	statsFlags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage:\n")
+		fmt.Fprintf(os.Stderr, "  %s stats [--json] schema.sql\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		statsFlags.PrintDefaults()
+	}
+	statsFlags.Parse(args)
+
+	if statsFlags.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "Error: Expected 1 argument, got %d\n\n", statsFlags.NArg())
+		statsFlags.Usage()
+		exit(1)
+	}
+
+	schemaPath := statsFlags.Arg(0)
+	sql, err := os.ReadFile(schemaPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Schema file '%s' not found\n", schemaPath)
+		exit(1)
+	}
+
+	tables := parseSchema(schemaPath, string(sql), 0)
+	stats := parser.CollectSchemaStats(tables)
+
+	for _, table := range tables {
+		for _, finding := range diff.FindRedundantIndexes(table) {
+			name := "UNNAMED"
+			if finding.Index.Name != nil {
+				name = *finding.Index.Name
+			}
+			fmt.Fprintf(os.Stderr, "-- Warning: index `%s` on `%s` is redundant with index `%s`\n", name, table.TableName, finding.CoveredBy)
+		}
+	}
+
+	if *jsonMode {
+		jsonOutput, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating JSON output: %v\n", err)
+			exit(1)
+		}
+		fmt.Println(string(jsonOutput))
+		return
+	}
+
+	fmt.Printf("Tables: %d\n", stats.Tables)
+	fmt.Printf("Columns: %d\n", stats.Columns)
+	fmt.Printf("Indexes: %d\n", stats.Indexes)
+	fmt.Printf("Foreign keys: %d\n", stats.ForeignKeys)
+	if stats.Tables > 0 {
+		fmt.Printf("Average columns per table: %.1f\n", stats.AverageColumnsPerTable())
+	}
+	printDistribution("Storage engines", stats.Engines)
+	printDistribution("Character sets", stats.Charsets)
+}
+
+// runMigrateCommand prints the cumulative migration stepping through consecutive schema versions
+func runMigrateCommand(args []string) {
+	migrateFlags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	pretty := migrateFlags.Bool("pretty", false, "Align clauses of generated multi-clause ALTER statements")
+	maxErrors := migrateFlags.Int("max-errors", 0, "Stop parsing after N unparseable CREATE TABLE statements (0 = no limit)")
+	comparison := addComparisonFlags(migrateFlags)
+	migrateFlags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage:\n")
+		fmt.Fprintf(os.Stderr, "  %s migrate [OPTIONS] v1.sql v2.sql [v3.sql ...]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Generates the ALTER statements migrating each schema version to the next, in order.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		migrateFlags.PrintDefaults()
+	}
+	migrateFlags.Parse(args)
+
+	if migrateFlags.NArg() < 2 {
+		fmt.Fprintf(os.Stderr, "Error: Expected at least 2 arguments, got %d\n\n", migrateFlags.NArg())
+		migrateFlags.Usage()
+		exit(1)
+	}
+
+	analyzer, matchTables := comparison.configure(false)
+
+	var versions []alter.SchemaVersion
+	for _, schemaPath := range migrateFlags.Args() {
+		sql, err := os.ReadFile(schemaPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Schema file '%s' not found\n", schemaPath)
+			exit(1)
+		}
+		tables := parseSchema(schemaPath, string(sql), *maxErrors)
+		comparison.normalizeTables(tables)
+		versions = append(versions, alter.SchemaVersion{Name: schemaPath, Tables: tables})
+	}
+
+	generator := alter.NewStatementGenerator()
+	generator.Pretty = *pretty
+
+	fmt.Print(alter.FormatMigrationSequence(generator.GenerateMigrationSequenceWith(analyzer, matchTables, versions)))
+}
+
+// comparisonFlags holds the command line flags that decide how tables are matched and compared,
+// shared by the diff and migrate commands
+type comparisonFlags struct {
+	tableRenameMap         *string
+	columnRenameMap        *string
+	caseInsensitiveNames   *bool
+	detectRenames          *bool
+	renameSimilarity       *float64
+	normalizeInlinePK      *bool
+	noNormalize            *bool
+	ignoreOrder            *bool
+	keepBoolean            *bool
+	ignoreAutoIncrement    *bool
+	detectReorder          *bool
+	detectReorderWithoutPK *bool
+	safeConversions        *string
+}
+
+// addComparisonFlags defines the comparison flags in flags
+func addComparisonFlags(flags *flag.FlagSet) *comparisonFlags {
+	return &comparisonFlags{
+		tableRenameMap:         flags.String("table-rename-map", "", "Comma-separated old:new table renames, compared as the same table"),
+		columnRenameMap:        flags.String("column-rename-map", "", "Comma-separated table.old:new column renames, compared as the same column"),
+		caseInsensitiveNames:   flags.Bool("case-insensitive-names", false, "Compare table, column, index and foreign key names ignoring case (servers with lower_case_table_names=1 or 2)"),
+		detectRenames:          flags.Bool("detect-renames", false, "Report a removed and an added column with matching definitions as a rename"),
+		renameSimilarity:       flags.Float64("rename-similarity", 1, "Share of column attributes that must match for --detect-renames (0-1)"),
+		normalizeInlinePK:      flags.Bool("normalize-inline-pk", false, "Treat a column-level PRIMARY KEY as the equivalent table-level PRIMARY KEY (col)"),
+		noNormalize:            flags.Bool("no-normalize", false, "Compare definitions exactly as written: option values by case, omitted and default foreign key actions and type synonyms (INTEGER, BOOL) as different"),
+		ignoreOrder:            flags.Bool("ignore-order", false, "Do not report reordered ENUM/SET values, partition columns or columns (overrides --detect-reorder)"),
+		keepBoolean:            flags.Bool("keep-boolean", false, "Report BOOL/BOOLEAN and TINYINT(1) as different types instead of comparing BOOLEAN as TINYINT(1)"),
+		ignoreAutoIncrement:    flags.Bool("ignore-auto-increment", false, "Do not report AUTO_INCREMENT=N table option changes (the counter differs between dumps)"),
+		detectReorder:          flags.Bool("detect-reorder", false, "Report moved columns and generate MODIFY COLUMN ... AFTER (and ADD COLUMN ... AFTER) to restore the column order"),
+		detectReorderWithoutPK: flags.Bool("detect-reorder-without-pk", false, "Like --detect-reorder, but only for tables without a primary key"),
+		safeConversions:        flags.String("safe-conversions", "", "Comma-separated from:to data type conversions to classify as safe, e.g. VARCHAR:TEXT"),
+	}
+}
+
+// configure parses the rename maps and safe conversions, exiting on errors, and returns the analyzer that compares
+// tables and the matcher that pairs them as the flags ask. With reverse, the schemas are compared
+// in the other direction and the renames are inverted.
+func (f *comparisonFlags) configure(reverse bool) (*diff.TableDiffAnalyzer, alter.TableMatcher) {
+	tableRenames, err := alter.ParseTableRenameMap(*f.tableRenameMap)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --table-rename-map: %v\n", err)
+		exit(1)
+	}
+	columnRenames, err := alter.ParseColumnRenameMap(*f.columnRenameMap)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --column-rename-map: %v\n", err)
+		exit(1)
+	}
+	safeConversions, err := diff.ParseSafeTypeConversions(*f.safeConversions)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --safe-conversions: %v\n", err)
+		exit(1)
+	}
+	if reverse {
+		tableRenames = alter.InvertTableRenames(tableRenames)
+		columnRenames = alter.InvertColumnRenames(columnRenames)
+	}
+
+	analyzer := diff.NewTableDiffAnalyzer()
+	analyzer.Normalize = !*f.noNormalize
+	analyzer.ColumnRenames = columnRenames
+	analyzer.IgnoreOrder = *f.ignoreOrder
+	analyzer.DetectColumnReorder = *f.detectReorder
+	analyzer.DetectColumnReorderWithoutPK = *f.detectReorderWithoutPK
+	analyzer.IgnoreAutoIncrement = *f.ignoreAutoIncrement
+	analyzer.DetectColumnRenames = *f.detectRenames
+	analyzer.CaseInsensitiveNames = *f.caseInsensitiveNames
+	analyzer.KeepBooleanType = *f.keepBoolean
+	analyzer.ColumnRenameSimilarity = *f.renameSimilarity
+	analyzer.SafeTypeConversions = safeConversions
+
+	matchTables := func(oldTables, newTables []*parser.CreateTableStatement) map[string]struct {
+		Old *parser.CreateTableStatement
+		New *parser.CreateTableStatement
+	} {
+		if *f.caseInsensitiveNames {
+			return alter.MatchTablesCaseInsensitive(oldTables, newTables, tableRenames)
+		}
+		return alter.MatchTablesWithRenames(oldTables, newTables, tableRenames)
+	}
+	return analyzer, matchTables
+}
+
+// normalizeTables rewrites the parsed tables as the normalization flags ask
+func (f *comparisonFlags) normalizeTables(schemas ...[]*parser.CreateTableStatement) {
+	if !*f.normalizeInlinePK {
+		return
+	}
+	for _, table := range slices.Concat(schemas...) {
+		parser.MoveInlinePrimaryKey(table)
+	}
+}
+
+// printDistribution prints per-value table counts in name order
+func printDistribution(title string, counts map[string]int) {
+	if len(counts) == 0 {
+		return
+	}
+	fmt.Printf("\n%s:\n", title)
+	for _, name := range slices.Sorted(maps.Keys(counts)) {
+		fmt.Printf("  - %s: %d\n", name, counts[name])
+	}
+}
+
+// readSchemaFile reads a schema file and converts it from encoding to UTF-8, exiting on errors
+func readSchemaFile(path, encoding string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Schema file '%s' not found\n", path)
+		exit(1)
+	}
+	sql, err := parser.DecodeSQL(data, encoding)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --encoding: %v\n", err)
+		exit(1)
+	}
+	return sql
+}
+
+// parseSchema parses a schema dump, reporting statements that could not be parsed
+// and aborting once the error limit is reached
+func parseSchema(path, sql string, maxErrors int) []*parser.CreateTableStatement {
+	tables, parseErrors, err := parser.ParseSQLDumpTolerant(sql, maxErrors)
+	for _, parseErr := range parseErrors {
+		fmt.Fprintf(os.Stderr, "-- Warning: %s: skipped %v\n", path, parseErr)
+	}
+	if err != nil {
+		if errors.Is(err, parser.ErrTooManyErrors) {
+			fmt.Fprintf(os.Stderr, "Error parsing schema '%s': error limit of %d reached\n", path, maxErrors)
+		} else {
+			fmt.Fprintf(os.Stderr, "Error parsing schema: %v\n", err)
+		}
+		exit(1)
+	}
+	return tables
+}
+
+// printDefaultsExcept prints the default usage of all command line flags except the hidden ones
+func printDefaultsExcept(hidden ...string) {
+	visible := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	visible.SetOutput(os.Stderr)
+	flag.VisitAll(func(f *flag.Flag) {
+		if !slices.Contains(hidden, f.Name) {
+			visible.Var(f.Value, f.Name, f.Usage)
+		}
+	})
+	visible.PrintDefaults()
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
+	return items
 }
 
 // filterTablesByName filters tables by name, returning only matching tables
@@ -226,110 +691,178 @@ func filterTablesByName(tables []*parser.CreateTableStatement, name string) []*p
 	return filtered
 }
 
-// handleJSONOutput outputs results in JSON format
-func handleJSONOutput(tableMatches map[string]struct {
+// handleMigrationOutput writes forward and rollback migration files for the matched tables in the
+// given layout to the given directory
+func handleMigrationOutput(generator *alter.StatementGenerator, analyzer *diff.TableDiffAnalyzer, tableMatches map[string]struct {
 	Old *parser.CreateTableStatement
 	New *parser.CreateTableStatement
-}, isVerbose bool) {
-	analyzer := diff.NewTableDiffAnalyzer()
-	results := make(map[string]*diff.TableDiff)
+}, dir, format, name string, verbose bool) {
+	if !slices.Contains([]string{"default", "golang-migrate", "flyway", "atlas"}, format) {
+		fmt.Fprintf(os.Stderr, "Error: Unknown migration format '%s' (expected default, golang-migrate, flyway or atlas)\n", format)
+		exit(1)
+	}
 
-	for tableName, match := range tableMatches {
-		if match.Old != nil && match.New != nil {
-			tableDiff := analyzer.CompareTables(match.Old, match.New)
-			if tableDiff.HasChanges() {
-				results[tableName] = tableDiff
-			}
-		} else if match.Old != nil {
-			// Table was removed
-			tableDiff := &diff.TableDiff{
-				OldTable: match.Old,
-				NewTable: nil,
-			}
-			results[tableName] = tableDiff
-		} else if match.New != nil {
-			// Table was added
-			tableDiff := &diff.TableDiff{
-				OldTable: nil,
-				NewTable: match.New,
-			}
-			results[tableName] = tableDiff
+	up, down := generator.GenerateMigrationWith(analyzer, tableMatches)
+	if len(up) == 0 {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "-- No differences found between schemas\n")
 		}
+		return
 	}
 
-	jsonOutput, err := json.MarshalIndent(results, "", "  ")
+	var upPath, downPath string
+	var err error
+	switch format {
+	case "golang-migrate":
+		upPath, downPath, err = alter.WriteGolangMigrateFiles(dir, name, up, down)
+	case "flyway":
+		upPath, downPath, err = alter.WriteFlywayFiles(dir, name, up, down)
+	case "atlas":
+		upPath, err = alter.WriteAtlasFiles(dir, name, time.Now(), up)
+	default:
+		upPath, downPath, err = alter.WriteMigrationFiles(dir, up, down)
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating JSON output: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "Error writing migration files: %v\n", err)
+		exit(1)
 	}
 
-	fmt.Println(string(jsonOutput))
+	fmt.Fprintf(os.Stderr, "-- Wrote %s (%d statements)\n", upPath, len(up))
+	if downPath != "" {
+		fmt.Fprintf(os.Stderr, "-- Wrote %s (%d statements)\n", downPath, len(down))
+	}
+}
 
-	if isVerbose {
-		fmt.Fprintf(os.Stderr, "-- Generated JSON output for %d tables\n", len(results))
+// warnDependencyCycles reports circular foreign key dependencies to stderr
+func warnDependencyCycles(tables []*parser.CreateTableStatement) {
+	cycles := alter.FindDependencyCycles(alter.BuildDependencyGraph(tables))
+	for _, cycle := range cycles {
+		fmt.Fprintf(os.Stderr, "-- Warning: circular foreign key dependency between tables: %s (requires SET FOREIGN_KEY_CHECKS=0 to create)\n",
+			strings.Join(cycle, ", "))
 	}
 }
 
-// handleDetailedOutput outputs human-readable detailed diff reports
-func handleDetailedOutput(tableMatches map[string]struct {
+// withImpact pairs statements that all have the same impact
+func withImpact(statements []string, impact alter.Impact) []alter.StatementImpact {
+	result := make([]alter.StatementImpact, len(statements))
+	for i, statement := range statements {
+		result[i] = alter.StatementImpact{SQL: statement, Impact: impact}
+	}
+	return result
+}
+
+// validateTableCharsets exits with an error when a table options statement generated for the
+// matched tables would pair a collation with a character set it does not belong to
+func validateTableCharsets(tableMatches map[string]struct {
 	Old *parser.CreateTableStatement
 	New *parser.CreateTableStatement
-}, isVerbose bool) {
-	analyzer := diff.NewTableDiffAnalyzer()
-	hasAnyChanges := false
-
-	for tableName, match := range tableMatches {
-		if match.Old != nil && match.New != nil {
-			tableDiff := analyzer.CompareTables(match.Old, match.New)
-			if tableDiff.HasChanges() {
-				hasAnyChanges = true
-				diff.PrintTableDiff(tableDiff, true) // detailed=true
-			}
-		} else if match.Old != nil {
-			// Table was removed
-			hasAnyChanges = true
-			fmt.Printf("\n%s\n", strings.Repeat("=", 60))
-			fmt.Printf("TABLE REMOVED: %s\n", tableName)
-			fmt.Printf("%s\n", strings.Repeat("=", 60))
-			fmt.Printf("❌ Table '%s' was removed from the schema\n", tableName)
-		} else if match.New != nil {
-			// Table was added
-			hasAnyChanges = true
-			fmt.Printf("\n%s\n", strings.Repeat("=", 60))
-			fmt.Printf("TABLE ADDED: %s\n", tableName)
-			fmt.Printf("%s\n", strings.Repeat("=", 60))
-			fmt.Printf("✅ Table '%s' was added to the schema\n", tableName)
+}, analyzer *diff.TableDiffAnalyzer) {
+	failed := false
+	for _, tableName := range slices.Sorted(maps.Keys(tableMatches)) {
+		match := tableMatches[tableName]
+		if match.Old == nil || match.New == nil {
+			continue
 		}
+		tableDiff := analyzer.CompareTables(match.Old, match.New)
+		if err := alter.ValidateTableOptionsCharset(tableDiff.TableOptionsDiff); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: table '%s': %v\n", tableName, err)
+			failed = true
+		}
+	}
+	if failed {
+		exit(1)
 	}
+}
 
-	if !hasAnyChanges {
-		fmt.Println("No differences found between schemas.")
-		if isVerbose {
-			fmt.Fprintf(os.Stderr, "-- Compared %d tables, no changes detected\n", len(tableMatches))
+// closeOutputFile closes the --output file and confirms on stderr that it was written
+func closeOutputFile(file *os.File) {
+	if err := file.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output file: %v\n", err)
+		exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "-- Wrote %s\n", file.Name())
+}
+
+// handleTablesOnlyOutput writes the names of added and removed tables without comparing the
+// tables present in both schemas, as JSON for the json and json-compact formats
+func handleTablesOnlyOutput(tableMatches map[string]struct {
+	Old *parser.CreateTableStatement
+	New *parser.CreateTableStatement
+}, format string, w io.Writer) {
+	added, removed := alter.DiffTableNames(tableMatches)
+
+	if format == "json" || format == "json-compact" {
+		// Empty lists are printed as [] rather than null
+		result := struct {
+			AddedTables   []string `json:"added_tables"`
+			RemovedTables []string `json:"removed_tables"`
+		}{AddedTables: []string{}, RemovedTables: []string{}}
+		result.AddedTables = append(result.AddedTables, added...)
+		result.RemovedTables = append(result.RemovedTables, removed...)
+
+		var jsonOutput []byte
+		var err error
+		if format == "json-compact" {
+			jsonOutput, err = json.Marshal(result)
+		} else {
+			jsonOutput, err = json.MarshalIndent(result, "", "  ")
 		}
-	} else {
-		// Print overall summary
-		fmt.Printf("\n%s\n", strings.Repeat("=", 60))
-		fmt.Printf("SUMMARY\n")
-		fmt.Printf("%s\n", strings.Repeat("=", 60))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating JSON output: %v\n", err)
+			exit(1)
+		}
+		fmt.Fprintln(w, string(jsonOutput))
+		return
+	}
 
-		totalTables := 0
-		tablesWithChanges := 0
-		for _, match := range tableMatches {
-			if match.Old != nil && match.New != nil {
-				totalTables++
-				tableDiff := analyzer.CompareTables(match.Old, match.New)
-				if tableDiff.HasChanges() {
-					tablesWithChanges++
-				}
+	if len(added) == 0 && len(removed) == 0 {
+		fmt.Fprintln(w, "No tables added or removed.")
+		return
+	}
+	for _, tableName := range added {
+		fmt.Fprintf(w, "Added table `%s`\n", output.ColorizeTableName(tableName))
+	}
+	for _, tableName := range removed {
+		fmt.Fprintf(w, "Removed table `%s`\n", output.ColorizeTableName(tableName))
+	}
+}
+
+// handleFormatOutput compares the matched tables and writes the schema diff with the named formatter,
+// attaching the statements the generator produces for it
+func handleFormatOutput(tableMatches map[string]struct {
+	Old *parser.CreateTableStatement
+	New *parser.CreateTableStatement
+}, analyzer *diff.TableDiffAnalyzer, generator *alter.StatementGenerator, format string, onlyModifiedColumns, isVerbose bool, w io.Writer) {
+	schemaDiff := &diff.SchemaDiff{
+		AddedTables:         []*parser.CreateTableStatement{},
+		RemovedTables:       []*parser.CreateTableStatement{},
+		ModifiedTables:      []*diff.TableDiff{},
+		OnlyModifiedColumns: onlyModifiedColumns,
+	}
+	for _, tableName := range slices.Sorted(maps.Keys(tableMatches)) {
+		match := tableMatches[tableName]
+		switch {
+		case match.Old == nil:
+			schemaDiff.AddedTables = append(schemaDiff.AddedTables, match.New)
+		case match.New == nil:
+			schemaDiff.RemovedTables = append(schemaDiff.RemovedTables, match.Old)
+		default:
+			schemaDiff.TablesCompared++
+			if tableDiff := analyzer.CompareTables(match.Old, match.New); tableDiff.HasChanges() {
+				schemaDiff.ModifiedTables = append(schemaDiff.ModifiedTables, tableDiff)
 			}
 		}
+	}
 
-		fmt.Printf("Tables analyzed: %d\n", totalTables)
-		fmt.Printf("Tables with changes: %d\n", tablesWithChanges)
+	generator.AttachStatements(schemaDiff)
 
-		if isVerbose {
-			fmt.Fprintf(os.Stderr, "-- Detailed analysis complete\n")
-		}
+	if err := diff.FormatSchemaDiff(w, format, schemaDiff); err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating %s output: %v\n", format, err)
+		exit(1)
+	}
+
+	if isVerbose {
+		tables := len(schemaDiff.AddedTables) + len(schemaDiff.RemovedTables) + len(schemaDiff.ModifiedTables)
+		fmt.Fprintf(os.Stderr, "-- Generated %s output for %d tables\n", format, tables)
 	}
 }
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestMain runs the command instead of the tests when runCLI starts the test binary
+func TestMain(m *testing.M) {
+	if os.Getenv("MYSQL_DIFF_RUN_MAIN") == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runCLI runs the command with args and returns its standard output
+func runCLI(t *testing.T, args ...string) string {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), "MYSQL_DIFF_RUN_MAIN=1")
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("mysql-diff %s failed: %v\n%s", strings.Join(args, " "), err, stderr.String())
+	}
+	return string(output)
+}
+
+// writeSchema writes sql to a schema file in a temporary directory and returns its path
+func writeSchema(t *testing.T, name, sql string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(sql), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestRollbackInvertsRenameMaps(t *testing.T) {
+	oldPath := writeSchema(t, "old.sql", "CREATE TABLE users (id INT, fname VARCHAR(50));")
+	newPath := writeSchema(t, "new.sql", "CREATE TABLE accounts (id INT, first_name VARCHAR(50));")
+	renames := []string{"--table-rename-map", "users:accounts", "--column-rename-map", "users.fname:first_name"}
+
+	forward := runCLI(t, append(renames, oldPath, newPath)...)
+	expected := "ALTER TABLE `users` RENAME TO `accounts`;\nALTER TABLE `accounts`\n  CHANGE COLUMN `fname` `first_name` VARCHAR(50);\n"
+	if forward != expected {
+		t.Errorf("Expected forward statements:\n%s\ngot:\n%s", expected, forward)
+	}
+
+	rollback := runCLI(t, append(renames, "--rollback", oldPath, newPath)...)
+	expected = "ALTER TABLE `accounts` RENAME TO `users`;\nALTER TABLE `users`\n  CHANGE COLUMN `first_name` `fname` VARCHAR(50);\n"
+	if rollback != expected {
+		t.Errorf("Expected rollback statements:\n%s\ngot:\n%s", expected, rollback)
+	}
+}
+
+func TestValidateCharsetErrorInEveryOutputMode(t *testing.T) {
+	oldPath := writeSchema(t, "old.sql", "CREATE TABLE users (id INT) DEFAULT CHARSET=latin1;")
+	newPath := writeSchema(t, "new.sql", "CREATE TABLE users (id INT) DEFAULT CHARSET=utf8mb4 COLLATE=latin1_swedish_ci;")
+
+	for _, mode := range [][]string{nil, {"--json"}, {"--detailed"}, {"--format", "markdown"}} {
+		args := append(append([]string{"--validate-charset", "error"}, mode...), oldPath, newPath)
+		cmd := exec.Command(os.Args[0], args...)
+		cmd.Env = append(os.Environ(), "MYSQL_DIFF_RUN_MAIN=1")
+		output, err := cmd.Output()
+		if err == nil {
+			t.Errorf("Expected mysql-diff %s to fail, got:\n%s", strings.Join(args, " "), output)
+		}
+		if len(output) != 0 {
+			t.Errorf("Expected no output from mysql-diff %s, got:\n%s", strings.Join(args, " "), output)
+		}
+	}
+}
+
+func TestNoNormalize(t *testing.T) {
+	oldPath := writeSchema(t, "old.sql", "CREATE TABLE users (id INTEGER, status BOOL);")
+	newPath := writeSchema(t, "new.sql", "CREATE TABLE users (id INT, status TINYINT(1));")
+
+	if output := runCLI(t, oldPath, newPath); output != "" {
+		t.Errorf("Expected no statements with normalization, got:\n%s", output)
+	}
+	expected := "ALTER TABLE `users`\n  MODIFY COLUMN `id` INT,\n  MODIFY COLUMN `status` TINYINT(1);\n"
+	if output := runCLI(t, "--no-normalize", oldPath, newPath); output != expected {
+		t.Errorf("Expected statements:\n%s\ngot:\n%s", expected, output)
+	}
+}
+
+func TestProfileReportedOnEveryExit(t *testing.T) {
+	oldPath := writeSchema(t, "old.sql", "CREATE TABLE users (id INT) DEFAULT CHARSET=latin1;")
+	newPath := writeSchema(t, "new.sql", "CREATE TABLE users (id INT) DEFAULT CHARSET=utf8mb4 COLLATE=latin1_swedish_ci;")
+
+	for _, args := range [][]string{
+		{"--profile", "--tables-only", oldPath, newPath},
+		{"--profile", "--validate-charset", "error", oldPath, newPath},
+	} {
+		cmd := exec.Command(os.Args[0], args...)
+		cmd.Env = append(os.Environ(), "MYSQL_DIFF_RUN_MAIN=1")
+		var stderr strings.Builder
+		cmd.Stderr = &stderr
+		cmd.Run()
+		if !strings.Contains(stderr.String(), "-- Profile:") {
+			t.Errorf("Expected a profile from mysql-diff %s, got:\n%s", strings.Join(args, " "), stderr.String())
+		}
+	}
+}
+
+func TestAdditiveOnlyCreatesNewTables(t *testing.T) {
+	oldPath := writeSchema(t, "old.sql", "CREATE TABLE users (id INT, legacy INT);\nCREATE TABLE logs (id INT);")
+	newPath := writeSchema(t, "new.sql", "CREATE TABLE users (id INT, email VARCHAR(100));\nCREATE TABLE orders (id INT);")
+
+	expected := "ALTER TABLE `users`\n  ADD COLUMN `email` VARCHAR(100);\nCREATE TABLE `orders` (\n  `id` INT\n);\n"
+	if output := runCLI(t, "--additive-only", oldPath, newPath); output != expected {
+		t.Errorf("Expected statements:\n%s\ngot:\n%s", expected, output)
+	}
+}
+
+func TestSafeConversions(t *testing.T) {
+	oldPath := writeSchema(t, "old.sql", "CREATE TABLE posts (id INT, body VARCHAR(255));")
+	newPath := writeSchema(t, "new.sql", "CREATE TABLE posts (id INT, body TEXT);")
+
+	unsafe := "(existing values may be truncated or rejected)"
+	if output := runCLI(t, "--explain", oldPath, newPath); !strings.Contains(output, unsafe) {
+		t.Errorf("Expected an unsafe conversion, got:\n%s", output)
+	}
+	if output := runCLI(t, "--explain", "--safe-conversions", "varchar:text", oldPath, newPath); strings.Contains(output, unsafe) {
+		t.Errorf("Expected a safe conversion with --safe-conversions, got:\n%s", output)
+	}
+}
+
+func TestStatementsInTableOrder(t *testing.T) {
+	var oldSQL, newSQL, expected strings.Builder
+	for _, name := range []string{"e", "b", "d", "a", "c"} {
+		fmt.Fprintf(&oldSQL, "CREATE TABLE %s (id INT);\n", name)
+		fmt.Fprintf(&newSQL, "CREATE TABLE %s (id INT, note TEXT);\n", name)
+	}
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		fmt.Fprintf(&expected, "ALTER TABLE `%s`\n  ADD COLUMN `note` TEXT;\n", name)
+	}
+	oldPath := writeSchema(t, "old.sql", oldSQL.String())
+	newPath := writeSchema(t, "new.sql", newSQL.String())
+
+	for range 5 {
+		if output := runCLI(t, oldPath, newPath); output != expected.String() {
+			t.Fatalf("Expected statements:\n%s\ngot:\n%s", expected.String(), output)
+		}
+	}
+}
+
+func TestMigrationDirRejectsReportModes(t *testing.T) {
+	oldPath := writeSchema(t, "old.sql", "CREATE TABLE users (id INT);")
+	newPath := writeSchema(t, "new.sql", "CREATE TABLE users (id BIGINT);")
+
+	for _, mode := range [][]string{{"--json"}, {"--format", "markdown"}, {"--tables-only"}} {
+		dir := filepath.Join(t.TempDir(), "migrations")
+		args := append(append([]string{"--migration-dir", dir}, mode...), oldPath, newPath)
+		cmd := exec.Command(os.Args[0], args...)
+		cmd.Env = append(os.Environ(), "MYSQL_DIFF_RUN_MAIN=1")
+		if output, err := cmd.CombinedOutput(); err == nil || !strings.Contains(string(output), "--migration-dir cannot be combined") {
+			t.Errorf("Expected mysql-diff %s to fail, got %v:\n%s", strings.Join(args, " "), err, output)
+		}
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("Expected no migration directory for mysql-diff %s", strings.Join(args, " "))
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/n0madic/mysql-diff/pkg/alter"
+	"github.com/n0madic/mysql-diff/pkg/diff"
+	"github.com/n0madic/mysql-diff/pkg/output"
+	"github.com/n0madic/mysql-diff/pkg/parser"
+)
+
+// runWatchCommand polls a live database schema and prints the statements that bring it back to
+// the target schema file whenever it drifts
+func runWatchCommand(args []string) {
+	watchFlags := flag.NewFlagSet("watch", flag.ExitOnError)
+	interval := watchFlags.Duration("interval", time.Minute, "Time between schema polls")
+	mysqldump := watchFlags.String("mysqldump", "mysqldump", "Path to the mysqldump binary used to read the live schema")
+	pretty := watchFlags.Bool("pretty", false, "Align clauses of generated multi-clause ALTER statements")
+	watchFlags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage:\n")
+		fmt.Fprintf(os.Stderr, "  %s watch [OPTIONS] dsn target.sql\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Polls the live schema with mysqldump --no-data and prints the statements that migrate it\n")
+		fmt.Fprintf(os.Stderr, "to target.sql whenever it drifts. The DSN has the form user:password@tcp(host:port)/dbname.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		watchFlags.PrintDefaults()
+	}
+	watchFlags.Parse(args)
+
+	if watchFlags.NArg() != 2 {
+		fmt.Fprintf(os.Stderr, "Error: Expected 2 arguments, got %d\n\n", watchFlags.NArg())
+		watchFlags.Usage()
+		os.Exit(1)
+	}
+	if *interval <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: --interval must be positive\n")
+		os.Exit(1)
+	}
+
+	dsn, err := parseDSN(watchFlags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	targetPath := watchFlags.Arg(1)
+	sql, err := os.ReadFile(targetPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Target file '%s' not found\n", targetPath)
+		os.Exit(1)
+	}
+
+	generator := alter.NewStatementGenerator()
+	generator.Pretty = *pretty
+
+	watcher := &diff.DriftWatcher{
+		Loader:   mysqldumpLoader(*mysqldump, dsn),
+		Target:   parseSchema(targetPath, string(sql), 0),
+		Interval: *interval,
+		OnDrift: func(sd *diff.SchemaDiff) {
+			fmt.Printf("-- %s: schema drifted from %s\n", time.Now().Format(time.RFC3339), targetPath)
+			for _, statement := range driftStatements(generator, sd) {
+				fmt.Println(output.ColorizeSQLStatement(statement))
+			}
+		},
+		OnSync: func() {
+			fmt.Printf("-- %s: schema matches %s again\n", time.Now().Format(time.RFC3339), targetPath)
+		},
+		OnError: func(err error) {
+			fmt.Fprintf(os.Stderr, "-- Warning: %s: %v (retrying in %v)\n", time.Now().Format(time.RFC3339), err, *interval)
+		},
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	watcher.Run(ctx)
+}
+
+// driftStatements generates the statements that migrate the live schema to the target schema
+func driftStatements(generator *alter.StatementGenerator, sd *diff.SchemaDiff) []string {
+	statements := alter.GenerateCreateTableStatements(sd.AddedTables, nil)
+	for _, tableDiff := range sd.ModifiedTables {
+		statements = append(statements, generator.GenerateAlterStatements(tableDiff)...)
+	}
+	return append(statements, alter.GenerateDropTableStatements(sd.RemovedTables, nil)...)
+}
+
+// mysqlDSN holds the connection settings of a user:password@tcp(host:port)/dbname DSN
+type mysqlDSN struct {
+	User     string
+	Password string
+	Protocol string
+	Address  string
+	Database string
+}
+
+// parseDSN parses a DSN in the [user[:password]@][protocol[(address)]]/dbname[?params] form
+// used by Go MySQL drivers. Parameters are ignored.
+func parseDSN(value string) (*mysqlDSN, error) {
+	slash := strings.LastIndex(value, "/")
+	if slash < 0 {
+		return nil, fmt.Errorf("invalid DSN '%s': missing /dbname", value)
+	}
+
+	dsn := &mysqlDSN{Database: value[slash+1:]}
+	if idx := strings.Index(dsn.Database, "?"); idx >= 0 {
+		dsn.Database = dsn.Database[:idx]
+	}
+	if dsn.Database == "" {
+		return nil, fmt.Errorf("invalid DSN '%s': missing database name", value)
+	}
+
+	rest := value[:slash]
+	if at := strings.LastIndex(rest, "@"); at >= 0 {
+		dsn.User, dsn.Password, _ = strings.Cut(rest[:at], ":")
+		rest = rest[at+1:]
+	}
+
+	if open := strings.Index(rest, "("); open >= 0 {
+		if !strings.HasSuffix(rest, ")") {
+			return nil, fmt.Errorf("invalid DSN '%s': unterminated address", value)
+		}
+		dsn.Protocol = rest[:open]
+		dsn.Address = rest[open+1 : len(rest)-1]
+	} else {
+		dsn.Protocol = rest
+	}
+
+	switch dsn.Protocol {
+	case "", "tcp", "unix":
+	default:
+		return nil, fmt.Errorf("invalid DSN '%s': unsupported protocol %s", value, dsn.Protocol)
+	}
+
+	return dsn, nil
+}
+
+// mysqldumpArgs returns the mysqldump arguments that dump the schema of the DSN's database
+func (dsn *mysqlDSN) mysqldumpArgs() []string {
+	args := []string{"--no-data", "--skip-comments", "--skip-add-drop-table", "--skip-triggers"}
+	if dsn.User != "" {
+		args = append(args, "--user="+dsn.User)
+	}
+	if dsn.Address != "" {
+		if dsn.Protocol == "unix" {
+			args = append(args, "--socket="+dsn.Address)
+		} else {
+			host, port, hasPort := strings.Cut(dsn.Address, ":")
+			args = append(args, "--protocol=TCP", "--host="+host)
+			if hasPort {
+				args = append(args, "--port="+port)
+			}
+		}
+	}
+	return append(args, dsn.Database)
+}
+
+// mysqldumpLoader loads the live schema by running mysqldump against the DSN's database.
+// The password is passed in MYSQL_PWD so it does not show up in the process list.
+func mysqldumpLoader(binary string, dsn *mysqlDSN) diff.SchemaLoader {
+	return diff.SchemaLoaderFunc(func(ctx context.Context) ([]*parser.CreateTableStatement, error) {
+		cmd := exec.CommandContext(ctx, binary, dsn.mysqldumpArgs()...)
+		if dsn.Password != "" {
+			cmd.Env = append(os.Environ(), "MYSQL_PWD="+dsn.Password)
+		}
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+
+		dump, err := cmd.Output()
+		if err != nil {
+			if message := strings.TrimSpace(stderr.String()); message != "" {
+				return nil, fmt.Errorf("mysqldump failed: %s", message)
+			}
+			return nil, fmt.Errorf("mysqldump failed: %w", err)
+		}
+		return parser.ParseSQLDump(string(dump))
+	})
+}
@@ -53,60 +53,22 @@ func main() {
 	fmt.Printf("New schema: %d tables\n", len(newTables))
 	fmt.Println()
 
-	// Create maps for easy lookup
-	oldTablesMap := make(map[string]*parser.CreateTableStatement)
-	newTablesMap := make(map[string]*parser.CreateTableStatement)
+	// Compare the schemas table by table
+	schemaDiff := diff.CompareSchemas(oldTables, newTables)
 
-	for _, table := range oldTables {
-		oldTablesMap[table.TableName] = table
+	for _, table := range schemaDiff.AddedTables {
+		fmt.Printf("+ TABLE ADDED: %s\n", table.TableName)
 	}
-	for _, table := range newTables {
-		newTablesMap[table.TableName] = table
+	for _, table := range schemaDiff.RemovedTables {
+		fmt.Printf("- TABLE REMOVED: %s\n", table.TableName)
 	}
-
-	// Find all table names
-	allTableNames := make(map[string]bool)
-	for name := range oldTablesMap {
-		allTableNames[name] = true
-	}
-	for name := range newTablesMap {
-		allTableNames[name] = true
+	for _, tableDiff := range schemaDiff.ModifiedTables {
+		diff.PrintDiffSummary(tableDiff)
 	}
 
-	// Analyze differences for each table
-	var diffs []*diff.TableDiff
-	tablesAdded := 0
-	tablesRemoved := 0
-	tablesModified := 0
-
-	for tableName := range allTableNames {
-		oldTable, hasOld := oldTablesMap[tableName]
-		newTable, hasNew := newTablesMap[tableName]
-
-		if !hasOld {
-			// Table added
-			tablesAdded++
-			fmt.Printf("+ TABLE ADDED: %s\n", tableName)
-			continue
-		}
-
-		if !hasNew {
-			// Table removed
-			tablesRemoved++
-			fmt.Printf("- TABLE REMOVED: %s\n", tableName)
-			continue
-		}
-
-		// Table exists in both, analyze differences
-		tableDiff := diff.CompareTables(oldTable, newTable)
-		if tableDiff.HasChanges() {
-			tablesModified++
-			diffs = append(diffs, tableDiff)
-			diff.PrintDiffSummary(tableDiff)
-		} else {
-			fmt.Printf("  TABLE UNCHANGED: %s\n", tableName)
-		}
-	}
+	tablesAdded := len(schemaDiff.AddedTables)
+	tablesRemoved := len(schemaDiff.RemovedTables)
+	tablesModified := len(schemaDiff.ModifiedTables)
 
 	// Print overall summary
 	fmt.Printf("\n%s\n", strings.Repeat("=", 60))
@@ -115,12 +77,12 @@ func main() {
 	fmt.Printf("Tables added: %d\n", tablesAdded)
 	fmt.Printf("Tables removed: %d\n", tablesRemoved)
 	fmt.Printf("Tables modified: %d\n", tablesModified)
-	fmt.Printf("Tables unchanged: %d\n", len(allTableNames)-tablesAdded-tablesRemoved-tablesModified)
+	fmt.Printf("Tables unchanged: %d\n", len(oldTables)-tablesRemoved-tablesModified)
 
 	// Print detailed diffs if requested
-	if len(diffs) > 0 {
-		fmt.Printf("\nDetailed differences for %d modified tables:\n", len(diffs))
-		for _, tableDiff := range diffs {
+	if tablesModified > 0 {
+		fmt.Printf("\nDetailed differences for %d modified tables:\n", tablesModified)
+		for _, tableDiff := range schemaDiff.ModifiedTables {
 			diff.PrintTableDiff(tableDiff, true)
 		}
 	}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -245,6 +246,12 @@ func printTableInfo(table *parser.CreateTableStatement, index int) {
 		if table.TableOptions.RowFormat != nil {
 			fmt.Printf("  - ROW_FORMAT: %s\n", *table.TableOptions.RowFormat)
 		}
+		if table.TableOptions.AvgRowLength != nil {
+			fmt.Printf("  - AVG_ROW_LENGTH: %d\n", *table.TableOptions.AvgRowLength)
+		}
+		if table.TableOptions.AutoextendSize != nil {
+			fmt.Printf("  - AUTOEXTEND_SIZE: %s\n", *table.TableOptions.AutoextendSize)
+		}
 		if table.TableOptions.StatsPersistent != nil {
 			fmt.Printf("  - STATS_PERSISTENT: %d\n", *table.TableOptions.StatsPersistent)
 		}
@@ -269,6 +276,9 @@ func printTableInfo(table *parser.CreateTableStatement, index int) {
 		if table.TableOptions.InsertMethod != nil {
 			fmt.Printf("  - INSERT_METHOD: %s\n", *table.TableOptions.InsertMethod)
 		}
+		if table.TableOptions.Connection != nil {
+			fmt.Printf("  - CONNECTION: %s\n", *table.TableOptions.Connection)
+		}
 	}
 
 	// Partitioning information
@@ -331,23 +341,22 @@ func parseSingleFile(dumpFile string) ParseResult {
 	fmt.Printf("File size: %d bytes\n", fileSize)
 	fmt.Println("Parsing SQL dump...")
 
-	// Parse the dump
-	tables, err := parser.ParseSQLDump(sqlContent)
+	// Parse the dump, reporting statements that could not be parsed
+	tables, parseErrors, err := parser.ParseSQLDumpTolerant(sqlContent, 0)
 	if err != nil {
-		errorMsg := fmt.Sprintf("SQL parsing failed: %v", err)
-		fmt.Printf("Error: %s\n", errorMsg)
-
-		// Try to provide context for parsing errors
-		errorStr := err.Error()
-
-		// Simple line/column extraction (would need more sophisticated parsing for full error context)
-		if strings.Contains(errorStr, "line") {
-			fmt.Printf("\nError details: %s\n", errorStr)
-		}
-
+		fmt.Printf("Error: SQL parsing failed: %v\n", err)
 		fmt.Printf("\nFatal error: Stopping execution due to SQL parsing errors in '%s'\n", dumpFile)
 		os.Exit(1)
 	}
+	for _, parseErr := range parseErrors {
+		var posErr *parser.ParseError
+		if errors.As(parseErr, &posErr) {
+			fmt.Printf("Warning: skipped statement: %s:%d:%d: %s (near %q)\n",
+				dumpFile, posErr.Line, posErr.Column, posErr.Message, posErr.Token)
+		} else {
+			fmt.Printf("Warning: skipped statement: %v\n", parseErr)
+		}
+	}
 
 	fmt.Printf("Found %d CREATE TABLE statements\n", len(tables))
 
@@ -381,36 +390,20 @@ func printAggregatedSummary(results []ParseResult) {
 	fmt.Printf("\nTotal file size: %s bytes\n", addCommas(totalFileSize))
 	fmt.Printf("Total tables found: %d\n", len(allTables))
 
-	// Statistics
-	totalColumns := 0
-	totalIndexes := 0
-	totalForeignKeys := 0
-
-	for _, table := range allTables {
-		totalColumns += len(table.Columns)
-		totalIndexes += len(table.Indexes)
-		totalForeignKeys += len(table.ForeignKeys)
-	}
+	stats := parser.CollectSchemaStats(allTables)
 
 	fmt.Println("\nAggregated Statistics:")
-	fmt.Printf("  - Total columns: %d\n", totalColumns)
-	fmt.Printf("  - Total indexes: %d\n", totalIndexes)
-	fmt.Printf("  - Total foreign keys: %d\n", totalForeignKeys)
-	if len(allTables) > 0 {
-		fmt.Printf("  - Average columns per table: %.1f\n", float64(totalColumns)/float64(len(allTables)))
+	fmt.Printf("  - Total columns: %d\n", stats.Columns)
+	fmt.Printf("  - Total indexes: %d\n", stats.Indexes)
+	fmt.Printf("  - Total foreign keys: %d\n", stats.ForeignKeys)
+	if stats.Tables > 0 {
+		fmt.Printf("  - Average columns per table: %.1f\n", stats.AverageColumnsPerTable())
 	}
 
 	// Engines used across all files
-	engines := make(map[string]bool)
-	for _, table := range allTables {
-		if table.TableOptions != nil && table.TableOptions.Engine != nil {
-			engines[*table.TableOptions.Engine] = true
-		}
-	}
-
-	if len(engines) > 0 {
+	if len(stats.Engines) > 0 {
 		var engineList []string
-		for engine := range engines {
+		for engine := range stats.Engines {
 			engineList = append(engineList, engine)
 		}
 		sort.Strings(engineList)
@@ -0,0 +1,53 @@
+package alter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/n0madic/mysql-diff/pkg/diff"
+)
+
+// charsetCollationPrefixes maps the common character sets to the prefixes of their collations.
+// utf8 is an alias of utf8mb3, whose collations MySQL 8.0 reports under either name.
+var charsetCollationPrefixes = map[string][]string{
+	"utf8":    {"utf8_", "utf8mb3_"},
+	"utf8mb3": {"utf8_", "utf8mb3_"},
+	"utf8mb4": {"utf8mb4_"},
+	"latin1":  {"latin1_"},
+	"ascii":   {"ascii_"},
+	"binary":  {"binary"},
+}
+
+// ValidateCharsetCollation returns an error when collation does not belong to charset. Character
+// sets outside the built-in utf8, utf8mb4, latin1, ascii and binary families are not checked.
+func ValidateCharsetCollation(charset, collation string) error {
+	prefixes, known := charsetCollationPrefixes[strings.ToLower(charset)]
+	if !known || collation == "" {
+		return nil
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(strings.ToLower(collation), prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("collation %s is not valid for character set %s", collation, charset)
+}
+
+// ValidateTableOptionsCharset checks the character set and collation that the table options
+// statement generated for optionsDiff sets. Only diffs that change the character set or the
+// collation are checked, against the pair of the new table options.
+func ValidateTableOptionsCharset(optionsDiff *diff.TableOptionsDiff) error {
+	if optionsDiff == nil || optionsDiff.NewOptions == nil || optionsDiff.ChangeType == diff.ChangeTypeRemoved {
+		return nil
+	}
+	if optionsDiff.ChangeType == diff.ChangeTypeModified &&
+		(optionsDiff.Changes == nil || optionsDiff.Changes.CharacterSet == nil && optionsDiff.Changes.Collate == nil) {
+		return nil
+	}
+
+	opts := optionsDiff.NewOptions
+	if opts.CharacterSet == nil || opts.Collate == nil {
+		return nil
+	}
+	return ValidateCharsetCollation(*opts.CharacterSet, *opts.Collate)
+}
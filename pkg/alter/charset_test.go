@@ -0,0 +1,77 @@
+package alter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/n0madic/mysql-diff/pkg/diff"
+	"github.com/n0madic/mysql-diff/pkg/parser"
+)
+
+func TestValidateCharsetCollation(t *testing.T) {
+	valid := [][2]string{
+		{"utf8mb4", "utf8mb4_0900_ai_ci"},
+		{"UTF8MB4", "utf8mb4_unicode_ci"},
+		{"utf8", "utf8mb3_general_ci"},
+		{"utf8mb3", "utf8_bin"},
+		{"latin1", "latin1_swedish_ci"},
+		{"binary", "binary"},
+		{"cp1251", "utf8mb4_bin"}, // unknown character sets are not checked
+	}
+	for _, pair := range valid {
+		if err := ValidateCharsetCollation(pair[0], pair[1]); err != nil {
+			t.Errorf("Expected %s with %s to be valid, got %v", pair[0], pair[1], err)
+		}
+	}
+
+	invalid := [][2]string{
+		{"utf8mb4", "utf8_general_ci"},
+		{"utf8", "utf8mb4_bin"},
+		{"latin1", "utf8mb4_0900_ai_ci"},
+	}
+	for _, pair := range invalid {
+		if err := ValidateCharsetCollation(pair[0], pair[1]); err == nil {
+			t.Errorf("Expected an error for %s with %s", pair[0], pair[1])
+		}
+	}
+}
+
+func TestValidateCharsetStatements(t *testing.T) {
+	parse := func(sql string) *parser.CreateTableStatement {
+		tables, err := parser.ParseSQLDump(sql)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", sql, err)
+		}
+		return tables[0]
+	}
+	oldTable := parse("CREATE TABLE users (id INT) ENGINE=InnoDB DEFAULT CHARSET=latin1 COLLATE=latin1_swedish_ci")
+	mismatched := parse("CREATE TABLE users (id INT) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=latin1_swedish_ci")
+
+	tableDiff := diff.NewTableDiffAnalyzer().CompareTables(oldTable, mismatched)
+	if err := ValidateTableOptionsCharset(tableDiff.TableOptionsDiff); err == nil {
+		t.Error("Expected the mismatched character set and collation to be reported")
+	}
+
+	generator := NewStatementGenerator()
+	generator.ValidateCharset = true
+	result := strings.Join(generator.GenerateAlterStatements(tableDiff), "\n")
+	expected := "-- Warning: `users` collation latin1_swedish_ci is not valid for character set utf8mb4"
+	if !strings.Contains(result, expected) || !strings.Contains(result, "DEFAULT CHARSET=utf8mb4") {
+		t.Errorf("Expected %q before the table options statement, got:\n%s", expected, result)
+	}
+
+	// Without the option only the statement is generated
+	if result := NewStatementGenerator().GenerateAlterStatements(tableDiff); len(result) != 1 {
+		t.Errorf("Expected only the table options statement, got %v", result)
+	}
+
+	// A matching pair, or a change that touches neither option, is not reported
+	matching := parse("CREATE TABLE users (id INT) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin")
+	if err := ValidateTableOptionsCharset(diff.NewTableDiffAnalyzer().CompareTables(oldTable, matching).TableOptionsDiff); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	engineOnly := parse("CREATE TABLE users (id INT) ENGINE=MyISAM DEFAULT CHARSET=utf8mb4 COLLATE=latin1_swedish_ci")
+	if err := ValidateTableOptionsCharset(diff.NewTableDiffAnalyzer().CompareTables(mismatched, engineOnly).TableOptionsDiff); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
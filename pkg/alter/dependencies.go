@@ -0,0 +1,145 @@
+package alter
+
+import (
+	"slices"
+
+	"github.com/n0madic/mysql-diff/pkg/parser"
+)
+
+// BuildDependencyGraph returns, for every table, the sorted list of tables it references via foreign keys
+func BuildDependencyGraph(tables []*parser.CreateTableStatement) map[string][]string {
+	graph := make(map[string][]string)
+
+	for _, table := range tables {
+		if table == nil {
+			continue
+		}
+
+		refs := make(map[string]bool)
+		for _, fk := range table.ForeignKeys {
+			if fk.Reference.TableName != "" {
+				refs[fk.Reference.TableName] = true
+			}
+		}
+		for _, col := range table.Columns {
+			if col.Reference != nil && col.Reference.TableName != "" {
+				refs[col.Reference.TableName] = true
+			}
+		}
+
+		deps := make([]string, 0, len(refs))
+		for name := range refs {
+			deps = append(deps, name)
+		}
+		slices.Sort(deps)
+
+		graph[table.TableName] = deps
+	}
+
+	return graph
+}
+
+// FindDependencyCycles returns groups of tables that reference each other through foreign keys.
+// Each group lists its participants in sorted order. A self-referencing table is not a cycle, since
+// it can be created with its own foreign keys.
+func FindDependencyCycles(graph map[string][]string) [][]string {
+	names := make([]string, 0, len(graph))
+	for name := range graph {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	// Tarjan's strongly connected components algorithm
+	index := 0
+	indexes := make(map[string]int)
+	lowlinks := make(map[string]int)
+	onStack := make(map[string]bool)
+	stack := []string{}
+	cycles := [][]string{}
+
+	var visit func(name string)
+	visit = func(name string) {
+		indexes[name] = index
+		lowlinks[name] = index
+		index++
+		stack = append(stack, name)
+		onStack[name] = true
+
+		for _, dep := range graph[name] {
+			if _, known := graph[dep]; !known {
+				// Referenced table is not part of the schema
+				continue
+			}
+			if _, visited := indexes[dep]; !visited {
+				visit(dep)
+				lowlinks[name] = min(lowlinks[name], lowlinks[dep])
+			} else if onStack[dep] {
+				lowlinks[name] = min(lowlinks[name], indexes[dep])
+			}
+		}
+
+		if lowlinks[name] != indexes[name] {
+			return
+		}
+
+		component := []string{}
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+			if last == name {
+				break
+			}
+		}
+
+		if len(component) > 1 {
+			slices.Sort(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, name := range names {
+		if _, visited := indexes[name]; !visited {
+			visit(name)
+		}
+	}
+
+	slices.SortFunc(cycles, func(a, b []string) int {
+		return slices.Compare(a, b)
+	})
+
+	return cycles
+}
+
+// sortByDependencies orders tables so that each one follows the tables it references via foreign
+// keys, as CREATE TABLE needs them; reversed, the order suits DROP TABLE. Tables are otherwise kept
+// in their given order, and the tables of a cycle in the order they are first reached.
+func sortByDependencies(tables []*parser.CreateTableStatement) []*parser.CreateTableStatement {
+	graph := BuildDependencyGraph(tables)
+	byName := make(map[string]*parser.CreateTableStatement, len(tables))
+	for _, table := range tables {
+		byName[table.TableName] = table
+	}
+
+	sorted := make([]*parser.CreateTableStatement, 0, len(tables))
+	visited := make(map[string]bool)
+	var visit func(name string)
+	visit = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+		for _, dep := range graph[name] {
+			if byName[dep] != nil {
+				visit(dep)
+			}
+		}
+		sorted = append(sorted, byName[name])
+	}
+	for _, table := range tables {
+		visit(table.TableName)
+	}
+
+	return sorted
+}
@@ -0,0 +1,120 @@
+package alter
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/n0madic/mysql-diff/pkg/parser"
+)
+
+func TestBuildDependencyGraph_Chain(t *testing.T) {
+	sql := `
+		CREATE TABLE users (id INT, PRIMARY KEY (id));
+		CREATE TABLE orders (
+			id INT,
+			user_id INT,
+			PRIMARY KEY (id),
+			FOREIGN KEY (user_id) REFERENCES users (id)
+		);
+		CREATE TABLE order_items (
+			id INT,
+			order_id INT,
+			user_id INT,
+			FOREIGN KEY (order_id) REFERENCES orders (id),
+			FOREIGN KEY (user_id) REFERENCES users (id)
+		);
+	`
+
+	tables, err := parser.ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("Failed to parse SQL: %v", err)
+	}
+
+	graph := BuildDependencyGraph(tables)
+
+	if len(graph) != 3 {
+		t.Fatalf("Expected 3 tables in graph, got %d", len(graph))
+	}
+	if deps, ok := graph["users"]; !ok || len(deps) != 0 {
+		t.Errorf("Expected users to have no dependencies, got %v", deps)
+	}
+	if deps := graph["orders"]; !slices.Equal(deps, []string{"users"}) {
+		t.Errorf("Expected orders to depend on [users], got %v", deps)
+	}
+	if deps := graph["order_items"]; !slices.Equal(deps, []string{"orders", "users"}) {
+		t.Errorf("Expected order_items to depend on [orders users], got %v", deps)
+	}
+}
+
+func TestBuildDependencyGraph_Cycle(t *testing.T) {
+	tables := []*parser.CreateTableStatement{
+		{
+			TableName: "a",
+			ForeignKeys: []parser.ForeignKeyDefinition{
+				{Columns: []string{"b_id"}, Reference: parser.ForeignKeyReference{TableName: "b", Columns: []string{"id"}}},
+			},
+		},
+		{
+			TableName: "b",
+			ForeignKeys: []parser.ForeignKeyDefinition{
+				{Columns: []string{"a_id"}, Reference: parser.ForeignKeyReference{TableName: "a", Columns: []string{"id"}}},
+			},
+		},
+	}
+
+	graph := BuildDependencyGraph(tables)
+
+	if deps := graph["a"]; !slices.Equal(deps, []string{"b"}) {
+		t.Errorf("Expected a to depend on [b], got %v", deps)
+	}
+	if deps := graph["b"]; !slices.Equal(deps, []string{"a"}) {
+		t.Errorf("Expected b to depend on [a], got %v", deps)
+	}
+}
+
+func TestFindDependencyCycles_TwoTableCycle(t *testing.T) {
+	graph := map[string][]string{
+		"a":     {"b"},
+		"b":     {"a"},
+		"c":     {"a"},
+		"users": {},
+	}
+
+	cycles := FindDependencyCycles(graph)
+
+	if len(cycles) != 1 {
+		t.Fatalf("Expected 1 cycle, got %d: %v", len(cycles), cycles)
+	}
+	if !slices.Equal(cycles[0], []string{"a", "b"}) {
+		t.Errorf("Expected cycle [a b], got %v", cycles[0])
+	}
+}
+
+func TestFindDependencyCycles_NoCycle(t *testing.T) {
+	graph := map[string][]string{
+		"users":       {},
+		"orders":      {"users"},
+		"order_items": {"orders", "users", "products"},
+	}
+
+	if cycles := FindDependencyCycles(graph); len(cycles) != 0 {
+		t.Errorf("Expected no cycles, got %v", cycles)
+	}
+}
+
+func TestFindDependencyCycles_SelfReference(t *testing.T) {
+	graph := map[string][]string{
+		"categories": {"categories"},
+	}
+
+	if cycles := FindDependencyCycles(graph); len(cycles) != 0 {
+		t.Errorf("Expected no cycle for a self-referencing table, got %v", cycles)
+	}
+
+	graph["products"] = []string{"categories", "products", "vendors"}
+	graph["vendors"] = []string{"products"}
+	cycles := FindDependencyCycles(graph)
+	if len(cycles) != 1 || !slices.Equal(cycles[0], []string{"products", "vendors"}) {
+		t.Errorf("Expected only the cycle [products vendors], got %v", cycles)
+	}
+}
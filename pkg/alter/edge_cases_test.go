@@ -1,6 +1,7 @@
 package alter
 
 import (
+	"slices"
 	"strings"
 	"testing"
 
@@ -323,6 +324,253 @@ func TestTableOptionsChanges(t *testing.T) {
 	}
 }
 
+func TestEngineChangeWarningComment(t *testing.T) {
+	oldEngine := "InnoDB"
+	newEngine := "MyISAM"
+
+	oldTable := createTestTable("accounts", []parser.ColumnDefinition{
+		createTestColumn("id", "INT"),
+	})
+	oldTable.TableOptions = &parser.TableOptions{Engine: &oldEngine}
+
+	newTable := createTestTable("accounts", []parser.ColumnDefinition{
+		createTestColumn("id", "INT"),
+	})
+	newTable.TableOptions = &parser.TableOptions{Engine: &newEngine}
+
+	generator := NewStatementGenerator()
+	statements := generator.GenerateAlterStatements(createTestTableDiff(oldTable, newTable))
+
+	if len(statements) != 2 {
+		t.Fatalf("Expected warning comment and ALTER statement, got %d statements: %v", len(statements), statements)
+	}
+	if !strings.HasPrefix(statements[0], "-- Warning: `accounts`") {
+		t.Errorf("Expected first statement to be a warning comment, got: %s", statements[0])
+	}
+	if !strings.Contains(statements[0], "FOREIGN KEY") {
+		t.Errorf("Expected warning to note foreign key behavior, got: %s", statements[0])
+	}
+	if statements[1] != "ALTER TABLE `accounts` ENGINE=MyISAM;" {
+		t.Errorf("Unexpected ALTER statement: %s", statements[1])
+	}
+}
+
+// TestCommentOnlyTableOptionsStatement tests that a table comment change is emitted on its own,
+// without restating the unchanged options of the table
+func TestCommentOnlyTableOptionsStatement(t *testing.T) {
+	oldTable := createTestTable("t", []parser.ColumnDefinition{createTestColumn("id", "INT")})
+	oldTable.TableOptions = &parser.TableOptions{
+		Engine:        stringPtr("InnoDB"),
+		AutoIncrement: intPtr(42),
+		CharacterSet:  stringPtr("utf8mb4"),
+		RowFormat:     stringPtr("DYNAMIC"),
+		Comment:       stringPtr("'old'"),
+	}
+	newTable := createTestTable("t", []parser.ColumnDefinition{createTestColumn("id", "INT")})
+	newOptions := *oldTable.TableOptions
+	newOptions.Comment = stringPtr("'new'")
+	newTable.TableOptions = &newOptions
+
+	generator := NewStatementGenerator()
+	statements := generator.GenerateAlterStatements(createTestTableDiff(oldTable, newTable))
+	if len(statements) != 1 || statements[0] != "ALTER TABLE `t` COMMENT='new';" {
+		t.Errorf("Expected only the comment statement, got %v", statements)
+	}
+
+	// Removing the comment clears it
+	newOptions.Comment = nil
+	statements = generator.GenerateAlterStatements(createTestTableDiff(oldTable, newTable))
+	if len(statements) != 1 || statements[0] != "ALTER TABLE `t` COMMENT='';" {
+		t.Errorf("Expected the comment to be cleared, got %v", statements)
+	}
+}
+
+func TestPackKeysChangeStatement(t *testing.T) {
+	engine := "MyISAM"
+
+	oldTable := createTestTable("logs", []parser.ColumnDefinition{
+		createTestColumn("id", "INT"),
+	})
+	oldTable.TableOptions = &parser.TableOptions{Engine: &engine, PackKeys: intPtr(1)}
+
+	newTable := createTestTable("logs", []parser.ColumnDefinition{
+		createTestColumn("id", "INT"),
+	})
+	newTable.TableOptions = &parser.TableOptions{Engine: &engine, PackKeys: intPtr(0)}
+
+	generator := NewStatementGenerator()
+	statements := generator.GenerateAlterStatements(createTestTableDiff(oldTable, newTable))
+
+	if len(statements) != 1 || statements[0] != "ALTER TABLE `logs` PACK_KEYS=0;" {
+		t.Errorf("Expected PACK_KEYS=0 statement, got %v", statements)
+	}
+
+	// Removing the option restores the default
+	newTable.TableOptions = &parser.TableOptions{Engine: &engine}
+	statements = generator.GenerateAlterStatements(createTestTableDiff(oldTable, newTable))
+
+	if len(statements) != 1 || statements[0] != "ALTER TABLE `logs` PACK_KEYS=DEFAULT;" {
+		t.Errorf("Expected PACK_KEYS=DEFAULT statement, got %v", statements)
+	}
+}
+
+func TestNumericTableOptionChangeStatements(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldOpts  *parser.TableOptions
+		newOpts  *parser.TableOptions
+		expected string
+	}{
+		{
+			name:     "stats persistent",
+			oldOpts:  &parser.TableOptions{StatsPersistent: intPtr(1)},
+			newOpts:  &parser.TableOptions{StatsPersistent: intPtr(0)},
+			expected: "ALTER TABLE `metrics` STATS_PERSISTENT=0;",
+		},
+		{
+			name:     "stats auto recalc reset",
+			oldOpts:  &parser.TableOptions{StatsAutoRecalc: intPtr(0)},
+			newOpts:  &parser.TableOptions{},
+			expected: "ALTER TABLE `metrics` STATS_AUTO_RECALC=DEFAULT;",
+		},
+		{
+			name:     "avg row length",
+			oldOpts:  &parser.TableOptions{AvgRowLength: intPtr(100)},
+			newOpts:  &parser.TableOptions{AvgRowLength: intPtr(250)},
+			expected: "ALTER TABLE `metrics` AVG_ROW_LENGTH=250;",
+		},
+		{
+			name:     "autoextend size",
+			oldOpts:  &parser.TableOptions{AutoextendSize: stringPtr("4M")},
+			newOpts:  &parser.TableOptions{AutoextendSize: stringPtr("8M")},
+			expected: "ALTER TABLE `metrics` AUTOEXTEND_SIZE=8M;",
+		},
+		{
+			name:     "autoextend size reset",
+			oldOpts:  &parser.TableOptions{AutoextendSize: stringPtr("4M")},
+			newOpts:  &parser.TableOptions{},
+			expected: "ALTER TABLE `metrics` AUTOEXTEND_SIZE=0;",
+		},
+		{
+			name:     "stats sample pages",
+			oldOpts:  &parser.TableOptions{StatsSamplePages: intPtr(20)},
+			newOpts:  &parser.TableOptions{StatsSamplePages: intPtr(64)},
+			expected: "ALTER TABLE `metrics` STATS_SAMPLE_PAGES=64;",
+		},
+	}
+
+	generator := NewStatementGenerator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldTable := createTestTable("metrics", []parser.ColumnDefinition{createTestColumn("id", "INT")})
+			oldTable.TableOptions = tt.oldOpts
+			newTable := createTestTable("metrics", []parser.ColumnDefinition{createTestColumn("id", "INT")})
+			newTable.TableOptions = tt.newOpts
+
+			statements := generator.GenerateAlterStatements(createTestTableDiff(oldTable, newTable))
+			if len(statements) != 1 || statements[0] != tt.expected {
+				t.Errorf("Expected [%s], got %v", tt.expected, statements)
+			}
+		})
+	}
+}
+
+func TestStorageTableOptionChangeStatements(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldOpts  *parser.TableOptions
+		newOpts  *parser.TableOptions
+		expected string
+	}{
+		{
+			name:     "row format",
+			oldOpts:  &parser.TableOptions{RowFormat: stringPtr("DYNAMIC")},
+			newOpts:  &parser.TableOptions{RowFormat: stringPtr("COMPRESSED"), KeyBlockSize: intPtr(8)},
+			expected: "ALTER TABLE `metrics` ROW_FORMAT=COMPRESSED KEY_BLOCK_SIZE=8;",
+		},
+		{
+			name:     "row format reset",
+			oldOpts:  &parser.TableOptions{RowFormat: stringPtr("COMPRESSED"), KeyBlockSize: intPtr(8)},
+			newOpts:  &parser.TableOptions{},
+			expected: "ALTER TABLE `metrics` ROW_FORMAT=DEFAULT KEY_BLOCK_SIZE=0;",
+		},
+		{
+			name:     "encryption",
+			oldOpts:  &parser.TableOptions{},
+			newOpts:  &parser.TableOptions{Encryption: stringPtr("Y")},
+			expected: "ALTER TABLE `metrics` ENCRYPTION='Y';",
+		},
+		{
+			name:     "encryption reset",
+			oldOpts:  &parser.TableOptions{Encryption: stringPtr("Y")},
+			newOpts:  &parser.TableOptions{},
+			expected: "ALTER TABLE `metrics` ENCRYPTION='N';",
+		},
+		{
+			name:     "compression reset",
+			oldOpts:  &parser.TableOptions{Compression: stringPtr("zlib")},
+			newOpts:  &parser.TableOptions{},
+			expected: "ALTER TABLE `metrics` COMPRESSION='None';",
+		},
+		{
+			name:     "max and min rows",
+			oldOpts:  &parser.TableOptions{MaxRows: intPtr(1000)},
+			newOpts:  &parser.TableOptions{MinRows: intPtr(10)},
+			expected: "ALTER TABLE `metrics` MAX_ROWS=0 MIN_ROWS=10;",
+		},
+		{
+			name:     "tablespace",
+			oldOpts:  &parser.TableOptions{},
+			newOpts:  &parser.TableOptions{Tablespace: stringPtr("ts1")},
+			expected: "ALTER TABLE `metrics` TABLESPACE `ts1`;",
+		},
+		{
+			name:     "merge union",
+			oldOpts:  &parser.TableOptions{Union: []string{"t1"}, InsertMethod: stringPtr("FIRST")},
+			newOpts:  &parser.TableOptions{Union: []string{"t1", "t2"}, InsertMethod: stringPtr("LAST")},
+			expected: "ALTER TABLE `metrics` UNION=(`t1`,`t2`) INSERT_METHOD=LAST;",
+		},
+	}
+
+	generator := NewStatementGenerator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldTable := createTestTable("metrics", []parser.ColumnDefinition{createTestColumn("id", "INT")})
+			oldTable.TableOptions = tt.oldOpts
+			newTable := createTestTable("metrics", []parser.ColumnDefinition{createTestColumn("id", "INT")})
+			newTable.TableOptions = tt.newOpts
+
+			statements := generator.GenerateAlterStatements(createTestTableDiff(oldTable, newTable))
+			if len(statements) != 1 || statements[0] != tt.expected {
+				t.Errorf("Expected [%s], got %v", tt.expected, statements)
+			}
+		})
+	}
+}
+
+func TestConnectionChangeStatement(t *testing.T) {
+	engine := "FEDERATED"
+
+	oldTable := createTestTable("remote_orders", []parser.ColumnDefinition{createTestColumn("id", "INT")})
+	oldTable.TableOptions = &parser.TableOptions{Engine: &engine, Connection: stringPtr("'mysql://app@db1:3306/shop/orders'")}
+
+	newTable := createTestTable("remote_orders", []parser.ColumnDefinition{createTestColumn("id", "INT")})
+	newTable.TableOptions = &parser.TableOptions{Engine: &engine, Connection: stringPtr("'mysql://app@db2:3306/shop/orders'")}
+
+	generator := NewStatementGenerator()
+	statements := generator.GenerateAlterStatements(createTestTableDiff(oldTable, newTable))
+
+	expected := "ALTER TABLE `remote_orders` CONNECTION='mysql://app@db2:3306/shop/orders';"
+	if len(statements) != 1 || statements[0] != expected {
+		t.Errorf("Expected [%s], got %v", expected, statements)
+	}
+
+	// Unquoted values are quoted on output
+	if quoted := quoteOptionString("mysql://app@db2/shop/orders"); quoted != "'mysql://app@db2/shop/orders'" {
+		t.Errorf("Expected value to be quoted, got %s", quoted)
+	}
+}
+
 func TestEmptyTableDiff(t *testing.T) {
 	// Test with no changes
 	table := createTestTable("test", []parser.ColumnDefinition{
@@ -412,3 +660,190 @@ func TestComplexDataTypeChanges(t *testing.T) {
 		t.Error("Expected UNSIGNED in statement")
 	}
 }
+
+func TestEmptyOldSchemaProducesAllCreates(t *testing.T) {
+	newTables := []*parser.CreateTableStatement{
+		createTestTable("users", []parser.ColumnDefinition{createTestColumn("id", "INT")}),
+		createTestTable("orders", []parser.ColumnDefinition{createTestColumn("id", "INT")}),
+	}
+
+	matches := MatchTablesByName(nil, newTables)
+	if len(matches) != 2 {
+		t.Fatalf("Expected 2 matches, got %d", len(matches))
+	}
+	for name, match := range matches {
+		if match.Old != nil || match.New == nil {
+			t.Errorf("Expected table %s to exist only in new schema", name)
+		}
+	}
+
+	creates := GenerateCreateTableStatements(newTables, map[string]bool{})
+	if len(creates) != 2 {
+		t.Fatalf("Expected 2 CREATE statements, got %d: %v", len(creates), creates)
+	}
+	if !strings.Contains(creates[0], "`users`") || !strings.Contains(creates[1], "`orders`") {
+		t.Errorf("Expected CREATE statements for users and orders, got %v", creates)
+	}
+
+	if drops := GenerateDropTableStatements(nil, map[string]bool{"users": true, "orders": true}); len(drops) != 0 {
+		t.Errorf("Expected no DROP statements, got %v", drops)
+	}
+}
+
+func TestEmptyNewSchemaProducesAllDrops(t *testing.T) {
+	oldTables := []*parser.CreateTableStatement{
+		createTestTable("users", []parser.ColumnDefinition{createTestColumn("id", "INT")}),
+		createTestTable("orders", []parser.ColumnDefinition{createTestColumn("id", "INT")}),
+	}
+
+	matches := MatchTablesByName(oldTables, nil)
+	if len(matches) != 2 {
+		t.Fatalf("Expected 2 matches, got %d", len(matches))
+	}
+	for name, match := range matches {
+		if match.Old == nil || match.New != nil {
+			t.Errorf("Expected table %s to exist only in old schema", name)
+		}
+	}
+
+	drops := GenerateDropTableStatements(oldTables, map[string]bool{})
+	expected := []string{"DROP TABLE IF EXISTS `users`;", "DROP TABLE IF EXISTS `orders`;"}
+	if len(drops) != len(expected) {
+		t.Fatalf("Expected %d DROP statements, got %d: %v", len(expected), len(drops), drops)
+	}
+	for i, stmt := range expected {
+		if drops[i] != stmt {
+			t.Errorf("Expected %s, got %s", stmt, drops[i])
+		}
+	}
+
+	if creates := GenerateCreateTableStatements(nil, map[string]bool{"users": true, "orders": true}); len(creates) != 0 {
+		t.Errorf("Expected no CREATE statements, got %v", creates)
+	}
+}
+
+func TestGenerateMigrationWithEmptySide(t *testing.T) {
+	tables := []*parser.CreateTableStatement{
+		createTestTable("users", []parser.ColumnDefinition{createTestColumn("id", "INT")}),
+	}
+
+	create := "CREATE TABLE `users` (\n  `id` INT\n);"
+	drop := "DROP TABLE IF EXISTS `users`;"
+
+	generator := NewStatementGenerator()
+	if up, down := generator.GenerateMigration(nil, tables); !slices.Equal(up, []string{create}) || !slices.Equal(down, []string{drop}) {
+		t.Errorf("Expected the table to be created and dropped again for empty old schema, got up=%v down=%v", up, down)
+	}
+	if up, down := generator.GenerateMigration(tables, nil); !slices.Equal(up, []string{drop}) || !slices.Equal(down, []string{create}) {
+		t.Errorf("Expected the table to be dropped and created again for empty new schema, got up=%v down=%v", up, down)
+	}
+	if up, down := generator.GenerateMigration(nil, nil); len(up) != 0 || len(down) != 0 {
+		t.Errorf("Expected no statements for two empty schemas, got up=%v down=%v", up, down)
+	}
+
+	steps := generator.GenerateMigrationSequence([]SchemaVersion{{Name: "empty.sql"}, {Name: "v1.sql", Tables: tables}})
+	if len(steps) != 1 || !slices.Equal(steps[0].Statements, []string{create}) {
+		t.Errorf("Expected a sequence from an empty schema to create the table, got %+v", steps)
+	}
+}
+
+func TestGeneratedColumnTransitionStatements(t *testing.T) {
+	plain := createTestColumn("total", "INT")
+	stored := createTestColumn("total", "INT")
+	stored.Generated = &parser.GeneratedColumn{Expression: "price * qty", Type: "STORED"}
+	virtual := createTestColumn("total", "INT")
+	virtual.Generated = &parser.GeneratedColumn{Expression: "price * qty", Type: "VIRTUAL"}
+
+	tests := []struct {
+		name     string
+		old      parser.ColumnDefinition
+		new      parser.ColumnDefinition
+		expected string
+	}{
+		{
+			name:     "plain to stored",
+			old:      plain,
+			new:      stored,
+			expected: "ALTER TABLE `orders`\n  MODIFY COLUMN `total` INT GENERATED ALWAYS AS (price * qty) STORED;",
+		},
+		{
+			name:     "stored to plain",
+			old:      stored,
+			new:      plain,
+			expected: "ALTER TABLE `orders`\n  MODIFY COLUMN `total` INT;",
+		},
+		{
+			name:     "plain to virtual",
+			old:      plain,
+			new:      virtual,
+			expected: "ALTER TABLE `orders`\n  DROP COLUMN `total`,\n  ADD COLUMN `total` INT GENERATED ALWAYS AS (price * qty) VIRTUAL;",
+		},
+		{
+			name:     "virtual to plain",
+			old:      virtual,
+			new:      plain,
+			expected: "ALTER TABLE `orders`\n  DROP COLUMN `total`,\n  ADD COLUMN `total` INT;",
+		},
+	}
+
+	generator := NewStatementGenerator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldTable := createTestTable("orders", []parser.ColumnDefinition{tt.old})
+			newTable := createTestTable("orders", []parser.ColumnDefinition{tt.new})
+
+			statements := generator.GenerateAlterStatements(createTestTableDiff(oldTable, newTable))
+			if len(statements) != 1 || statements[0] != tt.expected {
+				t.Errorf("Expected:\n%s\ngot: %v", tt.expected, statements)
+			}
+		})
+	}
+}
+
+func TestSkipCommentOnlyChanges(t *testing.T) {
+	oldComment := "Customer accounts"
+	newComment := "All customer accounts"
+	indexName := "idx_email"
+
+	oldTable := createTestTable("users", []parser.ColumnDefinition{
+		createTestColumn("id", "INT"),
+		createTestColumn("email", "VARCHAR"),
+	})
+	oldTable.Columns[1].Comment = stringPtr("Login email")
+	oldTable.Indexes = []parser.IndexDefinition{
+		{Name: &indexName, IndexType: "INDEX", Columns: []parser.IndexColumn{{Name: "email"}}, Comment: stringPtr("lookup")},
+	}
+	oldTable.TableOptions = &parser.TableOptions{Comment: &oldComment}
+
+	newTable := createTestTable("users", []parser.ColumnDefinition{
+		createTestColumn("id", "INT"),
+		createTestColumn("email", "VARCHAR"),
+	})
+	newTable.Columns[1].Comment = stringPtr("Primary login email")
+	newTable.Indexes = []parser.IndexDefinition{
+		{Name: &indexName, IndexType: "INDEX", Columns: []parser.IndexColumn{{Name: "email"}}, Comment: stringPtr("email lookup")},
+	}
+	newTable.TableOptions = &parser.TableOptions{Comment: &newComment}
+
+	tableDiff := createTestTableDiff(oldTable, newTable)
+	if !tableDiff.HasChanges() || tableDiff.ColumnsModified != 1 || tableDiff.IndexesModified != 1 || tableDiff.TableOptionsDiff == nil {
+		t.Fatalf("Expected comment changes to be reported by the diff, got %+v", tableDiff)
+	}
+
+	generator := NewStatementGenerator()
+	if statements := generator.GenerateAlterStatements(tableDiff); len(statements) != 2 {
+		t.Errorf("Expected comment changes to generate statements by default, got %v", statements)
+	}
+
+	generator.SkipCommentOnlyChanges = true
+	if statements := generator.GenerateAlterStatements(tableDiff); len(statements) != 0 {
+		t.Errorf("Expected no statements for comment-only changes, got %v", statements)
+	}
+
+	// Structural changes alongside a comment change are still generated
+	newTable.Columns[1].Nullable = boolPtr(false)
+	statements := generator.GenerateAlterStatements(createTestTableDiff(oldTable, newTable))
+	if len(statements) != 1 || !strings.Contains(statements[0], "MODIFY COLUMN `email` VARCHAR NOT NULL") {
+		t.Errorf("Expected structural column change to be generated, got %v", statements)
+	}
+}
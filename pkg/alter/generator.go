@@ -2,6 +2,10 @@ package alter
 
 import (
 	"fmt"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/n0madic/mysql-diff/pkg/diff"
@@ -9,7 +13,35 @@ import (
 )
 
 // StatementGenerator generates ALTER TABLE statements from table differences
-type StatementGenerator struct{}
+type StatementGenerator struct {
+	// Pretty aligns clause keywords and column names of multi-clause ALTER statements
+	Pretty bool
+
+	// SkipCommentOnlyChanges suppresses statements for column, index and table changes that only touch comments
+	SkipCommentOnlyChanges bool
+
+	// OmitDefaultFKActions skips ON DELETE/ON UPDATE clauses equivalent to MySQL's default (RESTRICT / NO ACTION)
+	OmitDefaultFKActions bool
+
+	// ExplicitFKIndexes adds an index for foreign key columns that no index covers,
+	// instead of leaving MySQL to create one implicitly
+	ExplicitFKIndexes bool
+
+	// OnlineDDL appends ALGORITHM=INPLACE, LOCK=NONE to ALTER statements, LOCK=SHARED instead
+	// when an in-place change blocks writes, or ALGORITHM=COPY when a change cannot be applied in place
+	OnlineDDL bool
+
+	// MySQL8 targets MySQL 8.0 and emits its syntax, such as RENAME COLUMN for name-only column changes
+	MySQL8 bool
+
+	// AdditiveOnly generates only the statements that add columns, indexes, foreign keys, check
+	// constraints and a missing primary key, skipping drops and modifications
+	AdditiveOnly bool
+
+	// ValidateCharset adds a warning comment before table options statements that pair a
+	// collation with a character set it does not belong to
+	ValidateCharset bool
+}
 
 // NewStatementGenerator creates a new ALTER statement generator
 func NewStatementGenerator() *StatementGenerator {
@@ -19,12 +51,44 @@ func NewStatementGenerator() *StatementGenerator {
 // GenerateAlterStatements generates all ALTER statements needed to transform old table to new table
 func (g *StatementGenerator) GenerateAlterStatements(tableDiff *diff.TableDiff) []string {
 	statements := []string{}
+	for _, statement := range g.generateAlterStatements(tableDiff) {
+		statements = append(statements, statement.SQL)
+	}
+	return statements
+}
+
+// splitComments separates the -- Note and -- Warning comments in statements from the SQL, for
+// output that is executed or parsed rather than read
+func splitComments(statements []string) (sql []string, comments []string) {
+	sql = []string{}
+	for _, statement := range statements {
+		if strings.HasPrefix(statement, "--") {
+			comments = append(comments, statement)
+		} else {
+			sql = append(sql, statement)
+		}
+	}
+	return sql, comments
+}
+
+// GenerateAlterStatementsWithImpact generates the same statements as GenerateAlterStatements,
+// each with a hint about how much of the table it touches. Comments have no impact.
+func (g *StatementGenerator) GenerateAlterStatementsWithImpact(tableDiff *diff.TableDiff) []StatementImpact {
+	return g.generateAlterStatements(tableDiff)
+}
+
+func (g *StatementGenerator) generateAlterStatements(tableDiff *diff.TableDiff) []StatementImpact {
+	statements := []StatementImpact{}
 
 	// Handle nil input gracefully
 	if tableDiff == nil {
 		return statements
 	}
 
+	if g.AdditiveOnly {
+		tableDiff = tableDiff.Additions()
+	}
+
 	// If no changes, return empty
 	if !tableDiff.HasChanges() {
 		return statements
@@ -43,38 +107,71 @@ func (g *StatementGenerator) GenerateAlterStatements(tableDiff *diff.TableDiff)
 
 	// Handle table rename first if needed
 	if tableDiff.TableNameChanged && tableDiff.NewTable != nil {
-		statements = append(statements, fmt.Sprintf("ALTER TABLE `%s` RENAME TO `%s`;", tableName, tableDiff.NewTable.TableName))
+		statements = append(statements, StatementImpact{
+			SQL:    fmt.Sprintf("ALTER TABLE `%s` RENAME TO `%s`;", tableName, tableDiff.NewTable.TableName),
+			Impact: ImpactMetadata,
+		})
 		tableName = tableDiff.NewTable.TableName // Use new name for subsequent operations
 	}
 
-	// Collect all column, index, and constraint changes
-	alterClauses := []string{}
-
-	// Process column changes
-	alterClauses = append(alterClauses, g.generateColumnChanges(tableDiff)...)
-
-	// Process primary key changes
+	// Collect all column, index, and constraint changes, grouped so that nothing is dropped before
+	// the clauses that may still reference it
+	clauses := g.generateColumnChanges(tableDiff)
 	if tableDiff.PrimaryKeyDiff != nil {
-		alterClauses = append(alterClauses, g.generatePrimaryKeyChanges(tableDiff.PrimaryKeyDiff)...)
+		clauses = append(clauses, g.generatePrimaryKeyChanges(tableDiff)...)
+	}
+	clauses = append(clauses, g.generateIndexChanges(tableDiff)...)
+	clauses = append(clauses, g.generateForeignKeyChanges(tableDiff)...)
+	clauses = append(clauses, g.generateCheckConstraintChanges(tableDiff)...)
+	alterClauses := orderClauses(clauses)
+
+	if !g.ExplicitFKIndexes {
+		for _, fkDiff := range tableDiff.ForeignKeyDiffs {
+			if fkDiff.ImplicitIndex {
+				statements = append(statements, StatementImpact{SQL: fmt.Sprintf("-- Note: MySQL will create an index on `%s` (%s) for the new foreign key",
+					tableName, strings.Join(fkDiff.NewFK.Columns, ", "))})
+			}
+		}
 	}
 
-	// Process index changes
-	alterClauses = append(alterClauses, g.generateIndexChanges(tableDiff)...)
-
-	// Process foreign key changes
-	alterClauses = append(alterClauses, g.generateForeignKeyChanges(tableDiff)...)
+	for _, idxDiff := range tableDiff.IndexDiffs {
+		if idxDiff.RedundantWith != "" {
+			statements = append(statements, StatementImpact{SQL: fmt.Sprintf("-- Note: new %s on `%s` is redundant with index `%s`",
+				g.formatIndexDefinition(idxDiff.NewIndex), tableName, idxDiff.RedundantWith)})
+		}
+	}
 
 	// Generate main ALTER TABLE statement if there are changes
 	if len(alterClauses) > 0 {
+		if g.OnlineDDL {
+			if requiresCopyAlgorithm(tableDiff) {
+				alterClauses = append(alterClauses, "ALGORITHM=COPY")
+			} else if requiresSharedLock(tableDiff) {
+				alterClauses = append(alterClauses, "ALGORITHM=INPLACE", "LOCK=SHARED")
+			} else {
+				alterClauses = append(alterClauses, "ALGORITHM=INPLACE", "LOCK=NONE")
+			}
+		}
+		if g.Pretty {
+			alterClauses = alignClauses(alterClauses)
+		}
 		alterStmt := fmt.Sprintf("ALTER TABLE `%s`\n  %s;", tableName, strings.Join(alterClauses, ",\n  "))
-		statements = append(statements, alterStmt)
+		statements = append(statements, StatementImpact{SQL: alterStmt, Impact: alterClausesImpact(tableDiff)})
 	}
 
 	// Process table options changes (separate ALTER statement)
 	if tableDiff.TableOptionsDiff != nil {
+		for _, warning := range tableDiff.TableOptionsDiff.Warnings {
+			statements = append(statements, StatementImpact{SQL: fmt.Sprintf("-- Warning: `%s` %s", tableName, warning)})
+		}
+		if g.ValidateCharset {
+			if err := ValidateTableOptionsCharset(tableDiff.TableOptionsDiff); err != nil {
+				statements = append(statements, StatementImpact{SQL: fmt.Sprintf("-- Warning: `%s` %v", tableName, err)})
+			}
+		}
 		tableOptionsStmt := g.generateTableOptionsChanges(tableName, tableDiff.TableOptionsDiff)
 		if tableOptionsStmt != "" {
-			statements = append(statements, tableOptionsStmt)
+			statements = append(statements, StatementImpact{SQL: tableOptionsStmt, Impact: tableOptionsImpact(tableDiff.TableOptionsDiff)})
 		}
 	}
 
@@ -82,40 +179,403 @@ func (g *StatementGenerator) GenerateAlterStatements(tableDiff *diff.TableDiff)
 	if tableDiff.PartitionDiff != nil {
 		partitionStmt := g.generatePartitionChanges(tableName, tableDiff.PartitionDiff)
 		if partitionStmt != "" {
-			statements = append(statements, partitionStmt)
+			statements = append(statements, StatementImpact{SQL: partitionStmt, Impact: ImpactRewrite})
 		}
 	}
 
 	return statements
 }
 
-func (g *StatementGenerator) generateColumnChanges(tableDiff *diff.TableDiff) []string {
-	clauses := []string{}
+// GenerateRollbackStatements generates the ALTER statements that undo the statements produced by
+// GenerateAlterStatements for the same diff, restoring the old definitions stored in it
+func (g *StatementGenerator) GenerateRollbackStatements(tableDiff *diff.TableDiff) []string {
+	if tableDiff == nil {
+		return []string{}
+	}
+	if g.AdditiveOnly {
+		// Undo only the additions; the generator must not filter the reversed diff again,
+		// since it consists of drops
+		reverser := *g
+		reverser.AdditiveOnly = false
+		return reverser.GenerateAlterStatements(tableDiff.Additions().Reverse())
+	}
+	return g.GenerateAlterStatements(tableDiff.Reverse())
+}
+
+// clauseGroup orders the clauses of a combined ALTER TABLE statement so that nothing is dropped
+// before the clauses that may still reference it
+type clauseGroup int
+
+const (
+	groupKeyAutoIncrement clauseGroup = iota // a primary key that a new AUTO_INCREMENT column needs
+	groupAddColumn
+	groupModifyColumn
+	groupMoveColumn // columns with a FIRST/AFTER clause, in the order of the new table
+	groupAddKey     // added and recreated indexes, primary keys, foreign keys and checks
+	groupDropKey
+	groupDropColumn
+)
+
+// alterClause holds the clauses generated for one column, index or constraint. Clauses that
+// belong together, like the drop and add of a recreated index, stay in their order.
+type alterClause struct {
+	group    clauseGroup
+	name     string
+	position int // position of an added or moved column in the new table
+	sql      []string
+}
+
+// orderClauses flattens clauses by group and alphabetically by name within a group. Added and
+// moved columns keep the order of the new table: added columns are appended in the order they are
+// added, and every AFTER clause must refer to a column that is already in place.
+func orderClauses(clauses []alterClause) []string {
+	slices.SortStableFunc(clauses, func(a, b alterClause) int {
+		if a.group != b.group {
+			return int(a.group) - int(b.group)
+		}
+		if a.group == groupAddColumn || a.group == groupMoveColumn {
+			return a.position - b.position
+		}
+		return strings.Compare(a.name, b.name)
+	})
+
+	ordered := []string{}
+	for _, clause := range clauses {
+		ordered = append(ordered, clause.sql...)
+	}
+	return ordered
+}
+
+// alignClauses pads clause keywords and object names so that they line up in columns
+func alignClauses(clauses []string) []string {
+	type clauseParts struct {
+		keyword string
+		name    string
+		rest    string
+	}
+
+	split := make([]clauseParts, len(clauses))
+	keywordWidth := 0
+	nameWidth := 0
+
+	for i, clause := range clauses {
+		parts := clauseParts{keyword: clause}
+
+		// The keyword ends where the first quoted identifier or column list begins
+		if pos := strings.IndexAny(clause, "`("); pos > 0 {
+			parts.keyword = strings.TrimSpace(clause[:pos])
+			remainder := clause[pos:]
+			if remainder[0] == '`' {
+				if end := strings.Index(remainder[1:], "`"); end >= 0 {
+					parts.name = remainder[:end+2]
+					parts.rest = strings.TrimSpace(remainder[end+2:])
+				} else {
+					parts.rest = remainder
+				}
+			} else {
+				parts.rest = remainder
+			}
+		}
+
+		keywordWidth = max(keywordWidth, len(parts.keyword))
+		if parts.name != "" {
+			nameWidth = max(nameWidth, len(parts.name))
+		}
+		split[i] = parts
+	}
+
+	aligned := make([]string, len(clauses))
+	for i, parts := range split {
+		line := fmt.Sprintf("%-*s", keywordWidth, parts.keyword)
+		if parts.name != "" {
+			line += " " + fmt.Sprintf("%-*s", nameWidth, parts.name)
+			if parts.rest != "" {
+				line += " " + parts.rest
+			}
+		} else if parts.rest != "" {
+			line += " " + parts.rest
+		}
+		aligned[i] = strings.TrimRight(line, " ")
+	}
+
+	return aligned
+}
+
+func (g *StatementGenerator) generateColumnChanges(tableDiff *diff.TableDiff) []alterClause {
+	clauses := []alterClause{}
 
 	for _, colDiff := range tableDiff.ColumnDiffs {
 		switch colDiff.ChangeType {
 		case diff.ChangeTypeAdded:
-			clauses = append(clauses, g.generateAddColumn(colDiff.NewColumn))
+			position := slices.IndexFunc(tableDiff.NewTable.Columns, func(col parser.ColumnDefinition) bool {
+				return col.Name == colDiff.NewColumn.Name
+			}) + 1
+			clause := alterClause{group: groupAddColumn, position: position, name: colDiff.Name,
+				sql: []string{g.generateAddColumn(colDiff.NewColumn)}}
+			if tableDiff.ColumnOrderTracked {
+				// Place the column among the moved columns instead of appending it
+				clause.group = groupMoveColumn
+				clause.sql[0] += " " + columnPositionClause(tableDiff, colDiff.NewColumn.Name)
+			}
+			clauses = append(clauses, clause)
 		case diff.ChangeTypeRemoved:
-			clauses = append(clauses, fmt.Sprintf("DROP COLUMN `%s`", colDiff.Name))
+			clauses = append(clauses, alterClause{group: groupDropColumn, name: colDiff.Name,
+				sql: []string{fmt.Sprintf("DROP COLUMN `%s`", colDiff.Name)}})
 		case diff.ChangeTypeModified:
-			clauses = append(clauses, g.generateModifyColumn(colDiff.NewColumn))
+			if g.SkipCommentOnlyChanges && colDiff.Changes != nil && colDiff.Changes.IsCommentOnly() {
+				continue
+			}
+			position := ""
+			clause := alterClause{group: groupModifyColumn, name: colDiff.Name}
+			if colDiff.Changes != nil && colDiff.Changes.Position != nil {
+				position = " " + columnPositionClause(tableDiff, colDiff.NewColumn.Name)
+				clause.group, clause.position = groupMoveColumn, colDiff.Changes.Position.New
+			}
+			if requiresColumnRebuild(colDiff) {
+				// MySQL cannot convert between a plain and a VIRTUAL generated column with MODIFY
+				clause.sql = append(clause.sql, fmt.Sprintf("DROP COLUMN `%s`", colDiff.OldColumn.Name))
+				clause.sql = append(clause.sql, g.generateAddColumn(colDiff.NewColumn)+position)
+			} else {
+				if colDiff.Changes != nil && colDiff.Changes.Name != nil {
+					if g.MySQL8 && colDiff.Changes.IsRenameOnly() {
+						clause.sql = append(clause.sql, fmt.Sprintf("RENAME COLUMN `%s` TO `%s`", colDiff.Changes.Name.Old, colDiff.Changes.Name.New))
+					} else if colDiff.Changes.IsRenameOnly() {
+						// Keep the old definition, which may intentionally differ for an ignored column
+						renamed := *colDiff.OldColumn
+						renamed.Name = colDiff.Changes.Name.New
+						clause.sql = append(clause.sql, g.generateChangeColumn(colDiff.Changes.Name.Old, &renamed)+position)
+					} else {
+						clause.sql = append(clause.sql, g.generateChangeColumn(colDiff.Changes.Name.Old, colDiff.NewColumn)+position)
+					}
+				} else if !isChecksOnly(colDiff.Changes) {
+					clause.sql = append(clause.sql, g.generateModifyColumn(colDiff.NewColumn)+position)
+				}
+				clause.sql = append(clause.sql, generateColumnCheckChanges(tableDiff, colDiff)...)
+			}
+			clauses = append(clauses, clause)
 		}
 	}
 
 	return clauses
 }
 
+// columnPositionClause returns the FIRST or AFTER clause that places a column where it is in the new
+// table. Columns added by the same statement are appended at the end and skipped, unless the column
+// order is tracked and they are placed as well.
+func columnPositionClause(tableDiff *diff.TableDiff, name string) string {
+	added := make(map[string]bool)
+	for _, colDiff := range tableDiff.ColumnDiffs {
+		if colDiff.ChangeType == diff.ChangeTypeAdded && !tableDiff.ColumnOrderTracked {
+			added[colDiff.Name] = true
+		}
+	}
+
+	previous := ""
+	for _, col := range tableDiff.NewTable.Columns {
+		if col.Name == name {
+			break
+		}
+		if !added[col.Name] {
+			previous = col.Name
+		}
+	}
+
+	if previous == "" {
+		return "FIRST"
+	}
+	return fmt.Sprintf("AFTER `%s`", previous)
+}
+
+// keysAutoIncrementColumn reports whether the new primary key covers an existing column that
+// becomes AUTO_INCREMENT
+func keysAutoIncrementColumn(tableDiff *diff.TableDiff) bool {
+	pkDiff := tableDiff.PrimaryKeyDiff
+	if pkDiff == nil || pkDiff.NewPK == nil {
+		return false
+	}
+
+	for _, colDiff := range tableDiff.ColumnDiffs {
+		if colDiff.ChangeType != diff.ChangeTypeModified || colDiff.Changes == nil ||
+			colDiff.Changes.AutoIncrement == nil || !colDiff.Changes.AutoIncrement.New {
+			continue
+		}
+		for _, col := range pkDiff.NewPK.Columns {
+			if strings.EqualFold(col.Name, colDiff.NewColumn.Name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// requiresCopyAlgorithm reports whether the changes of a table can only be applied by copying the
+// table: column type and character set conversions, ENUM/SET changes other than appending values,
+// adding a STORED generated column or turning a column into one, dropping the primary key without
+// replacing it, and adding a check constraint
+func requiresCopyAlgorithm(tableDiff *diff.TableDiff) bool {
+	for _, checkDiff := range tableDiff.CheckConstraintDiffs {
+		if checkDiff.ChangeType != diff.ChangeTypeRemoved {
+			return true
+		}
+	}
+
+	isStored := func(generated *parser.GeneratedColumn) bool {
+		return generated != nil && strings.EqualFold(generated.Type, "STORED")
+	}
+
+	for _, colDiff := range tableDiff.ColumnDiffs {
+		switch colDiff.ChangeType {
+		case diff.ChangeTypeAdded:
+			if isStored(colDiff.NewColumn.Generated) {
+				return true
+			}
+		case diff.ChangeTypeModified:
+			if changes := colDiff.Changes; changes != nil &&
+				(changes.DataType != nil || changes.CharacterSet != nil ||
+					(changes.EnumValues != nil && !valuesAppended(colDiff)) ||
+					(changes.Generated != nil && isStored(changes.Generated.New))) {
+				return true
+			}
+		}
+	}
+
+	return tableDiff.PrimaryKeyDiff != nil && tableDiff.PrimaryKeyDiff.ChangeType == diff.ChangeTypeRemoved
+}
+
+// requiresSharedLock reports whether the changes of a table run in place but do not permit
+// concurrent writes, so LOCK=NONE would be rejected: adding a FULLTEXT or SPATIAL index and adding
+// an AUTO_INCREMENT column
+func requiresSharedLock(tableDiff *diff.TableDiff) bool {
+	for _, idxDiff := range tableDiff.IndexDiffs {
+		if idxDiff.ChangeType != diff.ChangeTypeRemoved && idxDiff.NewIndex != nil &&
+			(strings.EqualFold(idxDiff.NewIndex.IndexType, "FULLTEXT") || strings.EqualFold(idxDiff.NewIndex.IndexType, "SPATIAL")) {
+			return true
+		}
+	}
+
+	for _, colDiff := range tableDiff.ColumnDiffs {
+		if colDiff.ChangeType == diff.ChangeTypeAdded && colDiff.NewColumn.AutoIncrement {
+			return true
+		}
+	}
+	return false
+}
+
+// valuesAppended reports whether an ENUM/SET column change only adds values after the existing ones
+func valuesAppended(colDiff diff.ColumnDiff) bool {
+	oldValues := colDiff.OldColumn.DataType.Parameters
+	newValues := colDiff.NewColumn.DataType.Parameters
+	return len(newValues) >= len(oldValues) && slices.Equal(oldValues, newValues[:len(oldValues)])
+}
+
+// requiresColumnRebuild reports whether a column turns into or out of a VIRTUAL generated column.
+// Only STORED generated columns can be converted to and from plain columns in place.
+func requiresColumnRebuild(colDiff diff.ColumnDiff) bool {
+	if colDiff.Changes == nil || colDiff.Changes.Generated == nil {
+		return false
+	}
+
+	generated := colDiff.Changes.Generated
+	switch {
+	case generated.Old == nil && generated.New != nil:
+		return generated.New.Type != "STORED"
+	case generated.Old != nil && generated.New == nil:
+		return generated.Old.Type != "STORED"
+	}
+	return false
+}
+
 func (g *StatementGenerator) generateAddColumn(column *parser.ColumnDefinition) string {
 	colDef := g.formatColumnDefinition(column)
 	return fmt.Sprintf("ADD COLUMN %s", colDef)
 }
 
 func (g *StatementGenerator) generateModifyColumn(column *parser.ColumnDefinition) string {
-	colDef := g.formatColumnDefinition(column)
+	colDef := g.formatColumnDefinition(withoutChecks(column))
 	return fmt.Sprintf("MODIFY COLUMN %s", colDef)
 }
 
+func (g *StatementGenerator) generateChangeColumn(oldName string, column *parser.ColumnDefinition) string {
+	colDef := g.formatColumnDefinition(withoutChecks(column))
+	return fmt.Sprintf("CHANGE COLUMN `%s` %s", oldName, colDef)
+}
+
+// withoutChecks returns the column without its inline CHECK constraints. MODIFY and CHANGE COLUMN
+// would add them as new constraints, so changed checks are dropped and added by generateColumnCheckChanges.
+func withoutChecks(column *parser.ColumnDefinition) *parser.ColumnDefinition {
+	if len(column.Checks) == 0 {
+		return column
+	}
+	stripped := *column
+	stripped.Checks = nil
+	return &stripped
+}
+
+// isChecksOnly reports whether the inline CHECK constraints are the only changed attribute of a column
+func isChecksOnly(changes *diff.ColumnChanges) bool {
+	if changes == nil || changes.Checks == nil {
+		return false
+	}
+	others := *changes
+	others.Checks = nil
+	return !others.HasChanges()
+}
+
+// generateColumnCheckChanges drops the old inline CHECK constraints of a modified column and adds the
+// new ones as table constraints, which is how MySQL stores them
+func generateColumnCheckChanges(tableDiff *diff.TableDiff, colDiff diff.ColumnDiff) []string {
+	if colDiff.Changes == nil || colDiff.Changes.Checks == nil {
+		return nil
+	}
+
+	// Named checks whose expression is unchanged only toggle their enforcement
+	kept := make(map[string]bool)
+	var toggles []string
+	for _, oldCheck := range colDiff.OldColumn.Checks {
+		if oldCheck.Name == nil || *oldCheck.Name == "" {
+			continue
+		}
+		for _, newCheck := range colDiff.NewColumn.Checks {
+			if newCheck.Name != nil && *newCheck.Name == *oldCheck.Name && newCheck.NormalizedExpression == oldCheck.NormalizedExpression {
+				kept[*oldCheck.Name] = true
+				if checkEnforced(oldCheck) != checkEnforced(newCheck) {
+					toggles = append(toggles, formatAlterCheck(*newCheck.Name, checkEnforced(newCheck)))
+				}
+			}
+		}
+	}
+
+	clauses := []string{}
+	for i, check := range colDiff.OldColumn.Checks {
+		if check.Name != nil && *check.Name != "" && kept[*check.Name] {
+			continue
+		}
+		if name := checkName(tableDiff, &colDiff.OldColumn.Checks[i]); name != "" {
+			clauses = append(clauses, fmt.Sprintf("DROP CHECK `%s`", name))
+		}
+	}
+	clauses = append(clauses, toggles...)
+	for i, check := range colDiff.NewColumn.Checks {
+		if check.Name == nil || !kept[*check.Name] {
+			clauses = append(clauses, fmt.Sprintf("ADD %s", formatCheckConstraint(&colDiff.NewColumn.Checks[i])))
+		}
+	}
+	return clauses
+}
+
+// checkEnforced reports whether a check constraint is enforced, which it is unless NOT ENFORCED
+func checkEnforced(check parser.CheckConstraint) bool {
+	return check.Enforced == nil || *check.Enforced
+}
+
+// formatAlterCheck formats the clause that changes the enforcement of a named check constraint
+func formatAlterCheck(name string, enforced bool) string {
+	if enforced {
+		return fmt.Sprintf("ALTER CHECK `%s` ENFORCED", name)
+	}
+	return fmt.Sprintf("ALTER CHECK `%s` NOT ENFORCED", name)
+}
+
 func (g *StatementGenerator) formatColumnDefinition(column *parser.ColumnDefinition) string {
 	parts := []string{fmt.Sprintf("`%s`", column.Name)}
 
@@ -140,6 +600,16 @@ func (g *StatementGenerator) formatColumnDefinition(column *parser.ColumnDefinit
 		parts = append(parts, fmt.Sprintf("COLLATE %s", *column.Collation))
 	}
 
+	// GENERATED column (must precede NULL/NOT NULL and other attributes)
+	if column.Generated != nil {
+		expr := column.Generated.Expression
+		genType := column.Generated.Type
+		if genType == "" {
+			genType = "VIRTUAL"
+		}
+		parts = append(parts, fmt.Sprintf("GENERATED ALWAYS AS (%s) %s", expr, genType))
+	}
+
 	// NULL/NOT NULL
 	if column.Nullable != nil {
 		if *column.Nullable {
@@ -149,8 +619,8 @@ func (g *StatementGenerator) formatColumnDefinition(column *parser.ColumnDefinit
 		}
 	}
 
-	// AUTO_INCREMENT
-	if column.AutoIncrement {
+	// AUTO_INCREMENT (not allowed on generated columns)
+	if column.AutoIncrement && column.Generated == nil {
 		parts = append(parts, "AUTO_INCREMENT")
 	}
 
@@ -164,24 +634,20 @@ func (g *StatementGenerator) formatColumnDefinition(column *parser.ColumnDefinit
 		parts = append(parts, "PRIMARY KEY")
 	}
 
-	// DEFAULT
-	if column.DefaultValue != nil && *column.DefaultValue != "" {
+	// DEFAULT (not allowed on generated columns)
+	if column.DefaultValue != nil && *column.DefaultValue != "" && column.Generated == nil {
 		upperDefault := strings.ToUpper(*column.DefaultValue)
-		if upperDefault == "CURRENT_TIMESTAMP" || upperDefault == "NULL" {
+		if diff.IsCurrentTimestampDefault(*column.DefaultValue) || upperDefault == "NULL" ||
+			parser.IsExpressionDefault(*column.DefaultValue) || isNumericLiteral(*column.DefaultValue) {
 			parts = append(parts, fmt.Sprintf("DEFAULT %s", *column.DefaultValue))
 		} else {
-			parts = append(parts, fmt.Sprintf("DEFAULT '%s'", *column.DefaultValue))
+			parts = append(parts, fmt.Sprintf("DEFAULT %s", quoteOptionString(*column.DefaultValue)))
 		}
 	}
 
-	// GENERATED column
-	if column.Generated != nil {
-		expr := column.Generated.Expression
-		genType := column.Generated.Type
-		if genType == "" {
-			genType = "VIRTUAL"
-		}
-		parts = append(parts, fmt.Sprintf("GENERATED ALWAYS AS (%s) %s", expr, genType))
+	// ON UPDATE
+	if column.OnUpdate != nil && *column.OnUpdate != "" && column.Generated == nil {
+		parts = append(parts, fmt.Sprintf("ON UPDATE %s", *column.OnUpdate))
 	}
 
 	// VISIBLE/INVISIBLE
@@ -195,7 +661,7 @@ func (g *StatementGenerator) formatColumnDefinition(column *parser.ColumnDefinit
 
 	// COMMENT
 	if column.Comment != nil && *column.Comment != "" {
-		parts = append(parts, fmt.Sprintf("COMMENT '%s'", *column.Comment))
+		parts = append(parts, fmt.Sprintf("COMMENT %s", quoteOptionString(*column.Comment)))
 	}
 
 	// COLUMN_FORMAT
@@ -208,26 +674,38 @@ func (g *StatementGenerator) formatColumnDefinition(column *parser.ColumnDefinit
 		parts = append(parts, fmt.Sprintf("STORAGE %s", *column.Storage))
 	}
 
+	// Inline CHECK constraints come last
+	for i := range column.Checks {
+		parts = append(parts, formatCheckConstraint(&column.Checks[i]))
+	}
+
 	return strings.Join(parts, " ")
 }
 
-func (g *StatementGenerator) generatePrimaryKeyChanges(pkDiff *diff.PrimaryKeyDiff) []string {
-	clauses := []string{}
+func (g *StatementGenerator) generatePrimaryKeyChanges(tableDiff *diff.TableDiff) []alterClause {
+	pkDiff := tableDiff.PrimaryKeyDiff
+	clause := alterClause{group: groupAddKey, name: "PRIMARY"}
 
 	switch pkDiff.ChangeType {
 	case diff.ChangeTypeRemoved:
-		clauses = append(clauses, "DROP PRIMARY KEY")
+		clause.group = groupDropKey
+		clause.sql = []string{"DROP PRIMARY KEY"}
 	case diff.ChangeTypeAdded:
 		pkDef := g.formatPrimaryKeyDefinition(pkDiff.NewPK)
-		clauses = append(clauses, fmt.Sprintf("ADD %s", pkDef))
+		clause.sql = []string{fmt.Sprintf("ADD %s", pkDef)}
 	case diff.ChangeTypeModified:
 		// Drop and recreate
-		clauses = append(clauses, "DROP PRIMARY KEY")
 		pkDef := g.formatPrimaryKeyDefinition(pkDiff.NewPK)
-		clauses = append(clauses, fmt.Sprintf("ADD %s", pkDef))
+		clause.sql = []string{"DROP PRIMARY KEY", fmt.Sprintf("ADD %s", pkDef)}
 	}
 
-	return clauses
+	// MySQL requires an AUTO_INCREMENT column to be a key, so a primary key for an existing
+	// column that becomes AUTO_INCREMENT is added before the column changes
+	if keysAutoIncrementColumn(tableDiff) {
+		clause.group = groupKeyAutoIncrement
+	}
+
+	return []alterClause{clause}
 }
 
 func (g *StatementGenerator) formatPrimaryKeyDefinition(pk *parser.PrimaryKeyDefinition) string {
@@ -243,41 +721,112 @@ func (g *StatementGenerator) formatPrimaryKeyDefinition(pk *parser.PrimaryKeyDef
 	return fmt.Sprintf("PRIMARY KEY (%s)", colList)
 }
 
-func (g *StatementGenerator) generateIndexChanges(tableDiff *diff.TableDiff) []string {
-	clauses := []string{}
+func (g *StatementGenerator) generateIndexChanges(tableDiff *diff.TableDiff) []alterClause {
+	clauses := []alterClause{}
 
+	// An index whose columns change under the same name is reported as removed and added again;
+	// its drop must stay in front of the add instead of moving to the drops
+	added := make(map[string]bool)
 	for _, idxDiff := range tableDiff.IndexDiffs {
-		switch idxDiff.ChangeType {
-		case diff.ChangeTypeRemoved:
-			if idxDiff.OldIndex.Name != nil && *idxDiff.OldIndex.Name != "" {
-				clauses = append(clauses, fmt.Sprintf("DROP INDEX `%s`", *idxDiff.OldIndex.Name))
-			} else {
-				// For unnamed indexes, we need to identify by columns
-				cols := []string{}
-				for _, col := range idxDiff.OldIndex.Columns {
-					cols = append(cols, fmt.Sprintf("`%s`", col.Name))
-				}
-				colList := strings.Join(cols, ", ")
-				clauses = append(clauses, fmt.Sprintf("DROP INDEX (%s)", colList))
-			}
+		if idxDiff.ChangeType == diff.ChangeTypeAdded {
+			added[strings.ToLower(indexName(tableDiff.NewTable, idxDiff.NewIndex))] = true
+		}
+	}
+	recreated := make(map[string]bool)
+
+	for _, idxDiff := range tableDiff.IndexDiffs {
+		if idxDiff.ChangeType != diff.ChangeTypeRemoved {
+			continue
+		}
+		name := indexName(tableDiff.OldTable, idxDiff.OldIndex)
+		if added[strings.ToLower(name)] {
+			recreated[strings.ToLower(name)] = true
+			continue
+		}
+		clauses = append(clauses, alterClause{group: groupDropKey, name: name,
+			sql: []string{fmt.Sprintf("DROP INDEX `%s`", name)}})
+	}
 
+	for _, idxDiff := range tableDiff.IndexDiffs {
+		switch idxDiff.ChangeType {
 		case diff.ChangeTypeAdded:
-			idxDef := g.formatIndexDefinition(idxDiff.NewIndex)
-			clauses = append(clauses, fmt.Sprintf("ADD %s", idxDef))
+			name := indexName(tableDiff.NewTable, idxDiff.NewIndex)
+			clause := alterClause{group: groupAddKey, name: name}
+			if recreated[strings.ToLower(name)] {
+				clause.sql = append(clause.sql, fmt.Sprintf("DROP INDEX `%s`", name))
+			}
+			clause.sql = append(clause.sql, fmt.Sprintf("ADD %s", g.formatIndexDefinition(idxDiff.NewIndex)))
+			clauses = append(clauses, clause)
 
 		case diff.ChangeTypeModified:
-			// Drop old and add new
-			if idxDiff.OldIndex.Name != nil && *idxDiff.OldIndex.Name != "" {
-				clauses = append(clauses, fmt.Sprintf("DROP INDEX `%s`", *idxDiff.OldIndex.Name))
+			if g.SkipCommentOnlyChanges && idxDiff.Changes != nil && idxDiff.Changes.IsCommentOnly() {
+				continue
 			}
+			// Drop old and add new
+			name := indexName(tableDiff.OldTable, idxDiff.OldIndex)
 			idxDef := g.formatIndexDefinition(idxDiff.NewIndex)
-			clauses = append(clauses, fmt.Sprintf("ADD %s", idxDef))
+			clauses = append(clauses, alterClause{group: groupAddKey, name: name,
+				sql: []string{fmt.Sprintf("DROP INDEX `%s`", name), fmt.Sprintf("ADD %s", idxDef)}})
 		}
 	}
 
 	return clauses
 }
 
+// indexName returns the name of an index of table. Unnamed indexes get the name MySQL assigns them:
+// the name of their first column, suffixed with _2, _3, ... when an earlier index already uses it.
+// The index is found by its position in table, so identical unnamed indexes keep distinct names;
+// an index that is not part of table falls back to the first equal one.
+func indexName(table *parser.CreateTableStatement, idx *parser.IndexDefinition) string {
+	if idx.Name != nil && *idx.Name != "" {
+		return *idx.Name
+	}
+
+	baseName := func(idx *parser.IndexDefinition) string {
+		if len(idx.Columns) == 0 || idx.Columns[0].Name == "" {
+			return "functional_index"
+		}
+		return idx.Columns[0].Name
+	}
+	if table == nil {
+		return baseName(idx)
+	}
+
+	// Column-level UNIQUE keys are named after their column and precede the table-level indexes
+	used := map[string]bool{"primary": true}
+	for _, column := range table.Columns {
+		if column.Unique {
+			used[strings.ToLower(column.Name)] = true
+		}
+	}
+
+	equal := ""
+	for i := range table.Indexes {
+		current := &table.Indexes[i]
+		name := ""
+		if current.Name != nil && *current.Name != "" {
+			name = *current.Name
+		} else {
+			name = baseName(current)
+			for n := 2; used[strings.ToLower(name)]; n++ {
+				name = fmt.Sprintf("%s_%d", baseName(current), n)
+			}
+		}
+		used[strings.ToLower(name)] = true
+
+		if current == idx {
+			return name
+		}
+		if equal == "" && reflect.DeepEqual(current, idx) {
+			equal = name
+		}
+	}
+	if equal != "" {
+		return equal
+	}
+	return baseName(idx)
+}
+
 func (g *StatementGenerator) formatIndexDefinition(idx *parser.IndexDefinition) string {
 	parts := []string{}
 
@@ -347,34 +896,148 @@ func (g *StatementGenerator) formatIndexDefinition(idx *parser.IndexDefinition)
 	return strings.Join(parts, " ")
 }
 
-func (g *StatementGenerator) generateForeignKeyChanges(tableDiff *diff.TableDiff) []string {
-	clauses := []string{}
+func (g *StatementGenerator) generateForeignKeyChanges(tableDiff *diff.TableDiff) []alterClause {
+	clauses := []alterClause{}
 
 	for _, fkDiff := range tableDiff.ForeignKeyDiffs {
 		switch fkDiff.ChangeType {
 		case diff.ChangeTypeRemoved:
 			if fkDiff.OldFK.Name != nil && *fkDiff.OldFK.Name != "" {
-				clauses = append(clauses, fmt.Sprintf("DROP FOREIGN KEY `%s`", *fkDiff.OldFK.Name))
+				clauses = append(clauses, alterClause{group: groupDropKey, name: *fkDiff.OldFK.Name,
+					sql: []string{fmt.Sprintf("DROP FOREIGN KEY `%s`", *fkDiff.OldFK.Name)}})
 			}
 			// For unnamed FKs, MySQL requires a name, so we can't handle this case easily
 
 		case diff.ChangeTypeAdded:
 			fkDef := g.formatForeignKeyDefinition(fkDiff.NewFK)
-			clauses = append(clauses, fmt.Sprintf("ADD %s", fkDef))
+			clause := alterClause{group: groupAddKey, name: *foreignKeyIndex(fkDiff.NewFK).Name}
+			if fkDiff.ImplicitIndex && g.ExplicitFKIndexes {
+				clause.sql = append(clause.sql, fmt.Sprintf("ADD %s", g.formatIndexDefinition(foreignKeyIndex(fkDiff.NewFK))))
+			}
+			clause.sql = append(clause.sql, fmt.Sprintf("ADD %s", fkDef))
+			clauses = append(clauses, clause)
 
 		case diff.ChangeTypeModified:
 			// Drop old and add new
+			clause := alterClause{group: groupAddKey, name: *foreignKeyIndex(fkDiff.NewFK).Name}
 			if fkDiff.OldFK.Name != nil && *fkDiff.OldFK.Name != "" {
-				clauses = append(clauses, fmt.Sprintf("DROP FOREIGN KEY `%s`", *fkDiff.OldFK.Name))
+				clause.sql = append(clause.sql, fmt.Sprintf("DROP FOREIGN KEY `%s`", *fkDiff.OldFK.Name))
 			}
 			fkDef := g.formatForeignKeyDefinition(fkDiff.NewFK)
-			clauses = append(clauses, fmt.Sprintf("ADD %s", fkDef))
+			clause.sql = append(clause.sql, fmt.Sprintf("ADD %s", fkDef))
+			clauses = append(clauses, clause)
+		}
+	}
+
+	return clauses
+}
+
+func (g *StatementGenerator) generateCheckConstraintChanges(tableDiff *diff.TableDiff) []alterClause {
+	clauses := []alterClause{}
+
+	for _, checkDiff := range tableDiff.CheckConstraintDiffs {
+		switch checkDiff.ChangeType {
+		case diff.ChangeTypeRemoved:
+			if name := checkName(tableDiff, checkDiff.OldCheck); name != "" {
+				clauses = append(clauses, alterClause{group: groupDropKey, name: name,
+					sql: []string{fmt.Sprintf("DROP CHECK `%s`", name)}})
+			}
+
+		case diff.ChangeTypeAdded:
+			check := formatCheckConstraint(checkDiff.NewCheck)
+			clauses = append(clauses, alterClause{group: groupAddKey, name: checkClauseName(checkDiff.NewCheck, check),
+				sql: []string{fmt.Sprintf("ADD %s", check)}})
+
+		case diff.ChangeTypeModified:
+			clause := alterClause{group: groupAddKey, name: checkClauseName(checkDiff.NewCheck, formatCheckConstraint(checkDiff.NewCheck))}
+			// MySQL 8.0 toggles the enforcement of a named constraint in place
+			if checkDiff.Changes != nil && checkDiff.Changes.Expression == nil && checkDiff.Changes.Enforced != nil &&
+				checkDiff.OldCheck.Name != nil && *checkDiff.OldCheck.Name != "" {
+				clause.sql = []string{formatAlterCheck(*checkDiff.OldCheck.Name, checkDiff.Changes.Enforced.New)}
+				clauses = append(clauses, clause)
+				continue
+			}
+			// Drop old and add new
+			if name := checkName(tableDiff, checkDiff.OldCheck); name != "" {
+				clause.sql = append(clause.sql, fmt.Sprintf("DROP CHECK `%s`", name))
+			}
+			clause.sql = append(clause.sql, fmt.Sprintf("ADD %s", formatCheckConstraint(checkDiff.NewCheck)))
+			clauses = append(clauses, clause)
 		}
 	}
 
 	return clauses
 }
 
+// checkName returns the name of a check constraint of the old table of tableDiff. Unnamed checks
+// get the name MySQL generates for them: the table name, _chk_ and their ordinal number among the
+// unnamed checks, counting the column checks in column order before the table checks. MySQL
+// renames generated names with the table, so the name after a table rename is used. Like
+// indexName, the check is found by its position in the table, falling back to the first equal one.
+func checkName(tableDiff *diff.TableDiff, check *parser.CheckConstraint) string {
+	if check.Name != nil && *check.Name != "" {
+		return *check.Name
+	}
+	table := tableDiff.OldTable
+	if table == nil {
+		return ""
+	}
+	tableName := table.TableName
+	if tableDiff.TableNameChanged && tableDiff.NewTable != nil {
+		tableName = tableDiff.NewTable.TableName
+	}
+
+	var checks []*parser.CheckConstraint
+	for i := range table.Columns {
+		for j := range table.Columns[i].Checks {
+			checks = append(checks, &table.Columns[i].Checks[j])
+		}
+	}
+	for i := range table.CheckConstraints {
+		checks = append(checks, &table.CheckConstraints[i])
+	}
+
+	ordinal := 0
+	equal := ""
+	for _, current := range checks {
+		if current.Name != nil && *current.Name != "" {
+			continue
+		}
+		ordinal++
+		name := fmt.Sprintf("%s_chk_%d", tableName, ordinal)
+		if current == check {
+			return name
+		}
+		if equal == "" && reflect.DeepEqual(current, check) {
+			equal = name
+		}
+	}
+	return equal
+}
+
+// checkClauseName returns the name a check constraint clause is ordered by: the constraint name,
+// or the formatted constraint for an unnamed check
+func checkClauseName(check *parser.CheckConstraint, formatted string) string {
+	if check.Name != nil && *check.Name != "" {
+		return *check.Name
+	}
+	return formatted
+}
+
+// foreignKeyIndex returns the index MySQL would create for a foreign key, named after the
+// constraint or, for an unnamed foreign key, its first column
+func foreignKeyIndex(fk *parser.ForeignKeyDefinition) *parser.IndexDefinition {
+	name := fk.Columns[0]
+	if fk.Name != nil && *fk.Name != "" {
+		name = *fk.Name
+	}
+	index := &parser.IndexDefinition{Name: &name, IndexType: "INDEX"}
+	for _, column := range fk.Columns {
+		index.Columns = append(index.Columns, parser.IndexColumn{Name: column})
+	}
+	return index
+}
+
 func (g *StatementGenerator) formatForeignKeyDefinition(fk *parser.ForeignKeyDefinition) string {
 	parts := []string{}
 
@@ -399,10 +1062,12 @@ func (g *StatementGenerator) formatForeignKeyDefinition(fk *parser.ForeignKeyDef
 	parts = append(parts, fmt.Sprintf("REFERENCES `%s` (%s)", fk.Reference.TableName, refColList))
 
 	// Referential actions
-	if fk.Reference.OnDelete != nil && *fk.Reference.OnDelete != "" {
+	if fk.Reference.OnDelete != nil && *fk.Reference.OnDelete != "" &&
+		!(g.OmitDefaultFKActions && diff.IsDefaultReferentialAction(fk.Reference.OnDelete)) {
 		parts = append(parts, fmt.Sprintf("ON DELETE %s", *fk.Reference.OnDelete))
 	}
-	if fk.Reference.OnUpdate != nil && *fk.Reference.OnUpdate != "" {
+	if fk.Reference.OnUpdate != nil && *fk.Reference.OnUpdate != "" &&
+		!(g.OmitDefaultFKActions && diff.IsDefaultReferentialAction(fk.Reference.OnUpdate)) {
 		parts = append(parts, fmt.Sprintf("ON UPDATE %s", *fk.Reference.OnUpdate))
 	}
 
@@ -411,77 +1076,185 @@ func (g *StatementGenerator) formatForeignKeyDefinition(fk *parser.ForeignKeyDef
 
 func (g *StatementGenerator) generateTableOptionsChanges(tableName string, optionsDiff *diff.TableOptionsDiff) string {
 	if optionsDiff.ChangeType == diff.ChangeTypeRemoved {
-		// Can't remove all table options, skip
+		// Reset the removed options to their server default, like the rollback of options added to
+		// a table. The engine and character set have no default to return to and are kept.
+		cleared := diff.CompareTables(&parser.CreateTableStatement{TableOptions: optionsDiff.OldOptions},
+			&parser.CreateTableStatement{TableOptions: &parser.TableOptions{}}).TableOptionsDiff
+		if cleared == nil {
+			return ""
+		}
+		optionsDiff = cleared
+	}
+	if g.SkipCommentOnlyChanges && optionsDiff.ChangeType == diff.ChangeTypeModified &&
+		optionsDiff.Changes != nil && optionsDiff.Changes.IsCommentOnly() {
 		return ""
 	}
 
+	// Options added to a table without options before are all new
+	changes := optionsDiff.Changes
+	if optionsDiff.ChangeType == diff.ChangeTypeAdded {
+		changes = nil
+	}
+	options := g.formatTableOptions(optionsDiff.NewOptions, changes)
+	if len(options) > 0 {
+		return fmt.Sprintf("ALTER TABLE `%s` %s;", tableName, strings.Join(options, " "))
+	}
+
+	return ""
+}
+
+// formatTableOptions builds the table option clauses for opts. When changes is not nil, only the
+// changed options are included and options that were removed are reset to their server default.
+func (g *StatementGenerator) formatTableOptions(opts *parser.TableOptions, changes *diff.TableOptionsChanges) []string {
 	options := []string{}
-	var opts *parser.TableOptions
 
-	if optionsDiff.ChangeType == diff.ChangeTypeAdded {
-		opts = optionsDiff.NewOptions
-	} else { // MODIFIED
-		opts = optionsDiff.NewOptions
+	// Without changes every set option is included; c allows reading the change fields either way
+	c := changes
+	if c == nil {
+		c = &diff.TableOptionsChanges{}
+	}
+	include := func(change *diff.FieldChange[any]) bool {
+		return changes == nil || change != nil
+	}
+	reset := func(change *diff.FieldChange[any]) bool {
+		return changes != nil && change != nil
 	}
 
-	// Build options list
-	if opts.Engine != nil && *opts.Engine != "" {
+	if include(c.Engine) && opts.Engine != nil && *opts.Engine != "" {
 		options = append(options, fmt.Sprintf("ENGINE=%s", *opts.Engine))
 	}
-	if opts.AutoIncrement != nil && *opts.AutoIncrement > 0 {
+	if include(c.AutoIncrement) && opts.AutoIncrement != nil && *opts.AutoIncrement > 0 {
 		options = append(options, fmt.Sprintf("AUTO_INCREMENT=%d", *opts.AutoIncrement))
 	}
-	if opts.CharacterSet != nil && *opts.CharacterSet != "" {
+	if include(c.CharacterSet) && opts.CharacterSet != nil && *opts.CharacterSet != "" {
 		options = append(options, fmt.Sprintf("DEFAULT CHARSET=%s", *opts.CharacterSet))
 	}
-	if opts.Collate != nil && *opts.Collate != "" {
+	if include(c.Collate) && opts.Collate != nil && *opts.Collate != "" {
 		options = append(options, fmt.Sprintf("COLLATE=%s", *opts.Collate))
 	}
-	if opts.Comment != nil && *opts.Comment != "" {
-		options = append(options, fmt.Sprintf("COMMENT='%s'", *opts.Comment))
+	if include(c.Comment) && opts.Comment != nil && *opts.Comment != "" {
+		options = append(options, fmt.Sprintf("COMMENT=%s", quoteOptionString(*opts.Comment)))
+	} else if reset(c.Comment) {
+		options = append(options, "COMMENT=''")
 	}
-	if opts.RowFormat != nil && *opts.RowFormat != "" {
+	if include(c.RowFormat) && opts.RowFormat != nil && *opts.RowFormat != "" {
 		options = append(options, fmt.Sprintf("ROW_FORMAT=%s", *opts.RowFormat))
+	} else if reset(c.RowFormat) {
+		options = append(options, "ROW_FORMAT=DEFAULT")
 	}
-	if opts.KeyBlockSize != nil && *opts.KeyBlockSize > 0 {
+	if include(c.KeyBlockSize) && opts.KeyBlockSize != nil && *opts.KeyBlockSize > 0 {
 		options = append(options, fmt.Sprintf("KEY_BLOCK_SIZE=%d", *opts.KeyBlockSize))
+	} else if reset(c.KeyBlockSize) {
+		options = append(options, "KEY_BLOCK_SIZE=0")
 	}
-	if opts.MaxRows != nil && *opts.MaxRows > 0 {
+	if include(c.MaxRows) && opts.MaxRows != nil && *opts.MaxRows > 0 {
 		options = append(options, fmt.Sprintf("MAX_ROWS=%d", *opts.MaxRows))
+	} else if reset(c.MaxRows) {
+		options = append(options, "MAX_ROWS=0")
 	}
-	if opts.MinRows != nil && *opts.MinRows > 0 {
+	if include(c.MinRows) && opts.MinRows != nil && *opts.MinRows > 0 {
 		options = append(options, fmt.Sprintf("MIN_ROWS=%d", *opts.MinRows))
-	}
-	if opts.Compression != nil && *opts.Compression != "" {
-		options = append(options, fmt.Sprintf("COMPRESSION='%s'", *opts.Compression))
-	}
-	if opts.Encryption != nil && *opts.Encryption != "" {
-		options = append(options, fmt.Sprintf("ENCRYPTION='%s'", *opts.Encryption))
-	}
-	if opts.StatsPersistent != nil && *opts.StatsPersistent != 0 {
+	} else if reset(c.MinRows) {
+		options = append(options, "MIN_ROWS=0")
+	}
+	if include(c.Tablespace) && opts.Tablespace != nil && *opts.Tablespace != "" {
+		options = append(options, fmt.Sprintf("TABLESPACE `%s`", *opts.Tablespace))
+	} else if reset(c.Tablespace) {
+		options = append(options, "TABLESPACE `innodb_file_per_table`")
+	}
+	// ALTER TABLE ignores the directory options, they only take effect when the table is created
+	if changes == nil && opts.DataDirectory != nil {
+		options = append(options, fmt.Sprintf("DATA DIRECTORY=%s", quoteOptionString(*opts.DataDirectory)))
+	}
+	if changes == nil && opts.IndexDirectory != nil {
+		options = append(options, fmt.Sprintf("INDEX DIRECTORY=%s", quoteOptionString(*opts.IndexDirectory)))
+	}
+	if include(c.AvgRowLength) && opts.AvgRowLength != nil {
+		options = append(options, fmt.Sprintf("AVG_ROW_LENGTH=%d", *opts.AvgRowLength))
+	} else if reset(c.AvgRowLength) {
+		options = append(options, "AVG_ROW_LENGTH=0")
+	}
+	if include(c.AutoextendSize) && opts.AutoextendSize != nil {
+		options = append(options, fmt.Sprintf("AUTOEXTEND_SIZE=%s", *opts.AutoextendSize))
+	} else if reset(c.AutoextendSize) {
+		options = append(options, "AUTOEXTEND_SIZE=0")
+	}
+	if include(c.Connection) && opts.Connection != nil && *opts.Connection != "" {
+		options = append(options, fmt.Sprintf("CONNECTION=%s", quoteOptionString(*opts.Connection)))
+	} else if reset(c.Connection) {
+		options = append(options, "CONNECTION=''")
+	}
+	if include(c.Compression) && opts.Compression != nil && *opts.Compression != "" {
+		options = append(options, fmt.Sprintf("COMPRESSION=%s", quoteOptionString(*opts.Compression)))
+	} else if reset(c.Compression) {
+		options = append(options, "COMPRESSION='None'")
+	}
+	if include(c.Encryption) && opts.Encryption != nil && *opts.Encryption != "" {
+		options = append(options, fmt.Sprintf("ENCRYPTION=%s", quoteOptionString(*opts.Encryption)))
+	} else if reset(c.Encryption) {
+		options = append(options, "ENCRYPTION='N'")
+	}
+	if include(c.StatsPersistent) && opts.StatsPersistent != nil {
 		options = append(options, fmt.Sprintf("STATS_PERSISTENT=%d", *opts.StatsPersistent))
+	} else if reset(c.StatsPersistent) {
+		options = append(options, "STATS_PERSISTENT=DEFAULT")
 	}
-	if opts.StatsAutoRecalc != nil && *opts.StatsAutoRecalc != 0 {
+	if include(c.StatsAutoRecalc) && opts.StatsAutoRecalc != nil {
 		options = append(options, fmt.Sprintf("STATS_AUTO_RECALC=%d", *opts.StatsAutoRecalc))
+	} else if reset(c.StatsAutoRecalc) {
+		options = append(options, "STATS_AUTO_RECALC=DEFAULT")
 	}
-	if opts.StatsSamplePages != nil && *opts.StatsSamplePages > 0 {
+	if include(c.StatsSamplePages) && opts.StatsSamplePages != nil && *opts.StatsSamplePages > 0 {
 		options = append(options, fmt.Sprintf("STATS_SAMPLE_PAGES=%d", *opts.StatsSamplePages))
+	} else if reset(c.StatsSamplePages) {
+		options = append(options, "STATS_SAMPLE_PAGES=DEFAULT")
 	}
-	if opts.PackKeys != nil && *opts.PackKeys != 0 {
+	if include(c.PackKeys) && opts.PackKeys != nil {
 		options = append(options, fmt.Sprintf("PACK_KEYS=%d", *opts.PackKeys))
+	} else if reset(c.PackKeys) {
+		// Option was removed, restore the server default
+		options = append(options, "PACK_KEYS=DEFAULT")
 	}
-	if opts.Checksum != nil && *opts.Checksum != 0 {
+	if include(c.Checksum) && opts.Checksum != nil {
 		options = append(options, fmt.Sprintf("CHECKSUM=%d", *opts.Checksum))
+	} else if reset(c.Checksum) {
+		options = append(options, "CHECKSUM=0")
 	}
-	if opts.DelayKeyWrite != nil && *opts.DelayKeyWrite != 0 {
+	if include(c.DelayKeyWrite) && opts.DelayKeyWrite != nil {
 		options = append(options, fmt.Sprintf("DELAY_KEY_WRITE=%d", *opts.DelayKeyWrite))
+	} else if reset(c.DelayKeyWrite) {
+		options = append(options, "DELAY_KEY_WRITE=0")
 	}
-
-	if len(options) > 0 {
-		return fmt.Sprintf("ALTER TABLE `%s` %s;", tableName, strings.Join(options, " "))
+	if include(c.Union) && len(opts.Union) > 0 {
+		tables := make([]string, len(opts.Union))
+		for i, table := range opts.Union {
+			tables[i] = fmt.Sprintf("`%s`", table)
+		}
+		options = append(options, fmt.Sprintf("UNION=(%s)", strings.Join(tables, ",")))
+	} else if reset(c.Union) {
+		options = append(options, "UNION=()")
+	}
+	if include(c.InsertMethod) && opts.InsertMethod != nil && *opts.InsertMethod != "" {
+		options = append(options, fmt.Sprintf("INSERT_METHOD=%s", *opts.InsertMethod))
+	} else if reset(c.InsertMethod) {
+		options = append(options, "INSERT_METHOD=NO")
 	}
 
-	return ""
+	return options
+}
+
+// isNumericLiteral reports whether value is an unquoted number such as 0, -1 or 1.5
+func isNumericLiteral(value string) bool {
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil
+}
+
+// quoteOptionString wraps a string option value in single quotes unless it is already quoted
+func quoteOptionString(value string) string {
+	if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
 }
 
 func (g *StatementGenerator) generatePartitionChanges(tableName string, partitionDiff *diff.PartitionDiff) string {
@@ -519,7 +1292,11 @@ func (g *StatementGenerator) formatPartitionDefinition(partitionOpts *parser.Par
 			cols = append(cols, fmt.Sprintf("`%s`", col))
 		}
 		colList := strings.Join(cols, ", ")
-		parts = append(parts, fmt.Sprintf("COLUMNS(%s)", colList))
+		if partitionOpts.Type == "KEY" {
+			parts = append(parts, fmt.Sprintf("(%s)", colList))
+		} else {
+			parts = append(parts, fmt.Sprintf("COLUMNS(%s)", colList))
+		}
 	} else {
 		parts = append(parts, "()")
 	}
@@ -528,19 +1305,63 @@ func (g *StatementGenerator) formatPartitionDefinition(partitionOpts *parser.Par
 		parts = append(parts, fmt.Sprintf("PARTITIONS %d", *partitionOpts.PartitionCount))
 	}
 
+	// Subpartitioning
+	if partitionOpts.SubPartitionType != "" {
+		parts = append(parts, "SUBPARTITION BY")
+		if partitionOpts.SubPartitionLinear {
+			parts = append(parts, "LINEAR")
+		}
+		parts = append(parts, partitionOpts.SubPartitionType)
+
+		if partitionOpts.SubPartitionExpression != nil && *partitionOpts.SubPartitionExpression != "" {
+			parts = append(parts, fmt.Sprintf("(%s)", *partitionOpts.SubPartitionExpression))
+		} else {
+			cols := []string{}
+			for _, col := range partitionOpts.SubPartitionColumns {
+				cols = append(cols, fmt.Sprintf("`%s`", col))
+			}
+			parts = append(parts, fmt.Sprintf("(%s)", strings.Join(cols, ", ")))
+		}
+
+		if partitionOpts.SubPartitionCount != nil && *partitionOpts.SubPartitionCount > 0 {
+			parts = append(parts, fmt.Sprintf("SUBPARTITIONS %d", *partitionOpts.SubPartitionCount))
+		}
+	}
+
 	// Add partition definitions if present
 	if len(partitionOpts.Partitions) > 0 {
 		partDefs := []string{}
 		for _, partDef := range partitionOpts.Partitions {
 			partStr := fmt.Sprintf("PARTITION `%s`", partDef.Name)
 			if len(partDef.Values) > 0 {
-				switch partDef.Type {
-				case "RANGE":
-					partStr += fmt.Sprintf(" VALUES LESS THAN (%s)", strings.Join(partDef.Values, ", "))
-				case "LIST":
-					partStr += fmt.Sprintf(" VALUES IN (%s)", strings.Join(partDef.Values, ", "))
+				values := strings.Join(partDef.Values, ", ")
+				switch {
+				case partDef.Type == "RANGE" && values == "MAXVALUE":
+					partStr += " VALUES LESS THAN MAXVALUE"
+				case partDef.Type == "RANGE":
+					partStr += fmt.Sprintf(" VALUES LESS THAN (%s)", values)
+				case partDef.Type == "LIST":
+					partStr += fmt.Sprintf(" VALUES IN (%s)", values)
 				}
 			}
+			if partDef.Comment != nil {
+				partStr += fmt.Sprintf(" COMMENT=%s", quoteOptionString(*partDef.Comment))
+			}
+			if partDef.DataDirectory != nil {
+				partStr += fmt.Sprintf(" DATA DIRECTORY=%s", quoteOptionString(*partDef.DataDirectory))
+			}
+			if partDef.IndexDirectory != nil {
+				partStr += fmt.Sprintf(" INDEX DIRECTORY=%s", quoteOptionString(*partDef.IndexDirectory))
+			}
+			if partDef.MaxRows != nil {
+				partStr += fmt.Sprintf(" MAX_ROWS=%d", *partDef.MaxRows)
+			}
+			if partDef.MinRows != nil {
+				partStr += fmt.Sprintf(" MIN_ROWS=%d", *partDef.MinRows)
+			}
+			if partDef.Tablespace != nil {
+				partStr += fmt.Sprintf(" TABLESPACE=`%s`", *partDef.Tablespace)
+			}
 			partDefs = append(partDefs, partStr)
 		}
 
@@ -554,19 +1375,83 @@ func (g *StatementGenerator) formatPartitionDefinition(partitionOpts *parser.Par
 	return strings.Join(parts, " ")
 }
 
+// FilterIgnoredTables returns the tables whose names match none of the given patterns.
+// Patterns use path.Match glob syntax, e.g. "tmp_*".
+func FilterIgnoredTables(tables []*parser.CreateTableStatement, patterns []string) []*parser.CreateTableStatement {
+	if len(patterns) == 0 {
+		return tables
+	}
+
+	var filtered []*parser.CreateTableStatement
+	for _, table := range tables {
+		if !matchesAnyPattern(table.TableName, patterns) {
+			filtered = append(filtered, table)
+		}
+	}
+	return filtered
+}
+
+// matchesAnyPattern reports whether name matches one of the glob patterns
+func matchesAnyPattern(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
 // MatchTablesByName matches tables from old and new schemas by name
 func MatchTablesByName(oldTables, newTables []*parser.CreateTableStatement) map[string]struct {
 	Old *parser.CreateTableStatement
 	New *parser.CreateTableStatement
 } {
-	oldMap := make(map[string]*parser.CreateTableStatement)
-	for _, table := range oldTables {
-		oldMap[table.TableName] = table
+	return MatchTablesWithRenames(oldTables, newTables, nil)
+}
+
+// MatchTablesWithRenames matches tables like MatchTablesByName, but pairs each old table listed in
+// renames (old name -> new name) with the new table of the mapped name. Matches are keyed by the new name.
+func MatchTablesWithRenames(oldTables, newTables []*parser.CreateTableStatement, renames map[string]string) map[string]struct {
+	Old *parser.CreateTableStatement
+	New *parser.CreateTableStatement
+} {
+	return matchTables(oldTables, newTables, renames, false)
+}
+
+// MatchTablesCaseInsensitive matches tables like MatchTablesWithRenames, comparing table names
+// ignoring case as MySQL does with lower_case_table_names=1 or 2. Matches are keyed by the name of
+// the new table, or of the old table for removed tables.
+func MatchTablesCaseInsensitive(oldTables, newTables []*parser.CreateTableStatement, renames map[string]string) map[string]struct {
+	Old *parser.CreateTableStatement
+	New *parser.CreateTableStatement
+} {
+	return matchTables(oldTables, newTables, renames, true)
+}
+
+// matchTables pairs old and new tables by name, optionally ignoring case
+func matchTables(oldTables, newTables []*parser.CreateTableStatement, renames map[string]string, caseInsensitive bool) map[string]struct {
+	Old *parser.CreateTableStatement
+	New *parser.CreateTableStatement
+} {
+	key := func(name string) string {
+		if caseInsensitive {
+			return strings.ToLower(name)
+		}
+		return name
 	}
 
 	newMap := make(map[string]*parser.CreateTableStatement)
 	for _, table := range newTables {
-		newMap[table.TableName] = table
+		newMap[key(table.TableName)] = table
+	}
+
+	oldMap := make(map[string]*parser.CreateTableStatement)
+	for _, table := range oldTables {
+		if newName, ok := renames[table.TableName]; ok && newMap[key(newName)] != nil {
+			oldMap[key(newName)] = table
+			continue
+		}
+		oldMap[key(table.TableName)] = table
 	}
 
 	allTableNames := make(map[string]bool)
@@ -582,41 +1467,154 @@ func MatchTablesByName(oldTables, newTables []*parser.CreateTableStatement) map[
 		New *parser.CreateTableStatement
 	})
 
-	for tableName := range allTableNames {
-		matches[tableName] = struct {
+	for name := range allTableNames {
+		match := struct {
 			Old *parser.CreateTableStatement
 			New *parser.CreateTableStatement
 		}{
-			Old: oldMap[tableName],
-			New: newMap[tableName],
+			Old: oldMap[name],
+			New: newMap[name],
+		}
+		// Key by the table's own name rather than its folded form
+		table := match.New
+		if table == nil {
+			table = match.Old
 		}
+		matches[table.TableName] = match
 	}
 
 	return matches
 }
 
-// GenerateCreateTableStatements generates CREATE TABLE comments for completely new tables
+// DiffTableNames returns the sorted names of the tables that only exist in the new schema and in the
+// old schema. Tables matched in both schemas, including declared renames, are not compared.
+func DiffTableNames(tableMatches map[string]struct {
+	Old *parser.CreateTableStatement
+	New *parser.CreateTableStatement
+}) (added, removed []string) {
+	for tableName, match := range tableMatches {
+		switch {
+		case match.Old == nil && match.New != nil:
+			added = append(added, tableName)
+		case match.Old != nil && match.New == nil:
+			removed = append(removed, tableName)
+		}
+	}
+	slices.Sort(added)
+	slices.Sort(removed)
+	return added, removed
+}
+
+// GenerateCreateTableStatements generates CREATE TABLE statements for completely new tables
 func GenerateCreateTableStatements(newTables []*parser.CreateTableStatement, existingNames map[string]bool) []string {
+	generator := NewStatementGenerator()
 	statements := []string{}
 
 	for _, table := range newTables {
 		if !existingNames[table.TableName] {
-			statements = append(statements, fmt.Sprintf("-- CREATE TABLE `%s` (...); -- New table, full definition needed", table.TableName))
+			statements = append(statements, generator.GenerateCreateTable(table))
 		}
 	}
 
 	return statements
 }
 
+// AttachStatements generates the statements that apply sd and stores them in it for output
+// formats that include the SQL: a CREATE TABLE for every added table, a DROP TABLE for every
+// removed table and the ALTER statements of every modified table
+func (g *StatementGenerator) AttachStatements(sd *diff.SchemaDiff) {
+	sd.CreatedTables = []string{}
+	sd.DroppedTables = []string{}
+	sd.TableStatements = make(map[string][]string)
+
+	for _, table := range sd.AddedTables {
+		sd.CreatedTables = append(sd.CreatedTables, table.TableName)
+		sd.TableStatements[table.TableName] = []string{g.GenerateCreateTable(table)}
+	}
+	for _, table := range sd.RemovedTables {
+		sd.DroppedTables = append(sd.DroppedTables, table.TableName)
+		sd.TableStatements[table.TableName] = []string{dropTableStatement(table.TableName)}
+	}
+	sd.TableNotes = make(map[string][]string)
+	for _, tableDiff := range sd.ModifiedTables {
+		statements, notes := splitComments(g.GenerateAlterStatements(tableDiff))
+		sd.TableStatements[tableDiff.NewTable.TableName] = statements
+		if len(notes) > 0 {
+			sd.TableNotes[tableDiff.NewTable.TableName] = notes
+		}
+	}
+}
+
+// GenerateCreateTable reconstructs a complete CREATE TABLE statement for table.
+// The result can be parsed again by parser.ParseSQLDump.
+func (g *StatementGenerator) GenerateCreateTable(table *parser.CreateTableStatement) string {
+	definitions := []string{}
+	for i := range table.Columns {
+		definitions = append(definitions, g.formatColumnDefinition(&table.Columns[i]))
+	}
+	if table.PrimaryKey != nil {
+		definitions = append(definitions, g.formatPrimaryKeyDefinition(table.PrimaryKey))
+	}
+	for i := range table.Indexes {
+		definitions = append(definitions, g.formatIndexDefinition(&table.Indexes[i]))
+	}
+	for i := range table.ForeignKeys {
+		definitions = append(definitions, g.formatForeignKeyDefinition(&table.ForeignKeys[i]))
+	}
+	for i := range table.CheckConstraints {
+		definitions = append(definitions, formatCheckConstraint(&table.CheckConstraints[i]))
+	}
+
+	var sb strings.Builder
+	sb.WriteString("CREATE ")
+	if table.Temporary {
+		sb.WriteString("TEMPORARY ")
+	}
+	sb.WriteString("TABLE ")
+	if table.IfNotExists {
+		sb.WriteString("IF NOT EXISTS ")
+	}
+	fmt.Fprintf(&sb, "`%s` (\n  %s\n)", table.TableName, strings.Join(definitions, ",\n  "))
+
+	if table.TableOptions != nil {
+		if options := g.formatTableOptions(table.TableOptions, nil); len(options) > 0 {
+			sb.WriteString(" " + strings.Join(options, " "))
+		}
+	}
+	if table.PartitionOptions != nil {
+		sb.WriteString("\n" + g.formatPartitionDefinition(table.PartitionOptions))
+	}
+	sb.WriteString(";")
+
+	return sb.String()
+}
+
+// formatCheckConstraint formats a table-level CHECK constraint
+func formatCheckConstraint(check *parser.CheckConstraint) string {
+	def := fmt.Sprintf("CHECK (%s)", check.Expression)
+	if check.Name != nil && *check.Name != "" {
+		def = fmt.Sprintf("CONSTRAINT `%s` %s", *check.Name, def)
+	}
+	if check.Enforced != nil && !*check.Enforced {
+		def += " NOT ENFORCED"
+	}
+	return def
+}
+
 // GenerateDropTableStatements generates DROP TABLE statements for removed tables
 func GenerateDropTableStatements(oldTables []*parser.CreateTableStatement, existingNames map[string]bool) []string {
 	statements := []string{}
 
 	for _, table := range oldTables {
 		if !existingNames[table.TableName] {
-			statements = append(statements, fmt.Sprintf("DROP TABLE IF EXISTS `%s`;", table.TableName))
+			statements = append(statements, dropTableStatement(table.TableName))
 		}
 	}
 
 	return statements
 }
+
+// dropTableStatement returns the statement that drops the named table
+func dropTableStatement(tableName string) string {
+	return fmt.Sprintf("DROP TABLE IF EXISTS `%s`;", tableName)
+}
@@ -1,6 +1,9 @@
 package alter
 
 import (
+	"bytes"
+	"encoding/json"
+	"slices"
 	"strings"
 	"testing"
 
@@ -74,6 +77,66 @@ func TestStatementGenerator_TableRename(t *testing.T) {
 	}
 }
 
+func TestStatementGenerator_Pretty(t *testing.T) {
+	generator := NewStatementGenerator()
+	generator.Pretty = true
+
+	oldTable := &parser.CreateTableStatement{
+		TableName: "users",
+		Columns: []parser.ColumnDefinition{
+			{Name: "id", DataType: parser.DataType{Name: "INT"}},
+			{Name: "name", DataType: parser.DataType{Name: "VARCHAR", Parameters: []string{"50"}}},
+		},
+	}
+
+	newTable := &parser.CreateTableStatement{
+		TableName: "users",
+		Columns: []parser.ColumnDefinition{
+			{Name: "id", DataType: parser.DataType{Name: "INT"}},
+			{Name: "name", DataType: parser.DataType{Name: "VARCHAR", Parameters: []string{"100"}}},
+			{Name: "phone", DataType: parser.DataType{Name: "VARCHAR", Parameters: []string{"20"}}},
+		},
+		PrimaryKey: &parser.PrimaryKeyDefinition{
+			Columns: []parser.IndexColumn{{Name: "id"}},
+		},
+	}
+
+	analyzer := diff.NewTableDiffAnalyzer()
+	tableDiff := analyzer.CompareTables(oldTable, newTable)
+
+	generator.Pretty = false
+	plain := generator.GenerateAlterStatements(tableDiff)
+	generator.Pretty = true
+	statements := generator.GenerateAlterStatements(tableDiff)
+
+	if len(statements) != 1 || len(plain) != 1 {
+		t.Fatalf("Expected 1 statement, got %d", len(statements))
+	}
+
+	lines := strings.Split(statements[0], "\n")
+	if lines[0] != "ALTER TABLE `users`" {
+		t.Errorf("Unexpected first line: %s", lines[0])
+	}
+
+	// Clause order follows column diff order, so compare lines as a set
+	expected := map[string]bool{
+		"  ADD COLUMN      `phone` VARCHAR(20),":  true,
+		"  MODIFY COLUMN   `name`  VARCHAR(100),": true,
+		"  ADD PRIMARY KEY (`id`);":               true,
+	}
+	for _, line := range lines[1:] {
+		if !expected[line] {
+			t.Errorf("Unexpected pretty line: %q", line)
+		}
+	}
+
+	// Pretty output only changes whitespace between tokens
+	normalize := func(s string) string { return strings.Join(strings.Fields(s), " ") }
+	if normalize(statements[0]) != normalize(plain[0]) {
+		t.Errorf("Pretty output differs from plain output beyond whitespace:\n%s\n%s", statements[0], plain[0])
+	}
+}
+
 func TestFormatColumnDefinition(t *testing.T) {
 	generator := NewStatementGenerator()
 
@@ -98,6 +161,43 @@ func TestFormatColumnDefinition(t *testing.T) {
 			},
 			expected: "`name` VARCHAR(255)",
 		},
+		{
+			name: "Column with expression default",
+			column: &parser.ColumnDefinition{
+				Name:         "tags",
+				DataType:     parser.DataType{Name: "JSON"},
+				DefaultValue: stringPtr("(JSON_ARRAY())"),
+			},
+			expected: "`tags` JSON DEFAULT (JSON_ARRAY())",
+		},
+		{
+			name: "Column with arithmetic expression default",
+			column: &parser.ColumnDefinition{
+				Name:         "total",
+				DataType:     parser.DataType{Name: "INT"},
+				DefaultValue: stringPtr("(price * quantity)"),
+			},
+			expected: "`total` INT DEFAULT (price * quantity)",
+		},
+		{
+			name: "Column with UUID() default",
+			column: &parser.ColumnDefinition{
+				Name:         "uuid",
+				DataType:     parser.DataType{Name: "VARCHAR", Parameters: []string{"36"}},
+				DefaultValue: stringPtr("(UUID())"),
+			},
+			expected: "`uuid` VARCHAR(36) DEFAULT (UUID())",
+		},
+		{
+			name: "Column with ON UPDATE",
+			column: &parser.ColumnDefinition{
+				Name:         "updated_at",
+				DataType:     parser.DataType{Name: "TIMESTAMP", Parameters: []string{"3"}},
+				DefaultValue: stringPtr("CURRENT_TIMESTAMP(3)"),
+				OnUpdate:     stringPtr("CURRENT_TIMESTAMP(3)"),
+			},
+			expected: "`updated_at` TIMESTAMP(3) DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3)",
+		},
 		{
 			name: "Column with NOT NULL",
 			column: &parser.ColumnDefinition{
@@ -134,6 +234,24 @@ func TestFormatColumnDefinition(t *testing.T) {
 			},
 			expected: "`created_at` TIMESTAMP DEFAULT CURRENT_TIMESTAMP",
 		},
+		{
+			name: "Column with NOW() default",
+			column: &parser.ColumnDefinition{
+				Name:         "created_at",
+				DataType:     parser.DataType{Name: "TIMESTAMP"},
+				DefaultValue: stringPtr("now()"),
+			},
+			expected: "`created_at` TIMESTAMP DEFAULT now()",
+		},
+		{
+			name: "Column with CURRENT_TIMESTAMP(6) default",
+			column: &parser.ColumnDefinition{
+				Name:         "created_at",
+				DataType:     parser.DataType{Name: "DATETIME", Parameters: []string{"6"}},
+				DefaultValue: stringPtr("CURRENT_TIMESTAMP(6)"),
+			},
+			expected: "`created_at` DATETIME(6) DEFAULT CURRENT_TIMESTAMP(6)",
+		},
 		{
 			name: "Column with UNSIGNED",
 			column: &parser.ColumnDefinition{
@@ -161,6 +279,16 @@ func TestFormatColumnDefinition(t *testing.T) {
 			},
 			expected: "`notes` TEXT COMMENT 'User notes'",
 		},
+		{
+			name: "Generated column with NOT NULL",
+			column: &parser.ColumnDefinition{
+				Name:      "total",
+				DataType:  parser.DataType{Name: "INT"},
+				Nullable:  boolPtr(false),
+				Generated: &parser.GeneratedColumn{Expression: "price * qty", Type: "STORED"},
+			},
+			expected: "`total` INT GENERATED ALWAYS AS (price * qty) STORED NOT NULL",
+		},
 	}
 
 	for _, tt := range tests {
@@ -351,6 +479,19 @@ func TestFormatForeignKeyDefinition(t *testing.T) {
 			},
 			expected: "FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE RESTRICT",
 		},
+		{
+			name: "Foreign key with NO ACTION and SET DEFAULT",
+			fk: &parser.ForeignKeyDefinition{
+				Columns: []string{"user_id"},
+				Reference: parser.ForeignKeyReference{
+					TableName: "users",
+					Columns:   []string{"id"},
+					OnDelete:  stringPtr("NO ACTION"),
+					OnUpdate:  stringPtr("SET DEFAULT"),
+				},
+			},
+			expected: "FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE NO ACTION ON UPDATE SET DEFAULT",
+		},
 		{
 			name: "Composite foreign key",
 			fk: &parser.ForeignKeyDefinition{
@@ -374,6 +515,32 @@ func TestFormatForeignKeyDefinition(t *testing.T) {
 	}
 }
 
+func TestFormatForeignKeyDefinition_OmitDefaultActions(t *testing.T) {
+	generator := NewStatementGenerator()
+	generator.OmitDefaultFKActions = true
+
+	fk := &parser.ForeignKeyDefinition{
+		Columns: []string{"user_id"},
+		Reference: parser.ForeignKeyReference{
+			TableName: "users",
+			Columns:   []string{"id"},
+			OnDelete:  stringPtr("CASCADE"),
+			OnUpdate:  stringPtr("NO ACTION"),
+		},
+	}
+
+	expected := "FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE"
+	if result := generator.formatForeignKeyDefinition(fk); result != expected {
+		t.Errorf("Expected '%s', got: '%s'", expected, result)
+	}
+
+	fk.Reference.OnDelete = stringPtr("RESTRICT")
+	expected = "FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)"
+	if result := generator.formatForeignKeyDefinition(fk); result != expected {
+		t.Errorf("Expected '%s', got: '%s'", expected, result)
+	}
+}
+
 func TestGenerateTableOptionsChanges(t *testing.T) {
 	generator := NewStatementGenerator()
 
@@ -428,6 +595,49 @@ func TestGenerateTableOptionsChanges(t *testing.T) {
 	}
 }
 
+func TestFormatPartitionDefinition(t *testing.T) {
+	generator := NewStatementGenerator()
+
+	tests := []struct {
+		name     string
+		opts     *parser.PartitionOptions
+		expected string
+	}{
+		{
+			name:     "hash partitioning",
+			opts:     &parser.PartitionOptions{Type: "HASH", Expression: stringPtr("id"), PartitionCount: intPtr(4)},
+			expected: "PARTITION BY HASH (id) PARTITIONS 4",
+		},
+		{
+			name:     "key partitioning",
+			opts:     &parser.PartitionOptions{Type: "KEY", Columns: []string{"id", "user_id"}},
+			expected: "PARTITION BY KEY (`id`, `user_id`)",
+		},
+		{
+			name:     "list columns partitioning",
+			opts:     &parser.PartitionOptions{Type: "LIST", Columns: []string{"city"}},
+			expected: "PARTITION BY LIST COLUMNS(`city`)",
+		},
+		{
+			name: "range partition definitions",
+			opts: &parser.PartitionOptions{Type: "RANGE", Expression: stringPtr("id"), Partitions: []parser.PartitionDefinition{
+				{Name: "p0", Type: "RANGE", Values: []string{"100"}, Comment: stringPtr("old rows")},
+				{Name: "pmax", Type: "RANGE", Values: []string{"MAXVALUE"}},
+			}},
+			expected: "PARTITION BY RANGE (id) ( PARTITION `p0` VALUES LESS THAN (100) COMMENT='old rows', PARTITION `pmax` VALUES LESS THAN MAXVALUE )",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := generator.formatPartitionDefinition(tt.opts)
+			if result != tt.expected {
+				t.Errorf("Expected '%s', got: '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
 func TestMatchTablesByName(t *testing.T) {
 	oldTables := []*parser.CreateTableStatement{
 		{TableName: "users"},
@@ -468,6 +678,76 @@ func TestMatchTablesByName(t *testing.T) {
 	}
 }
 
+func TestDiffTableNames(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump(`
+		CREATE TABLE users (id INT, name VARCHAR(50));
+		CREATE TABLE legacy (id INT);
+		CREATE TABLE audit (id INT);`)
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(`
+		CREATE TABLE users (id BIGINT, email VARCHAR(100), KEY idx_email (email)) ENGINE=MyISAM;
+		CREATE TABLE orders (id INT);
+		CREATE TABLE audit_log (id INT);`)
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	added, removed := DiffTableNames(MatchTablesByName(oldTables, newTables))
+	// users changed its structure but exists in both schemas
+	if !slices.Equal(added, []string{"audit_log", "orders"}) {
+		t.Errorf("Expected added tables [audit_log orders], got %v", added)
+	}
+	if !slices.Equal(removed, []string{"audit", "legacy"}) {
+		t.Errorf("Expected removed tables [audit legacy], got %v", removed)
+	}
+
+	// A declared rename pairs the tables, so neither is reported
+	added, removed = DiffTableNames(MatchTablesWithRenames(oldTables, newTables, map[string]string{"audit": "audit_log"}))
+	if !slices.Equal(added, []string{"orders"}) || !slices.Equal(removed, []string{"legacy"}) {
+		t.Errorf("Expected renamed table to be matched, got added %v, removed %v", added, removed)
+	}
+}
+
+func TestFilterIgnoredTables(t *testing.T) {
+	oldSQL := `
+		CREATE TABLE users (id INT, name VARCHAR(50));
+		CREATE TABLE tmp_import (id INT);
+		CREATE TABLE cache (id INT);
+	`
+	newSQL := `
+		CREATE TABLE users (id INT, name VARCHAR(50));
+		CREATE TABLE tmp_import (id INT, payload TEXT);
+		CREATE TABLE cache (id INT, expires_at DATETIME);
+	`
+
+	oldTables, err := parser.ParseSQLDump(oldSQL)
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(newSQL)
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	generator := NewStatementGenerator()
+	if up, _ := generator.GenerateMigration(oldTables, newTables); len(up) != 2 {
+		t.Fatalf("Expected 2 statements without filtering, got %d: %v", len(up), up)
+	}
+
+	patterns := []string{"tmp_*", "cache"}
+	oldTables = FilterIgnoredTables(oldTables, patterns)
+	newTables = FilterIgnoredTables(newTables, patterns)
+
+	if len(oldTables) != 1 || oldTables[0].TableName != "users" {
+		t.Errorf("Expected only users table to remain, got %d tables", len(oldTables))
+	}
+	if up, _ := generator.GenerateMigration(oldTables, newTables); len(up) != 0 {
+		t.Errorf("Expected ignored tables to produce no statements, got %v", up)
+	}
+}
+
 func TestGenerateDropTableStatements(t *testing.T) {
 	oldTables := []*parser.CreateTableStatement{
 		{TableName: "users"},
@@ -496,7 +776,7 @@ func TestGenerateCreateTableStatements(t *testing.T) {
 	newTables := []*parser.CreateTableStatement{
 		{TableName: "users"},
 		{TableName: "products"},
-		{TableName: "orders"},
+		{TableName: "orders", Columns: []parser.ColumnDefinition{{Name: "id", DataType: parser.DataType{Name: "INT"}}}},
 	}
 
 	existingNames := map[string]bool{
@@ -510,12 +790,66 @@ func TestGenerateCreateTableStatements(t *testing.T) {
 		t.Errorf("Expected 1 create statement, got %d", len(statements))
 	}
 
-	expected := "-- CREATE TABLE `orders` (...); -- New table, full definition needed"
+	expected := "CREATE TABLE `orders` (\n  `id` INT\n);"
 	if statements[0] != expected {
 		t.Errorf("Expected '%s', got: '%s'", expected, statements[0])
 	}
 }
 
+func TestGenerateCreateTableRoundTrip(t *testing.T) {
+	sql := `CREATE TABLE users (id INT NOT NULL AUTO_INCREMENT, PRIMARY KEY (id));
+CREATE TABLE orders (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  user_id INT NOT NULL,
+  status ENUM('new', 'paid') NOT NULL DEFAULT 'new' COMMENT 'Order status',
+  total DECIMAL(10,2) DEFAULT 0,
+  note VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NULL,
+  attrs JSON DEFAULT (JSON_OBJECT()),
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  total_cents INT GENERATED ALWAYS AS (total * 100) STORED,
+  PRIMARY KEY (id, created_at),
+  UNIQUE KEY uk_user_status (user_id, status),
+  KEY idx_note (note(20) DESC) COMMENT 'prefix',
+  FULLTEXT KEY ft_note (note),
+  CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+  CHECK (total >= 0)
+) ENGINE=InnoDB AUTO_INCREMENT=100 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Customer orders'
+PARTITION BY HASH (id) PARTITIONS 4;`
+
+	tables, err := parser.ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	statements := GenerateCreateTableStatements(tables, map[string]bool{})
+	if len(statements) != len(tables) {
+		t.Fatalf("Expected %d create statements, got %d", len(tables), len(statements))
+	}
+
+	reparsed, err := parser.ParseSQLDump(strings.Join(statements, "\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse generated statements: %v\n%s", err, strings.Join(statements, "\n"))
+	}
+	if len(reparsed) != len(tables) {
+		t.Fatalf("Expected %d tables after round trip, got %d:\n%s", len(tables), len(reparsed), strings.Join(statements, "\n"))
+	}
+
+	analyzer := diff.NewTableDiffAnalyzer()
+	analyzer.Normalize = false
+	for i, table := range tables {
+		tableDiff := analyzer.CompareTables(table, reparsed[i])
+		if tableDiff.HasChanges() {
+			var report strings.Builder
+			diff.FprintTableDiff(&report, tableDiff, true)
+			t.Errorf("Table %s changed after round trip:\n%s\n%s", table.TableName, statements[i], report.String())
+		}
+		if len(reparsed[i].CheckConstraints) != len(table.CheckConstraints) {
+			t.Errorf("Table %s has %d check constraints after round trip, want %d",
+				table.TableName, len(reparsed[i].CheckConstraints), len(table.CheckConstraints))
+		}
+	}
+}
+
 // Helper function to create bool pointers
 func boolPtr(b bool) *bool {
 	return &b
@@ -530,3 +864,897 @@ func stringPtr(s string) *string {
 func intPtr(i int) *int {
 	return &i
 }
+
+func TestForeignKeyImplicitIndex(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump(`CREATE TABLE orders (id INT, user_id INT, shop_id INT, KEY idx_shop (shop_id, id));`)
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(`CREATE TABLE orders (id INT, user_id INT, shop_id INT, KEY idx_shop (shop_id, id),
+		FOREIGN KEY (user_id) REFERENCES users (id),
+		FOREIGN KEY (shop_id) REFERENCES shops (id));`)
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	tableDiff := diff.NewTableDiffAnalyzer().CompareTables(oldTables[0], newTables[0])
+	for _, fkDiff := range tableDiff.ForeignKeyDiffs {
+		// shop_id leads idx_shop, user_id is not indexed
+		if expected := fkDiff.NewFK.Columns[0] == "user_id"; fkDiff.ImplicitIndex != expected {
+			t.Errorf("Expected ImplicitIndex=%v for %v", expected, fkDiff.NewFK.Columns)
+		}
+	}
+
+	generator := NewStatementGenerator()
+	result := strings.Join(generator.GenerateAlterStatements(tableDiff), "\n")
+	if !strings.Contains(result, "-- Note: MySQL will create an index on `orders` (user_id) for the new foreign key") {
+		t.Errorf("Expected a note about the implicit index, got:\n%s", result)
+	}
+	if strings.Contains(result, "ADD INDEX") || strings.Contains(result, "(shop_id) for") {
+		t.Errorf("Expected no explicit index and no note for the indexed column, got:\n%s", result)
+	}
+
+	generator.ExplicitFKIndexes = true
+	result = strings.Join(generator.GenerateAlterStatements(tableDiff), "\n")
+	if strings.Contains(result, "-- Note") {
+		t.Errorf("Expected no note with explicit indexes, got:\n%s", result)
+	}
+	indexPos := strings.Index(result, "ADD INDEX `user_id` (`user_id`)")
+	fkPos := strings.Index(result, "ADD FOREIGN KEY (`user_id`)")
+	if indexPos < 0 || fkPos < 0 || indexPos > fkPos {
+		t.Errorf("Expected ADD INDEX `user_id` before the foreign key, got:\n%s", result)
+	}
+	if strings.Count(result, "ADD INDEX") != 1 {
+		t.Errorf("Expected a single explicit index, got:\n%s", result)
+	}
+}
+
+func TestRedundantIndexNote(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump(`CREATE TABLE orders (id INT, user_id INT, status INT, KEY idx_user_status (user_id, status));`)
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(`CREATE TABLE orders (id INT, user_id INT, status INT, KEY idx_user_status (user_id, status), KEY idx_user (user_id));`)
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	tableDiff := diff.NewTableDiffAnalyzer().CompareTables(oldTables[0], newTables[0])
+	result := strings.Join(NewStatementGenerator().GenerateAlterStatements(tableDiff), "\n")
+	if !strings.Contains(result, "-- Note: new INDEX `idx_user` (`user_id`) on `orders` is redundant with index `idx_user_status`") {
+		t.Errorf("Expected a note about the redundant index, got:\n%s", result)
+	}
+	// The index is still added as requested
+	if !strings.Contains(result, "ADD INDEX `idx_user` (`user_id`)") {
+		t.Errorf("Expected the redundant index to be added, got:\n%s", result)
+	}
+}
+
+func TestPrimaryKeyWithAutoIncrement(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldSQL   string
+		newSQL   string
+		expected string
+	}{
+		{
+			name:     "existing column becomes AUTO_INCREMENT",
+			oldSQL:   "CREATE TABLE t (id INT NOT NULL, name VARCHAR(50))",
+			newSQL:   "CREATE TABLE t (id INT NOT NULL AUTO_INCREMENT, name VARCHAR(50), PRIMARY KEY (id))",
+			expected: "ALTER TABLE `t`\n  ADD PRIMARY KEY (`id`),\n  MODIFY COLUMN `id` INT NOT NULL AUTO_INCREMENT;",
+		},
+		{
+			name:     "primary key moves to the AUTO_INCREMENT column",
+			oldSQL:   "CREATE TABLE t (id INT NOT NULL, code INT NOT NULL, PRIMARY KEY (code))",
+			newSQL:   "CREATE TABLE t (id INT NOT NULL AUTO_INCREMENT, code INT NOT NULL, PRIMARY KEY (id))",
+			expected: "ALTER TABLE `t`\n  DROP PRIMARY KEY,\n  ADD PRIMARY KEY (`id`),\n  MODIFY COLUMN `id` INT NOT NULL AUTO_INCREMENT;",
+		},
+		{
+			name:     "added AUTO_INCREMENT column",
+			oldSQL:   "CREATE TABLE t (name VARCHAR(50))",
+			newSQL:   "CREATE TABLE t (name VARCHAR(50), id INT NOT NULL AUTO_INCREMENT, PRIMARY KEY (id))",
+			expected: "ALTER TABLE `t`\n  ADD COLUMN `id` INT NOT NULL AUTO_INCREMENT,\n  ADD PRIMARY KEY (`id`);",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldTables, err := parser.ParseSQLDump(tt.oldSQL)
+			if err != nil {
+				t.Fatalf("Failed to parse old SQL: %v", err)
+			}
+			newTables, err := parser.ParseSQLDump(tt.newSQL)
+			if err != nil {
+				t.Fatalf("Failed to parse new SQL: %v", err)
+			}
+
+			tableDiff := diff.NewTableDiffAnalyzer().CompareTables(oldTables[0], newTables[0])
+			statements := NewStatementGenerator().GenerateAlterStatements(tableDiff)
+			if len(statements) != 1 || statements[0] != tt.expected {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tt.expected, strings.Join(statements, "\n"))
+			}
+		})
+	}
+}
+
+func TestUnnamedIndexDrop(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldSQL   string
+		newSQL   string
+		expected string
+	}{
+		{
+			name:     "unnamed unique index",
+			oldSQL:   "CREATE TABLE t (id INT, email VARCHAR(100), UNIQUE KEY (email))",
+			newSQL:   "CREATE TABLE t (id INT, email VARCHAR(100))",
+			expected: "DROP INDEX `email`",
+		},
+		{
+			name:     "multi-column unnamed index after one on the same first column",
+			oldSQL:   "CREATE TABLE t (a INT, b INT, KEY (a), KEY (a, b))",
+			newSQL:   "CREATE TABLE t (a INT, b INT, KEY (a))",
+			expected: "DROP INDEX `a_2`",
+		},
+		{
+			name:     "duplicate of an identical unnamed index",
+			oldSQL:   "CREATE TABLE t (a INT, KEY (a), KEY (a))",
+			newSQL:   "CREATE TABLE t (a INT, KEY (a))",
+			expected: "DROP INDEX `a_2`",
+		},
+		{
+			name:     "name taken by a column-level UNIQUE key",
+			oldSQL:   "CREATE TABLE t (email VARCHAR(100) UNIQUE, name VARCHAR(50), KEY (email, name))",
+			newSQL:   "CREATE TABLE t (email VARCHAR(100) UNIQUE, name VARCHAR(50))",
+			expected: "DROP INDEX `email_2`",
+		},
+		{
+			name:     "modified unnamed index",
+			oldSQL:   "CREATE TABLE t (a INT, b INT, KEY (a, b))",
+			newSQL:   "CREATE TABLE t (a INT, b INT, KEY (a, b) COMMENT 'lookup')",
+			expected: "DROP INDEX `a`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldTables, err := parser.ParseSQLDump(tt.oldSQL)
+			if err != nil {
+				t.Fatalf("Failed to parse old SQL: %v", err)
+			}
+			newTables, err := parser.ParseSQLDump(tt.newSQL)
+			if err != nil {
+				t.Fatalf("Failed to parse new SQL: %v", err)
+			}
+
+			tableDiff := diff.NewTableDiffAnalyzer().CompareTables(oldTables[0], newTables[0])
+			result := strings.Join(NewStatementGenerator().GenerateAlterStatements(tableDiff), "\n")
+			if !strings.Contains(result, tt.expected) {
+				t.Errorf("Expected %q, got:\n%s", tt.expected, result)
+			}
+			if strings.Contains(result, "DROP INDEX (") {
+				t.Errorf("Expected no DROP INDEX by column list, got:\n%s", result)
+			}
+		})
+	}
+}
+
+func TestOnlineDDLAlgorithm(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldSQL   string
+		newSQL   string
+		expected string
+	}{
+		{
+			name:     "add index runs in place",
+			oldSQL:   "CREATE TABLE t (id INT, email VARCHAR(100))",
+			newSQL:   "CREATE TABLE t (id INT, email VARCHAR(100), INDEX idx_email (email))",
+			expected: "ALGORITHM=INPLACE,\n  LOCK=NONE;",
+		},
+		{
+			name:     "appending an ENUM value runs in place",
+			oldSQL:   "CREATE TABLE t (id INT, status ENUM('new','paid'))",
+			newSQL:   "CREATE TABLE t (id INT, status ENUM('new','paid','shipped'))",
+			expected: "ALGORITHM=INPLACE,\n  LOCK=NONE;",
+		},
+		{
+			name:     "inserting an ENUM value needs a copy",
+			oldSQL:   "CREATE TABLE t (id INT, status ENUM('new','paid'))",
+			newSQL:   "CREATE TABLE t (id INT, status ENUM('new','pending','paid'))",
+			expected: "ALGORITHM=COPY;",
+		},
+		{
+			name:     "add STORED generated column needs a copy",
+			oldSQL:   "CREATE TABLE t (id INT, price INT)",
+			newSQL:   "CREATE TABLE t (id INT, price INT, doubled INT GENERATED ALWAYS AS (price * 2) STORED)",
+			expected: "ALGORITHM=COPY;",
+		},
+		{
+			name:     "column type change needs a copy",
+			oldSQL:   "CREATE TABLE t (id INT, total INT)",
+			newSQL:   "CREATE TABLE t (id INT, total BIGINT)",
+			expected: "ALGORITHM=COPY;",
+		},
+		{
+			name:     "add FULLTEXT index blocks writes",
+			oldSQL:   "CREATE TABLE t (id INT, body TEXT)",
+			newSQL:   "CREATE TABLE t (id INT, body TEXT, FULLTEXT INDEX ft_body (body))",
+			expected: "ALGORITHM=INPLACE,\n  LOCK=SHARED;",
+		},
+		{
+			name:     "add SPATIAL index blocks writes",
+			oldSQL:   "CREATE TABLE t (id INT, location POINT NOT NULL SRID 4326)",
+			newSQL:   "CREATE TABLE t (id INT, location POINT NOT NULL SRID 4326, SPATIAL INDEX sp_location (location))",
+			expected: "ALGORITHM=INPLACE,\n  LOCK=SHARED;",
+		},
+		{
+			name:     "add AUTO_INCREMENT column blocks writes",
+			oldSQL:   "CREATE TABLE t (name VARCHAR(50))",
+			newSQL:   "CREATE TABLE t (id INT NOT NULL AUTO_INCREMENT, name VARCHAR(50), PRIMARY KEY (id))",
+			expected: "ALGORITHM=INPLACE,\n  LOCK=SHARED;",
+		},
+		{
+			name:     "drop primary key needs a copy",
+			oldSQL:   "CREATE TABLE t (id INT, PRIMARY KEY (id))",
+			newSQL:   "CREATE TABLE t (id INT)",
+			expected: "ALGORITHM=COPY;",
+		},
+	}
+
+	generator := NewStatementGenerator()
+	generator.OnlineDDL = true
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldTables, err := parser.ParseSQLDump(tt.oldSQL)
+			if err != nil {
+				t.Fatalf("Failed to parse old SQL: %v", err)
+			}
+			newTables, err := parser.ParseSQLDump(tt.newSQL)
+			if err != nil {
+				t.Fatalf("Failed to parse new SQL: %v", err)
+			}
+
+			tableDiff := diff.NewTableDiffAnalyzer().CompareTables(oldTables[0], newTables[0])
+			statements := generator.GenerateAlterStatements(tableDiff)
+			if len(statements) != 1 {
+				t.Fatalf("Expected 1 statement, got %d: %v", len(statements), statements)
+			}
+			if !strings.HasSuffix(statements[0], tt.expected) {
+				t.Errorf("Expected statement ending with %q, got:\n%s", tt.expected, statements[0])
+			}
+			if tt.expected == "ALGORITHM=COPY;" && strings.Contains(statements[0], "INPLACE") {
+				t.Errorf("Expected no INPLACE for a copying change, got:\n%s", statements[0])
+			}
+		})
+	}
+}
+
+func TestGenerateRollbackStatements(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump(`CREATE TABLE users (
+  id INT NOT NULL,
+  fname VARCHAR(50),
+  legacy INT,
+  PRIMARY KEY (id),
+  KEY idx_legacy (legacy)
+);`)
+	if err != nil {
+		t.Fatalf("Failed to parse old schema: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(`CREATE TABLE accounts (
+  id INT NOT NULL,
+  first_name VARCHAR(100),
+  created_at DATETIME,
+  PRIMARY KEY (id),
+  KEY idx_created (created_at)
+);`)
+	if err != nil {
+		t.Fatalf("Failed to parse new schema: %v", err)
+	}
+
+	analyzer := diff.NewTableDiffAnalyzer()
+	analyzer.ColumnRenames = map[string]map[string]string{"users": {"fname": "first_name"}}
+	tableDiff := analyzer.CompareTables(oldTables[0], newTables[0])
+
+	statements := NewStatementGenerator().GenerateRollbackStatements(tableDiff)
+	if len(statements) != 2 {
+		t.Fatalf("Expected rename and ALTER statements, got %d: %v", len(statements), statements)
+	}
+	if statements[0] != "ALTER TABLE `accounts` RENAME TO `users`;" {
+		t.Errorf("Expected the table to be renamed back, got: %s", statements[0])
+	}
+
+	for _, expected := range []string{
+		"CHANGE COLUMN `first_name` `fname` VARCHAR(50)",
+		"DROP COLUMN `created_at`",
+		"ADD COLUMN `legacy` INT",
+		"DROP INDEX `idx_created`",
+		"ADD INDEX `idx_legacy` (`legacy`)",
+	} {
+		if !strings.Contains(statements[1], expected) {
+			t.Errorf("Expected rollback to contain %q, got:\n%s", expected, statements[1])
+		}
+	}
+
+	if got := NewStatementGenerator().GenerateRollbackStatements(nil); len(got) != 0 {
+		t.Errorf("Expected no statements for a nil diff, got %v", got)
+	}
+}
+
+func TestIgnoredColumnRenameKeepsDefinition(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump("CREATE TABLE users (id INT, cache TEXT COMMENT 'mysqldiff:ignore');")
+	if err != nil {
+		t.Fatalf("Failed to parse old schema: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump("CREATE TABLE users (id INT, cache_data VARCHAR(255) NOT NULL COMMENT 'mysqldiff:ignore');")
+	if err != nil {
+		t.Fatalf("Failed to parse new schema: %v", err)
+	}
+
+	analyzer := diff.NewTableDiffAnalyzer()
+	analyzer.ColumnRenames = map[string]map[string]string{"users": {"cache": "cache_data"}}
+	statements := NewStatementGenerator().GenerateAlterStatements(analyzer.CompareTables(oldTables[0], newTables[0]))
+	if len(statements) != 1 || !strings.Contains(statements[0], "CHANGE COLUMN `cache` `cache_data` TEXT COMMENT 'mysqldiff:ignore'") {
+		t.Errorf("Expected the ignored column to be renamed with its old definition, got %v", statements)
+	}
+}
+
+func TestAdditiveOnlyStatements(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump(`CREATE TABLE users (id INT NOT NULL, name VARCHAR(50), legacy INT,
+  KEY idx_name (name), KEY idx_legacy (legacy)) ENGINE=MyISAM;`)
+	if err != nil {
+		t.Fatalf("Failed to parse old schema: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(`CREATE TABLE users (id INT NOT NULL, name VARCHAR(100), email VARCHAR(255),
+  PRIMARY KEY (id), KEY idx_name (name, email), UNIQUE KEY uk_email (email)) ENGINE=InnoDB;`)
+	if err != nil {
+		t.Fatalf("Failed to parse new schema: %v", err)
+	}
+
+	tableDiff := diff.NewTableDiffAnalyzer().CompareTables(oldTables[0], newTables[0])
+	generator := NewStatementGenerator()
+	generator.AdditiveOnly = true
+
+	result := strings.Join(generator.GenerateAlterStatements(tableDiff), "\n")
+	for _, expected := range []string{"ADD COLUMN `email` VARCHAR(255)", "ADD PRIMARY KEY (`id`)", "ADD UNIQUE INDEX `uk_email` (`email`)"} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected %q in:\n%s", expected, result)
+		}
+	}
+	for _, unexpected := range []string{"DROP", "MODIFY", "ENGINE", "idx_name"} {
+		if strings.Contains(result, unexpected) {
+			t.Errorf("Expected no %q in additive-only statements:\n%s", unexpected, result)
+		}
+	}
+
+	// The rollback undoes only the additions
+	rollback := strings.Join(generator.GenerateRollbackStatements(tableDiff), "\n")
+	for _, expected := range []string{"DROP COLUMN `email`", "DROP PRIMARY KEY", "DROP INDEX `uk_email`"} {
+		if !strings.Contains(rollback, expected) {
+			t.Errorf("Expected %q in rollback:\n%s", expected, rollback)
+		}
+	}
+	for _, unexpected := range []string{"legacy", "MODIFY", "ENGINE", "idx_name"} {
+		if strings.Contains(rollback, unexpected) {
+			t.Errorf("Expected no %q in additive-only rollback:\n%s", unexpected, rollback)
+		}
+	}
+
+	// A diff with only removals and modifications generates nothing
+	newTables, err = parser.ParseSQLDump(`CREATE TABLE users (id BIGINT NOT NULL, name VARCHAR(50), KEY idx_name (name, id));`)
+	if err != nil {
+		t.Fatalf("Failed to parse new schema: %v", err)
+	}
+	tableDiff = diff.NewTableDiffAnalyzer().CompareTables(oldTables[0], newTables[0])
+	if statements := generator.GenerateAlterStatements(tableDiff); len(statements) != 0 {
+		t.Errorf("Expected no statements, got %v", statements)
+	}
+}
+
+func TestAttachStatementsJSON(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump("CREATE TABLE users (id INT); CREATE TABLE logs (id INT);")
+	if err != nil {
+		t.Fatalf("Failed to parse old schema: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump("CREATE TABLE users (id BIGINT); CREATE TABLE orders (id INT);")
+	if err != nil {
+		t.Fatalf("Failed to parse new schema: %v", err)
+	}
+
+	schemaDiff := diff.CompareSchemas(oldTables, newTables)
+	NewStatementGenerator().AttachStatements(schemaDiff)
+
+	var buf bytes.Buffer
+	if err := diff.FormatSchemaDiff(&buf, "json", schemaDiff); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var results map[string]json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &results); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+
+	var migration struct {
+		CreatedTables []string `json:"created_tables"`
+		DroppedTables []string `json:"dropped_tables"`
+	}
+	if err := json.Unmarshal(results["@migration"], &migration); err != nil {
+		t.Fatalf("Expected the @migration object, got %s", results["@migration"])
+	}
+	if !slices.Equal(migration.CreatedTables, []string{"orders"}) {
+		t.Errorf("Expected created_tables [orders], got %v", migration.CreatedTables)
+	}
+	if !slices.Equal(migration.DroppedTables, []string{"logs"}) {
+		t.Errorf("Expected dropped_tables [logs], got %v", migration.DroppedTables)
+	}
+
+	expected := map[string]string{
+		"users":  "ALTER TABLE `users`\n  MODIFY COLUMN `id` BIGINT;",
+		"orders": "CREATE TABLE `orders` (\n  `id` INT\n);",
+		"logs":   "DROP TABLE IF EXISTS `logs`;",
+	}
+	for name, statement := range expected {
+		var table struct {
+			ColumnsModified int      `json:"columns_modified"`
+			AlterStatements []string `json:"alter_statements"`
+		}
+		if err := json.Unmarshal(results[name], &table); err != nil {
+			t.Fatalf("Expected table %s in the JSON output: %v", name, err)
+		}
+		if !slices.Equal(table.AlterStatements, []string{statement}) {
+			t.Errorf("Expected alter_statements [%q] for %s, got %q", statement, name, table.AlterStatements)
+		}
+		if name == "users" && table.ColumnsModified != 1 {
+			t.Errorf("Expected the diff fields to be kept, got %s", results[name])
+		}
+	}
+}
+
+func TestAttachStatementsKeepsNotesApart(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump("CREATE TABLE orders (id INT, user_id INT);")
+	if err != nil {
+		t.Fatalf("Failed to parse old schema: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump("CREATE TABLE orders (id INT, user_id INT, FOREIGN KEY (user_id) REFERENCES users (id));")
+	if err != nil {
+		t.Fatalf("Failed to parse new schema: %v", err)
+	}
+
+	schemaDiff := diff.CompareSchemas(oldTables, newTables)
+	NewStatementGenerator().AttachStatements(schemaDiff)
+
+	var buf bytes.Buffer
+	if err := diff.FormatSchemaDiff(&buf, "json", schemaDiff); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var results map[string]json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &results); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+	var orders struct {
+		AlterStatements []string `json:"alter_statements"`
+		Notes           []string `json:"notes"`
+	}
+	if err := json.Unmarshal(results["orders"], &orders); err != nil {
+		t.Fatalf("Expected table orders in the JSON output: %v", err)
+	}
+	if len(orders.AlterStatements) != 1 || strings.HasPrefix(orders.AlterStatements[0], "--") {
+		t.Errorf("Expected only the ALTER statement in alter_statements, got %q", orders.AlterStatements)
+	}
+	if len(orders.Notes) != 1 || !strings.HasPrefix(orders.Notes[0], "-- Note: MySQL will create an index") {
+		t.Errorf("Expected the implicit index note in notes, got %q", orders.Notes)
+	}
+
+	up, down := NewStatementGenerator().GenerateMigration(oldTables, newTables)
+	for _, statement := range append(up, down...) {
+		if strings.HasPrefix(statement, "--") {
+			t.Errorf("Expected no comments in the migration, got %q", statement)
+		}
+	}
+}
+
+func TestCheckConstraintStatements(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump(`CREATE TABLE orders (id INT, total INT,
+  CONSTRAINT chk_total CHECK (total >= 0), CONSTRAINT chk_legacy CHECK (id > 0));`)
+	if err != nil {
+		t.Fatalf("Failed to parse old schema: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(`CREATE TABLE orders (id INT, total INT,
+  CONSTRAINT chk_total CHECK (total > 0), CONSTRAINT chk_id CHECK (id < 1000) NOT ENFORCED);`)
+	if err != nil {
+		t.Fatalf("Failed to parse new schema: %v", err)
+	}
+
+	tableDiff := diff.NewTableDiffAnalyzer().CompareTables(oldTables[0], newTables[0])
+	result := strings.Join(NewStatementGenerator().GenerateAlterStatements(tableDiff), "\n")
+
+	for _, expected := range []string{
+		"DROP CHECK `chk_legacy`",
+		"DROP CHECK `chk_total`",
+		"ADD CONSTRAINT `chk_total` CHECK (total > 0)",
+		"ADD CONSTRAINT `chk_id` CHECK (id < 1000) NOT ENFORCED",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected %q in:\n%s", expected, result)
+		}
+	}
+
+	rollback := strings.Join(NewStatementGenerator().GenerateRollbackStatements(tableDiff), "\n")
+	for _, expected := range []string{"DROP CHECK `chk_id`", "ADD CONSTRAINT `chk_legacy` CHECK (id > 0)"} {
+		if !strings.Contains(rollback, expected) {
+			t.Errorf("Expected %q in rollback:\n%s", expected, rollback)
+		}
+	}
+}
+
+func TestCheckEnforcementStatements(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump(`CREATE TABLE orders (qty INT CONSTRAINT chk_qty_col CHECK (qty < 1000),
+  CONSTRAINT chk_qty CHECK (qty > 0), CONSTRAINT chk_total CHECK (qty < 100) NOT ENFORCED);`)
+	if err != nil {
+		t.Fatalf("Failed to parse old schema: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(`CREATE TABLE orders (qty INT CONSTRAINT chk_qty_col CHECK (qty < 1000) NOT ENFORCED,
+  CONSTRAINT chk_qty CHECK (qty > 0) NOT ENFORCED, CONSTRAINT chk_total CHECK (qty < 100) ENFORCED);`)
+	if err != nil {
+		t.Fatalf("Failed to parse new schema: %v", err)
+	}
+
+	tableDiff := diff.NewTableDiffAnalyzer().CompareTables(oldTables[0], newTables[0])
+	result := strings.Join(NewStatementGenerator().GenerateAlterStatements(tableDiff), "\n")
+	for _, expected := range []string{"ALTER CHECK `chk_qty` NOT ENFORCED", "ALTER CHECK `chk_total` ENFORCED", "ALTER CHECK `chk_qty_col` NOT ENFORCED"} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected %q in:\n%s", expected, result)
+		}
+	}
+	if strings.Contains(result, "DROP CHECK") || strings.Contains(result, "ADD CONSTRAINT") || strings.Contains(result, "MODIFY") {
+		t.Errorf("Expected enforcement toggles without recreating the constraints, got:\n%s", result)
+	}
+
+	rollback := strings.Join(NewStatementGenerator().GenerateRollbackStatements(tableDiff), "\n")
+	for _, expected := range []string{"ALTER CHECK `chk_qty` ENFORCED", "ALTER CHECK `chk_total` NOT ENFORCED", "ALTER CHECK `chk_qty_col` ENFORCED"} {
+		if !strings.Contains(rollback, expected) {
+			t.Errorf("Expected %q in rollback:\n%s", expected, rollback)
+		}
+	}
+}
+
+func TestColumnCheckConstraintStatements(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump(`CREATE TABLE people (id INT, age INT CONSTRAINT chk_age CHECK (age >= 0), score INT);`)
+	if err != nil {
+		t.Fatalf("Failed to parse old schema: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(`CREATE TABLE people (id BIGINT, age INT CONSTRAINT chk_age CHECK (age >= 18) NOT ENFORCED,
+  score INT CHECK (score <= 100), level INT CHECK (level > 0));`)
+	if err != nil {
+		t.Fatalf("Failed to parse new schema: %v", err)
+	}
+
+	tableDiff := diff.NewTableDiffAnalyzer().CompareTables(oldTables[0], newTables[0])
+	result := strings.Join(NewStatementGenerator().GenerateAlterStatements(tableDiff), "\n")
+
+	for _, expected := range []string{
+		"MODIFY COLUMN `id` BIGINT",
+		"DROP CHECK `chk_age`",
+		"ADD CONSTRAINT `chk_age` CHECK (age >= 18) NOT ENFORCED",
+		"ADD CHECK (score <= 100)",
+		"ADD COLUMN `level` INT CHECK (level > 0)",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected %q in:\n%s", expected, result)
+		}
+	}
+	// A check-only change does not rewrite the column
+	if strings.Contains(result, "MODIFY COLUMN `age`") || strings.Contains(result, "MODIFY COLUMN `score`") {
+		t.Errorf("Expected no MODIFY COLUMN for check-only changes, got:\n%s", result)
+	}
+
+	// Checks are not repeated when another attribute of the column changes
+	newTables, err = parser.ParseSQLDump(`CREATE TABLE people (id INT, age BIGINT CONSTRAINT chk_age CHECK (age >= 0), score INT);`)
+	if err != nil {
+		t.Fatalf("Failed to parse new schema: %v", err)
+	}
+	tableDiff = diff.NewTableDiffAnalyzer().CompareTables(oldTables[0], newTables[0])
+	result = strings.Join(NewStatementGenerator().GenerateAlterStatements(tableDiff), "\n")
+	if expected := "ALTER TABLE `people`\n  MODIFY COLUMN `age` BIGINT;"; result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestUnnamedCheckDrop(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldSQL   string
+		newSQL   string
+		expected string
+	}{
+		{
+			name:     "removed table check",
+			oldSQL:   "CREATE TABLE t (a INT, b INT, CHECK (a > 0), CONSTRAINT chk_b CHECK (b > 0), CHECK (a < b))",
+			newSQL:   "CREATE TABLE t (a INT, b INT, CHECK (a > 0), CONSTRAINT chk_b CHECK (b > 0))",
+			expected: "ALTER TABLE `t`\n  DROP CHECK `t_chk_2`;",
+		},
+		{
+			name:     "modified table check",
+			oldSQL:   "CREATE TABLE t (a INT, CHECK (a > 0))",
+			newSQL:   "CREATE TABLE t (a INT, CHECK (a > 0) NOT ENFORCED)",
+			expected: "ALTER TABLE `t`\n  DROP CHECK `t_chk_1`,\n  ADD CHECK (a > 0) NOT ENFORCED;",
+		},
+		{
+			name:     "column checks are numbered before table checks",
+			oldSQL:   "CREATE TABLE t (a INT CHECK (a > 0), b INT, CHECK (b > 0))",
+			newSQL:   "CREATE TABLE t (a INT CHECK (a > 0), b INT)",
+			expected: "ALTER TABLE `t`\n  DROP CHECK `t_chk_2`;",
+		},
+		{
+			name:     "changed column check",
+			oldSQL:   "CREATE TABLE t (a INT CHECK (a > 0), b INT CHECK (b > 0))",
+			newSQL:   "CREATE TABLE t (a INT CHECK (a > 0), b INT CHECK (b > 1))",
+			expected: "ALTER TABLE `t`\n  DROP CHECK `t_chk_2`,\n  ADD CHECK (b > 1);",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldTables, err := parser.ParseSQLDump(tt.oldSQL)
+			if err != nil {
+				t.Fatalf("Failed to parse old SQL: %v", err)
+			}
+			newTables, err := parser.ParseSQLDump(tt.newSQL)
+			if err != nil {
+				t.Fatalf("Failed to parse new SQL: %v", err)
+			}
+
+			tableDiff := diff.NewTableDiffAnalyzer().CompareTables(oldTables[0], newTables[0])
+			result := strings.Join(NewStatementGenerator().GenerateAlterStatements(tableDiff), "\n")
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+
+	// Generated names follow a table rename
+	oldTables, err := parser.ParseSQLDump("CREATE TABLE t (a INT, CHECK (a > 0))")
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump("CREATE TABLE u (a INT)")
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+	tableDiff := diff.NewTableDiffAnalyzer().CompareTables(oldTables[0], newTables[0])
+	result := strings.Join(NewStatementGenerator().GenerateAlterStatements(tableDiff), "\n")
+	if !strings.Contains(result, "DROP CHECK `u_chk_1`") {
+		t.Errorf("Expected the check to be dropped under the new table name, got:\n%s", result)
+	}
+}
+
+func TestColumnReorderStatements(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump(`CREATE TABLE users (
+  id INT NOT NULL,
+  name VARCHAR(50),
+  email VARCHAR(100),
+  created_at DATETIME
+);`)
+	if err != nil {
+		t.Fatalf("Failed to parse old schema: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(`CREATE TABLE users (
+  email VARCHAR(100),
+  id INT NOT NULL,
+  phone VARCHAR(20),
+  created_at TIMESTAMP,
+  name VARCHAR(50)
+);`)
+	if err != nil {
+		t.Fatalf("Failed to parse new schema: %v", err)
+	}
+
+	analyzer := diff.NewTableDiffAnalyzer()
+	analyzer.DetectColumnReorder = true
+	tableDiff := analyzer.CompareTables(oldTables[0], newTables[0])
+
+	generator := NewStatementGenerator()
+	statements := generator.GenerateAlterStatements(tableDiff)
+	if len(statements) != 1 {
+		t.Fatalf("Expected 1 statement, got %d: %v", len(statements), statements)
+	}
+	statement := statements[0]
+
+	// email and created_at keep their relative order; id and name move and the added phone column
+	// is placed between them
+	for _, expected := range []string{
+		"ADD COLUMN `phone` VARCHAR(20) AFTER `id`",
+		"MODIFY COLUMN `created_at` TIMESTAMP",
+		"MODIFY COLUMN `id` INT NOT NULL AFTER `email`",
+		"MODIFY COLUMN `name` VARCHAR(50) AFTER `created_at`",
+	} {
+		if !strings.Contains(statement, expected) {
+			t.Errorf("Expected statement to contain %q, got:\n%s", expected, statement)
+		}
+	}
+	if strings.Index(statement, "MODIFY COLUMN `id`") > strings.Index(statement, "MODIFY COLUMN `name`") {
+		t.Errorf("Expected moves in new column order, got:\n%s", statement)
+	}
+
+	rollback := generator.GenerateRollbackStatements(tableDiff)
+	if len(rollback) != 1 {
+		t.Fatalf("Expected 1 rollback statement, got %d: %v", len(rollback), rollback)
+	}
+	for _, expected := range []string{
+		"DROP COLUMN `phone`",
+		"MODIFY COLUMN `id` INT NOT NULL FIRST",
+		"MODIFY COLUMN `name` VARCHAR(50) AFTER `id`",
+	} {
+		if !strings.Contains(rollback[0], expected) {
+			t.Errorf("Expected rollback to contain %q, got:\n%s", expected, rollback[0])
+		}
+	}
+}
+
+func TestAlterClauseOrder(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump(`CREATE TABLE orders (id INT NOT NULL, legacy INT, old_note TEXT, qty INT, customer_id INT,
+  PRIMARY KEY (id), KEY idx_legacy (legacy), KEY idx_qty (qty),
+  CONSTRAINT fk_legacy FOREIGN KEY (legacy) REFERENCES legacy (id));`)
+	if err != nil {
+		t.Fatalf("Failed to parse old schema: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(`CREATE TABLE orders (id INT NOT NULL, qty BIGINT, customer_id BIGINT, zone INT, amount DECIMAL(10,2),
+  PRIMARY KEY (id), KEY idx_qty (qty, id), KEY idx_customer (customer_id),
+  CONSTRAINT fk_customer FOREIGN KEY (customer_id) REFERENCES customers (id));`)
+	if err != nil {
+		t.Fatalf("Failed to parse new schema: %v", err)
+	}
+
+	tableDiff := diff.NewTableDiffAnalyzer().CompareTables(oldTables[0], newTables[0])
+	// Run the generator a few times, since the diffs come in map iteration order
+	for range 5 {
+		statements := NewStatementGenerator().GenerateAlterStatements(tableDiff)
+		if len(statements) != 1 {
+			t.Fatalf("Expected 1 statement, got %d: %v", len(statements), statements)
+		}
+		expected := "ALTER TABLE `orders`\n  " + strings.Join([]string{
+			"ADD COLUMN `zone` INT",
+			"ADD COLUMN `amount` DECIMAL(10,2)",
+			"MODIFY COLUMN `customer_id` BIGINT",
+			"MODIFY COLUMN `qty` BIGINT",
+			"ADD CONSTRAINT `fk_customer` FOREIGN KEY (`customer_id`) REFERENCES `customers` (`id`)",
+			"ADD INDEX `idx_customer` (`customer_id`)",
+			"DROP INDEX `idx_qty`",
+			"ADD INDEX `idx_qty` (`qty`, `id`)",
+			"DROP FOREIGN KEY `fk_legacy`",
+			"DROP INDEX `idx_legacy`",
+			"DROP COLUMN `legacy`",
+			"DROP COLUMN `old_note`",
+		}, ",\n  ") + ";"
+		if statements[0] != expected {
+			t.Fatalf("Expected:\n%s\ngot:\n%s", expected, statements[0])
+		}
+	}
+}
+
+func TestFulltextParserStatements(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump(`CREATE TABLE articles (id INT, body TEXT, FULLTEXT KEY ft_body (body));`)
+	if err != nil {
+		t.Fatalf("Failed to parse old schema: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(`CREATE TABLE articles (id INT, body TEXT,
+  FULLTEXT KEY ft_body (body) WITH PARSER ngram COMMENT 'cjk search');`)
+	if err != nil {
+		t.Fatalf("Failed to parse new schema: %v", err)
+	}
+
+	tableDiff := diff.NewTableDiffAnalyzer().CompareTables(oldTables[0], newTables[0])
+	if len(tableDiff.IndexDiffs) != 1 || tableDiff.IndexDiffs[0].Changes == nil || tableDiff.IndexDiffs[0].Changes.Parser == nil {
+		t.Fatalf("Expected a parser change of ft_body, got %+v", tableDiff.IndexDiffs)
+	}
+
+	result := strings.Join(NewStatementGenerator().GenerateAlterStatements(tableDiff), "\n")
+	for _, expected := range []string{"DROP INDEX `ft_body`", "ADD FULLTEXT INDEX `ft_body` (`body`) WITH PARSER ngram COMMENT 'cjk search'"} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected %q in:\n%s", expected, result)
+		}
+	}
+}
+
+func TestTableOptionsRollback(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump(`CREATE TABLE logs (id INT) ENGINE=MyISAM DEFAULT CHARSET=latin1 COMMENT='old' ROW_FORMAT=FIXED;`)
+	if err != nil {
+		t.Fatalf("Failed to parse old schema: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(`CREATE TABLE logs (id INT) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='new';`)
+	if err != nil {
+		t.Fatalf("Failed to parse new schema: %v", err)
+	}
+
+	tableDiff := diff.NewTableDiffAnalyzer().CompareTables(oldTables[0], newTables[0])
+	forward := NewStatementGenerator().GenerateAlterStatements(tableDiff)
+	if expected := "ALTER TABLE `logs` ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='new' ROW_FORMAT=DEFAULT;"; !slices.Contains(forward, expected) {
+		t.Errorf("Expected %q in %v", expected, forward)
+	}
+	rollback := NewStatementGenerator().GenerateRollbackStatements(tableDiff)
+	if expected := "ALTER TABLE `logs` ENGINE=MyISAM DEFAULT CHARSET=latin1 COMMENT='old' ROW_FORMAT=FIXED;"; !slices.Contains(rollback, expected) {
+		t.Errorf("Expected the rollback to restore every option with %q, got %v", expected, rollback)
+	}
+	if len(rollback) == 0 || !strings.Contains(rollback[0], "engine change InnoDB -> MyISAM") {
+		t.Errorf("Expected a warning for the engine change of the rollback, got %v", rollback)
+	}
+
+	// Options added to a table without options fall back to their defaults
+	plainTables, err := parser.ParseSQLDump(`CREATE TABLE logs (id INT);`)
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	addedDiff := diff.NewTableDiffAnalyzer().CompareTables(plainTables[0], newTables[0])
+	rollback = NewStatementGenerator().GenerateRollbackStatements(addedDiff)
+	if expected := []string{"ALTER TABLE `logs` COMMENT='';"}; !slices.Equal(rollback, expected) {
+		t.Errorf("Expected %v, got %v", expected, rollback)
+	}
+}
+
+func TestColumnReorderStatementsReversed(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump(`CREATE TABLE t (id INT, a INT, b INT, c INT, d INT);`)
+	if err != nil {
+		t.Fatalf("Failed to parse old schema: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(`CREATE TABLE t (id INT, d INT, c INT, b INT, a INT);`)
+	if err != nil {
+		t.Fatalf("Failed to parse new schema: %v", err)
+	}
+
+	analyzer := diff.NewTableDiffAnalyzer()
+	analyzer.DetectColumnReorder = true
+	tableDiff := analyzer.CompareTables(oldTables[0], newTables[0])
+
+	// The moves run in new column order, not alphabetically, so every AFTER column is in place
+	expected := "ALTER TABLE `t`\n  " + strings.Join([]string{
+		"MODIFY COLUMN `c` INT AFTER `d`",
+		"MODIFY COLUMN `b` INT AFTER `c`",
+		"MODIFY COLUMN `a` INT AFTER `b`",
+	}, ",\n  ") + ";"
+	statements := NewStatementGenerator().GenerateAlterStatements(tableDiff)
+	if len(statements) != 1 || statements[0] != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%v", expected, statements)
+	}
+}
+
+func TestColumnReorderPlacesAddedColumns(t *testing.T) {
+	tests := []struct {
+		old      string
+		new      string
+		expected []string
+	}{
+		{"CREATE TABLE t (id INT, a INT, b INT);", "CREATE TABLE t (id INT, b INT, n INT, a INT);",
+			[]string{"ADD COLUMN `n` INT AFTER `b`", "MODIFY COLUMN `a` INT AFTER `n`"}},
+		{"CREATE TABLE t (id INT, a INT);", "CREATE TABLE t (n INT, id INT, a INT);",
+			[]string{"ADD COLUMN `n` INT FIRST"}},
+		{"CREATE TABLE t (id INT, a INT, b INT, c INT);", "CREATE TABLE t (id INT, c INT, n INT, a INT, b INT);",
+			[]string{"MODIFY COLUMN `c` INT AFTER `id`", "ADD COLUMN `n` INT AFTER `c`"}},
+	}
+
+	for _, tt := range tests {
+		oldTables, err := parser.ParseSQLDump(tt.old)
+		if err != nil {
+			t.Fatalf("Failed to parse old schema: %v", err)
+		}
+		newTables, err := parser.ParseSQLDump(tt.new)
+		if err != nil {
+			t.Fatalf("Failed to parse new schema: %v", err)
+		}
+
+		analyzer := diff.NewTableDiffAnalyzer()
+		analyzer.DetectColumnReorder = true
+		tableDiff := analyzer.CompareTables(oldTables[0], newTables[0])
+
+		expected := "ALTER TABLE `t`\n  " + strings.Join(tt.expected, ",\n  ") + ";"
+		if statements := NewStatementGenerator().GenerateAlterStatements(tableDiff); len(statements) != 1 || statements[0] != expected {
+			t.Errorf("%s -> %s: expected:\n%s\ngot:\n%v", tt.old, tt.new, expected, statements)
+		}
+	}
+
+	// Without reorder detection added columns are appended
+	oldTables, _ := parser.ParseSQLDump("CREATE TABLE t (id INT, a INT);")
+	newTables, _ := parser.ParseSQLDump("CREATE TABLE t (id INT, n INT, a INT);")
+	statements := NewStatementGenerator().GenerateAlterStatements(diff.NewTableDiffAnalyzer().CompareTables(oldTables[0], newTables[0]))
+	if len(statements) != 1 || strings.Contains(statements[0], "AFTER") {
+		t.Errorf("Expected the column to be appended, got %v", statements)
+	}
+}
@@ -0,0 +1,122 @@
+package alter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/n0madic/mysql-diff/pkg/diff"
+)
+
+// Impact estimates how much of a table a generated statement touches. Without a database the row
+// count is unknown, so it only tells metadata changes apart from statements that read or copy rows.
+type Impact string
+
+const (
+	// ImpactMetadata changes only the table definition, like renames, comments and defaults
+	ImpactMetadata Impact = "metadata-only"
+	// ImpactInPlace reads or rebuilds the rows without copying the table, like adding an index
+	ImpactInPlace Impact = "in-place"
+	// ImpactRewrite copies every row of the table, like column type or engine changes. These are
+	// the statements that --online marks with ALGORITHM=COPY.
+	ImpactRewrite Impact = "rewrite"
+)
+
+// StatementImpact is a generated statement with its impact; comments have an empty impact
+type StatementImpact struct {
+	SQL    string
+	Impact Impact
+}
+
+// alterClausesImpact returns the impact of the combined ALTER statement of a table diff
+func alterClausesImpact(tableDiff *diff.TableDiff) Impact {
+	if requiresCopyAlgorithm(tableDiff) {
+		return ImpactRewrite
+	}
+
+	if tableDiff.PrimaryKeyDiff != nil {
+		return ImpactInPlace
+	}
+	for _, colDiff := range tableDiff.ColumnDiffs {
+		if colDiff.ChangeType != diff.ChangeTypeModified || !isMetadataOnlyColumnChange(colDiff) {
+			return ImpactInPlace
+		}
+	}
+	// New and recreated indexes are built from the rows and new foreign keys are checked against
+	// them; dropping either one only changes the definition
+	for _, idxDiff := range tableDiff.IndexDiffs {
+		if idxDiff.ChangeType != diff.ChangeTypeRemoved {
+			return ImpactInPlace
+		}
+	}
+	for _, fkDiff := range tableDiff.ForeignKeyDiffs {
+		if fkDiff.ChangeType != diff.ChangeTypeRemoved {
+			return ImpactInPlace
+		}
+	}
+	return ImpactMetadata
+}
+
+// isMetadataOnlyColumnChange reports whether a modified column only changes its name, comment,
+// default or visibility, or appends ENUM/SET values
+func isMetadataOnlyColumnChange(colDiff diff.ColumnDiff) bool {
+	if colDiff.Changes == nil {
+		return true
+	}
+	others := *colDiff.Changes
+	others.Name, others.Comment, others.DefaultValue, others.Visible = nil, nil, nil, nil
+	if others.EnumValues != nil && valuesAppended(colDiff) {
+		others.EnumValues = nil
+	}
+	return !others.HasChanges()
+}
+
+// tableOptionsImpact returns the impact of the table options statement of a table diff. Changing
+// the engine, row format, key block size, tablespace or encryption copies the table; a new default
+// character set only applies to columns added later.
+func tableOptionsImpact(optionsDiff *diff.TableOptionsDiff) Impact {
+	if changes := optionsDiff.Changes; changes != nil && optionsDiff.ChangeType == diff.ChangeTypeModified {
+		if changes.Engine != nil || changes.RowFormat != nil || changes.KeyBlockSize != nil ||
+			changes.Tablespace != nil || changes.Encryption != nil {
+			return ImpactRewrite
+		}
+		return ImpactMetadata
+	}
+
+	opts := optionsDiff.NewOptions
+	if opts != nil && (opts.Engine != nil || opts.RowFormat != nil || opts.KeyBlockSize != nil ||
+		opts.Tablespace != nil || opts.Encryption != nil) {
+		return ImpactRewrite
+	}
+	return ImpactMetadata
+}
+
+// SummarizeImpact describes how many of the statements rewrite a table, run in place or change
+// only metadata, such as "3 statements will rewrite the table, 1 runs in place"
+func SummarizeImpact(statements []StatementImpact) string {
+	counts := make(map[Impact]int)
+	for _, statement := range statements {
+		counts[statement.Impact]++
+	}
+
+	plural := func(count int, singular, plural string) string {
+		if count == 1 {
+			return fmt.Sprintf("%d statement %s", count, singular)
+		}
+		return fmt.Sprintf("%d statements %s", count, plural)
+	}
+
+	var parts []string
+	if count := counts[ImpactRewrite]; count > 0 {
+		parts = append(parts, plural(count, "will rewrite the table", "will rewrite the table"))
+	}
+	if count := counts[ImpactInPlace]; count > 0 {
+		parts = append(parts, plural(count, "runs in place", "run in place"))
+	}
+	if count := counts[ImpactMetadata]; count > 0 {
+		parts = append(parts, plural(count, "changes only metadata", "change only metadata"))
+	}
+	if len(parts) == 0 {
+		return "No statements to apply"
+	}
+	return strings.Join(parts, ", ")
+}
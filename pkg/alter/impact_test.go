@@ -0,0 +1,83 @@
+package alter
+
+import (
+	"testing"
+
+	"github.com/n0madic/mysql-diff/pkg/diff"
+	"github.com/n0madic/mysql-diff/pkg/parser"
+)
+
+func TestStatementImpact(t *testing.T) {
+	const oldSQL = "CREATE TABLE users (id INT NOT NULL, name VARCHAR(50) COMMENT 'name', status ENUM('a','b'), KEY idx_name (name)) ENGINE=InnoDB"
+
+	tests := []struct {
+		name   string
+		newSQL string
+		impact []Impact
+	}{
+		{"comment and default", "CREATE TABLE users (id INT NOT NULL, name VARCHAR(50) DEFAULT 'x' COMMENT 'full name', status ENUM('a','b'), KEY idx_name (name)) ENGINE=InnoDB",
+			[]Impact{ImpactMetadata}},
+		{"column rename", "CREATE TABLE users (id INT NOT NULL, name VARCHAR(50) COMMENT 'name', state ENUM('a','b'), KEY idx_name (name)) ENGINE=InnoDB",
+			[]Impact{ImpactMetadata}},
+		{"appended enum value", "CREATE TABLE users (id INT NOT NULL, name VARCHAR(50) COMMENT 'name', status ENUM('a','b','c'), KEY idx_name (name)) ENGINE=InnoDB",
+			[]Impact{ImpactMetadata}},
+		{"dropped index", "CREATE TABLE users (id INT NOT NULL, name VARCHAR(50) COMMENT 'name', status ENUM('a','b')) ENGINE=InnoDB",
+			[]Impact{ImpactMetadata}},
+		{"added index", "CREATE TABLE users (id INT NOT NULL, name VARCHAR(50) COMMENT 'name', status ENUM('a','b'), KEY idx_name (name), KEY idx_status (status)) ENGINE=InnoDB",
+			[]Impact{ImpactInPlace}},
+		{"added column", "CREATE TABLE users (id INT NOT NULL, name VARCHAR(50) COMMENT 'name', status ENUM('a','b'), age INT, KEY idx_name (name)) ENGINE=InnoDB",
+			[]Impact{ImpactInPlace}},
+		{"type change", "CREATE TABLE users (id BIGINT NOT NULL, name VARCHAR(50) COMMENT 'name', status ENUM('a','b'), KEY idx_name (name)) ENGINE=InnoDB",
+			[]Impact{ImpactRewrite}},
+		{"charset conversion", "CREATE TABLE users (id INT NOT NULL, name VARCHAR(50) CHARACTER SET latin1 COMMENT 'name', status ENUM('a','b'), KEY idx_name (name)) ENGINE=InnoDB",
+			[]Impact{ImpactRewrite}},
+		{"engine change", "CREATE TABLE users (id INT NOT NULL, name VARCHAR(50) COMMENT 'name', status ENUM('a','b'), KEY idx_name (name)) ENGINE=MyISAM",
+			[]Impact{"", ImpactRewrite}},
+		{"default charset", "CREATE TABLE users (id INT NOT NULL, name VARCHAR(50) COMMENT 'name', status ENUM('a','b'), KEY idx_name (name)) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+			[]Impact{ImpactMetadata}},
+		{"table rename", "CREATE TABLE accounts (id INT NOT NULL, name VARCHAR(50) COMMENT 'name', status ENUM('a','b'), KEY idx_name (name)) ENGINE=InnoDB",
+			[]Impact{ImpactMetadata}},
+	}
+
+	oldTables, err := parser.ParseSQLDump(oldSQL)
+	if err != nil {
+		t.Fatalf("Failed to parse old schema: %v", err)
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			newTables, err := parser.ParseSQLDump(test.newSQL)
+			if err != nil {
+				t.Fatalf("Failed to parse new schema: %v", err)
+			}
+			analyzer := diff.NewTableDiffAnalyzer()
+			analyzer.ColumnRenames = map[string]map[string]string{"users": {"status": "state"}}
+
+			statements := NewStatementGenerator().GenerateAlterStatementsWithImpact(analyzer.CompareTables(oldTables[0], newTables[0]))
+			if len(statements) != len(test.impact) {
+				t.Fatalf("Expected %d statements, got %v", len(test.impact), statements)
+			}
+			for i, statement := range statements {
+				if statement.Impact != test.impact[i] {
+					t.Errorf("Expected impact %q for %s, got %q", test.impact[i], statement.SQL, statement.Impact)
+				}
+			}
+		})
+	}
+}
+
+func TestSummarizeImpact(t *testing.T) {
+	statements := []StatementImpact{
+		{SQL: "ALTER TABLE `a` MODIFY COLUMN `id` BIGINT;", Impact: ImpactRewrite},
+		{SQL: "-- Warning: `a` engine change"},
+		{SQL: "ALTER TABLE `a` ENGINE=InnoDB;", Impact: ImpactRewrite},
+		{SQL: "ALTER TABLE `b` ADD INDEX `k` (`n`);", Impact: ImpactInPlace},
+		{SQL: "ALTER TABLE `c` COMMENT='x';", Impact: ImpactMetadata},
+	}
+	expected := "2 statements will rewrite the table, 1 statement runs in place, 1 statement changes only metadata"
+	if summary := SummarizeImpact(statements); summary != expected {
+		t.Errorf("Expected %q, got %q", expected, summary)
+	}
+	if summary := SummarizeImpact(nil); summary != "No statements to apply" {
+		t.Errorf("Expected no statements, got %q", summary)
+	}
+}
@@ -0,0 +1,324 @@
+package alter
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/n0madic/mysql-diff/pkg/diff"
+	"github.com/n0madic/mysql-diff/pkg/parser"
+)
+
+// migrationFilePattern matches sequence-numbered migration files like 0001_up.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(up|down)\.sql$`)
+
+// golangMigrateFilePattern matches golang-migrate files like 000001_add_users.up.sql
+var golangMigrateFilePattern = regexp.MustCompile(`^(\d+)_(.*)\.(up|down)\.sql$`)
+
+// flywayFilePattern matches Flyway versioned and undo migrations like V3__add_users.sql
+var flywayFilePattern = regexp.MustCompile(`^[VU](\d+)__(.*)\.sql$`)
+
+// atlasVersionFormat is the timestamp layout of Atlas migration versions
+const atlasVersionFormat = "20060102150405"
+
+// atlasSumFile is the name of the integrity file of an Atlas migration directory
+const atlasSumFile = "atlas.sum"
+
+// migrationNameReplacer matches the characters of a migration name that are not kept in file names
+var migrationNameReplacer = regexp.MustCompile(`[^a-z0-9]+`)
+
+// GenerateMigration generates forward (up) and rollback (down) statements migrating oldTables to
+// newTables, with tables matched by name and compared by a default analyzer
+func (g *StatementGenerator) GenerateMigration(oldTables, newTables []*parser.CreateTableStatement) (up []string, down []string) {
+	return g.GenerateMigrationWith(diff.NewTableDiffAnalyzer(), MatchTablesByName(oldTables, newTables))
+}
+
+// GenerateMigrationWith generates forward (up) and rollback (down) statements for matched tables,
+// comparing the tables present in both schemas with analyzer. Up creates the added tables, alters
+// the changed ones and then drops the removed ones, so foreign keys added by the alters find their
+// tables and the removed tables are no longer referenced when they are dropped; down undoes these
+// steps in reverse order. Added tables are created after the tables they reference and removed
+// tables dropped before them. With AdditiveOnly, removed tables are kept.
+func (g *StatementGenerator) GenerateMigrationWith(analyzer *diff.TableDiffAnalyzer, tableMatches map[string]struct {
+	Old *parser.CreateTableStatement
+	New *parser.CreateTableStatement
+}) (up []string, down []string) {
+	var added, removed []*parser.CreateTableStatement
+	var alters, rollbacks []string
+
+	for _, tableName := range slices.Sorted(maps.Keys(tableMatches)) {
+		match := tableMatches[tableName]
+		switch {
+		case match.Old == nil && match.New != nil:
+			added = append(added, match.New)
+		case match.Old != nil && match.New == nil:
+			if !g.AdditiveOnly {
+				removed = append(removed, match.Old)
+			}
+		case match.Old != nil && match.New != nil:
+			tableDiff := analyzer.CompareTables(match.Old, match.New)
+			forward, _ := splitComments(g.GenerateAlterStatements(tableDiff))
+			rollback, _ := splitComments(g.GenerateRollbackStatements(tableDiff))
+			alters = append(alters, forward...)
+			rollbacks = append(rollbacks, rollback...)
+		}
+	}
+
+	var creates, undoCreates, drops, recreates []string
+	for _, table := range sortByDependencies(added) {
+		creates = append(creates, g.GenerateCreateTable(table))
+		undoCreates = slices.Insert(undoCreates, 0, dropTableStatement(table.TableName))
+	}
+	for _, table := range sortByDependencies(removed) {
+		recreates = append(recreates, g.GenerateCreateTable(table))
+		drops = slices.Insert(drops, 0, dropTableStatement(table.TableName))
+	}
+
+	return slices.Concat([]string{}, creates, alters, drops), slices.Concat([]string{}, recreates, rollbacks, undoCreates)
+}
+
+// SchemaVersion is a named snapshot of a schema
+type SchemaVersion struct {
+	Name   string
+	Tables []*parser.CreateTableStatement
+}
+
+// MigrationStep holds the statements that migrate one schema version to the next
+type MigrationStep struct {
+	From       string
+	To         string
+	Statements []string
+}
+
+// TableMatcher pairs the tables of two schema versions, like MatchTablesWithRenames
+type TableMatcher func(oldTables, newTables []*parser.CreateTableStatement) map[string]struct {
+	Old *parser.CreateTableStatement
+	New *parser.CreateTableStatement
+}
+
+// GenerateMigrationSequence generates the forward migration between each consecutive
+// pair of schema versions, in the given order
+func (g *StatementGenerator) GenerateMigrationSequence(versions []SchemaVersion) []MigrationStep {
+	return g.GenerateMigrationSequenceWith(diff.NewTableDiffAnalyzer(), MatchTablesByName, versions)
+}
+
+// GenerateMigrationSequenceWith generates the forward migration between each consecutive pair of
+// schema versions like GenerateMigrationWith, matching the tables of each pair with match
+func (g *StatementGenerator) GenerateMigrationSequenceWith(analyzer *diff.TableDiffAnalyzer, match TableMatcher, versions []SchemaVersion) []MigrationStep {
+	var steps []MigrationStep
+
+	for i := 1; i < len(versions); i++ {
+		up, _ := g.GenerateMigrationWith(analyzer, match(versions[i-1].Tables, versions[i].Tables))
+		steps = append(steps, MigrationStep{
+			From:       versions[i-1].Name,
+			To:         versions[i].Name,
+			Statements: up,
+		})
+	}
+
+	return steps
+}
+
+// FormatMigrationSequence concatenates the statements of all steps, each preceded by a section header
+func FormatMigrationSequence(steps []MigrationStep) string {
+	var sb strings.Builder
+
+	for i, step := range steps {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fmt.Sprintf("-- Step %d: %s -> %s\n", i+1, step.From, step.To))
+		if len(step.Statements) == 0 {
+			sb.WriteString("-- No changes\n")
+			continue
+		}
+		for _, statement := range step.Statements {
+			sb.WriteString(statement + "\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// NextMigrationSequence returns the next free sequence number for migration files in dir
+func NextMigrationSequence(dir string) (int, error) {
+	return nextSequence(dir, migrationFilePattern)
+}
+
+// nextSequence returns one more than the highest sequence number of the files in dir whose
+// names match pattern, with the number as its first submatch
+func nextSequence(dir string, pattern *regexp.Regexp) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 1, nil
+		}
+		return 0, err
+	}
+
+	highest := 0
+	for _, entry := range entries {
+		matches := pattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			continue
+		}
+		if seq, err := strconv.Atoi(matches[1]); err == nil && seq > highest {
+			highest = seq
+		}
+	}
+
+	return highest + 1, nil
+}
+
+// WriteMigrationFiles writes up and down statements to NNNN_up.sql and NNNN_down.sql files in dir,
+// using the next free sequence number, and returns the paths of the written files
+func WriteMigrationFiles(dir string, up, down []string) (upPath string, downPath string, err error) {
+	return writeMigrationPair(dir, migrationFilePattern, func(seq int) (string, string) {
+		return fmt.Sprintf("%04d_up.sql", seq), fmt.Sprintf("%04d_down.sql", seq)
+	}, up, down)
+}
+
+// WriteGolangMigrateFiles writes up and down statements in the layout of golang-migrate's sequential
+// migrations, NNNNNN_name.up.sql and NNNNNN_name.down.sql, using the next free version in dir.
+// The name is lowercased and runs of other characters than letters and digits become underscores.
+func WriteGolangMigrateFiles(dir, name string, up, down []string) (upPath string, downPath string, err error) {
+	title, err := migrationTitle(name)
+	if err != nil {
+		return "", "", err
+	}
+
+	return writeMigrationPair(dir, golangMigrateFilePattern, func(version int) (string, string) {
+		return fmt.Sprintf("%06d_%s.up.sql", version, title), fmt.Sprintf("%06d_%s.down.sql", version, title)
+	}, up, down)
+}
+
+// WriteFlywayFiles writes up statements to a Flyway versioned migration V<version>__name.sql and down
+// statements to the matching undo migration U<version>__name.sql, using the next free version in dir.
+// The name is normalized as for WriteGolangMigrateFiles; Flyway shows its underscores as spaces.
+func WriteFlywayFiles(dir, name string, up, down []string) (upPath string, undoPath string, err error) {
+	title, err := migrationTitle(name)
+	if err != nil {
+		return "", "", err
+	}
+
+	return writeMigrationPair(dir, flywayFilePattern, func(version int) (string, string) {
+		return fmt.Sprintf("V%d__%s.sql", version, title), fmt.Sprintf("U%d__%s.sql", version, title)
+	}, up, down)
+}
+
+// WriteAtlasFiles writes up statements to an Atlas migration file <version>_name.sql, with the version
+// taken from the given time, and rewrites the atlas.sum integrity file of dir to cover it. Atlas
+// computes down migrations itself, so no rollback file is written.
+func WriteAtlasFiles(dir, name string, version time.Time, up []string) (path string, err error) {
+	title, err := migrationTitle(name)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create migration directory: %w", err)
+	}
+
+	path = filepath.Join(dir, fmt.Sprintf("%s_%s.sql", version.UTC().Format(atlasVersionFormat), title))
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("migration file %s already exists", path)
+	}
+	if err := os.WriteFile(path, []byte(formatMigrationFile(up)), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	sum, err := atlasSum(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read migration directory: %w", err)
+	}
+	sumPath := filepath.Join(dir, atlasSumFile)
+	if err := os.WriteFile(sumPath, []byte(sum), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", sumPath, err)
+	}
+
+	return path, nil
+}
+
+// atlasSum computes the content of atlas.sum for the .sql files in dir like Atlas's HashFile: one
+// line per file, in name order, with the running hash of the names and contents up to that file,
+// preceded by the sum of the directory, a hash over every file name and file hash pair
+func atlasSum(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	sum := sha256.New()
+	var lines strings.Builder
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return "", err
+		}
+		hash.Write([]byte(entry.Name()))
+		hash.Write(content)
+		fileHash := base64.StdEncoding.EncodeToString(hash.Sum(nil))
+
+		sum.Write([]byte(entry.Name()))
+		sum.Write([]byte(fileHash))
+		fmt.Fprintf(&lines, "%s h1:%s\n", entry.Name(), fileHash)
+	}
+
+	return fmt.Sprintf("h1:%s\n%s", base64.StdEncoding.EncodeToString(sum.Sum(nil)), lines.String()), nil
+}
+
+// migrationTitle lowercases a migration name for use in file names, replacing runs of characters
+// other than letters and digits with underscores
+func migrationTitle(name string) (string, error) {
+	title := strings.Trim(migrationNameReplacer.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if title == "" {
+		return "", fmt.Errorf("invalid migration name %q", name)
+	}
+	return title, nil
+}
+
+// writeMigrationPair writes up and down statements to the files named by fileNames for the next
+// free sequence number among the files in dir that match pattern
+func writeMigrationPair(dir string, pattern *regexp.Regexp, fileNames func(seq int) (up, down string), up, down []string) (upPath string, downPath string, err error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", "", fmt.Errorf("failed to create migration directory: %w", err)
+	}
+
+	seq, err := nextSequence(dir, pattern)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read migration directory: %w", err)
+	}
+
+	upName, downName := fileNames(seq)
+	upPath = filepath.Join(dir, upName)
+	downPath = filepath.Join(dir, downName)
+
+	if err := os.WriteFile(upPath, []byte(formatMigrationFile(up)), 0o644); err != nil {
+		return "", "", fmt.Errorf("failed to write %s: %w", upPath, err)
+	}
+	if err := os.WriteFile(downPath, []byte(formatMigrationFile(down)), 0o644); err != nil {
+		return "", "", fmt.Errorf("failed to write %s: %w", downPath, err)
+	}
+
+	return upPath, downPath, nil
+}
+
+// formatMigrationFile joins statements into file content
+func formatMigrationFile(statements []string) string {
+	if len(statements) == 0 {
+		return ""
+	}
+	return strings.Join(statements, "\n\n") + "\n"
+}
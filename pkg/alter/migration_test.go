@@ -0,0 +1,360 @@
+package alter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/n0madic/mysql-diff/pkg/diff"
+	"github.com/n0madic/mysql-diff/pkg/parser"
+)
+
+func TestGenerateMigration_WritesUpAndDownFiles(t *testing.T) {
+	oldSQL := `CREATE TABLE users (id INT NOT NULL, name VARCHAR(50), PRIMARY KEY (id));`
+	newSQL := `CREATE TABLE users (id INT NOT NULL, name VARCHAR(50), email VARCHAR(100), PRIMARY KEY (id));`
+
+	oldTables, err := parser.ParseSQLDump(oldSQL)
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(newSQL)
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	generator := NewStatementGenerator()
+	up, down := generator.GenerateMigration(oldTables, newTables)
+
+	dir := filepath.Join(t.TempDir(), "migrations")
+	upPath, downPath, err := WriteMigrationFiles(dir, up, down)
+	if err != nil {
+		t.Fatalf("Failed to write migration files: %v", err)
+	}
+
+	if filepath.Base(upPath) != "0001_up.sql" {
+		t.Errorf("Expected up file 0001_up.sql, got %s", filepath.Base(upPath))
+	}
+	if filepath.Base(downPath) != "0001_down.sql" {
+		t.Errorf("Expected down file 0001_down.sql, got %s", filepath.Base(downPath))
+	}
+
+	upContent, err := os.ReadFile(upPath)
+	if err != nil {
+		t.Fatalf("Failed to read up file: %v", err)
+	}
+	downContent, err := os.ReadFile(downPath)
+	if err != nil {
+		t.Fatalf("Failed to read down file: %v", err)
+	}
+
+	if !strings.Contains(string(upContent), "ADD COLUMN `email` VARCHAR(100)") {
+		t.Errorf("Expected up migration to add email column, got: %s", upContent)
+	}
+	if !strings.Contains(string(downContent), "DROP COLUMN `email`") {
+		t.Errorf("Expected down migration to drop email column, got: %s", downContent)
+	}
+}
+
+func TestGenerateMigrationWith_AnalyzerAndMatches(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump(`CREATE TABLE users (id INT, fname VARCHAR(50)) AUTO_INCREMENT=10;
+CREATE TABLE logs (id INT);`)
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(`CREATE TABLE accounts (id INT, first_name VARCHAR(50)) AUTO_INCREMENT=20;
+CREATE TABLE orders (id INT);`)
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	analyzer := diff.NewTableDiffAnalyzer()
+	analyzer.ColumnRenames = map[string]map[string]string{"users": {"fname": "first_name"}}
+	analyzer.IgnoreAutoIncrement = true
+	matches := MatchTablesWithRenames(oldTables, newTables, map[string]string{"users": "accounts"})
+
+	up, down := NewStatementGenerator().GenerateMigrationWith(analyzer, matches)
+	expectedUp := []string{
+		"CREATE TABLE `orders` (\n  `id` INT\n);",
+		"ALTER TABLE `users` RENAME TO `accounts`;",
+		"ALTER TABLE `accounts`\n  CHANGE COLUMN `fname` `first_name` VARCHAR(50);",
+		"DROP TABLE IF EXISTS `logs`;",
+	}
+	if !slices.Equal(up, expectedUp) {
+		t.Errorf("Expected up %q, got %q", expectedUp, up)
+	}
+	expectedDown := []string{
+		"CREATE TABLE `logs` (\n  `id` INT\n);",
+		"ALTER TABLE `accounts` RENAME TO `users`;",
+		"ALTER TABLE `users`\n  CHANGE COLUMN `first_name` `fname` VARCHAR(50);",
+		"DROP TABLE IF EXISTS `orders`;",
+	}
+	if !slices.Equal(down, expectedDown) {
+		t.Errorf("Expected down %q, got %q", expectedDown, down)
+	}
+
+	generator := NewStatementGenerator()
+	generator.AdditiveOnly = true
+	if up, _ := generator.GenerateMigrationWith(analyzer, matches); slices.Contains(up, "DROP TABLE IF EXISTS `logs`;") {
+		t.Errorf("Expected --additive-only to keep removed tables, got %q", up)
+	}
+}
+
+func TestGenerateMigrationWith_ForeignKeyOrder(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump(`CREATE TABLE a (id INT, b_id INT);
+CREATE TABLE y (id INT, PRIMARY KEY (id));
+CREATE TABLE z (id INT, y_id INT, CONSTRAINT fk_z FOREIGN KEY (y_id) REFERENCES y (id));
+CREATE TABLE zz (id INT, y_id INT, CONSTRAINT fk_zz FOREIGN KEY (y_id) REFERENCES y (id));`)
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(`CREATE TABLE a (id INT, b_id INT, CONSTRAINT fk_b FOREIGN KEY (b_id) REFERENCES b (id));
+CREATE TABLE b (id INT, c_id INT, PRIMARY KEY (id), CONSTRAINT fk_c FOREIGN KEY (c_id) REFERENCES c (id));
+CREATE TABLE c (id INT, PRIMARY KEY (id));
+CREATE TABLE z (id INT, y_id INT);`)
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	// Each statement may only reference tables that exist at that point
+	up, down := NewStatementGenerator().GenerateMigration(oldTables, newTables)
+	expectedUp := []string{"CREATE TABLE `c`", "CREATE TABLE `b`", "ALTER TABLE `a`", "ALTER TABLE `z`", "DROP TABLE IF EXISTS `zz`", "DROP TABLE IF EXISTS `y`"}
+	expectedDown := []string{"CREATE TABLE `y`", "CREATE TABLE `zz`", "ALTER TABLE `a`", "ALTER TABLE `z`", "DROP TABLE IF EXISTS `b`", "DROP TABLE IF EXISTS `c`"}
+	for _, tt := range []struct {
+		name       string
+		statements []string
+		expected   []string
+	}{{"up", up, expectedUp}, {"down", down, expectedDown}} {
+		if len(tt.statements) != len(tt.expected) {
+			t.Fatalf("Expected %d %s statements, got %q", len(tt.expected), tt.name, tt.statements)
+		}
+		for i, prefix := range tt.expected {
+			if !strings.HasPrefix(tt.statements[i], prefix) {
+				t.Errorf("Expected %s statement %d to start with %q, got %q", tt.name, i+1, prefix, tt.statements[i])
+			}
+		}
+	}
+}
+
+func TestWriteMigrationFiles_NextSequence(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"0001_up.sql", "0001_down.sql", "0007_up.sql", "0007_down.sql", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	upPath, downPath, err := WriteMigrationFiles(dir, []string{"ALTER TABLE `t` ADD COLUMN `c` INT;"}, []string{"ALTER TABLE `t` DROP COLUMN `c`;"})
+	if err != nil {
+		t.Fatalf("Failed to write migration files: %v", err)
+	}
+
+	if filepath.Base(upPath) != "0008_up.sql" || filepath.Base(downPath) != "0008_down.sql" {
+		t.Errorf("Expected sequence 0008, got %s and %s", filepath.Base(upPath), filepath.Base(downPath))
+	}
+}
+
+func TestWriteGolangMigrateFiles_Naming(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"000001_create_users.up.sql", "000001_create_users.down.sql", "000003_add_orders.up.sql", "0009_up.sql"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	up := []string{"ALTER TABLE `t` ADD COLUMN `c` INT;"}
+	down := []string{"ALTER TABLE `t` DROP COLUMN `c`;"}
+	upPath, downPath, err := WriteGolangMigrateFiles(dir, "Add column C to t", up, down)
+	if err != nil {
+		t.Fatalf("Failed to write migration files: %v", err)
+	}
+
+	// golang-migrate parses {version}_{title}.{up|down}.sql; the NNNN_up.sql files are not counted
+	if filepath.Base(upPath) != "000004_add_column_c_to_t.up.sql" || filepath.Base(downPath) != "000004_add_column_c_to_t.down.sql" {
+		t.Errorf("Expected version 000004 files, got %s and %s", filepath.Base(upPath), filepath.Base(downPath))
+	}
+	for _, path := range []string{upPath, downPath} {
+		if !golangMigrateFilePattern.MatchString(filepath.Base(path)) {
+			t.Errorf("Expected %s to match the golang-migrate naming", filepath.Base(path))
+		}
+	}
+
+	upContent, err := os.ReadFile(upPath)
+	if err != nil {
+		t.Fatalf("Failed to read up file: %v", err)
+	}
+	if string(upContent) != up[0]+"\n" {
+		t.Errorf("Expected up file to contain the forward statement, got: %s", upContent)
+	}
+	downContent, err := os.ReadFile(downPath)
+	if err != nil {
+		t.Fatalf("Failed to read down file: %v", err)
+	}
+	if string(downContent) != down[0]+"\n" {
+		t.Errorf("Expected down file to contain the rollback statement, got: %s", downContent)
+	}
+
+	if _, _, err := WriteGolangMigrateFiles(dir, "--", up, down); err == nil {
+		t.Error("Expected an error for a name without letters or digits")
+	}
+}
+
+func TestWriteFlywayFiles_Naming(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"V1__init.sql", "V2__add_users.sql", "U2__add_users.sql", "000005_other.up.sql"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	upPath, undoPath, err := WriteFlywayFiles(dir, "Add orders", []string{"ALTER TABLE `t` ADD COLUMN `c` INT;"}, []string{"ALTER TABLE `t` DROP COLUMN `c`;"})
+	if err != nil {
+		t.Fatalf("Failed to write migration files: %v", err)
+	}
+
+	// Flyway expects V<version>__<description>.sql and U<version>__<description>.sql for undo
+	if filepath.Base(upPath) != "V3__add_orders.sql" || filepath.Base(undoPath) != "U3__add_orders.sql" {
+		t.Errorf("Expected version 3 files, got %s and %s", filepath.Base(upPath), filepath.Base(undoPath))
+	}
+
+	content, err := os.ReadFile(undoPath)
+	if err != nil {
+		t.Fatalf("Failed to read undo file: %v", err)
+	}
+	if string(content) != "ALTER TABLE `t` DROP COLUMN `c`;\n" {
+		t.Errorf("Expected undo file to contain the rollback statement, got: %s", content)
+	}
+}
+
+func TestWriteAtlasFiles_NamingAndSum(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "20240101000000_init.sql"), []byte("CREATE TABLE `t` (\n  `id` INT\n);\n"), 0o644); err != nil {
+		t.Fatalf("Failed to create initial migration: %v", err)
+	}
+
+	version := time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)
+	path, err := WriteAtlasFiles(dir, "Add column c", version, []string{"ALTER TABLE `t` ADD COLUMN `c` INT;"})
+	if err != nil {
+		t.Fatalf("Failed to write migration files: %v", err)
+	}
+	if filepath.Base(path) != "20240315103000_add_column_c.sql" {
+		t.Errorf("Expected Atlas timestamp naming, got %s", filepath.Base(path))
+	}
+
+	sum, err := os.ReadFile(filepath.Join(dir, "atlas.sum"))
+	if err != nil {
+		t.Fatalf("Failed to read atlas.sum: %v", err)
+	}
+
+	// Golden atlas.sum computed with the algorithm of Atlas's migrate.HashFile: each file with the
+	// running hash of all names and contents up to it, after the hash of the name and hash pairs
+	expected := `h1:iVtcpV4v9tn+tzC757jfSg1uqf+bp5qGYAXiIV+WRF0=
+20240101000000_init.sql h1:M7tS0g974F5zh2e6OC3p7IFMC4xJYX0CJquqo2GEgc4=
+20240315103000_add_column_c.sql h1:VzGIkQsmPcoP9+CvQmV/yqLzdQVctbTGgeB62jXr7pU=
+`
+	if string(sum) != expected {
+		t.Errorf("Expected atlas.sum:\n%s\ngot:\n%s", expected, sum)
+	}
+
+	if _, err := WriteAtlasFiles(dir, "Add column c", version, nil); err == nil {
+		t.Error("Expected an error when the migration file already exists")
+	}
+}
+
+func TestGenerateMigrationSequence_ThreeVersions(t *testing.T) {
+	schemas := []string{
+		`CREATE TABLE users (id INT NOT NULL, PRIMARY KEY (id));`,
+		`CREATE TABLE users (id INT NOT NULL, name VARCHAR(50), PRIMARY KEY (id));`,
+		`CREATE TABLE users (id INT NOT NULL, name VARCHAR(50), email VARCHAR(100), PRIMARY KEY (id), INDEX idx_email (email));`,
+	}
+
+	var versions []SchemaVersion
+	for i, sql := range schemas {
+		tables, err := parser.ParseSQLDump(sql)
+		if err != nil {
+			t.Fatalf("Failed to parse schema %d: %v", i+1, err)
+		}
+		versions = append(versions, SchemaVersion{Name: fmt.Sprintf("v%d.sql", i+1), Tables: tables})
+	}
+
+	generator := NewStatementGenerator()
+	steps := generator.GenerateMigrationSequence(versions)
+
+	if len(steps) != 2 {
+		t.Fatalf("Expected 2 migration steps, got %d", len(steps))
+	}
+	if steps[0].From != "v1.sql" || steps[0].To != "v2.sql" {
+		t.Errorf("Unexpected first step: %s -> %s", steps[0].From, steps[0].To)
+	}
+	if steps[1].From != "v2.sql" || steps[1].To != "v3.sql" {
+		t.Errorf("Unexpected second step: %s -> %s", steps[1].From, steps[1].To)
+	}
+
+	combined := FormatMigrationSequence(steps)
+
+	step1 := strings.Index(combined, "-- Step 1: v1.sql -> v2.sql")
+	addName := strings.Index(combined, "ADD COLUMN `name` VARCHAR(50)")
+	step2 := strings.Index(combined, "-- Step 2: v2.sql -> v3.sql")
+	addEmail := strings.Index(combined, "ADD COLUMN `email` VARCHAR(100)")
+	addIndex := strings.Index(combined, "ADD INDEX `idx_email`")
+
+	if step1 < 0 || addName < 0 || step2 < 0 || addEmail < 0 || addIndex < 0 {
+		t.Fatalf("Combined migration is missing expected sections or statements:\n%s", combined)
+	}
+	if !(step1 < addName && addName < step2 && step2 < addEmail && step2 < addIndex) {
+		t.Errorf("Statements are not ordered by step:\n%s", combined)
+	}
+	if strings.Count(combined, "ADD COLUMN `name`") != 1 {
+		t.Errorf("Expected name column to be added exactly once:\n%s", combined)
+	}
+}
+
+func TestGenerateMigrationSequenceWith_Renames(t *testing.T) {
+	schemas := []string{
+		`CREATE TABLE users (id INT, fname VARCHAR(50));`,
+		`CREATE TABLE accounts (id INT, first_name VARCHAR(50));`,
+		`CREATE TABLE accounts (id INT, first_name VARCHAR(50)); CREATE TABLE orders (id INT);`,
+	}
+
+	var versions []SchemaVersion
+	for i, sql := range schemas {
+		tables, err := parser.ParseSQLDump(sql)
+		if err != nil {
+			t.Fatalf("Failed to parse schema %d: %v", i+1, err)
+		}
+		versions = append(versions, SchemaVersion{Name: fmt.Sprintf("v%d.sql", i+1), Tables: tables})
+	}
+
+	analyzer := diff.NewTableDiffAnalyzer()
+	analyzer.ColumnRenames = map[string]map[string]string{"users": {"fname": "first_name"}}
+	match := func(oldTables, newTables []*parser.CreateTableStatement) map[string]struct {
+		Old *parser.CreateTableStatement
+		New *parser.CreateTableStatement
+	} {
+		return MatchTablesWithRenames(oldTables, newTables, map[string]string{"users": "accounts"})
+	}
+
+	steps := NewStatementGenerator().GenerateMigrationSequenceWith(analyzer, match, versions)
+	if len(steps) != 2 {
+		t.Fatalf("Expected 2 migration steps, got %d", len(steps))
+	}
+	expected := []string{"ALTER TABLE `users` RENAME TO `accounts`;", "ALTER TABLE `accounts`\n  CHANGE COLUMN `fname` `first_name` VARCHAR(50);"}
+	if !slices.Equal(steps[0].Statements, expected) {
+		t.Errorf("Expected the first step to rename the table and column, got %q", steps[0].Statements)
+	}
+	if expected := []string{"CREATE TABLE `orders` (\n  `id` INT\n);"}; !slices.Equal(steps[1].Statements, expected) {
+		t.Errorf("Expected the second step to create orders, got %q", steps[1].Statements)
+	}
+}
+
+func TestFormatMigrationSequence_EmptyStep(t *testing.T) {
+	combined := FormatMigrationSequence([]MigrationStep{{From: "v1.sql", To: "v2.sql"}})
+
+	expected := "-- Step 1: v1.sql -> v2.sql\n-- No changes\n"
+	if combined != expected {
+		t.Errorf("Expected %q, got %q", expected, combined)
+	}
+}
@@ -0,0 +1,93 @@
+package alter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseTableRenameMap parses a comma-separated list of old:new table renames
+func ParseTableRenameMap(value string) (map[string]string, error) {
+	renames := make(map[string]string)
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		oldName, newName, err := splitRename(entry)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := renames[oldName]; exists {
+			return nil, fmt.Errorf("table %q is renamed more than once", oldName)
+		}
+		renames[oldName] = newName
+	}
+
+	return renames, nil
+}
+
+// ParseColumnRenameMap parses a comma-separated list of table.old:new column renames
+// into a map of table name -> old column name -> new column name
+func ParseColumnRenameMap(value string) (map[string]map[string]string, error) {
+	renames := make(map[string]map[string]string)
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		qualifiedName, newName, err := splitRename(entry)
+		if err != nil {
+			return nil, err
+		}
+		tableName, oldName, found := strings.Cut(qualifiedName, ".")
+		if !found || tableName == "" || oldName == "" {
+			return nil, fmt.Errorf("invalid column rename %q: expected table.old:new", entry)
+		}
+
+		if renames[tableName] == nil {
+			renames[tableName] = make(map[string]string)
+		}
+		if _, exists := renames[tableName][oldName]; exists {
+			return nil, fmt.Errorf("column %q is renamed more than once", qualifiedName)
+		}
+		renames[tableName][oldName] = newName
+	}
+
+	return renames, nil
+}
+
+// InvertTableRenames returns the table renames of ParseTableRenameMap in the other direction
+// (new name -> old name), for comparing the schemas in reverse
+func InvertTableRenames(renames map[string]string) map[string]string {
+	inverted := make(map[string]string, len(renames))
+	for oldName, newName := range renames {
+		inverted[newName] = oldName
+	}
+	return inverted
+}
+
+// InvertColumnRenames returns the column renames of ParseColumnRenameMap in the other direction
+// (table name -> new column name -> old column name), for comparing the schemas in reverse. The
+// analyzer finds the renames of a table under its old or its new name, so the keys are kept.
+func InvertColumnRenames(renames map[string]map[string]string) map[string]map[string]string {
+	inverted := make(map[string]map[string]string, len(renames))
+	for tableName, columns := range renames {
+		inverted[tableName] = InvertTableRenames(columns)
+	}
+	return inverted
+}
+
+// splitRename splits an old:new rename entry
+func splitRename(entry string) (string, string, error) {
+	oldName, newName, found := strings.Cut(entry, ":")
+	oldName = strings.TrimSpace(oldName)
+	newName = strings.TrimSpace(newName)
+	if !found || oldName == "" || newName == "" {
+		return "", "", fmt.Errorf("invalid rename %q: expected old:new", entry)
+	}
+	return oldName, newName, nil
+}
@@ -0,0 +1,234 @@
+package alter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/n0madic/mysql-diff/pkg/diff"
+	"github.com/n0madic/mysql-diff/pkg/parser"
+)
+
+func TestParseTableRenameMap(t *testing.T) {
+	renames, err := ParseTableRenameMap("users:accounts, logs : audit_log,")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(renames) != 2 || renames["users"] != "accounts" || renames["logs"] != "audit_log" {
+		t.Errorf("Unexpected renames: %v", renames)
+	}
+
+	for _, invalid := range []string{"users", "users:", ":accounts", "a:b,a:c"} {
+		if _, err := ParseTableRenameMap(invalid); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestParseColumnRenameMap(t *testing.T) {
+	renames, err := ParseColumnRenameMap("users.fname:first_name,users.lname:last_name,orders.qty:quantity")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if renames["users"]["fname"] != "first_name" || renames["users"]["lname"] != "last_name" || renames["orders"]["qty"] != "quantity" {
+		t.Errorf("Unexpected renames: %v", renames)
+	}
+
+	for _, invalid := range []string{"fname:first_name", ".fname:first_name", "users.:first_name", "users.fname"} {
+		if _, err := ParseColumnRenameMap(invalid); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestInvertRenames(t *testing.T) {
+	tables := InvertTableRenames(map[string]string{"users": "accounts", "logs": "audit_log"})
+	if len(tables) != 2 || tables["accounts"] != "users" || tables["audit_log"] != "logs" {
+		t.Errorf("Unexpected inverted table renames: %v", tables)
+	}
+
+	columns := InvertColumnRenames(map[string]map[string]string{"users": {"fname": "first_name"}})
+	if len(columns) != 1 || len(columns["users"]) != 1 || columns["users"]["first_name"] != "fname" {
+		t.Errorf("Unexpected inverted column renames: %v", columns)
+	}
+}
+
+func TestDeclaredRenamesProduceRenameStatements(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump(`CREATE TABLE users (id INT NOT NULL, fname VARCHAR(50), PRIMARY KEY (id));`)
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(`CREATE TABLE accounts (id INT NOT NULL, first_name VARCHAR(100), PRIMARY KEY (id));`)
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	// Without declared renames the tables are unrelated
+	if match := MatchTablesByName(oldTables, newTables)["accounts"]; match.Old != nil {
+		t.Fatal("Expected accounts to be unmatched without a rename map")
+	}
+
+	matches := MatchTablesWithRenames(oldTables, newTables, map[string]string{"users": "accounts"})
+	if len(matches) != 1 {
+		t.Fatalf("Expected renamed tables to be matched into 1 entry, got %d", len(matches))
+	}
+	match := matches["accounts"]
+	if match.Old == nil || match.New == nil {
+		t.Fatal("Expected users to be matched with accounts")
+	}
+
+	analyzer := diff.NewTableDiffAnalyzer()
+	analyzer.ColumnRenames = map[string]map[string]string{"users": {"fname": "first_name"}}
+	tableDiff := analyzer.CompareTables(match.Old, match.New)
+
+	if tableDiff.ColumnsAdded != 0 || tableDiff.ColumnsRemoved != 0 || tableDiff.ColumnsModified != 1 {
+		t.Fatalf("Expected a single modified column, got +%d -%d ~%d",
+			tableDiff.ColumnsAdded, tableDiff.ColumnsRemoved, tableDiff.ColumnsModified)
+	}
+
+	statements := NewStatementGenerator().GenerateAlterStatements(tableDiff)
+	result := strings.Join(statements, "\n")
+
+	if !strings.Contains(result, "ALTER TABLE `users` RENAME TO `accounts`;") {
+		t.Errorf("Expected table rename, got:\n%s", result)
+	}
+	if !strings.Contains(result, "CHANGE COLUMN `fname` `first_name` VARCHAR(100)") {
+		t.Errorf("Expected CHANGE COLUMN for the renamed column, got:\n%s", result)
+	}
+	if strings.Contains(result, "DROP") || strings.Contains(result, "ADD COLUMN") {
+		t.Errorf("Expected no drop or add for declared renames, got:\n%s", result)
+	}
+}
+
+func TestColumnRenameToMissingColumnIsIgnored(t *testing.T) {
+	oldTable := createTestTable("users", []parser.ColumnDefinition{createTestColumn("fname", "VARCHAR")})
+	newTable := createTestTable("users", []parser.ColumnDefinition{createTestColumn("given_name", "VARCHAR")})
+
+	analyzer := diff.NewTableDiffAnalyzer()
+	analyzer.ColumnRenames = map[string]map[string]string{"users": {"fname": "first_name"}}
+	tableDiff := analyzer.CompareTables(oldTable, newTable)
+
+	if tableDiff.ColumnsAdded != 1 || tableDiff.ColumnsRemoved != 1 {
+		t.Errorf("Expected drop and add when the rename target does not exist, got +%d -%d",
+			tableDiff.ColumnsAdded, tableDiff.ColumnsRemoved)
+	}
+}
+
+func TestMySQL8RenameColumn(t *testing.T) {
+	oldTable := createTestTable("users", []parser.ColumnDefinition{
+		createTestColumn("fname", "VARCHAR"),
+		createTestColumn("lname", "VARCHAR"),
+	})
+	newTable := createTestTable("users", []parser.ColumnDefinition{
+		createTestColumn("first_name", "VARCHAR"),
+		createTestColumn("last_name", "TEXT"),
+	})
+
+	analyzer := diff.NewTableDiffAnalyzer()
+	analyzer.ColumnRenames = map[string]map[string]string{"users": {"fname": "first_name", "lname": "last_name"}}
+	tableDiff := analyzer.CompareTables(oldTable, newTable)
+
+	generator := NewStatementGenerator()
+	result := strings.Join(generator.GenerateAlterStatements(tableDiff), "\n")
+	if strings.Contains(result, "RENAME COLUMN") {
+		t.Errorf("Expected CHANGE COLUMN without the MySQL 8 target, got:\n%s", result)
+	}
+
+	generator.MySQL8 = true
+	result = strings.Join(generator.GenerateAlterStatements(tableDiff), "\n")
+	if !strings.Contains(result, "RENAME COLUMN `fname` TO `first_name`") {
+		t.Errorf("Expected RENAME COLUMN for the name-only change, got:\n%s", result)
+	}
+	// A rename that also changes the definition still needs CHANGE COLUMN
+	if !strings.Contains(result, "CHANGE COLUMN `lname` `last_name` TEXT") {
+		t.Errorf("Expected CHANGE COLUMN for the rename with a type change, got:\n%s", result)
+	}
+}
+
+func TestDetectedColumnRenameStatements(t *testing.T) {
+	oldTable := createTestTable("users", []parser.ColumnDefinition{
+		createTestColumn("id", "INT"),
+		createTestColumn("fname", "VARCHAR"),
+	})
+	newTable := createTestTable("users", []parser.ColumnDefinition{
+		createTestColumn("id", "INT"),
+		createTestColumn("first_name", "VARCHAR"),
+	})
+
+	analyzer := diff.NewTableDiffAnalyzer()
+	analyzer.DetectColumnRenames = true
+	result := strings.Join(NewStatementGenerator().GenerateAlterStatements(analyzer.CompareTables(oldTable, newTable)), "\n")
+
+	if !strings.Contains(result, "CHANGE COLUMN `fname` `first_name` VARCHAR") {
+		t.Errorf("Expected CHANGE COLUMN for the detected rename, got:\n%s", result)
+	}
+	if strings.Contains(result, "DROP COLUMN") || strings.Contains(result, "ADD COLUMN") {
+		t.Errorf("Expected no DROP/ADD COLUMN for the detected rename, got:\n%s", result)
+	}
+}
+
+func TestCaseInsensitiveNames(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump(`CREATE TABLE Users (Id INT NOT NULL, OrgId INT, Name VARCHAR(50), PRIMARY KEY (Id),
+		KEY idx_Name (Name), CONSTRAINT fk_Org FOREIGN KEY (OrgId) REFERENCES Orgs (Id), CONSTRAINT Chk_Id CHECK (Id > 0));`)
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(`CREATE TABLE users (id INT NOT NULL, orgid INT, name VARCHAR(50), email VARCHAR(255), PRIMARY KEY (id),
+		KEY idx_name (name), CONSTRAINT fk_org FOREIGN KEY (orgid) REFERENCES orgs (id), CONSTRAINT chk_id CHECK (Id > 0));`)
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	if matches := MatchTablesWithRenames(oldTables, newTables, nil); len(matches) != 2 {
+		t.Fatalf("Expected Users and users to be unmatched by default, got %d entries", len(matches))
+	}
+
+	matches := MatchTablesCaseInsensitive(oldTables, newTables, nil)
+	match := matches["users"]
+	if len(matches) != 1 || match.Old == nil || match.New == nil {
+		t.Fatalf("Expected Users to be matched with users, got %v", matches)
+	}
+
+	analyzer := diff.NewTableDiffAnalyzer()
+	analyzer.CaseInsensitiveNames = true
+	tableDiff := analyzer.CompareTables(match.Old, match.New)
+	if tableDiff.TableNameChanged || tableDiff.PrimaryKeyDiff != nil || len(tableDiff.IndexDiffs) != 0 || len(tableDiff.ForeignKeyDiffs) != 0 ||
+		len(tableDiff.CheckConstraintDiffs) != 0 {
+		t.Errorf("Expected differently cased names to be equal, got %+v", tableDiff)
+	}
+	if added := tableDiff.AddedColumns(); len(tableDiff.ColumnDiffs) != 1 || len(added) != 1 || added[0].Name != "email" {
+		t.Errorf("Expected only the email column to be added, got %+v", tableDiff.ColumnDiffs)
+	}
+
+	result := strings.Join(NewStatementGenerator().GenerateAlterStatements(tableDiff), "\n")
+	if expected := "ALTER TABLE `Users`\n  ADD COLUMN `email` VARCHAR(255);"; result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+
+	analyzer.CaseInsensitiveNames = false
+	if tableDiff := analyzer.CompareTables(match.Old, match.New); !tableDiff.TableNameChanged || tableDiff.ColumnsRemoved != 3 {
+		t.Errorf("Expected names to differ by case without CaseInsensitiveNames, got %+v", tableDiff.ColumnDiffs)
+	}
+}
+
+func TestCaseInsensitiveColumnRenames(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump("CREATE TABLE users (id INT, fname VARCHAR(50));")
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump("CREATE TABLE users (id INT, first_name VARCHAR(50));")
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	analyzer := diff.NewTableDiffAnalyzer()
+	analyzer.ColumnRenames = map[string]map[string]string{"Users": {"FName": "first_name"}}
+	if tableDiff := analyzer.CompareTables(oldTables[0], newTables[0]); tableDiff.ColumnsRemoved != 1 {
+		t.Errorf("Expected the rename of Users.FName not to apply to users.fname by default, got %+v", tableDiff.ColumnDiffs)
+	}
+
+	analyzer.CaseInsensitiveNames = true
+	result := strings.Join(NewStatementGenerator().GenerateAlterStatements(analyzer.CompareTables(oldTables[0], newTables[0])), "\n")
+	if expected := "ALTER TABLE `users`\n  CHANGE COLUMN `fname` `first_name` VARCHAR(50);"; result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
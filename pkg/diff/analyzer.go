@@ -1,7 +1,7 @@
 package diff
 
 import (
-	"fmt"
+	"maps"
 	"slices"
 	"strings"
 
@@ -9,11 +9,183 @@ import (
 )
 
 // TableDiffAnalyzer analyzes differences between two table structures
-type TableDiffAnalyzer struct{}
+type TableDiffAnalyzer struct {
+	// Normalize compares case-insensitive option values (engine, charset, collation) ignoring case,
+	// treats an omitted foreign key action, RESTRICT and NO ACTION as equal and compares data type
+	// synonyms such as INTEGER, NUMERIC and BOOL as the type MySQL stores (INT, DECIMAL, TINYINT(1)).
+	// NewTableDiffAnalyzer enables it.
+	Normalize bool
+
+	// Normalization holds custom type and charset equivalence rules
+	Normalization NormalizationConfig
+
+	// ColumnRenames declares column renames per table (table name -> old column name -> new column name).
+	// A renamed column is compared with its new counterpart instead of being reported as removed and added.
+	ColumnRenames map[string]map[string]string
+
+	// IgnoreOrder compares ENUM/SET values and partition column lists as sets, so reordering
+	// them is not reported. It also turns off DetectColumnReorder and DetectColumnReorderWithoutPK.
+	IgnoreOrder bool
+
+	// IgnoreAutoIncrement does not compare the AUTO_INCREMENT table option, whose value differs
+	// between dumps as the counter advances
+	IgnoreAutoIncrement bool
+
+	// SafeTypeConversions marks data type conversions as safe for ClassifyTypeChange regardless of
+	// the type parameters, e.g. "VARCHAR": {"TEXT", "MEDIUMTEXT"}. Type names are matched case-insensitively.
+	SafeTypeConversions map[string][]string
+
+	// DetectColumnReorder reports columns whose position relative to the other columns changed
+	// as modified, with the old and new ordinal in ColumnChanges.Position
+	DetectColumnReorder bool
+
+	// DetectColumnReorderWithoutPK reports moved columns like DetectColumnReorder, but only for
+	// tables without a primary key, whose physical column order tends to matter more
+	DetectColumnReorderWithoutPK bool
+
+	// DetectColumnRenames pairs a removed column with an added column of a matching definition
+	// (see ColumnRenameSimilarity) and reports it as renamed instead of removed and added
+	DetectColumnRenames bool
+
+	// ColumnRenameSimilarity is the share of column attributes (type, nullability, default, ...)
+	// that must match for DetectColumnRenames to pair two columns. Zero requires identical definitions.
+	ColumnRenameSimilarity float64
+
+	// CaseInsensitiveNames compares table, column, index, foreign key and check constraint names
+	// ignoring case, as MySQL does on servers with lower_case_table_names=1 or 2, so renaming `Users`
+	// to `users` is no change. The table and old column names of ColumnRenames are matched the same way.
+	CaseInsensitiveNames bool
+
+	// KeepBooleanType compares BOOL and BOOLEAN literally instead of as the TINYINT(1) MySQL
+	// stores, so changing a BOOLEAN column to TINYINT(1) is reported
+	KeepBooleanType bool
+
+	// Options selects the change categories CompareTables analyzes; nil compares all of them
+	Options *AnalyzerOptions
+}
+
+// AnalyzerOptions selects the change categories an analyzer compares. CompareTables leaves the
+// diffs of a disabled category empty without looking at that part of the tables.
+type AnalyzerOptions struct {
+	CompareColumns          bool
+	CompareIndexes          bool // the primary key and the indexes
+	CompareForeignKeys      bool
+	CompareCheckConstraints bool
+	ComparePartitions       bool
+	CompareTableOptions     bool
+}
+
+// DefaultAnalyzerOptions returns options that compare every change category
+func DefaultAnalyzerOptions() AnalyzerOptions {
+	return AnalyzerOptions{
+		CompareColumns:          true,
+		CompareIndexes:          true,
+		CompareForeignKeys:      true,
+		CompareCheckConstraints: true,
+		ComparePartitions:       true,
+		CompareTableOptions:     true,
+	}
+}
+
+// IgnoreColumnMarker in the comment of a column, as in COMMENT 'mysqldiff:ignore', marks a column
+// that intentionally differs between schemas. Changes of a marked column are not reported; adding,
+// removing or renaming it still is.
+const IgnoreColumnMarker = "mysqldiff:ignore"
 
 // NewTableDiffAnalyzer creates a new analyzer instance
 func NewTableDiffAnalyzer() *TableDiffAnalyzer {
-	return &TableDiffAnalyzer{}
+	return &TableDiffAnalyzer{
+		Normalize: true,
+	}
+}
+
+// NewTableDiffAnalyzerWithOptions creates an analyzer that compares only the change categories
+// enabled in opts, e.g. columns and indexes without foreign keys and partitioning
+func NewTableDiffAnalyzerWithOptions(opts AnalyzerOptions) *TableDiffAnalyzer {
+	a := NewTableDiffAnalyzer()
+	a.Options = &opts
+	return a
+}
+
+// options returns the change categories to compare
+func (a *TableDiffAnalyzer) options() AnalyzerOptions {
+	if a.Options == nil {
+		return DefaultAnalyzerOptions()
+	}
+	return *a.Options
+}
+
+// optionValueEqual compares option values, ignoring case when normalization is enabled
+func (a *TableDiffAnalyzer) optionValueEqual(oldValue, newValue *string) bool {
+	if a.Normalize {
+		return ptrEqualFold(oldValue, newValue)
+	}
+	return ptrEqual(oldValue, newValue)
+}
+
+// referentialActionEqual compares foreign key ON DELETE/ON UPDATE actions, treating MySQL's
+// default action as equal to an omitted one when normalization is enabled
+func (a *TableDiffAnalyzer) referentialActionEqual(oldAction, newAction *string) bool {
+	if a.Normalize {
+		return normalizeReferentialAction(oldAction) == normalizeReferentialAction(newAction)
+	}
+	return ptrEqual(oldAction, newAction)
+}
+
+// defaultValueEqual compares column defaults, treating the CURRENT_TIMESTAMP family
+// (NOW(), LOCALTIMESTAMP, any letter case) as equal and comparing expression defaults
+// by their normalized form when normalization is enabled
+func (a *TableDiffAnalyzer) defaultValueEqual(oldDefault, newDefault *string) bool {
+	if a.Normalize && oldDefault != nil && newDefault != nil &&
+		parser.IsExpressionDefault(*oldDefault) && parser.IsExpressionDefault(*newDefault) {
+		return parser.NormalizeExpression(*oldDefault) == parser.NormalizeExpression(*newDefault)
+	}
+	if a.Normalize && oldDefault != nil && newDefault != nil {
+		oldTimestamp, oldIsTimestamp := normalizeTimestampDefault(*oldDefault)
+		newTimestamp, newIsTimestamp := normalizeTimestampDefault(*newDefault)
+		if oldIsTimestamp && newIsTimestamp {
+			return oldTimestamp == newTimestamp
+		}
+	}
+	return ptrEqual(oldDefault, newDefault)
+}
+
+// valueListEqual compares value lists, ignoring their order when IgnoreOrder is enabled
+func (a *TableDiffAnalyzer) valueListEqual(oldValues, newValues []string) bool {
+	if a.IgnoreOrder {
+		oldSorted := slices.Clone(oldValues)
+		newSorted := slices.Clone(newValues)
+		slices.Sort(oldSorted)
+		slices.Sort(newSorted)
+		return slices.Equal(oldSorted, newSorted)
+	}
+	return slices.Equal(oldValues, newValues)
+}
+
+// nameKey returns the name used to match identifiers, folded to lower case with CaseInsensitiveNames
+func (a *TableDiffAnalyzer) nameKey(name string) string {
+	if a.CaseInsensitiveNames {
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+// namesEqual compares two identifiers, ignoring case with CaseInsensitiveNames
+func (a *TableDiffAnalyzer) namesEqual(oldName, newName string) bool {
+	return a.nameKey(oldName) == a.nameKey(newName)
+}
+
+// namePtrEqual compares two optional identifiers like namesEqual
+func (a *TableDiffAnalyzer) namePtrEqual(oldName, newName *string) bool {
+	if oldName == nil || newName == nil {
+		return oldName == newName
+	}
+	return a.namesEqual(*oldName, *newName)
+}
+
+// namesListEqual compares two identifier lists like namesEqual
+func (a *TableDiffAnalyzer) namesListEqual(oldNames, newNames []string) bool {
+	return slices.EqualFunc(oldNames, newNames, a.namesEqual)
 }
 
 // CompareTables compares two table structures and returns a complete diff analysis
@@ -24,6 +196,8 @@ func (a *TableDiffAnalyzer) CompareTables(oldTable, newTable *parser.CreateTable
 		ColumnDiffs:     []ColumnDiff{},
 		IndexDiffs:      []IndexDiff{},
 		ForeignKeyDiffs: []ForeignKeyDiff{},
+
+		CheckConstraintDiffs: []CheckConstraintDiff{},
 	}
 
 	// Handle nil tables gracefully
@@ -33,7 +207,7 @@ func (a *TableDiffAnalyzer) CompareTables(oldTable, newTable *parser.CreateTable
 
 	// Check table name change (only if both tables exist)
 	if oldTable != nil && newTable != nil {
-		diff.TableNameChanged = oldTable.TableName != newTable.TableName
+		diff.TableNameChanged = !a.namesEqual(oldTable.TableName, newTable.TableName)
 	}
 
 	// Get table components safely
@@ -41,6 +215,7 @@ func (a *TableDiffAnalyzer) CompareTables(oldTable, newTable *parser.CreateTable
 	var oldPK *parser.PrimaryKeyDefinition
 	var oldIndexes []parser.IndexDefinition
 	var oldFKs []parser.ForeignKeyDefinition
+	var oldChecks []parser.CheckConstraint
 	var oldOptions *parser.TableOptions
 	var oldPartitions *parser.PartitionOptions
 
@@ -48,6 +223,7 @@ func (a *TableDiffAnalyzer) CompareTables(oldTable, newTable *parser.CreateTable
 	var newPK *parser.PrimaryKeyDefinition
 	var newIndexes []parser.IndexDefinition
 	var newFKs []parser.ForeignKeyDefinition
+	var newChecks []parser.CheckConstraint
 	var newOptions *parser.TableOptions
 	var newPartitions *parser.PartitionOptions
 
@@ -56,6 +232,7 @@ func (a *TableDiffAnalyzer) CompareTables(oldTable, newTable *parser.CreateTable
 		oldPK = oldTable.PrimaryKey
 		oldIndexes = oldTable.Indexes
 		oldFKs = oldTable.ForeignKeys
+		oldChecks = oldTable.CheckConstraints
 		oldOptions = oldTable.TableOptions
 		oldPartitions = oldTable.PartitionOptions
 	}
@@ -65,17 +242,45 @@ func (a *TableDiffAnalyzer) CompareTables(oldTable, newTable *parser.CreateTable
 		newPK = newTable.PrimaryKey
 		newIndexes = newTable.Indexes
 		newFKs = newTable.ForeignKeys
+		newChecks = newTable.CheckConstraints
 		newOptions = newTable.TableOptions
 		newPartitions = newTable.PartitionOptions
 	}
 
-	// Compare each component
-	diff.ColumnDiffs = a.compareColumns(oldColumns, newColumns)
-	diff.PrimaryKeyDiff = a.comparePrimaryKeys(oldPK, newPK)
-	diff.IndexDiffs = a.compareIndexes(oldIndexes, newIndexes)
-	diff.ForeignKeyDiffs = a.compareForeignKeys(oldFKs, newFKs)
-	diff.TableOptionsDiff = a.compareTableOptions(oldOptions, newOptions)
-	diff.PartitionDiff = a.comparePartitions(oldPartitions, newPartitions)
+	// Compare each enabled component
+	opts := a.options()
+	if opts.CompareColumns {
+		columnRenames := a.columnRenamesFor(oldTable, newTable)
+		if a.DetectColumnRenames {
+			columnRenames = a.detectColumnRenames(oldColumns, newColumns, columnRenames)
+		}
+		diff.ColumnDiffs = a.compareColumns(oldColumns, newColumns, columnRenames)
+		if !a.IgnoreOrder && (a.DetectColumnReorder || a.DetectColumnReorderWithoutPK && !hasPrimaryKey(newTable)) {
+			diff.ColumnDiffs = a.detectColumnReorders(diff.ColumnDiffs, oldColumns, newColumns, columnRenames)
+			diff.ColumnOrderTracked = true
+		}
+	}
+	if opts.CompareIndexes {
+		diff.PrimaryKeyDiff = a.comparePrimaryKeys(oldPK, newPK)
+		diff.IndexDiffs = a.compareIndexes(oldIndexes, newIndexes)
+		markRedundantIndexes(diff.IndexDiffs, newTable)
+	}
+	if opts.CompareForeignKeys {
+		diff.ForeignKeyDiffs = a.compareForeignKeys(oldFKs, newFKs)
+		markImplicitForeignKeyIndexes(diff.ForeignKeyDiffs, newTable)
+	}
+	if opts.CompareCheckConstraints {
+		diff.CheckConstraintDiffs = a.compareCheckConstraints(oldChecks, newChecks)
+	}
+	if opts.CompareTableOptions {
+		diff.TableOptionsDiff = a.compareTableOptions(oldOptions, newOptions)
+		if warning := utf8mb4KeyLengthWarning(diff.TableOptionsDiff, newTable); warning != "" {
+			diff.TableOptionsDiff.Warnings = append(diff.TableOptionsDiff.Warnings, warning)
+		}
+	}
+	if opts.ComparePartitions {
+		diff.PartitionDiff = a.comparePartitions(oldPartitions, newPartitions)
+	}
 
 	// Update counters
 	a.updateCounters(diff)
@@ -83,38 +288,295 @@ func (a *TableDiffAnalyzer) CompareTables(oldTable, newTable *parser.CreateTable
 	return diff
 }
 
-// compareColumns compares column definitions between old and new tables
-func (a *TableDiffAnalyzer) compareColumns(oldColumns, newColumns []parser.ColumnDefinition) []ColumnDiff {
+// columnRenamesFor returns the declared column renames for a table, looked up by its old name first.
+// Tables are looked up and the returned renames keyed by nameKey, so that with CaseInsensitiveNames a
+// rename declared for Users.A applies to column a of table users.
+func (a *TableDiffAnalyzer) columnRenamesFor(oldTable, newTable *parser.CreateTableStatement) map[string]string {
+	lookup := func(tableName string) (map[string]string, bool) {
+		if renames, ok := a.ColumnRenames[tableName]; ok {
+			return renames, true
+		}
+		for _, name := range slices.Sorted(maps.Keys(a.ColumnRenames)) {
+			if a.namesEqual(name, tableName) {
+				return a.ColumnRenames[name], true
+			}
+		}
+		return nil, false
+	}
+
+	var renames map[string]string
+	for _, table := range []*parser.CreateTableStatement{oldTable, newTable} {
+		if table == nil {
+			continue
+		}
+		var ok bool
+		if renames, ok = lookup(table.TableName); ok {
+			break
+		}
+	}
+	if !a.CaseInsensitiveNames || renames == nil {
+		return renames
+	}
+
+	keyed := make(map[string]string, len(renames))
+	for oldName, newName := range renames {
+		keyed[a.nameKey(oldName)] = newName
+	}
+	return keyed
+}
+
+// detectColumnRenames returns the declared renames extended with detected ones. Each old column
+// that has no counterpart of the same name is paired with the most similar unmatched new column,
+// provided their similarity reaches the threshold; ties go to the new column listed first.
+func (a *TableDiffAnalyzer) detectColumnRenames(oldColumns, newColumns []parser.ColumnDefinition, declared map[string]string) map[string]string {
+	threshold := a.ColumnRenameSimilarity
+	if threshold <= 0 {
+		threshold = 1
+	}
+
+	oldNames := make(map[string]bool)
+	for _, col := range oldColumns {
+		oldNames[a.nameKey(col.Name)] = true
+	}
+	newNames := make(map[string]bool)
+	for _, col := range newColumns {
+		newNames[a.nameKey(col.Name)] = true
+	}
+
+	renames := make(map[string]string)
+	matched := make(map[string]bool)
+	for oldName, newName := range declared {
+		renames[oldName] = newName
+		matched[a.nameKey(newName)] = true
+	}
+
+	for _, oldCol := range oldColumns {
+		if newNames[a.nameKey(oldCol.Name)] {
+			continue
+		}
+		if _, ok := renames[a.nameKey(oldCol.Name)]; ok {
+			continue
+		}
+
+		bestName := ""
+		bestSimilarity := 0.0
+		for _, newCol := range newColumns {
+			if oldNames[a.nameKey(newCol.Name)] || matched[a.nameKey(newCol.Name)] {
+				continue
+			}
+			renamed := newCol
+			renamed.Name = oldCol.Name
+			if similarity := columnSimilarity(a.compareColumnDefinitions(oldCol, renamed)); similarity > bestSimilarity {
+				bestName, bestSimilarity = newCol.Name, similarity
+			}
+		}
+
+		if bestName != "" && bestSimilarity >= threshold {
+			renames[a.nameKey(oldCol.Name)] = bestName
+			matched[a.nameKey(bestName)] = true
+		}
+	}
+
+	return renames
+}
+
+// hasPrimaryKey reports whether a table has a table-level or column-level primary key
+func hasPrimaryKey(table *parser.CreateTableStatement) bool {
+	if table == nil {
+		return false
+	}
+	if table.PrimaryKey != nil {
+		return true
+	}
+	for _, column := range table.Columns {
+		if column.PrimaryKey {
+			return true
+		}
+	}
+	return false
+}
+
+// detectColumnReorders adds position changes for the columns present in both tables that moved.
+// Columns in the longest common subsequence of both orders stay in place, so a column that is added,
+// dropped or moved does not make the columns after it count as moved.
+func (a *TableDiffAnalyzer) detectColumnReorders(diffs []ColumnDiff, oldColumns, newColumns []parser.ColumnDefinition, renames map[string]string) []ColumnDiff {
+	newIndexes := make(map[string]int)
+	for i, col := range newColumns {
+		newIndexes[a.nameKey(col.Name)] = i
+	}
+
+	// Positions of the common columns in the new table, in old table order
+	oldIndexes := make(map[string]int)
+	var oldOrder []int
+	for i, col := range oldColumns {
+		name := a.nameKey(col.Name)
+		if newName, ok := renames[a.nameKey(col.Name)]; ok {
+			if _, exists := newIndexes[a.nameKey(newName)]; exists {
+				name = a.nameKey(newName)
+			}
+		}
+		if newIndex, exists := newIndexes[name]; exists {
+			oldIndexes[name] = i
+			oldOrder = append(oldOrder, newIndex)
+		}
+	}
+	newOrder := slices.Clone(oldOrder)
+	slices.Sort(newOrder)
+
+	stay := make(map[int]bool)
+	for _, newIndex := range longestCommonSubsequence(oldOrder, newOrder) {
+		stay[newIndex] = true
+	}
+
+	for _, newIndex := range newOrder {
+		if stay[newIndex] {
+			continue
+		}
+		name := newColumns[newIndex].Name
+		if isIgnoredColumn(newColumns[newIndex]) || isIgnoredColumn(oldColumns[oldIndexes[a.nameKey(name)]]) {
+			continue
+		}
+		position := &FieldChange[int]{Old: oldIndexes[a.nameKey(name)] + 1, New: newIndex + 1}
+
+		found := false
+		for i := range diffs {
+			if a.namesEqual(diffs[i].Name, name) && diffs[i].ChangeType == ChangeTypeModified {
+				diffs[i].Changes.Position = position
+				found = true
+				break
+			}
+		}
+		if !found {
+			oldCol := oldColumns[oldIndexes[a.nameKey(name)]]
+			newCol := newColumns[newIndex]
+			diffs = append(diffs, ColumnDiff{
+				Name:       name,
+				ChangeType: ChangeTypeModified,
+				OldColumn:  &oldCol,
+				NewColumn:  &newCol,
+				Changes:    &ColumnChanges{Position: position},
+			})
+		}
+	}
+
+	sortColumnMoves(diffs)
+	return diffs
+}
+
+// sortColumnMoves moves the column diffs with position changes to the end, ordered by their new
+// position, so that every AFTER clause refers to a column that is already in place
+func sortColumnMoves(diffs []ColumnDiff) {
+	newPosition := func(colDiff ColumnDiff) int {
+		if colDiff.Changes == nil || colDiff.Changes.Position == nil {
+			return 0
+		}
+		return colDiff.Changes.Position.New
+	}
+	slices.SortStableFunc(diffs, func(a, b ColumnDiff) int {
+		return newPosition(a) - newPosition(b)
+	})
+}
+
+// longestCommonSubsequence returns a longest common subsequence of a and b
+func longestCommonSubsequence(a, b []int) []int {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	var common []int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			common = append(common, a[i])
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return common
+}
+
+// columnSimilarity returns the share of column attributes, other than the name, that are unchanged
+func columnSimilarity(changes *ColumnChanges) float64 {
+	changed := []bool{
+		changes.DataType != nil || changes.EnumValues != nil, changes.Nullable != nil, changes.DefaultValue != nil,
+		changes.OnUpdate != nil, changes.AutoIncrement != nil, changes.Unique != nil,
+		changes.PrimaryKey != nil, changes.Comment != nil, changes.Collation != nil,
+		changes.CharacterSet != nil, changes.Visible != nil, changes.ColumnFormat != nil,
+		changes.Storage != nil, changes.Generated != nil, changes.Checks != nil,
+	}
+
+	unchanged := 0
+	for _, isChanged := range changed {
+		if !isChanged {
+			unchanged++
+		}
+	}
+	return float64(unchanged) / float64(len(changed))
+}
+
+// compareColumns compares column definitions between old and new tables.
+// Old columns listed in renames are matched with the new column of the mapped name.
+func (a *TableDiffAnalyzer) compareColumns(oldColumns, newColumns []parser.ColumnDefinition, renames map[string]string) []ColumnDiff {
 	var diffs []ColumnDiff
 
 	// Create maps for easy lookup
 	oldColsMap := make(map[string]parser.ColumnDefinition)
 	newColsMap := make(map[string]parser.ColumnDefinition)
 
-	for _, col := range oldColumns {
-		oldColsMap[col.Name] = col
-	}
 	for _, col := range newColumns {
-		newColsMap[col.Name] = col
+		newColsMap[a.nameKey(col.Name)] = col
+	}
+	for _, col := range oldColumns {
+		if newName, ok := renames[a.nameKey(col.Name)]; ok {
+			if _, exists := newColsMap[a.nameKey(newName)]; exists {
+				oldColsMap[a.nameKey(newName)] = col
+				continue
+			}
+		}
+		oldColsMap[a.nameKey(col.Name)] = col
 	}
 
-	// Find all column names
-	allColumnNames := make(map[string]bool)
-	for name := range oldColsMap {
-		allColumnNames[name] = true
+	// Visit the columns in the order of the new table, then the removed columns in the order of
+	// the old table, so that the diffs come out the same on every run
+	var columnNames []string
+	seen := make(map[string]bool)
+	for _, col := range newColumns {
+		if name := a.nameKey(col.Name); !seen[name] {
+			columnNames = append(columnNames, name)
+			seen[name] = true
+		}
 	}
-	for name := range newColsMap {
-		allColumnNames[name] = true
+	for _, col := range oldColumns {
+		if name := a.nameKey(col.Name); !seen[name] {
+			if _, exists := oldColsMap[name]; exists {
+				columnNames = append(columnNames, name)
+				seen[name] = true
+			}
+		}
 	}
 
-	for colName := range allColumnNames {
+	for _, colName := range columnNames {
 		oldCol, hasOld := oldColsMap[colName]
 		newCol, hasNew := newColsMap[colName]
 
 		if !hasOld {
 			// Column added
 			diffs = append(diffs, ColumnDiff{
-				Name:       colName,
+				Name:       newCol.Name,
 				ChangeType: ChangeTypeAdded,
 				NewColumn:  &newCol,
 				Changes:    &ColumnChanges{},
@@ -122,22 +584,35 @@ func (a *TableDiffAnalyzer) compareColumns(oldColumns, newColumns []parser.Colum
 		} else if !hasNew {
 			// Column removed
 			diffs = append(diffs, ColumnDiff{
-				Name:       colName,
+				Name:       oldCol.Name,
 				ChangeType: ChangeTypeRemoved,
 				OldColumn:  &oldCol,
 				Changes:    &ColumnChanges{},
 			})
 		} else {
 			// Column exists in both, check for changes
-			changes := a.compareColumnDefinitions(oldCol, newCol)
+			var changes *ColumnChanges
+			if isIgnoredColumn(oldCol) || isIgnoredColumn(newCol) {
+				// Only a declared rename is reported for an ignored column
+				changes = &ColumnChanges{}
+				if !a.namesEqual(oldCol.Name, newCol.Name) {
+					changes.Name = &FieldChange[string]{Old: oldCol.Name, New: newCol.Name}
+				}
+			} else {
+				changes = a.compareColumnDefinitions(oldCol, newCol)
+			}
 			if changes.HasChanges() {
-				diffs = append(diffs, ColumnDiff{
-					Name:       colName,
+				colDiff := ColumnDiff{
+					Name:       newCol.Name,
 					ChangeType: ChangeTypeModified,
 					OldColumn:  &oldCol,
 					NewColumn:  &newCol,
 					Changes:    changes,
-				})
+				}
+				if changes.DataType != nil || changes.EnumValues != nil {
+					colDiff.Conversion = a.ClassifyTypeChange(oldCol.DataType, newCol.DataType)
+				}
+				diffs = append(diffs, colDiff)
 			}
 		}
 	}
@@ -145,15 +620,32 @@ func (a *TableDiffAnalyzer) compareColumns(oldColumns, newColumns []parser.Colum
 	return diffs
 }
 
+// isIgnoredColumn reports whether the comment of a column carries IgnoreColumnMarker
+func isIgnoredColumn(col parser.ColumnDefinition) bool {
+	return col.Comment != nil && strings.Contains(strings.ToLower(*col.Comment), IgnoreColumnMarker)
+}
+
 // compareColumnDefinitions compares two column definitions and returns changes
 func (a *TableDiffAnalyzer) compareColumnDefinitions(oldCol, newCol parser.ColumnDefinition) *ColumnChanges {
 	changes := &ColumnChanges{}
 
-	// Compare data type
+	// Compare name (only differs for declared renames)
+	if !a.namesEqual(oldCol.Name, newCol.Name) {
+		changes.Name = &FieldChange[string]{
+			Old: oldCol.Name,
+			New: newCol.Name,
+		}
+	}
+
+	// Compare data type; ENUM and SET columns that only change their values report those values
 	if !a.dataTypesEqual(oldCol.DataType, newCol.DataType) {
-		changes.DataType = &FieldChange[string]{
-			Old: a.dataTypeToString(oldCol.DataType),
-			New: a.dataTypeToString(newCol.DataType),
+		if enumChange := a.compareValueLists(oldCol.DataType, newCol.DataType); enumChange != nil {
+			changes.EnumValues = enumChange
+		} else {
+			changes.DataType = &FieldChange[string]{
+				Old: a.dataTypeToString(oldCol.DataType),
+				New: a.dataTypeToString(newCol.DataType),
+			}
 		}
 	}
 
@@ -166,13 +658,20 @@ func (a *TableDiffAnalyzer) compareColumnDefinitions(oldCol, newCol parser.Colum
 	}
 
 	// Compare default value
-	if !ptrEqual(oldCol.DefaultValue, newCol.DefaultValue) {
+	if !a.columnDefaultEqual(oldCol, newCol) {
 		changes.DefaultValue = &FieldChange[any]{
 			Old: ptrToValue(oldCol.DefaultValue),
 			New: ptrToValue(newCol.DefaultValue),
 		}
 	}
 
+	if !a.defaultValueEqual(oldCol.OnUpdate, newCol.OnUpdate) {
+		changes.OnUpdate = &FieldChange[any]{
+			Old: ptrToValue(oldCol.OnUpdate),
+			New: ptrToValue(newCol.OnUpdate),
+		}
+	}
+
 	// Compare boolean attributes
 	if oldCol.AutoIncrement != newCol.AutoIncrement {
 		changes.AutoIncrement = &FieldChange[bool]{
@@ -210,7 +709,7 @@ func (a *TableDiffAnalyzer) compareColumnDefinitions(oldCol, newCol parser.Colum
 		}
 	}
 
-	if !ptrEqual(oldCol.CharacterSet, newCol.CharacterSet) {
+	if !a.charsetEqual(oldCol.CharacterSet, newCol.CharacterSet) {
 		changes.CharacterSet = &FieldChange[any]{
 			Old: ptrToValue(oldCol.CharacterSet),
 			New: ptrToValue(newCol.CharacterSet),
@@ -246,30 +745,71 @@ func (a *TableDiffAnalyzer) compareColumnDefinitions(oldCol, newCol parser.Colum
 		}
 	}
 
+	// Compare inline CHECK constraints like table-level ones
+	if len(a.compareCheckConstraints(oldCol.Checks, newCol.Checks)) > 0 {
+		changes.Checks = &FieldChange[[]string]{
+			Old: columnCheckDefinitions(oldCol.Checks),
+			New: columnCheckDefinitions(newCol.Checks),
+		}
+	}
+
 	return changes
 }
 
-// dataTypesEqual checks if two data types are equal
+// dataTypesEqual checks if two data types are equal after canonicalizing type synonyms
 func (a *TableDiffAnalyzer) dataTypesEqual(oldDT, newDT parser.DataType) bool {
-	return oldDT.Name == newDT.Name &&
-		slices.Equal(oldDT.Parameters, newDT.Parameters) &&
+	oldDT = a.canonicalDataType(oldDT)
+	newDT = a.canonicalDataType(newDT)
+	oldName := oldDT.Name
+	newName := newDT.Name
+	parametersEqual := slices.Equal(oldDT.Parameters, newDT.Parameters)
+	if isValueListType(oldName) && isValueListType(newName) {
+		parametersEqual = a.valueListEqual(oldDT.Parameters, newDT.Parameters)
+	}
+	return a.optionValueEqual(&oldName, &newName) &&
+		parametersEqual &&
 		oldDT.Unsigned == newDT.Unsigned &&
 		oldDT.Zerofill == newDT.Zerofill
 }
 
-// dataTypeToString converts DataType to string representation
-func (a *TableDiffAnalyzer) dataTypeToString(dt parser.DataType) string {
-	result := dt.Name
-	if len(dt.Parameters) > 0 {
-		result += fmt.Sprintf("(%s)", strings.Join(dt.Parameters, ","))
+// compareValueLists returns the values added to and removed from an ENUM or SET type, or nil when
+// the types differ in anything but their values
+func (a *TableDiffAnalyzer) compareValueLists(oldDT, newDT parser.DataType) *EnumChange {
+	oldDT = a.canonicalDataType(oldDT)
+	newDT = a.canonicalDataType(newDT)
+	if !isValueListType(oldDT.Name) || !strings.EqualFold(oldDT.Name, newDT.Name) ||
+		oldDT.Unsigned != newDT.Unsigned || oldDT.Zerofill != newDT.Zerofill {
+		return nil
 	}
-	if dt.Unsigned {
-		result += " UNSIGNED"
+
+	change := &EnumChange{}
+	var keptOld, keptNew []string
+	for _, value := range oldDT.Parameters {
+		if slices.Contains(newDT.Parameters, value) {
+			keptOld = append(keptOld, value)
+		} else {
+			change.Removed = append(change.Removed, value)
+		}
 	}
-	if dt.Zerofill {
-		result += " ZEROFILL"
+	for _, value := range newDT.Parameters {
+		if slices.Contains(oldDT.Parameters, value) {
+			keptNew = append(keptNew, value)
+		} else {
+			change.Added = append(change.Added, value)
+		}
 	}
-	return result
+	change.Reordered = !a.IgnoreOrder && !slices.Equal(keptOld, keptNew)
+	return change
+}
+
+// isValueListType reports whether the type's parameters are a list of allowed values (ENUM, SET)
+func isValueListType(name string) bool {
+	return strings.EqualFold(name, "ENUM") || strings.EqualFold(name, "SET")
+}
+
+// dataTypeToString converts DataType to string representation
+func (a *TableDiffAnalyzer) dataTypeToString(dt parser.DataType) string {
+	return formatDataType(dt)
 }
 
 // updateCounters updates summary counters in the diff object
@@ -310,12 +850,69 @@ func (a *TableDiffAnalyzer) updateCounters(diff *TableDiff) {
 		}
 	}
 
+	// Count check constraint changes
+	for _, checkDiff := range diff.CheckConstraintDiffs {
+		switch checkDiff.ChangeType {
+		case ChangeTypeAdded:
+			diff.CheckConstraintsAdded++
+		case ChangeTypeRemoved:
+			diff.CheckConstraintsRemoved++
+		case ChangeTypeModified:
+			diff.CheckConstraintsModified++
+		}
+	}
+
 	// Update table-level flags
 	diff.TableOptionsChanged = diff.TableOptionsDiff != nil
 }
 
+// CompareSchemas compares two complete schemas, matching tables by name.
+// Added, removed and modified tables are each ordered by table name; unchanged tables are left out.
+func (a *TableDiffAnalyzer) CompareSchemas(oldTables, newTables []*parser.CreateTableStatement) *SchemaDiff {
+	sd := &SchemaDiff{
+		AddedTables:    []*parser.CreateTableStatement{},
+		RemovedTables:  []*parser.CreateTableStatement{},
+		ModifiedTables: []*TableDiff{},
+	}
+
+	oldByName := make(map[string]*parser.CreateTableStatement, len(oldTables))
+	for _, table := range oldTables {
+		oldByName[a.nameKey(table.TableName)] = table
+	}
+	newByName := make(map[string]*parser.CreateTableStatement, len(newTables))
+	for _, table := range newTables {
+		newByName[a.nameKey(table.TableName)] = table
+	}
+
+	for _, newTable := range sortedTables(newTables) {
+		oldTable, exists := oldByName[a.nameKey(newTable.TableName)]
+		if !exists {
+			sd.AddedTables = append(sd.AddedTables, newTable)
+			continue
+		}
+		sd.TablesCompared++
+		if tableDiff := a.CompareTables(oldTable, newTable); tableDiff.HasChanges() {
+			sd.ModifiedTables = append(sd.ModifiedTables, tableDiff)
+		}
+	}
+
+	for _, oldTable := range sortedTables(oldTables) {
+		if _, exists := newByName[a.nameKey(oldTable.TableName)]; !exists {
+			sd.RemovedTables = append(sd.RemovedTables, oldTable)
+		}
+	}
+
+	return sd
+}
+
 // CompareTables is a convenience function to compare two tables
 func CompareTables(oldTable, newTable *parser.CreateTableStatement) *TableDiff {
 	analyzer := NewTableDiffAnalyzer()
 	return analyzer.CompareTables(oldTable, newTable)
 }
+
+// CompareSchemas is a convenience function to compare two complete schemas
+func CompareSchemas(oldTables, newTables []*parser.CreateTableStatement) *SchemaDiff {
+	analyzer := NewTableDiffAnalyzer()
+	return analyzer.CompareSchemas(oldTables, newTables)
+}
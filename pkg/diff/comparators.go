@@ -2,7 +2,9 @@ package diff
 
 import (
 	"fmt"
+	"reflect"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/n0madic/mysql-diff/pkg/parser"
@@ -44,7 +46,7 @@ func (a *TableDiffAnalyzer) comparePrimaryKeys(oldPK, newPK *parser.PrimaryKeyDe
 		newCols[i] = col.Name
 	}
 
-	if !slices.Equal(oldCols, newCols) {
+	if !a.namesListEqual(oldCols, newCols) {
 		changes.Columns = &FieldChange[[]string]{
 			Old: oldCols,
 			New: newCols,
@@ -52,7 +54,7 @@ func (a *TableDiffAnalyzer) comparePrimaryKeys(oldPK, newPK *parser.PrimaryKeyDe
 	}
 
 	// Compare other attributes
-	if !ptrEqual(oldPK.Name, newPK.Name) {
+	if !a.namePtrEqual(oldPK.Name, newPK.Name) {
 		changes.Name = &FieldChange[any]{
 			Old: ptrToValue(oldPK.Name),
 			New: ptrToValue(newPK.Name),
@@ -93,11 +95,11 @@ func (a *TableDiffAnalyzer) compareIndexes(oldIndexes, newIndexes []parser.Index
 	exactKey := func(idx parser.IndexDefinition) string {
 		cols := make([]string, len(idx.Columns))
 		for i, col := range idx.Columns {
-			cols[i] = col.Name
+			cols[i] = a.nameKey(col.Name)
 		}
 		name := ""
 		if idx.Name != nil {
-			name = *idx.Name
+			name = a.nameKey(*idx.Name)
 		}
 		return fmt.Sprintf("%s:%s:%s", name, strings.Join(cols, ":"), idx.IndexType)
 	}
@@ -105,69 +107,82 @@ func (a *TableDiffAnalyzer) compareIndexes(oldIndexes, newIndexes []parser.Index
 	structuralKey := func(idx parser.IndexDefinition) string {
 		cols := make([]string, len(idx.Columns))
 		for i, col := range idx.Columns {
-			cols[i] = col.Name
+			cols[i] = a.nameKey(col.Name)
 		}
 		return fmt.Sprintf("%s:%s", strings.Join(cols, ":"), idx.IndexType)
 	}
 
-	// Maps for exact matches
-	oldExactMap := make(map[string]parser.IndexDefinition)
-	newExactMap := make(map[string]parser.IndexDefinition)
+	// Maps for exact matches. The entries point into the index slices, so that a diff identifies the
+	// index by its position even when several unnamed indexes are identical.
+	oldExactMap := make(map[string][]*parser.IndexDefinition)
+	newExactMap := make(map[string][]*parser.IndexDefinition)
 
 	// Maps for structural matches (for detecting renames)
-	oldStructuralMap := make(map[string][]parser.IndexDefinition)
-	newStructuralMap := make(map[string][]parser.IndexDefinition)
-
-	for _, idx := range oldIndexes {
-		exactKey := exactKey(idx)
-		structKey := structuralKey(idx)
-		oldExactMap[exactKey] = idx
+	oldStructuralMap := make(map[string][]*parser.IndexDefinition)
+	newStructuralMap := make(map[string][]*parser.IndexDefinition)
+
+	// Keys in the order of the tables, so that the diffs come out the same on every run
+	var oldExactKeys, oldStructuralKeys []string
+
+	for i := range oldIndexes {
+		idx := &oldIndexes[i]
+		exactKey := exactKey(*idx)
+		structKey := structuralKey(*idx)
+		if _, exists := oldExactMap[exactKey]; !exists {
+			oldExactKeys = append(oldExactKeys, exactKey)
+		}
+		if _, exists := oldStructuralMap[structKey]; !exists {
+			oldStructuralKeys = append(oldStructuralKeys, structKey)
+		}
+		oldExactMap[exactKey] = append(oldExactMap[exactKey], idx)
 		oldStructuralMap[structKey] = append(oldStructuralMap[structKey], idx)
 	}
-	for _, idx := range newIndexes {
-		exactKey := exactKey(idx)
-		structKey := structuralKey(idx)
-		newExactMap[exactKey] = idx
-		newStructuralMap[structKey] = append(newStructuralMap[structKey], idx)
+	for i := range newIndexes {
+		idx := &newIndexes[i]
+		newExactMap[exactKey(*idx)] = append(newExactMap[exactKey(*idx)], idx)
+		newStructuralMap[structuralKey(*idx)] = append(newStructuralMap[structuralKey(*idx)], idx)
 	}
 
 	// Track processed indexes to avoid duplicates
-	processedOld := make(map[string]bool)
-	processedNew := make(map[string]bool)
-
-	// First pass: find exact matches
-	for exactKey, oldIdx := range oldExactMap {
-		if newIdx, exists := newExactMap[exactKey]; exists {
+	processedOld := make(map[*parser.IndexDefinition]bool)
+	processedNew := make(map[*parser.IndexDefinition]bool)
+
+	// First pass: find exact matches, pairing identical indexes in the order of the tables
+	for _, exactKey := range oldExactKeys {
+		oldIdxList, newIdxList := oldExactMap[exactKey], newExactMap[exactKey]
+		for i := 0; i < len(oldIdxList) && i < len(newIdxList); i++ {
+			oldIdx, newIdx := oldIdxList[i], newIdxList[i]
 			// Exact match found, check for changes
-			changes := a.compareIndexDefinitions(oldIdx, newIdx)
+			changes := a.compareIndexDefinitions(*oldIdx, *newIdx)
 			if changes.HasChanges() {
 				diffs = append(diffs, IndexDiff{
 					Name:       oldIdx.Name,
 					ChangeType: ChangeTypeModified,
-					OldIndex:   &oldIdx,
-					NewIndex:   &newIdx,
+					OldIndex:   oldIdx,
+					NewIndex:   newIdx,
 					Changes:    changes,
 				})
 			}
-			processedOld[exactKey] = true
-			processedNew[exactKey] = true
+			processedOld[oldIdx] = true
+			processedNew[newIdx] = true
 		}
 	}
 
 	// Second pass: find potential renames (same structure, different name)
-	for structKey, oldIdxList := range oldStructuralMap {
+	for _, structKey := range oldStructuralKeys {
+		oldIdxList := oldStructuralMap[structKey]
 		if newIdxList, exists := newStructuralMap[structKey]; exists {
 			// Find unprocessed indexes with same structure
-			var unprocessedOld, unprocessedNew []parser.IndexDefinition
+			var unprocessedOld, unprocessedNew []*parser.IndexDefinition
 
 			for _, oldIdx := range oldIdxList {
-				if !processedOld[exactKey(oldIdx)] {
+				if !processedOld[oldIdx] {
 					unprocessedOld = append(unprocessedOld, oldIdx)
 				}
 			}
 
 			for _, newIdx := range newIdxList {
-				if !processedNew[exactKey(newIdx)] {
+				if !processedNew[newIdx] {
 					unprocessedNew = append(unprocessedNew, newIdx)
 				}
 			}
@@ -183,39 +198,39 @@ func (a *TableDiffAnalyzer) compareIndexes(oldIndexes, newIndexes []parser.Index
 				newIdx := unprocessedNew[i]
 
 				// This is a rename - treat as modification
-				changes := a.compareIndexDefinitions(oldIdx, newIdx)
+				changes := a.compareIndexDefinitions(*oldIdx, *newIdx)
 				diffs = append(diffs, IndexDiff{
 					Name:       oldIdx.Name,
 					ChangeType: ChangeTypeModified,
-					OldIndex:   &oldIdx,
-					NewIndex:   &newIdx,
+					OldIndex:   oldIdx,
+					NewIndex:   newIdx,
 					Changes:    changes,
 				})
 
-				processedOld[exactKey(oldIdx)] = true
-				processedNew[exactKey(newIdx)] = true
+				processedOld[oldIdx] = true
+				processedNew[newIdx] = true
 			}
 		}
 	}
 
 	// Third pass: handle remaining unprocessed indexes as additions/removals
-	for exactKey, oldIdx := range oldExactMap {
-		if !processedOld[exactKey] {
+	for i := range oldIndexes {
+		if oldIdx := &oldIndexes[i]; !processedOld[oldIdx] {
 			diffs = append(diffs, IndexDiff{
 				Name:       oldIdx.Name,
 				ChangeType: ChangeTypeRemoved,
-				OldIndex:   &oldIdx,
+				OldIndex:   oldIdx,
 				Changes:    &IndexChanges{},
 			})
 		}
 	}
 
-	for exactKey, newIdx := range newExactMap {
-		if !processedNew[exactKey] {
+	for i := range newIndexes {
+		if newIdx := &newIndexes[i]; !processedNew[newIdx] {
 			diffs = append(diffs, IndexDiff{
 				Name:       newIdx.Name,
 				ChangeType: ChangeTypeAdded,
-				NewIndex:   &newIdx,
+				NewIndex:   newIdx,
 				Changes:    &IndexChanges{},
 			})
 		}
@@ -229,7 +244,7 @@ func (a *TableDiffAnalyzer) compareIndexDefinitions(oldIdx, newIdx parser.IndexD
 	changes := &IndexChanges{}
 
 	// Compare basic attributes
-	if !ptrEqual(oldIdx.Name, newIdx.Name) {
+	if !a.namePtrEqual(oldIdx.Name, newIdx.Name) {
 		changes.Name = &FieldChange[any]{
 			Old: ptrToValue(oldIdx.Name),
 			New: ptrToValue(newIdx.Name),
@@ -320,7 +335,7 @@ func (a *TableDiffAnalyzer) indexColumnsEqual(oldCols, newCols []parser.IndexCol
 
 	for i, oldCol := range oldCols {
 		newCol := newCols[i]
-		if oldCol.Name != newCol.Name ||
+		if !a.namesEqual(oldCol.Name, newCol.Name) ||
 			!ptrEqual(oldCol.Length, newCol.Length) ||
 			!ptrEqual(oldCol.Direction, newCol.Direction) {
 			return false
@@ -352,11 +367,11 @@ func (a *TableDiffAnalyzer) compareForeignKeys(oldFKs, newFKs []parser.ForeignKe
 
 	// Create maps for comparison
 	fkKey := func(fk parser.ForeignKeyDefinition) string {
-		cols := strings.Join(fk.Columns, ":")
-		ref := fmt.Sprintf("%s:%s", fk.Reference.TableName, strings.Join(fk.Reference.Columns, ":"))
+		cols := a.nameKey(strings.Join(fk.Columns, ":"))
+		ref := a.nameKey(fmt.Sprintf("%s:%s", fk.Reference.TableName, strings.Join(fk.Reference.Columns, ":")))
 		name := ""
 		if fk.Name != nil {
-			name = *fk.Name
+			name = a.nameKey(*fk.Name)
 		}
 		return fmt.Sprintf("%s:%s:%s", name, cols, ref)
 	}
@@ -371,16 +386,18 @@ func (a *TableDiffAnalyzer) compareForeignKeys(oldFKs, newFKs []parser.ForeignKe
 		newFKsMap[fkKey(fk)] = fk
 	}
 
-	// Find all FK keys
-	allFKKeys := make(map[string]bool)
-	for key := range oldFKsMap {
-		allFKKeys[key] = true
-	}
-	for key := range newFKsMap {
-		allFKKeys[key] = true
+	// Visit the foreign keys in the order of the new table, then the removed ones in the order of
+	// the old table
+	var fkKeys []string
+	seen := make(map[string]bool)
+	for _, fk := range slices.Concat(newFKs, oldFKs) {
+		if key := fkKey(fk); !seen[key] {
+			fkKeys = append(fkKeys, key)
+			seen[key] = true
+		}
 	}
 
-	for fkKeyStr := range allFKKeys {
+	for _, fkKeyStr := range fkKeys {
 		oldFK, hasOld := oldFKsMap[fkKeyStr]
 		newFK, hasNew := newFKsMap[fkKeyStr]
 
@@ -418,19 +435,155 @@ func (a *TableDiffAnalyzer) compareForeignKeys(oldFKs, newFKs []parser.ForeignKe
 	return diffs
 }
 
+// markImplicitForeignKeyIndexes flags added foreign keys whose columns are not indexed in the new table
+func markImplicitForeignKeyIndexes(fkDiffs []ForeignKeyDiff, newTable *parser.CreateTableStatement) {
+	if newTable == nil {
+		return
+	}
+	for i, fkDiff := range fkDiffs {
+		if fkDiff.ChangeType == ChangeTypeAdded && !HasIndexForColumns(newTable, fkDiff.NewFK.Columns) {
+			fkDiffs[i].ImplicitIndex = true
+		}
+	}
+}
+
+// HasIndexForColumns reports whether the primary key or an index of the table starts with the
+// given columns in order, which is what MySQL requires to back a foreign key
+func HasIndexForColumns(table *parser.CreateTableStatement, columns []string) bool {
+	leadsWith := func(indexColumns []parser.IndexColumn) bool {
+		if len(columns) == 0 || len(indexColumns) < len(columns) {
+			return false
+		}
+		for i, column := range columns {
+			if !strings.EqualFold(indexColumns[i].Name, column) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if table.PrimaryKey != nil && leadsWith(table.PrimaryKey.Columns) {
+		return true
+	}
+	for _, column := range table.Columns {
+		if len(columns) == 1 && (column.PrimaryKey || column.Unique) && strings.EqualFold(column.Name, columns[0]) {
+			return true
+		}
+	}
+	for _, idx := range table.Indexes {
+		if idx.IndexType != "FULLTEXT" && idx.IndexType != "SPATIAL" && leadsWith(idx.Columns) {
+			return true
+		}
+	}
+	return false
+}
+
+// markRedundantIndexes flags added indexes that another index of the new table already covers
+func markRedundantIndexes(idxDiffs []IndexDiff, newTable *parser.CreateTableStatement) {
+	if newTable == nil {
+		return
+	}
+	redundant := FindRedundantIndexes(newTable)
+	for i, idxDiff := range idxDiffs {
+		if idxDiff.ChangeType != ChangeTypeAdded {
+			continue
+		}
+		for _, finding := range redundant {
+			if reflect.DeepEqual(*idxDiff.NewIndex, *finding.Index) {
+				idxDiffs[i].RedundantWith = finding.CoveredBy
+				break
+			}
+		}
+	}
+}
+
+// FindRedundantIndexes returns the indexes of the table that another index already covers: a
+// non-unique index whose columns are a left prefix of the primary key or of another index, and
+// a unique index with the same columns as the primary key or another unique index. Of two
+// identical indexes the later one is reported. FULLTEXT and SPATIAL indexes are never redundant.
+func FindRedundantIndexes(table *parser.CreateTableStatement) []RedundantIndex {
+	type coveringIndex struct {
+		name     string
+		columns  []parser.IndexColumn
+		unique   bool
+		position int // position in table.Indexes, -1 for the primary key and column constraints
+	}
+
+	var candidates []coveringIndex
+	if table.PrimaryKey != nil {
+		candidates = append(candidates, coveringIndex{"PRIMARY", table.PrimaryKey.Columns, true, -1})
+	}
+	for _, column := range table.Columns {
+		if column.PrimaryKey {
+			candidates = append(candidates, coveringIndex{"PRIMARY", []parser.IndexColumn{{Name: column.Name}}, true, -1})
+		} else if column.Unique {
+			candidates = append(candidates, coveringIndex{column.Name, []parser.IndexColumn{{Name: column.Name}}, true, -1})
+		}
+	}
+	for i := range table.Indexes {
+		idx := &table.Indexes[i]
+		if idx.IndexType != "FULLTEXT" && idx.IndexType != "SPATIAL" {
+			candidates = append(candidates, coveringIndex{indexName(idx), idx.Columns, idx.IndexType == "UNIQUE", i})
+		}
+	}
+
+	var redundant []RedundantIndex
+	for i := range table.Indexes {
+		idx := &table.Indexes[i]
+		if idx.IndexType == "FULLTEXT" || idx.IndexType == "SPATIAL" {
+			continue
+		}
+		unique := idx.IndexType == "UNIQUE"
+
+		for _, candidate := range candidates {
+			if candidate.position == i || !indexColumnsPrefix(idx.Columns, candidate.columns) {
+				continue
+			}
+			covered := false
+			if len(idx.Columns) < len(candidate.columns) {
+				// A shorter unique index enforces a stronger constraint than the longer one
+				covered = !unique
+			} else if unique {
+				covered = candidate.unique && candidate.position < i
+			} else {
+				covered = candidate.unique || candidate.position < i
+			}
+			if covered {
+				redundant = append(redundant, RedundantIndex{Index: idx, CoveredBy: candidate.name})
+				break
+			}
+		}
+	}
+	return redundant
+}
+
+// indexColumnsPrefix reports whether the columns, including their prefix lengths, are a left prefix
+// of the other index columns
+func indexColumnsPrefix(columns, other []parser.IndexColumn) bool {
+	if len(columns) == 0 || len(columns) > len(other) {
+		return false
+	}
+	for i, column := range columns {
+		if !strings.EqualFold(column.Name, other[i].Name) || !ptrEqual(column.Length, other[i].Length) {
+			return false
+		}
+	}
+	return true
+}
+
 // compareForeignKeyDefinitions compares two foreign key definitions
 func (a *TableDiffAnalyzer) compareForeignKeyDefinitions(oldFK, newFK parser.ForeignKeyDefinition) *ForeignKeyChanges {
 	changes := &ForeignKeyChanges{}
 
 	// Compare basic attributes
-	if !ptrEqual(oldFK.Name, newFK.Name) {
+	if !a.namePtrEqual(oldFK.Name, newFK.Name) {
 		changes.Name = &FieldChange[any]{
 			Old: ptrToValue(oldFK.Name),
 			New: ptrToValue(newFK.Name),
 		}
 	}
 
-	if !slices.Equal(oldFK.Columns, newFK.Columns) {
+	if !a.namesListEqual(oldFK.Columns, newFK.Columns) {
 		changes.Columns = &FieldChange[[]string]{
 			Old: oldFK.Columns,
 			New: newFK.Columns,
@@ -438,28 +591,28 @@ func (a *TableDiffAnalyzer) compareForeignKeyDefinitions(oldFK, newFK parser.For
 	}
 
 	// Compare reference
-	if oldFK.Reference.TableName != newFK.Reference.TableName {
+	if !a.namesEqual(oldFK.Reference.TableName, newFK.Reference.TableName) {
 		changes.ReferenceTable = &FieldChange[string]{
 			Old: oldFK.Reference.TableName,
 			New: newFK.Reference.TableName,
 		}
 	}
 
-	if !slices.Equal(oldFK.Reference.Columns, newFK.Reference.Columns) {
+	if !a.namesListEqual(oldFK.Reference.Columns, newFK.Reference.Columns) {
 		changes.ReferenceColumns = &FieldChange[[]string]{
 			Old: oldFK.Reference.Columns,
 			New: newFK.Reference.Columns,
 		}
 	}
 
-	if !ptrEqual(oldFK.Reference.OnDelete, newFK.Reference.OnDelete) {
+	if !a.referentialActionEqual(oldFK.Reference.OnDelete, newFK.Reference.OnDelete) {
 		changes.OnDelete = &FieldChange[any]{
 			Old: ptrToValue(oldFK.Reference.OnDelete),
 			New: ptrToValue(newFK.Reference.OnDelete),
 		}
 	}
 
-	if !ptrEqual(oldFK.Reference.OnUpdate, newFK.Reference.OnUpdate) {
+	if !a.referentialActionEqual(oldFK.Reference.OnUpdate, newFK.Reference.OnUpdate) {
 		changes.OnUpdate = &FieldChange[any]{
 			Old: ptrToValue(oldFK.Reference.OnUpdate),
 			New: ptrToValue(newFK.Reference.OnUpdate),
@@ -469,12 +622,145 @@ func (a *TableDiffAnalyzer) compareForeignKeyDefinitions(oldFK, newFK parser.For
 	return changes
 }
 
+// compareCheckConstraints compares check constraints. Named constraints are matched by name,
+// unnamed ones by their normalized expression. Diffs follow the order of the old and then the new constraints.
+func (a *TableDiffAnalyzer) compareCheckConstraints(oldChecks, newChecks []parser.CheckConstraint) []CheckConstraintDiff {
+	diffs := []CheckConstraintDiff{}
+
+	// The diffs point into the check slices, so that the generated name of an unnamed check can be
+	// told from its position
+	newByKey := make(map[string]*parser.CheckConstraint)
+	for i := range newChecks {
+		newByKey[a.checkIdentity(newChecks[i])] = &newChecks[i]
+	}
+	oldKeys := make(map[string]bool)
+
+	for i := range oldChecks {
+		oldCheck := &oldChecks[i]
+		key := a.checkIdentity(*oldCheck)
+		oldKeys[key] = true
+
+		newCheck, hasNew := newByKey[key]
+		if !hasNew {
+			diffs = append(diffs, CheckConstraintDiff{
+				Name:       oldCheck.Name,
+				ChangeType: ChangeTypeRemoved,
+				OldCheck:   oldCheck,
+				Changes:    &CheckConstraintChanges{},
+			})
+			continue
+		}
+
+		changes := a.compareCheckConstraintDefinitions(*oldCheck, *newCheck)
+		if changes.HasChanges() {
+			diffs = append(diffs, CheckConstraintDiff{
+				Name:       oldCheck.Name,
+				ChangeType: ChangeTypeModified,
+				OldCheck:   oldCheck,
+				NewCheck:   newCheck,
+				Changes:    changes,
+			})
+		}
+	}
+
+	for i := range newChecks {
+		if newCheck := &newChecks[i]; !oldKeys[a.checkIdentity(*newCheck)] {
+			diffs = append(diffs, CheckConstraintDiff{
+				Name:       newCheck.Name,
+				ChangeType: ChangeTypeAdded,
+				NewCheck:   newCheck,
+				Changes:    &CheckConstraintChanges{},
+			})
+		}
+	}
+
+	return diffs
+}
+
+// compareCheckConstraintDefinitions compares two check constraints of the same name
+func (a *TableDiffAnalyzer) compareCheckConstraintDefinitions(oldCheck, newCheck parser.CheckConstraint) *CheckConstraintChanges {
+	changes := &CheckConstraintChanges{}
+
+	expressionEqual := oldCheck.Expression == newCheck.Expression
+	if a.Normalize {
+		expressionEqual = normalizedCheckExpression(oldCheck) == normalizedCheckExpression(newCheck)
+	}
+	if !expressionEqual {
+		changes.Expression = &FieldChange[string]{
+			Old: oldCheck.Expression,
+			New: newCheck.Expression,
+		}
+	}
+
+	if isCheckEnforced(oldCheck) != isCheckEnforced(newCheck) {
+		changes.Enforced = &FieldChange[bool]{
+			Old: isCheckEnforced(oldCheck),
+			New: isCheckEnforced(newCheck),
+		}
+	}
+
+	return changes
+}
+
+// columnCheckDefinitions formats inline CHECK constraints as they appear in a column definition
+func columnCheckDefinitions(checks []parser.CheckConstraint) []string {
+	definitions := []string{}
+	for _, check := range checks {
+		definition := fmt.Sprintf("CHECK (%s)", check.Expression)
+		if check.Name != nil && *check.Name != "" {
+			definition = fmt.Sprintf("CONSTRAINT `%s` %s", *check.Name, definition)
+		}
+		if !isCheckEnforced(check) {
+			definition += " NOT ENFORCED"
+		}
+		definitions = append(definitions, definition)
+	}
+	return definitions
+}
+
+// checkConstraintIdentity identifies a check constraint by name or, when unnamed, by its normalized expression
+func checkConstraintIdentity(check parser.CheckConstraint) string {
+	if check.Name != nil && *check.Name != "" {
+		return "name:" + *check.Name
+	}
+	return "expr:" + normalizedCheckExpression(check)
+}
+
+// checkIdentity identifies a check constraint like checkConstraintIdentity, with the name compared
+// like the other identifiers of the analyzer
+func (a *TableDiffAnalyzer) checkIdentity(check parser.CheckConstraint) string {
+	if check.Name != nil && *check.Name != "" {
+		return "name:" + a.nameKey(*check.Name)
+	}
+	return checkConstraintIdentity(check)
+}
+
+// normalizedCheckExpression returns the normalized expression of a check constraint,
+// normalizing it on demand for constraints that were not built by the parser
+func normalizedCheckExpression(check parser.CheckConstraint) string {
+	if check.NormalizedExpression != "" {
+		return check.NormalizedExpression
+	}
+	return parser.NormalizeExpression(check.Expression)
+}
+
+// isCheckEnforced reports whether a check constraint is enforced, which is MySQL's default
+func isCheckEnforced(check parser.CheckConstraint) bool {
+	return check.Enforced == nil || *check.Enforced
+}
+
 // compareTableOptions compares table options
 func (a *TableDiffAnalyzer) compareTableOptions(oldOpts, newOpts *parser.TableOptions) *TableOptionsDiff {
 	if oldOpts == nil && newOpts == nil {
 		return nil
 	}
 
+	// Options that set nothing but the counter are treated as absent
+	if a.IgnoreAutoIncrement && (oldOpts == nil || newOpts == nil) &&
+		isAutoIncrementOnly(oldOpts) && isAutoIncrementOnly(newOpts) {
+		return nil
+	}
+
 	if oldOpts == nil {
 		return &TableOptionsDiff{
 			ChangeType: ChangeTypeAdded,
@@ -495,28 +781,28 @@ func (a *TableDiffAnalyzer) compareTableOptions(oldOpts, newOpts *parser.TableOp
 	changes := &TableOptionsChanges{}
 
 	// Compare all table option attributes
-	if !ptrEqual(oldOpts.Engine, newOpts.Engine) {
+	if !a.optionValueEqual(oldOpts.Engine, newOpts.Engine) {
 		changes.Engine = &FieldChange[any]{
 			Old: ptrToValue(oldOpts.Engine),
 			New: ptrToValue(newOpts.Engine),
 		}
 	}
 
-	if !ptrEqual(oldOpts.AutoIncrement, newOpts.AutoIncrement) {
+	if !a.IgnoreAutoIncrement && !ptrEqual(oldOpts.AutoIncrement, newOpts.AutoIncrement) {
 		changes.AutoIncrement = &FieldChange[any]{
 			Old: ptrToValue(oldOpts.AutoIncrement),
 			New: ptrToValue(newOpts.AutoIncrement),
 		}
 	}
 
-	if !ptrEqual(oldOpts.CharacterSet, newOpts.CharacterSet) {
+	if !a.charsetEqual(oldOpts.CharacterSet, newOpts.CharacterSet) {
 		changes.CharacterSet = &FieldChange[any]{
 			Old: ptrToValue(oldOpts.CharacterSet),
 			New: ptrToValue(newOpts.CharacterSet),
 		}
 	}
 
-	if !ptrEqual(oldOpts.Collate, newOpts.Collate) {
+	if !a.optionValueEqual(oldOpts.Collate, newOpts.Collate) {
 		changes.Collate = &FieldChange[any]{
 			Old: ptrToValue(oldOpts.Collate),
 			New: ptrToValue(newOpts.Collate),
@@ -530,20 +816,343 @@ func (a *TableDiffAnalyzer) compareTableOptions(oldOpts, newOpts *parser.TableOp
 		}
 	}
 
-	// Add more table options comparisons as needed...
+	if !ptrEqual(oldOpts.PackKeys, newOpts.PackKeys) {
+		changes.PackKeys = &FieldChange[any]{
+			Old: ptrToValue(oldOpts.PackKeys),
+			New: ptrToValue(newOpts.PackKeys),
+		}
+	}
+
+	if !ptrEqual(oldOpts.Checksum, newOpts.Checksum) {
+		changes.Checksum = &FieldChange[any]{
+			Old: ptrToValue(oldOpts.Checksum),
+			New: ptrToValue(newOpts.Checksum),
+		}
+	}
+
+	if !ptrEqual(oldOpts.DelayKeyWrite, newOpts.DelayKeyWrite) {
+		changes.DelayKeyWrite = &FieldChange[any]{
+			Old: ptrToValue(oldOpts.DelayKeyWrite),
+			New: ptrToValue(newOpts.DelayKeyWrite),
+		}
+	}
+
+	if !ptrEqual(oldOpts.StatsPersistent, newOpts.StatsPersistent) {
+		changes.StatsPersistent = &FieldChange[any]{
+			Old: ptrToValue(oldOpts.StatsPersistent),
+			New: ptrToValue(newOpts.StatsPersistent),
+		}
+	}
+
+	if !ptrEqual(oldOpts.StatsAutoRecalc, newOpts.StatsAutoRecalc) {
+		changes.StatsAutoRecalc = &FieldChange[any]{
+			Old: ptrToValue(oldOpts.StatsAutoRecalc),
+			New: ptrToValue(newOpts.StatsAutoRecalc),
+		}
+	}
+
+	if !ptrEqual(oldOpts.StatsSamplePages, newOpts.StatsSamplePages) {
+		changes.StatsSamplePages = &FieldChange[any]{
+			Old: ptrToValue(oldOpts.StatsSamplePages),
+			New: ptrToValue(newOpts.StatsSamplePages),
+		}
+	}
+
+	if !ptrEqual(oldOpts.AvgRowLength, newOpts.AvgRowLength) {
+		changes.AvgRowLength = &FieldChange[any]{
+			Old: ptrToValue(oldOpts.AvgRowLength),
+			New: ptrToValue(newOpts.AvgRowLength),
+		}
+	}
+
+	if !ptrEqual(oldOpts.AutoextendSize, newOpts.AutoextendSize) {
+		changes.AutoextendSize = &FieldChange[any]{
+			Old: ptrToValue(oldOpts.AutoextendSize),
+			New: ptrToValue(newOpts.AutoextendSize),
+		}
+	}
+
+	if !ptrEqual(oldOpts.Connection, newOpts.Connection) {
+		changes.Connection = &FieldChange[any]{
+			Old: ptrToValue(oldOpts.Connection),
+			New: ptrToValue(newOpts.Connection),
+		}
+	}
+
+	if !a.optionValueEqual(oldOpts.RowFormat, newOpts.RowFormat) {
+		changes.RowFormat = &FieldChange[any]{
+			Old: ptrToValue(oldOpts.RowFormat),
+			New: ptrToValue(newOpts.RowFormat),
+		}
+	}
+
+	if !ptrEqual(oldOpts.KeyBlockSize, newOpts.KeyBlockSize) {
+		changes.KeyBlockSize = &FieldChange[any]{
+			Old: ptrToValue(oldOpts.KeyBlockSize),
+			New: ptrToValue(newOpts.KeyBlockSize),
+		}
+	}
+
+	if !ptrEqual(oldOpts.MaxRows, newOpts.MaxRows) {
+		changes.MaxRows = &FieldChange[any]{
+			Old: ptrToValue(oldOpts.MaxRows),
+			New: ptrToValue(newOpts.MaxRows),
+		}
+	}
+
+	if !ptrEqual(oldOpts.MinRows, newOpts.MinRows) {
+		changes.MinRows = &FieldChange[any]{
+			Old: ptrToValue(oldOpts.MinRows),
+			New: ptrToValue(newOpts.MinRows),
+		}
+	}
+
+	if !ptrEqual(oldOpts.Tablespace, newOpts.Tablespace) {
+		changes.Tablespace = &FieldChange[any]{
+			Old: ptrToValue(oldOpts.Tablespace),
+			New: ptrToValue(newOpts.Tablespace),
+		}
+	}
+
+	if !ptrEqual(oldOpts.DataDirectory, newOpts.DataDirectory) {
+		changes.DataDirectory = &FieldChange[any]{
+			Old: ptrToValue(oldOpts.DataDirectory),
+			New: ptrToValue(newOpts.DataDirectory),
+		}
+	}
+
+	if !ptrEqual(oldOpts.IndexDirectory, newOpts.IndexDirectory) {
+		changes.IndexDirectory = &FieldChange[any]{
+			Old: ptrToValue(oldOpts.IndexDirectory),
+			New: ptrToValue(newOpts.IndexDirectory),
+		}
+	}
+
+	if !a.optionValueEqual(oldOpts.Encryption, newOpts.Encryption) {
+		changes.Encryption = &FieldChange[any]{
+			Old: ptrToValue(oldOpts.Encryption),
+			New: ptrToValue(newOpts.Encryption),
+		}
+	}
+
+	if !a.optionValueEqual(oldOpts.Compression, newOpts.Compression) {
+		changes.Compression = &FieldChange[any]{
+			Old: ptrToValue(oldOpts.Compression),
+			New: ptrToValue(newOpts.Compression),
+		}
+	}
+
+	if !slices.EqualFunc(oldOpts.Union, newOpts.Union, strings.EqualFold) {
+		changes.Union = &FieldChange[any]{
+			Old: sliceToValue(oldOpts.Union),
+			New: sliceToValue(newOpts.Union),
+		}
+	}
+
+	if !a.optionValueEqual(oldOpts.InsertMethod, newOpts.InsertMethod) {
+		changes.InsertMethod = &FieldChange[any]{
+			Old: ptrToValue(oldOpts.InsertMethod),
+			New: ptrToValue(newOpts.InsertMethod),
+		}
+	}
 
 	if changes.HasChanges() {
-		return &TableOptionsDiff{
+		optionsDiff := &TableOptionsDiff{
 			ChangeType: ChangeTypeModified,
 			OldOptions: oldOpts,
 			NewOptions: newOpts,
 			Changes:    changes,
 		}
+		if changes.Engine != nil {
+			optionsDiff.Warnings = append(optionsDiff.Warnings, engineChangeWarning(oldOpts.Engine, newOpts.Engine))
+		}
+		return optionsDiff
 	}
 
 	return nil
 }
 
+// isAutoIncrementOnly reports whether table options are missing or set no option but AUTO_INCREMENT
+func isAutoIncrementOnly(opts *parser.TableOptions) bool {
+	if opts == nil {
+		return true
+	}
+	withoutCounter := *opts
+	withoutCounter.AutoIncrement = nil
+	return reflect.ValueOf(withoutCounter).IsZero()
+}
+
+// partitionDefinitionSummaries describes each partition definition in one line, e.g.
+// "p0 VALUES LESS THAN (100) COMMENT 'old rows'", so that definitions can be compared and printed
+func partitionDefinitionSummaries(partitions []parser.PartitionDefinition) []string {
+	summaries := make([]string, len(partitions))
+	for i, partition := range partitions {
+		summary := partition.Name
+		if len(partition.Values) > 0 {
+			values := strings.Join(partition.Values, ", ")
+			switch {
+			case partition.Type == "LIST":
+				summary += fmt.Sprintf(" VALUES IN (%s)", values)
+			case values == "MAXVALUE":
+				summary += " VALUES LESS THAN MAXVALUE"
+			default:
+				summary += fmt.Sprintf(" VALUES LESS THAN (%s)", values)
+			}
+		}
+		if partition.Comment != nil {
+			summary += fmt.Sprintf(" COMMENT '%s'", *partition.Comment)
+		}
+		if partition.DataDirectory != nil {
+			summary += fmt.Sprintf(" DATA DIRECTORY '%s'", *partition.DataDirectory)
+		}
+		if partition.IndexDirectory != nil {
+			summary += fmt.Sprintf(" INDEX DIRECTORY '%s'", *partition.IndexDirectory)
+		}
+		if partition.MaxRows != nil {
+			summary += fmt.Sprintf(" MAX_ROWS %d", *partition.MaxRows)
+		}
+		if partition.MinRows != nil {
+			summary += fmt.Sprintf(" MIN_ROWS %d", *partition.MinRows)
+		}
+		if partition.Tablespace != nil {
+			summary += fmt.Sprintf(" TABLESPACE %s", *partition.Tablespace)
+		}
+		summaries[i] = summary
+	}
+	return summaries
+}
+
+// engineChangeWarning describes the implications of switching a table to a different storage engine
+func engineChangeWarning(oldEngine, newEngine *string) string {
+	oldName := "default"
+	if oldEngine != nil {
+		oldName = *oldEngine
+	}
+	newName := "default"
+	if newEngine != nil {
+		newName = *newEngine
+	}
+
+	oldInnoDB := oldEngine != nil && strings.EqualFold(*oldEngine, "InnoDB")
+	newInnoDB := newEngine != nil && strings.EqualFold(*newEngine, "InnoDB")
+
+	switch {
+	case !oldInnoDB && newInnoDB:
+		return fmt.Sprintf("engine change %s -> %s rebuilds the table; InnoDB enforces FOREIGN KEY constraints and adds transactions and row-level locking, so existing data must satisfy all foreign keys", oldName, newName)
+	case oldInnoDB && !newInnoDB:
+		return fmt.Sprintf("engine change %s -> %s rebuilds the table; %s does not enforce FOREIGN KEY constraints or support transactions, so referential integrity and crash safety are lost", oldName, newName, newName)
+	default:
+		return fmt.Sprintf("engine change %s -> %s rebuilds the table; foreign key, transaction and locking behavior may differ between engines", oldName, newName)
+	}
+}
+
+// InnoDB index key limits: a key part of a COMPACT or REDUNDANT table holds at most 767 bytes,
+// a key of any row format at most 3072 bytes
+const (
+	smallKeyPrefixLimit = 767
+	indexKeyLimit       = 3072
+)
+
+// utf8mb4KeyLengthWarning warns when a table switches its character set to utf8mb4 and the indexes on
+// string columns that use the table character set no longer fit the InnoDB key limits at 4 bytes per
+// character. Key parts over 767 bytes need ROW_FORMAT=DYNAMIC or COMPRESSED.
+func utf8mb4KeyLengthWarning(optionsDiff *TableOptionsDiff, table *parser.CreateTableStatement) string {
+	if optionsDiff == nil || optionsDiff.Changes == nil || optionsDiff.Changes.CharacterSet == nil || table == nil {
+		return ""
+	}
+	newOpts := optionsDiff.NewOptions
+	if newOpts == nil || newOpts.CharacterSet == nil || !strings.EqualFold(*newOpts.CharacterSet, "utf8mb4") {
+		return ""
+	}
+	oldCharset := "default"
+	if optionsDiff.OldOptions != nil && optionsDiff.OldOptions.CharacterSet != nil {
+		oldCharset = *optionsDiff.OldOptions.CharacterSet
+	}
+	largePrefix := newOpts.RowFormat != nil &&
+		(strings.EqualFold(*newOpts.RowFormat, "DYNAMIC") || strings.EqualFold(*newOpts.RowFormat, "COMPRESSED"))
+
+	// Every index of the table with its key parts, including PRIMARY KEY and UNIQUE column attributes
+	type tableIndex struct {
+		name    string
+		columns []parser.IndexColumn
+	}
+	var indexes []tableIndex
+	if table.PrimaryKey != nil {
+		indexes = append(indexes, tableIndex{"PRIMARY", table.PrimaryKey.Columns})
+	}
+	for _, col := range table.Columns {
+		switch {
+		case col.PrimaryKey:
+			indexes = append(indexes, tableIndex{"PRIMARY", []parser.IndexColumn{{Name: col.Name}}})
+		case col.Unique:
+			indexes = append(indexes, tableIndex{col.Name, []parser.IndexColumn{{Name: col.Name}}})
+		}
+	}
+	for _, idx := range table.Indexes {
+		name := ""
+		if idx.Name != nil {
+			name = *idx.Name
+		} else if len(idx.Columns) > 0 {
+			name = idx.Columns[0].Name
+		}
+		indexes = append(indexes, tableIndex{name, idx.Columns})
+	}
+
+	var tooLong []string
+	for _, idx := range indexes {
+		keyLength := 0
+		prefixTooLong := false
+		for _, part := range idx.columns {
+			partLength := utf8mb4KeyPartLength(table, part)
+			keyLength += partLength
+			if partLength > smallKeyPrefixLimit {
+				prefixTooLong = true
+			}
+		}
+		if keyLength > indexKeyLimit || (prefixTooLong && !largePrefix) {
+			tooLong = append(tooLong, fmt.Sprintf("`%s` (%d bytes)", idx.name, keyLength))
+		}
+	}
+	if len(tooLong) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("character set change %s -> utf8mb4 stores up to 4 bytes per character, so the keys of index %s may exceed the InnoDB key limits; "+
+		"key parts over %d bytes require ROW_FORMAT=DYNAMIC or COMPRESSED and keys over %d bytes need shorter prefixes",
+		oldCharset, strings.Join(tooLong, ", "), smallKeyPrefixLimit, indexKeyLimit)
+}
+
+// utf8mb4KeyPartLength returns the bytes a key part takes when its column is stored in utf8mb4, or
+// zero when the column is not a string column using the table character set
+func utf8mb4KeyPartLength(table *parser.CreateTableStatement, part parser.IndexColumn) int {
+	for _, col := range table.Columns {
+		if !strings.EqualFold(col.Name, part.Name) {
+			continue
+		}
+		// A column with its own character set or collation keeps it when the table default changes
+		if col.CharacterSet != nil || col.Collation != nil {
+			return 0
+		}
+
+		chars := 0
+		switch strings.ToUpper(col.DataType.Name) {
+		case "CHAR", "VARCHAR":
+			if len(col.DataType.Parameters) > 0 {
+				chars, _ = strconv.Atoi(col.DataType.Parameters[0])
+			}
+		case "TINYTEXT", "TEXT", "MEDIUMTEXT", "LONGTEXT":
+			// Text columns are always indexed by a prefix
+		default:
+			return 0
+		}
+		if part.Length != nil {
+			chars = *part.Length
+		}
+		return chars * 4
+	}
+	return 0
+}
+
 // comparePartitions compares partition options
 func (a *TableDiffAnalyzer) comparePartitions(oldPart, newPart *parser.PartitionOptions) *PartitionDiff {
 	if oldPart == nil && newPart == nil {
@@ -591,7 +1200,7 @@ func (a *TableDiffAnalyzer) comparePartitions(oldPart, newPart *parser.Partition
 		}
 	}
 
-	if !slices.Equal(oldPart.Columns, newPart.Columns) {
+	if !a.valueListEqual(oldPart.Columns, newPart.Columns) {
 		changes.Columns = &FieldChange[[]string]{
 			Old: oldPart.Columns,
 			New: newPart.Columns,
@@ -605,13 +1214,19 @@ func (a *TableDiffAnalyzer) comparePartitions(oldPart, newPart *parser.Partition
 		}
 	}
 
-	// Compare partition definitions (simplified)
-	oldPartCount := len(oldPart.Partitions)
-	newPartCount := len(newPart.Partitions)
-	if oldPartCount != newPartCount {
+	if !ptrEqual(oldPart.SubPartitionCount, newPart.SubPartitionCount) {
+		changes.SubPartitionsCount = &FieldChange[any]{
+			Old: ptrToValue(oldPart.SubPartitionCount),
+			New: ptrToValue(newPart.SubPartitionCount),
+		}
+	}
+
+	oldDefinitions := partitionDefinitionSummaries(oldPart.Partitions)
+	newDefinitions := partitionDefinitionSummaries(newPart.Partitions)
+	if !slices.Equal(oldDefinitions, newDefinitions) {
 		changes.PartitionDefinitions = &FieldChange[any]{
-			Old: oldPartCount,
-			New: newPartCount,
+			Old: sliceToValue(oldDefinitions),
+			New: sliceToValue(newDefinitions),
 		}
 	}
 
@@ -0,0 +1,146 @@
+package diff
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/n0madic/mysql-diff/pkg/parser"
+)
+
+// ConversionRisk classifies a column data type change by whether existing values survive it
+type ConversionRisk string
+
+const (
+	// ConversionSafe means every value of the old type can be stored in the new type
+	ConversionSafe ConversionRisk = "safe"
+	// ConversionUnsafe means existing values may be truncated, rounded or rejected
+	ConversionUnsafe ConversionRisk = "unsafe"
+)
+
+// typeFamilies lists data types whose members can each hold every value of the members before them
+var typeFamilies = [][]string{
+	{"TINYINT", "SMALLINT", "MEDIUMINT", "INT", "BIGINT"},
+	{"FLOAT", "DOUBLE"},
+	{"TINYTEXT", "TEXT", "MEDIUMTEXT", "LONGTEXT"},
+	{"TINYBLOB", "BLOB", "MEDIUMBLOB", "LONGBLOB"},
+}
+
+// ClassifyTypeChange reports whether changing a column from oldDT to newDT preserves existing values.
+// Conversions listed in SafeTypeConversions are safe; otherwise a conversion is safe when it keeps the
+// type and does not decrease its length or precision, or widens within a type family such as INT to
+// BIGINT. Everything else is unsafe.
+func (a *TableDiffAnalyzer) ClassifyTypeChange(oldDT, newDT parser.DataType) ConversionRisk {
+	oldDT = a.canonicalDataType(oldDT)
+	newDT = a.canonicalDataType(newDT)
+	oldName := strings.ToUpper(oldDT.Name)
+	newName := strings.ToUpper(newDT.Name)
+
+	for from, targets := range a.SafeTypeConversions {
+		if strings.EqualFold(from, oldName) && slices.ContainsFunc(targets, func(target string) bool {
+			return strings.EqualFold(target, newName)
+		}) {
+			return ConversionSafe
+		}
+	}
+
+	// Dropping UNSIGNED loses the upper half of the range unless the type also widens
+	signednessSafe := oldDT.Unsigned == newDT.Unsigned || (oldDT.Unsigned && !newDT.Unsigned && oldName != newName)
+
+	if oldName == newName {
+		// The display width of integer types does not limit their values
+		if slices.Contains(typeFamilies[0], oldName) {
+			return conversionRisk(signednessSafe)
+		}
+		if signednessSafe && parametersWiden(oldName, oldDT.Parameters, newDT.Parameters) {
+			return ConversionSafe
+		}
+		return ConversionUnsafe
+	}
+
+	for _, family := range typeFamilies {
+		oldRank := slices.Index(family, oldName)
+		newRank := slices.Index(family, newName)
+		if oldRank >= 0 && newRank > oldRank && signednessSafe {
+			return ConversionSafe
+		}
+	}
+	return ConversionUnsafe
+}
+
+// ParseSafeTypeConversions parses a comma-separated list of from:to data type conversions into a
+// SafeTypeConversions map, e.g. "VARCHAR:TEXT,VARCHAR:MEDIUMTEXT"
+func ParseSafeTypeConversions(value string) (map[string][]string, error) {
+	conversions := make(map[string][]string)
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		from, to, found := strings.Cut(entry, ":")
+		from = strings.ToUpper(strings.TrimSpace(from))
+		to = strings.ToUpper(strings.TrimSpace(to))
+		if !found || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid type conversion %q: expected from:to", entry)
+		}
+		if !slices.Contains(conversions[from], to) {
+			conversions[from] = append(conversions[from], to)
+		}
+	}
+
+	return conversions, nil
+}
+
+// conversionRisk returns ConversionSafe for true and ConversionUnsafe for false
+func conversionRisk(safe bool) ConversionRisk {
+	if safe {
+		return ConversionSafe
+	}
+	return ConversionUnsafe
+}
+
+// parametersWiden reports whether the new type parameters allow at least the values of the old ones.
+// ENUM and SET must keep all their values, and DECIMAL must keep both its digits before the
+// decimal point and its scale.
+func parametersWiden(typeName string, oldParams, newParams []string) bool {
+	if isValueListType(typeName) {
+		for _, value := range oldParams {
+			if !slices.Contains(newParams, value) {
+				return false
+			}
+		}
+		return true
+	}
+
+	oldValues, oldOK := parseIntParameters(oldParams)
+	newValues, newOK := parseIntParameters(newParams)
+	if !oldOK || !newOK || len(oldValues) != len(newValues) {
+		return slices.Equal(oldParams, newParams)
+	}
+
+	if typeName == "DECIMAL" && len(oldValues) == 2 {
+		return newValues[0]-newValues[1] >= oldValues[0]-oldValues[1] && newValues[1] >= oldValues[1]
+	}
+	for i := range oldValues {
+		if newValues[i] < oldValues[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// parseIntParameters converts type parameters to integers, reporting whether all of them are numbers
+func parseIntParameters(params []string) ([]int, bool) {
+	values := make([]int, len(params))
+	for i, param := range params {
+		value, err := strconv.Atoi(param)
+		if err != nil {
+			return nil, false
+		}
+		values[i] = value
+	}
+	return values, true
+}
@@ -0,0 +1,116 @@
+package diff
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/n0madic/mysql-diff/pkg/parser"
+)
+
+func TestClassifyTypeChange(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldDT    parser.DataType
+		newDT    parser.DataType
+		expected ConversionRisk
+	}{
+		{"int to bigint", parser.DataType{Name: "INT"}, parser.DataType{Name: "BIGINT"}, ConversionSafe},
+		{"int display width", parser.DataType{Name: "INT", Parameters: []string{"11"}}, parser.DataType{Name: "INT", Parameters: []string{"5"}}, ConversionSafe},
+		{"bigint to int", parser.DataType{Name: "BIGINT"}, parser.DataType{Name: "INT"}, ConversionUnsafe},
+		{"unsigned to signed", parser.DataType{Name: "INT", Unsigned: true}, parser.DataType{Name: "INT"}, ConversionUnsafe},
+		{"unsigned to wider signed", parser.DataType{Name: "INT", Unsigned: true}, parser.DataType{Name: "BIGINT"}, ConversionSafe},
+		{"signed to unsigned", parser.DataType{Name: "INT"}, parser.DataType{Name: "BIGINT", Unsigned: true}, ConversionUnsafe},
+		{"varchar length increase", parser.DataType{Name: "VARCHAR", Parameters: []string{"50"}}, parser.DataType{Name: "VARCHAR", Parameters: []string{"100"}}, ConversionSafe},
+		{"varchar length decrease", parser.DataType{Name: "VARCHAR", Parameters: []string{"100"}}, parser.DataType{Name: "VARCHAR", Parameters: []string{"50"}}, ConversionUnsafe},
+		{"decimal precision increase", parser.DataType{Name: "DECIMAL", Parameters: []string{"10", "2"}}, parser.DataType{Name: "DECIMAL", Parameters: []string{"12", "4"}}, ConversionSafe},
+		{"decimal integer digits decrease", parser.DataType{Name: "DECIMAL", Parameters: []string{"10", "2"}}, parser.DataType{Name: "DECIMAL", Parameters: []string{"10", "4"}}, ConversionUnsafe},
+		{"numeric synonym", parser.DataType{Name: "NUMERIC", Parameters: []string{"10", "2"}}, parser.DataType{Name: "DECIMAL", Parameters: []string{"12", "2"}}, ConversionSafe},
+		{"enum value added", parser.DataType{Name: "ENUM", Parameters: []string{"'a'", "'b'"}}, parser.DataType{Name: "ENUM", Parameters: []string{"'a'", "'b'", "'c'"}}, ConversionSafe},
+		{"enum value removed", parser.DataType{Name: "ENUM", Parameters: []string{"'a'", "'b'"}}, parser.DataType{Name: "ENUM", Parameters: []string{"'a'"}}, ConversionUnsafe},
+		{"text to mediumtext", parser.DataType{Name: "TEXT"}, parser.DataType{Name: "MEDIUMTEXT"}, ConversionSafe},
+		{"varchar to text", parser.DataType{Name: "VARCHAR", Parameters: []string{"255"}}, parser.DataType{Name: "TEXT"}, ConversionUnsafe},
+		{"int to varchar", parser.DataType{Name: "INT"}, parser.DataType{Name: "VARCHAR", Parameters: []string{"20"}}, ConversionUnsafe},
+	}
+
+	analyzer := NewTableDiffAnalyzer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := analyzer.ClassifyTypeChange(tt.oldDT, tt.newDT); result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestCustomSafeTypeConversions(t *testing.T) {
+	oldTable := parseSingleTable(t, "CREATE TABLE posts (id INT, body VARCHAR(255), views BIGINT)")
+	newTable := parseSingleTable(t, "CREATE TABLE posts (id INT, body TEXT, views INT)")
+
+	conversions := func(analyzer *TableDiffAnalyzer) map[string]ConversionRisk {
+		result := make(map[string]ConversionRisk)
+		for _, colDiff := range analyzer.CompareTables(oldTable, newTable).ColumnDiffs {
+			result[colDiff.Name] = colDiff.Conversion
+		}
+		return result
+	}
+
+	defaults := conversions(NewTableDiffAnalyzer())
+	if defaults["body"] != ConversionUnsafe || defaults["views"] != ConversionUnsafe {
+		t.Fatalf("Expected both conversions to be unsafe by default, got %v", defaults)
+	}
+
+	analyzer := NewTableDiffAnalyzer()
+	analyzer.SafeTypeConversions = map[string][]string{"varchar": {"text", "MEDIUMTEXT"}}
+	custom := conversions(analyzer)
+	if custom["body"] != ConversionSafe {
+		t.Errorf("Expected VARCHAR to TEXT to be safe with custom rules, got %q", custom["body"])
+	}
+	if custom["views"] != ConversionUnsafe {
+		t.Errorf("Expected BIGINT to INT to stay unsafe, got %q", custom["views"])
+	}
+
+	// Only unsafe conversions are annotated in explanations
+	unsafe := "Changed `views` from BIGINT to INT (existing values may be truncated or rejected)"
+	sentences := ExplainTableDiff(analyzer.CompareTables(oldTable, newTable))
+	if !slices.Contains(sentences, unsafe) {
+		t.Errorf("Expected sentence %q, got %v", unsafe, sentences)
+	}
+	if !slices.Contains(sentences, "Changed `body` from VARCHAR(255) to TEXT") {
+		t.Errorf("Expected unannotated body sentence, got %v", sentences)
+	}
+}
+
+func TestCustomSafeConversionExplanation(t *testing.T) {
+	oldTable := parseSingleTable(t, "CREATE TABLE posts (id INT, title VARCHAR(255))")
+	newTable := parseSingleTable(t, "CREATE TABLE posts (id INT, title VARCHAR(100))")
+
+	unsafe := "Changed `title` from VARCHAR(255) to VARCHAR(100) (decreased length; existing values may be truncated or rejected)"
+	if sentences := ExplainTableDiff(NewTableDiffAnalyzer().CompareTables(oldTable, newTable)); !slices.Contains(sentences, unsafe) {
+		t.Errorf("Expected sentence %q, got %v", unsafe, sentences)
+	}
+
+	analyzer := NewTableDiffAnalyzer()
+	analyzer.SafeTypeConversions = map[string][]string{"VARCHAR": {"VARCHAR"}}
+	safe := "Changed `title` from VARCHAR(255) to VARCHAR(100)"
+	if sentences := ExplainTableDiff(analyzer.CompareTables(oldTable, newTable)); !slices.Contains(sentences, safe) {
+		t.Errorf("Expected sentence %q, got %v", safe, sentences)
+	}
+}
+
+func TestParseSafeTypeConversions(t *testing.T) {
+	conversions, err := ParseSafeTypeConversions("varchar:TEXT, VARCHAR:mediumtext,VARCHAR:TEXT,,INT:BIGINT")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string][]string{"VARCHAR": {"TEXT", "MEDIUMTEXT"}, "INT": {"BIGINT"}}
+	if !reflect.DeepEqual(conversions, expected) {
+		t.Errorf("Expected %v, got %v", expected, conversions)
+	}
+
+	for _, value := range []string{"VARCHAR", "VARCHAR:", ":TEXT"} {
+		if _, err := ParseSafeTypeConversions(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
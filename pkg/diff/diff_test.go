@@ -1,9 +1,15 @@
 package diff
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 
+	"github.com/n0madic/mysql-diff/pkg/output"
 	"github.com/n0madic/mysql-diff/pkg/parser"
 )
 
@@ -710,6 +716,85 @@ func TestTableOptionsModified(t *testing.T) {
 	}
 }
 
+// TestEngineChangeWarning tests that an engine change carries an advisory annotation
+func TestEngineChangeWarning(t *testing.T) {
+	sql1 := "CREATE TABLE test (id INT) ENGINE=MyISAM"
+	sql2 := "CREATE TABLE test (id INT) ENGINE=InnoDB"
+
+	oldTables, err := parser.ParseSQLDump(sql1)
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(sql2)
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	analyzer := NewTableDiffAnalyzer()
+	diff := analyzer.CompareTables(oldTables[0], newTables[0])
+
+	if diff.TableOptionsDiff == nil {
+		t.Fatal("Expected table options diff to be not nil")
+	}
+	if len(diff.TableOptionsDiff.Warnings) != 1 {
+		t.Fatalf("Expected 1 warning for engine change, got %d", len(diff.TableOptionsDiff.Warnings))
+	}
+
+	warning := diff.TableOptionsDiff.Warnings[0]
+	if !strings.Contains(warning, "MyISAM -> InnoDB") {
+		t.Errorf("Expected warning to mention the engine change, got: %s", warning)
+	}
+	if !strings.Contains(warning, "FOREIGN KEY") {
+		t.Errorf("Expected warning to mention foreign key implications, got: %s", warning)
+	}
+
+	// Non-engine option changes should not carry the advisory
+	sql3 := "CREATE TABLE test (id INT) ENGINE=MyISAM COMMENT='changed'"
+	commentTables, err := parser.ParseSQLDump(sql3)
+	if err != nil {
+		t.Fatalf("Failed to parse SQL: %v", err)
+	}
+	diff = analyzer.CompareTables(oldTables[0], commentTables[0])
+	if diff.TableOptionsDiff == nil {
+		t.Fatal("Expected table options diff to be not nil")
+	}
+	if len(diff.TableOptionsDiff.Warnings) != 0 {
+		t.Errorf("Expected no warnings for comment change, got %v", diff.TableOptionsDiff.Warnings)
+	}
+}
+
+// TestUtf8mb4KeyLengthWarning tests the advisory for a utf8mb4 switch of a table with indexed long strings
+func TestUtf8mb4KeyLengthWarning(t *testing.T) {
+	oldTable := parseSingleTable(t, `CREATE TABLE test (id INT, email VARCHAR(255), note TEXT, code VARCHAR(10),
+		KEY idx_email (email), KEY idx_note (note(100)), KEY idx_code (code)) ENGINE=InnoDB DEFAULT CHARSET=latin1 ROW_FORMAT=COMPACT`)
+
+	analyzer := NewTableDiffAnalyzer()
+	diff := analyzer.CompareTables(oldTable, parseSingleTable(t, `CREATE TABLE test (id INT, email VARCHAR(255), note TEXT, code VARCHAR(10),
+		KEY idx_email (email), KEY idx_note (note(100)), KEY idx_code (code)) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 ROW_FORMAT=COMPACT`))
+	if diff.TableOptionsDiff == nil || len(diff.TableOptionsDiff.Warnings) != 1 {
+		t.Fatalf("Expected 1 warning for the utf8mb4 change, got %+v", diff.TableOptionsDiff)
+	}
+	warning := diff.TableOptionsDiff.Warnings[0]
+	if !strings.Contains(warning, "latin1 -> utf8mb4") || !strings.Contains(warning, "`idx_email` (1020 bytes)") || !strings.Contains(warning, "ROW_FORMAT=DYNAMIC") {
+		t.Errorf("Expected the warning to name the change, index and row format, got: %s", warning)
+	}
+	if strings.Contains(warning, "idx_note") || strings.Contains(warning, "idx_code") {
+		t.Errorf("Expected short keys to fit, got: %s", warning)
+	}
+
+	// DYNAMIC allows 3072-byte key parts, and columns with their own character set are unaffected
+	for _, sql := range []string{
+		`CREATE TABLE test (id INT, email VARCHAR(255), note TEXT, code VARCHAR(10),
+			KEY idx_email (email), KEY idx_note (note(100)), KEY idx_code (code)) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 ROW_FORMAT=DYNAMIC`,
+		`CREATE TABLE test (id INT, email VARCHAR(255) CHARACTER SET latin1, note TEXT, code VARCHAR(10),
+			KEY idx_email (email), KEY idx_note (note(100)), KEY idx_code (code)) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 ROW_FORMAT=COMPACT`,
+	} {
+		if diff := analyzer.CompareTables(oldTable, parseSingleTable(t, sql)); len(diff.TableOptionsDiff.Warnings) != 0 {
+			t.Errorf("Expected no warning for %s, got %v", sql, diff.TableOptionsDiff.Warnings)
+		}
+	}
+}
+
 // TestMultipleTableOptionsChanges tests detection of multiple table options changes
 func TestMultipleTableOptionsChanges(t *testing.T) {
 	sql1 := `
@@ -786,6 +871,61 @@ func TestMultipleTableOptionsChanges(t *testing.T) {
 	}
 }
 
+// TestCharsetSpellingEquivalence tests that DEFAULT CHARSET and CHARACTER SET compare equal
+func TestCharsetSpellingEquivalence(t *testing.T) {
+	sql1 := "CREATE TABLE test (id INT) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4"
+	sql2 := "CREATE TABLE test (id INT) ENGINE=InnoDB CHARACTER SET=utf8mb4"
+
+	oldTables, err := parser.ParseSQLDump(sql1)
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(sql2)
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	analyzer := NewTableDiffAnalyzer()
+	diff := analyzer.CompareTables(oldTables[0], newTables[0])
+
+	if diff.HasChanges() {
+		t.Errorf("Expected no changes between charset spellings, got table options diff %+v", diff.TableOptionsDiff)
+	}
+}
+
+// TestEngineCaseInsensitiveComparison tests that option values differing only in case are equal when normalized
+func TestEngineCaseInsensitiveComparison(t *testing.T) {
+	sql1 := "CREATE TABLE test (id INT) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci"
+	sql2 := "CREATE TABLE test (id INT) ENGINE=INNODB DEFAULT CHARSET=UTF8MB4 COLLATE=UTF8MB4_GENERAL_CI"
+
+	oldTables, err := parser.ParseSQLDump(sql1)
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(sql2)
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	analyzer := NewTableDiffAnalyzer()
+	diff := analyzer.CompareTables(oldTables[0], newTables[0])
+
+	if diff.HasChanges() {
+		t.Errorf("Expected no changes when normalized, got table options diff %+v", diff.TableOptionsDiff.Changes)
+	}
+
+	// Without normalization the raw values are compared
+	analyzer.Normalize = false
+	diff = analyzer.CompareTables(oldTables[0], newTables[0])
+
+	if diff.TableOptionsDiff == nil {
+		t.Fatal("Expected table options diff without normalization")
+	}
+	if diff.TableOptionsDiff.Changes.Engine == nil {
+		t.Error("Expected engine change without normalization")
+	}
+}
+
 // TestPartitionOptionsAdded tests detection of added partitioning
 func TestPartitionOptionsAdded(t *testing.T) {
 	sql1 := "CREATE TABLE test (id INT, data VARCHAR(100))"
@@ -828,6 +968,121 @@ func TestPartitionOptionsAdded(t *testing.T) {
 	}
 }
 
+// TestPartitionLinearChange tests detection of LINEAR being added to hash partitioning
+// TestPartitionDefinitionChanges tests that changed bounds and options of individual partitions
+// are detected even when the number of partitions stays the same
+func TestPartitionDefinitionChanges(t *testing.T) {
+	oldSQL := "CREATE TABLE events (id INT) PARTITION BY RANGE (id) (PARTITION p0 VALUES LESS THAN (100), PARTITION p1 VALUES LESS THAN MAXVALUE)"
+	tests := []struct {
+		name    string
+		newSQL  string
+		changed bool
+	}{
+		{"same definitions", "CREATE TABLE events (id INT) PARTITION BY RANGE (id) (PARTITION p0 VALUES LESS THAN (100), PARTITION p1 VALUES LESS THAN MAXVALUE)", false},
+		{"changed bound", "CREATE TABLE events (id INT) PARTITION BY RANGE (id) (PARTITION p0 VALUES LESS THAN (200), PARTITION p1 VALUES LESS THAN MAXVALUE)", true},
+		{"renamed partition", "CREATE TABLE events (id INT) PARTITION BY RANGE (id) (PARTITION p_low VALUES LESS THAN (100), PARTITION p1 VALUES LESS THAN MAXVALUE)", true},
+		{"added comment", "CREATE TABLE events (id INT) PARTITION BY RANGE (id) (PARTITION p0 VALUES LESS THAN (100) COMMENT 'old', PARTITION p1 VALUES LESS THAN MAXVALUE)", true},
+	}
+
+	oldTables, err := parser.ParseSQLDump(oldSQL)
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newTables, err := parser.ParseSQLDump(tt.newSQL)
+			if err != nil {
+				t.Fatalf("Failed to parse new SQL: %v", err)
+			}
+
+			partitionDiff := NewTableDiffAnalyzer().CompareTables(oldTables[0], newTables[0]).PartitionDiff
+			if changed := partitionDiff != nil && partitionDiff.Changes.PartitionDefinitions != nil; changed != tt.changed {
+				t.Errorf("Expected partition definition change %v, got %+v", tt.changed, partitionDiff)
+			}
+		})
+	}
+}
+
+func TestPartitionLinearChange(t *testing.T) {
+	sql1 := "CREATE TABLE test (id INT) PARTITION BY HASH(id) PARTITIONS 4"
+	sql2 := "CREATE TABLE test (id INT) PARTITION BY LINEAR HASH(id) PARTITIONS 4"
+
+	oldTables, err := parser.ParseSQLDump(sql1)
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(sql2)
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	analyzer := NewTableDiffAnalyzer()
+	diff := analyzer.CompareTables(oldTables[0], newTables[0])
+
+	if diff.PartitionDiff == nil {
+		t.Fatal("Expected partition diff to be not nil")
+	}
+	if diff.PartitionDiff.ChangeType != ChangeTypeModified {
+		t.Errorf("Expected partition change type MODIFIED, got %s", diff.PartitionDiff.ChangeType)
+	}
+
+	linearChange := diff.PartitionDiff.Changes.Linear
+	if linearChange == nil {
+		t.Fatal("Expected linear change in partition diff")
+	}
+	if linearChange.Old || !linearChange.New {
+		t.Errorf("Expected linear change false->true, got %v->%v", linearChange.Old, linearChange.New)
+	}
+	if diff.PartitionDiff.Changes.Type != nil {
+		t.Error("Expected partition type to be unchanged")
+	}
+}
+
+// TestSubPartitionCountChange tests detection of a change in the number of subpartitions
+func TestSubPartitionCountChange(t *testing.T) {
+	sql1 := `
+		CREATE TABLE sales (id INT, sold DATE)
+		PARTITION BY RANGE (YEAR(sold)) SUBPARTITION BY HASH (TO_DAYS(sold)) SUBPARTITIONS 2 (
+			PARTITION p0 VALUES LESS THAN (2020),
+			PARTITION p1 VALUES LESS THAN MAXVALUE
+		)
+	`
+	sql2 := `
+		CREATE TABLE sales (id INT, sold DATE)
+		PARTITION BY RANGE (YEAR(sold)) SUBPARTITION BY HASH (TO_DAYS(sold)) SUBPARTITIONS 4 (
+			PARTITION p0 VALUES LESS THAN (2020),
+			PARTITION p1 VALUES LESS THAN MAXVALUE
+		)
+	`
+
+	oldTables, err := parser.ParseSQLDump(sql1)
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(sql2)
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	analyzer := NewTableDiffAnalyzer()
+	diff := analyzer.CompareTables(oldTables[0], newTables[0])
+
+	if diff.PartitionDiff == nil {
+		t.Fatal("Expected partition diff to be not nil")
+	}
+
+	subChange := diff.PartitionDiff.Changes.SubPartitionsCount
+	if subChange == nil {
+		t.Fatal("Expected subpartitions count change in partition diff")
+	}
+	if subChange.Old != 2 || subChange.New != 4 {
+		t.Errorf("Expected subpartitions count change 2->4, got %v->%v", subChange.Old, subChange.New)
+	}
+	if diff.PartitionDiff.Changes.Type != nil || diff.PartitionDiff.Changes.Expression != nil {
+		t.Error("Expected partition type and expression to be unchanged")
+	}
+}
+
 // TestDataTypeUnsignedZerofillChanges tests detection of UNSIGNED and ZEROFILL changes
 func TestDataTypeUnsignedZerofillChanges(t *testing.T) {
 	sql1 := "CREATE TABLE test (id INT)"
@@ -1041,6 +1296,71 @@ func TestPrintTableDiffFunction(t *testing.T) {
 	PrintDiffSummary(diff)
 }
 
+// TestFprintTableDiffDetailedOutput tests that detailed output is written to the given writer
+func TestFprintTableDiffDetailedOutput(t *testing.T) {
+	colorsEnabled := output.Colors.Enabled
+	output.SetColorsEnabled(false)
+	defer output.SetColorsEnabled(colorsEnabled)
+
+	oldTables, err := parser.ParseSQLDump("CREATE TABLE test (id INT, code VARCHAR(10), INDEX idx_code (code))")
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump("CREATE TABLE test (id INT, code VARCHAR(20), name VARCHAR(255))")
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	var buf bytes.Buffer
+	FprintTableDiff(&buf, CompareTables(oldTables[0], newTables[0]), true)
+	out := buf.String()
+
+	for _, expected := range []string{
+		"TABLE DIFF: test -> test",
+		"Columns: +1 -0 ~1",
+		"Indexes: +0 -1 ~0",
+		"COLUMN CHANGES:",
+		"+ name: VARCHAR(255)",
+		"data_type: VARCHAR(10) -> VARCHAR(20)",
+		"INDEX CHANGES:",
+		"- INDEX idx_code (code)",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, out)
+		}
+	}
+
+	buf.Reset()
+	FprintTableDiff(&buf, CompareTables(oldTables[0], newTables[0]), false)
+	if strings.Contains(buf.String(), "COLUMN CHANGES:") {
+		t.Errorf("Expected non-detailed output without column changes, got:\n%s", buf.String())
+	}
+}
+
+// TestFprintDiffSummaryOutput tests the concise summary written to a writer
+func TestFprintDiffSummaryOutput(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump("CREATE TABLE test (id INT)")
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump("CREATE TABLE test (id INT, name VARCHAR(255))")
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	var buf bytes.Buffer
+	FprintDiffSummary(&buf, CompareTables(oldTables[0], newTables[0]))
+	if buf.String() != "Table test: +1 cols\n" {
+		t.Errorf("Unexpected summary: %q", buf.String())
+	}
+
+	buf.Reset()
+	FprintDiffSummary(&buf, CompareTables(oldTables[0], oldTables[0]))
+	if buf.String() != "Table test: No changes\n" {
+		t.Errorf("Unexpected summary: %q", buf.String())
+	}
+}
+
 // TestEmptyTablesComparison tests comparison of tables with no columns (edge case)
 func TestEmptyTablesComparison(t *testing.T) {
 	sql1 := "CREATE TABLE test1 (id INT)"
@@ -1182,6 +1502,37 @@ func TestGetSummaryMethod(t *testing.T) {
 	}
 }
 
+// TestColumnChangeHelpers tests that the column helpers return the added, removed and modified subsets
+func TestColumnChangeHelpers(t *testing.T) {
+	oldTable := parseSingleTable(t, "CREATE TABLE test (id INT, name VARCHAR(50), age INT, note TEXT)")
+	newTable := parseSingleTable(t, "CREATE TABLE test (id BIGINT, name VARCHAR(50), email VARCHAR(255), note TEXT, city VARCHAR(100))")
+
+	diff := CompareTables(oldTable, newTable)
+
+	var added []string
+	for _, col := range diff.AddedColumns() {
+		added = append(added, col.Name)
+	}
+	slices.Sort(added) // column diffs are not ordered
+	if !reflect.DeepEqual(added, []string{"city", "email"}) {
+		t.Errorf("Expected added columns city and email, got %v", added)
+	}
+
+	removed := diff.RemovedColumns()
+	if len(removed) != 1 || removed[0].Name != "age" {
+		t.Errorf("Expected removed column age, got %v", removed)
+	}
+
+	modified := diff.ModifiedColumns()
+	if len(modified) != 1 || modified[0].Name != "id" || modified[0].Changes.DataType == nil {
+		t.Errorf("Expected modified column id with a data type change, got %+v", modified)
+	}
+
+	if unchanged := CompareTables(oldTable, oldTable); unchanged.AddedColumns() != nil || unchanged.RemovedColumns() != nil || unchanged.ModifiedColumns() != nil {
+		t.Error("Expected no columns for identical tables")
+	}
+}
+
 // TestCommentChanges tests detection of comment changes
 func TestCommentChanges(t *testing.T) {
 	sql1 := "CREATE TABLE test (id INT COMMENT 'Old comment')"
@@ -1263,3 +1614,702 @@ func TestUniqueConstraintChanges(t *testing.T) {
 		t.Errorf("Expected new unique to be true, got %v", uniqueChange.New)
 	}
 }
+
+// TestPackKeysChange tests that a PACK_KEYS change is detected
+func TestPackKeysChange(t *testing.T) {
+	sql1 := "CREATE TABLE test (id INT) ENGINE=MyISAM PACK_KEYS=0"
+	sql2 := "CREATE TABLE test (id INT) ENGINE=MyISAM PACK_KEYS=1"
+
+	oldTables, err := parser.ParseSQLDump(sql1)
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(sql2)
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	analyzer := NewTableDiffAnalyzer()
+	diff := analyzer.CompareTables(oldTables[0], newTables[0])
+
+	if diff.TableOptionsDiff == nil {
+		t.Fatal("Expected table options diff")
+	}
+	changes := diff.TableOptionsDiff.Changes
+	if changes.PackKeys == nil {
+		t.Fatal("Expected PACK_KEYS change")
+	}
+	if changes.PackKeys.Old != 0 || changes.PackKeys.New != 1 {
+		t.Errorf("Expected PACK_KEYS change 0->1, got %v->%v", changes.PackKeys.Old, changes.PackKeys.New)
+	}
+	if changes.Checksum != nil || changes.DelayKeyWrite != nil {
+		t.Errorf("Expected only PACK_KEYS to change, got %+v", changes)
+	}
+}
+
+// TestStatsOptionChanges tests that changes to InnoDB statistics options are detected
+func TestStatsOptionChanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldSQL  string
+		newSQL  string
+		changed func(*TableOptionsChanges) *FieldChange[any]
+	}{
+		{
+			name:    "STATS_PERSISTENT",
+			oldSQL:  "CREATE TABLE test (id INT) ENGINE=InnoDB STATS_PERSISTENT=0",
+			newSQL:  "CREATE TABLE test (id INT) ENGINE=InnoDB STATS_PERSISTENT=1",
+			changed: func(c *TableOptionsChanges) *FieldChange[any] { return c.StatsPersistent },
+		},
+		{
+			name:    "STATS_AUTO_RECALC",
+			oldSQL:  "CREATE TABLE test (id INT) ENGINE=InnoDB",
+			newSQL:  "CREATE TABLE test (id INT) ENGINE=InnoDB STATS_AUTO_RECALC=0",
+			changed: func(c *TableOptionsChanges) *FieldChange[any] { return c.StatsAutoRecalc },
+		},
+		{
+			name:    "STATS_SAMPLE_PAGES",
+			oldSQL:  "CREATE TABLE test (id INT) ENGINE=InnoDB STATS_SAMPLE_PAGES=20",
+			newSQL:  "CREATE TABLE test (id INT) ENGINE=InnoDB STATS_SAMPLE_PAGES=50",
+			changed: func(c *TableOptionsChanges) *FieldChange[any] { return c.StatsSamplePages },
+		},
+	}
+
+	analyzer := NewTableDiffAnalyzer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldTables, err := parser.ParseSQLDump(tt.oldSQL)
+			if err != nil {
+				t.Fatalf("Failed to parse old SQL: %v", err)
+			}
+			newTables, err := parser.ParseSQLDump(tt.newSQL)
+			if err != nil {
+				t.Fatalf("Failed to parse new SQL: %v", err)
+			}
+
+			diff := analyzer.CompareTables(oldTables[0], newTables[0])
+			if diff.TableOptionsDiff == nil {
+				t.Fatal("Expected table options diff")
+			}
+			if tt.changed(diff.TableOptionsDiff.Changes) == nil {
+				t.Errorf("Expected %s change, got %+v", tt.name, diff.TableOptionsDiff.Changes)
+			}
+		})
+	}
+}
+
+// TestIndexAlgorithmChange tests that a change to an inline index ALGORITHM is detected
+func TestIndexAlgorithmChange(t *testing.T) {
+	sql1 := "CREATE TABLE test (id INT, email VARCHAR(100), INDEX idx_email (email) ALGORITHM=INPLACE)"
+	sql2 := "CREATE TABLE test (id INT, email VARCHAR(100), INDEX idx_email (email) ALGORITHM=COPY LOCK=SHARED)"
+
+	oldTables, err := parser.ParseSQLDump(sql1)
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(sql2)
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	analyzer := NewTableDiffAnalyzer()
+	diff := analyzer.CompareTables(oldTables[0], newTables[0])
+
+	if diff.IndexesModified != 1 || len(diff.IndexDiffs) != 1 {
+		t.Fatalf("Expected 1 modified index, got %d", diff.IndexesModified)
+	}
+	changes := diff.IndexDiffs[0].Changes
+	if changes.Algorithm == nil || changes.Algorithm.Old != "INPLACE" || changes.Algorithm.New != "COPY" {
+		t.Errorf("Expected algorithm change INPLACE->COPY, got %+v", changes.Algorithm)
+	}
+	if changes.Lock == nil || changes.Lock.Old != nil || changes.Lock.New != "SHARED" {
+		t.Errorf("Expected lock change nil->SHARED, got %+v", changes.Lock)
+	}
+}
+
+// TestGeneratedColumnTransitions tests that a column becoming generated, and back, is detected
+func TestGeneratedColumnTransitions(t *testing.T) {
+	plainSQL := "CREATE TABLE orders (price INT, qty INT, total INT)"
+	generatedSQL := "CREATE TABLE orders (price INT, qty INT, total INT GENERATED ALWAYS AS (ABS(price)) STORED)"
+
+	plainTables, err := parser.ParseSQLDump(plainSQL)
+	if err != nil {
+		t.Fatalf("Failed to parse plain SQL: %v", err)
+	}
+	generatedTables, err := parser.ParseSQLDump(generatedSQL)
+	if err != nil {
+		t.Fatalf("Failed to parse generated SQL: %v", err)
+	}
+
+	analyzer := NewTableDiffAnalyzer()
+
+	toGenerated := analyzer.CompareTables(plainTables[0], generatedTables[0])
+	if len(toGenerated.ColumnDiffs) != 1 || toGenerated.ColumnDiffs[0].Name != "total" {
+		t.Fatalf("Expected 1 column diff for total, got %+v", toGenerated.ColumnDiffs)
+	}
+	generated := toGenerated.ColumnDiffs[0].Changes.Generated
+	if generated == nil || generated.Old != nil || generated.New == nil {
+		t.Fatalf("Expected generated change nil->expression, got %+v", generated)
+	}
+	if generated.New.Expression != "ABS ( price )" || generated.New.Type != "STORED" {
+		t.Errorf("Expected STORED generated expression 'ABS ( price )', got %+v", generated.New)
+	}
+
+	toPlain := analyzer.CompareTables(generatedTables[0], plainTables[0])
+	if len(toPlain.ColumnDiffs) != 1 {
+		t.Fatalf("Expected 1 column diff, got %d", len(toPlain.ColumnDiffs))
+	}
+	generated = toPlain.ColumnDiffs[0].Changes.Generated
+	if generated == nil || generated.Old == nil || generated.New != nil {
+		t.Errorf("Expected generated change expression->nil, got %+v", generated)
+	}
+}
+
+// TestForeignKeyDefaultActionEquivalence tests that omitted, RESTRICT and NO ACTION foreign key actions compare equal
+func TestForeignKeyDefaultActionEquivalence(t *testing.T) {
+	base := "CREATE TABLE orders (id INT, user_id INT, CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id)%s)"
+	parse := func(clause string) *parser.CreateTableStatement {
+		tables, err := parser.ParseSQLDump(fmt.Sprintf(base, clause))
+		if err != nil || len(tables) != 1 {
+			t.Fatalf("Failed to parse SQL with %q: %v", clause, err)
+		}
+		return tables[0]
+	}
+
+	omitted := parse("")
+	restrict := parse(" ON DELETE RESTRICT ON UPDATE RESTRICT")
+	noAction := parse(" ON DELETE NO ACTION ON UPDATE NO ACTION")
+	cascade := parse(" ON DELETE CASCADE")
+
+	analyzer := NewTableDiffAnalyzer()
+	if diff := analyzer.CompareTables(omitted, restrict); diff.HasChanges() {
+		t.Errorf("Expected omitted and RESTRICT actions to be equal, got %+v", diff.ForeignKeyDiffs)
+	}
+	if diff := analyzer.CompareTables(restrict, noAction); diff.HasChanges() {
+		t.Errorf("Expected RESTRICT and NO ACTION to be equal, got %+v", diff.ForeignKeyDiffs)
+	}
+	if diff := analyzer.CompareTables(omitted, cascade); diff.ForeignKeysModified != 1 {
+		t.Errorf("Expected CASCADE to differ from omitted action, got %d modified foreign keys", diff.ForeignKeysModified)
+	}
+
+	// Without normalization the explicit action is a change
+	analyzer.Normalize = false
+	if diff := analyzer.CompareTables(omitted, restrict); diff.ForeignKeysModified != 1 {
+		t.Errorf("Expected raw comparison to report a change, got %d modified foreign keys", diff.ForeignKeysModified)
+	}
+}
+
+// TestAvgRowLengthChange tests that an AVG_ROW_LENGTH change is detected
+func TestAvgRowLengthChange(t *testing.T) {
+	sql1 := "CREATE TABLE archive (id INT) ENGINE=MyISAM MAX_ROWS=1000000 AVG_ROW_LENGTH=100"
+	sql2 := "CREATE TABLE archive (id INT) ENGINE=MyISAM MAX_ROWS=1000000 AVG_ROW_LENGTH=250"
+
+	oldTables, err := parser.ParseSQLDump(sql1)
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(sql2)
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	if opts := oldTables[0].TableOptions; opts.AvgRowLength == nil || *opts.AvgRowLength != 100 {
+		t.Fatalf("Expected AVG_ROW_LENGTH=100 to be parsed, got %v", opts.AvgRowLength)
+	}
+
+	analyzer := NewTableDiffAnalyzer()
+	diff := analyzer.CompareTables(oldTables[0], newTables[0])
+
+	if diff.TableOptionsDiff == nil || diff.TableOptionsDiff.Changes.AvgRowLength == nil {
+		t.Fatal("Expected AVG_ROW_LENGTH change")
+	}
+	change := diff.TableOptionsDiff.Changes.AvgRowLength
+	if change.Old != 100 || change.New != 250 {
+		t.Errorf("Expected AVG_ROW_LENGTH change 100->250, got %v->%v", change.Old, change.New)
+	}
+}
+
+// TestAutoextendSizeChange tests that an AUTOEXTEND_SIZE change is detected with its size suffix
+func TestAutoextendSizeChange(t *testing.T) {
+	sql1 := "CREATE TABLE events (id INT) ENGINE=InnoDB AUTOEXTEND_SIZE=4M"
+	sql2 := "CREATE TABLE events (id INT) ENGINE=InnoDB AUTOEXTEND_SIZE 8m"
+
+	oldTables, err := parser.ParseSQLDump(sql1)
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(sql2)
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	if opts := oldTables[0].TableOptions; opts.AutoextendSize == nil || *opts.AutoextendSize != "4M" {
+		t.Fatalf("Expected AUTOEXTEND_SIZE=4M to be parsed, got %v", opts.AutoextendSize)
+	}
+
+	analyzer := NewTableDiffAnalyzer()
+	diff := analyzer.CompareTables(oldTables[0], newTables[0])
+
+	if diff.TableOptionsDiff == nil || diff.TableOptionsDiff.Changes.AutoextendSize == nil {
+		t.Fatal("Expected AUTOEXTEND_SIZE change")
+	}
+	change := diff.TableOptionsDiff.Changes.AutoextendSize
+	if change.Old != "4M" || change.New != "8M" {
+		t.Errorf("Expected AUTOEXTEND_SIZE change 4M->8M, got %v->%v", change.Old, change.New)
+	}
+
+	same := analyzer.CompareTables(oldTables[0], oldTables[0])
+	if same.HasChanges() {
+		t.Error("Expected identical AUTOEXTEND_SIZE to produce no changes")
+	}
+}
+
+// TestConnectionChange tests that a FEDERATED CONNECTION string change is detected with its quoting preserved
+func TestConnectionChange(t *testing.T) {
+	sql1 := "CREATE TABLE remote (id INT) ENGINE=FEDERATED CONNECTION='mysql://app@db1:3306/shop/orders'"
+	sql2 := "CREATE TABLE remote (id INT) ENGINE=FEDERATED CONNECTION='mysql://app@db2:3306/shop/orders'"
+
+	oldTables, err := parser.ParseSQLDump(sql1)
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(sql2)
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	analyzer := NewTableDiffAnalyzer()
+	diff := analyzer.CompareTables(oldTables[0], newTables[0])
+
+	if diff.TableOptionsDiff == nil || diff.TableOptionsDiff.Changes.Connection == nil {
+		t.Fatal("Expected CONNECTION change")
+	}
+	change := diff.TableOptionsDiff.Changes.Connection
+	if change.Old != "'mysql://app@db1:3306/shop/orders'" || change.New != "'mysql://app@db2:3306/shop/orders'" {
+		t.Errorf("Expected quoted connection strings, got %v -> %v", change.Old, change.New)
+	}
+}
+
+// TestCompareSchemas tests that whole-schema comparison reports added, removed and modified tables
+func TestCompareSchemas(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump(`
+		CREATE TABLE users (id INT, name VARCHAR(50));
+		CREATE TABLE logs (id INT);
+		CREATE TABLE settings (name VARCHAR(50));
+		CREATE TABLE audit (id INT);`)
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(`
+		CREATE TABLE users (id INT, name VARCHAR(100));
+		CREATE TABLE settings (name VARCHAR(50));
+		CREATE TABLE orders (id INT);
+		CREATE TABLE invoices (id INT);`)
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	sd := CompareSchemas(oldTables, newTables)
+	if !sd.HasChanges() {
+		t.Fatal("Expected schema changes")
+	}
+
+	tableNames := func(tables []*parser.CreateTableStatement) []string {
+		var names []string
+		for _, table := range tables {
+			names = append(names, table.TableName)
+		}
+		return names
+	}
+	if got := tableNames(sd.AddedTables); !slices.Equal(got, []string{"invoices", "orders"}) {
+		t.Errorf("Expected added tables [invoices orders], got %v", got)
+	}
+	if got := tableNames(sd.RemovedTables); !slices.Equal(got, []string{"audit", "logs"}) {
+		t.Errorf("Expected removed tables [audit logs], got %v", got)
+	}
+	if len(sd.ModifiedTables) != 1 || sd.ModifiedTables[0].NewTable.TableName != "users" {
+		t.Fatalf("Expected only users to be modified, got %d modified tables", len(sd.ModifiedTables))
+	}
+
+	data, err := json.Marshal(sd)
+	if err != nil {
+		t.Fatalf("Failed to marshal schema diff: %v", err)
+	}
+	for _, key := range []string{`"added_tables"`, `"removed_tables"`, `"modified_tables"`} {
+		if !strings.Contains(string(data), key) {
+			t.Errorf("Expected %s in JSON output", key)
+		}
+	}
+
+	if same := CompareSchemas(oldTables, oldTables); same.HasChanges() {
+		t.Error("Expected no changes when comparing a schema with itself")
+	}
+}
+
+func TestTableDiffReverse(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump(`CREATE TABLE users (id INT, email VARCHAR(100), legacy INT, KEY idx_email (email)) ENGINE=MyISAM;`)
+	if err != nil {
+		t.Fatalf("Failed to parse old schema: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(`CREATE TABLE users (id INT, email VARCHAR(255), created_at DATETIME) ENGINE=InnoDB;`)
+	if err != nil {
+		t.Fatalf("Failed to parse new schema: %v", err)
+	}
+
+	analyzer := NewTableDiffAnalyzer()
+	forward := analyzer.CompareTables(oldTables[0], newTables[0])
+	reversed := forward.Reverse()
+
+	if reversed.OldTable != forward.NewTable || reversed.NewTable != forward.OldTable {
+		t.Error("Expected reversed diff to swap old and new tables")
+	}
+	if reversed.ColumnsAdded != forward.ColumnsRemoved || reversed.ColumnsRemoved != forward.ColumnsAdded ||
+		reversed.IndexesAdded != forward.IndexesRemoved || reversed.IndexesRemoved != forward.IndexesAdded {
+		t.Errorf("Expected added and removed counters to be swapped, got %+v", reversed.GetSummary())
+	}
+
+	for _, colDiff := range reversed.ColumnDiffs {
+		switch colDiff.Name {
+		case "legacy":
+			if colDiff.ChangeType != ChangeTypeAdded || colDiff.NewColumn == nil {
+				t.Errorf("Expected removed column legacy to be added back, got %s", colDiff.ChangeType)
+			}
+		case "created_at":
+			if colDiff.ChangeType != ChangeTypeRemoved || colDiff.OldColumn == nil {
+				t.Errorf("Expected added column created_at to be removed, got %s", colDiff.ChangeType)
+			}
+		case "email":
+			if change := colDiff.Changes.DataType; change == nil || change.Old != "VARCHAR(255)" || change.New != "VARCHAR(100)" {
+				t.Errorf("Expected email data type VARCHAR(255) -> VARCHAR(100), got %+v", change)
+			}
+		}
+	}
+
+	optionsDiff := reversed.TableOptionsDiff
+	if optionsDiff == nil || optionsDiff.Changes.Engine == nil || optionsDiff.Changes.Engine.New != "MyISAM" {
+		t.Fatalf("Expected engine to revert to MyISAM, got %+v", optionsDiff)
+	}
+	if len(optionsDiff.Warnings) != 1 || !strings.Contains(optionsDiff.Warnings[0], "InnoDB -> MyISAM") {
+		t.Errorf("Expected a warning for the reverted engine change, got %v", optionsDiff.Warnings)
+	}
+
+	if forward.ColumnsAdded != 1 || forward.TableOptionsDiff.Changes.Engine.New != "InnoDB" {
+		t.Error("Expected the original diff to be left unchanged")
+	}
+}
+
+func TestDetectColumnRenames(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump(`CREATE TABLE users (
+  id INT NOT NULL,
+  fname VARCHAR(50) NOT NULL,
+  note TEXT,
+  legacy INT NOT NULL DEFAULT 0
+);`)
+	if err != nil {
+		t.Fatalf("Failed to parse old schema: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(`CREATE TABLE users (
+  id INT NOT NULL,
+  first_name VARCHAR(50) NOT NULL,
+  remarks TEXT COMMENT 'free text',
+  created_at DATETIME
+);`)
+	if err != nil {
+		t.Fatalf("Failed to parse new schema: %v", err)
+	}
+
+	columnDiffs := func(analyzer *TableDiffAnalyzer) map[string]ColumnDiff {
+		diffs := make(map[string]ColumnDiff)
+		for _, colDiff := range analyzer.CompareTables(oldTables[0], newTables[0]).ColumnDiffs {
+			diffs[colDiff.Name] = colDiff
+		}
+		return diffs
+	}
+
+	// Disabled by default
+	if diffs := columnDiffs(NewTableDiffAnalyzer()); diffs["fname"].ChangeType != ChangeTypeRemoved ||
+		diffs["first_name"].ChangeType != ChangeTypeAdded {
+		t.Errorf("Expected fname removed and first_name added without rename detection, got %+v", diffs)
+	}
+
+	analyzer := NewTableDiffAnalyzer()
+	analyzer.DetectColumnRenames = true
+	diffs := columnDiffs(analyzer)
+
+	renamed, ok := diffs["first_name"]
+	if !ok || renamed.ChangeType != ChangeTypeModified || !renamed.Changes.IsRenameOnly() ||
+		renamed.Changes.Name.Old != "fname" {
+		t.Errorf("Expected fname to be detected as renamed to first_name, got %+v", renamed)
+	}
+	if _, ok := diffs["fname"]; ok {
+		t.Error("Expected no separate diff for the renamed column fname")
+	}
+	// A changed comment keeps note/remarks apart when identical definitions are required,
+	// and legacy differs from created_at in type, nullability and default
+	for name, changeType := range map[string]ChangeType{
+		"note": ChangeTypeRemoved, "remarks": ChangeTypeAdded,
+		"legacy": ChangeTypeRemoved, "created_at": ChangeTypeAdded,
+	} {
+		if diffs[name].ChangeType != changeType {
+			t.Errorf("Expected column %s to be %s, got %s", name, changeType, diffs[name].ChangeType)
+		}
+	}
+
+	analyzer.ColumnRenameSimilarity = 0.9
+	diffs = columnDiffs(analyzer)
+	if remarks := diffs["remarks"]; remarks.ChangeType != ChangeTypeModified || remarks.Changes.Name == nil ||
+		remarks.Changes.Comment == nil {
+		t.Errorf("Expected note to be detected as renamed to remarks with a new comment, got %+v", remarks)
+	}
+	if diffs["legacy"].ChangeType != ChangeTypeRemoved {
+		t.Errorf("Expected legacy to stay removed at similarity 0.9, got %s", diffs["legacy"].ChangeType)
+	}
+}
+
+func TestCheckConstraintChanges(t *testing.T) {
+	oldTables, err := parser.ParseSQLDump(`CREATE TABLE orders (
+  id INT,
+  total INT,
+  qty INT,
+  CONSTRAINT chk_total CHECK (total >= 0),
+  CONSTRAINT chk_legacy CHECK (id > 0),
+  CONSTRAINT chk_qty CHECK (qty > 0)
+);`)
+	if err != nil {
+		t.Fatalf("Failed to parse old schema: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(`CREATE TABLE orders (
+  id INT,
+  total INT,
+  qty INT,
+  CONSTRAINT chk_total CHECK (total > 0),
+  CONSTRAINT chk_qty CHECK ( qty>0 ) NOT ENFORCED,
+  CONSTRAINT chk_id CHECK (id < 1000000)
+);`)
+	if err != nil {
+		t.Fatalf("Failed to parse new schema: %v", err)
+	}
+
+	tableDiff := NewTableDiffAnalyzer().CompareTables(oldTables[0], newTables[0])
+	if !tableDiff.HasChanges() {
+		t.Fatal("Expected check constraint changes")
+	}
+	if tableDiff.CheckConstraintsAdded != 1 || tableDiff.CheckConstraintsRemoved != 1 || tableDiff.CheckConstraintsModified != 2 {
+		t.Errorf("Expected +1 -1 ~2 check constraints, got %+v", tableDiff.GetSummary().CheckConstraints)
+	}
+
+	byName := make(map[string]CheckConstraintDiff)
+	for _, checkDiff := range tableDiff.CheckConstraintDiffs {
+		byName[*checkDiff.Name] = checkDiff
+	}
+	if byName["chk_id"].ChangeType != ChangeTypeAdded {
+		t.Errorf("Expected chk_id to be added, got %s", byName["chk_id"].ChangeType)
+	}
+	if byName["chk_legacy"].ChangeType != ChangeTypeRemoved {
+		t.Errorf("Expected chk_legacy to be removed, got %s", byName["chk_legacy"].ChangeType)
+	}
+	if change := byName["chk_total"].Changes.Expression; change == nil || change.Old != "total >= 0" || change.New != "total > 0" {
+		t.Errorf("Expected chk_total expression total >= 0 -> total > 0, got %+v", change)
+	}
+	qty := byName["chk_qty"].Changes
+	if qty.Expression != nil {
+		t.Errorf("Expected whitespace-only expression difference to be ignored, got %+v", qty.Expression)
+	}
+	if qty.Enforced == nil || !qty.Enforced.Old || qty.Enforced.New {
+		t.Errorf("Expected chk_qty to become NOT ENFORCED, got %+v", qty.Enforced)
+	}
+
+	if same := NewTableDiffAnalyzer().CompareTables(oldTables[0], oldTables[0]); same.HasChanges() {
+		t.Error("Expected no changes when comparing a table with itself")
+	}
+}
+
+func TestColumnCheckChanges(t *testing.T) {
+	oldTable := parseSingleTable(t, "CREATE TABLE people (age INT CHECK (age >= 0), score INT CONSTRAINT chk_score CHECK (score <= 100))")
+
+	analyzer := NewTableDiffAnalyzer()
+	same := parseSingleTable(t, "CREATE TABLE people (age INT CHECK (age>=0), score INT CONSTRAINT chk_score CHECK (score <= 100) ENFORCED)")
+	if diff := analyzer.CompareTables(oldTable, same); diff.HasChanges() {
+		t.Errorf("Expected equivalent checks to be equal, got %+v", diff.ColumnDiffs)
+	}
+
+	changed := parseSingleTable(t, "CREATE TABLE people (age INT, score INT CONSTRAINT chk_score CHECK (score <= 100) NOT ENFORCED)")
+	diff := analyzer.CompareTables(oldTable, changed)
+	if diff.ColumnsModified != 2 {
+		t.Fatalf("Expected 2 modified columns, got %d", diff.ColumnsModified)
+	}
+	for _, colDiff := range diff.ColumnDiffs {
+		checks := colDiff.Changes.Checks
+		if checks == nil {
+			t.Fatalf("Expected a checks change for %s", colDiff.Name)
+		}
+		switch colDiff.Name {
+		case "age":
+			if !reflect.DeepEqual(checks.Old, []string{"CHECK (age >= 0)"}) || len(checks.New) != 0 {
+				t.Errorf("Expected the age check to be removed, got %+v", checks)
+			}
+		case "score":
+			if !reflect.DeepEqual(checks.New, []string{"CONSTRAINT `chk_score` CHECK (score <= 100) NOT ENFORCED"}) {
+				t.Errorf("Expected chk_score to become NOT ENFORCED, got %+v", checks)
+			}
+		}
+	}
+}
+
+func TestEnumValueChanges(t *testing.T) {
+	values := []string{"'v1'", "'v2'", "'v3'", "'v4'", "'v5'", "'v6'", "'v7'", "'v8'", "'v9'", "'v10'"}
+	oldTable := parseSingleTable(t, fmt.Sprintf("CREATE TABLE jobs (state ENUM(%s))", strings.Join(values, ",")))
+	newTable := parseSingleTable(t, fmt.Sprintf("CREATE TABLE jobs (state ENUM(%s,'v11'))", strings.Join(values, ",")))
+
+	analyzer := NewTableDiffAnalyzer()
+	tableDiff := analyzer.CompareTables(oldTable, newTable)
+	if len(tableDiff.ColumnDiffs) != 1 {
+		t.Fatalf("Expected 1 column diff, got %d", len(tableDiff.ColumnDiffs))
+	}
+	colDiff := tableDiff.ColumnDiffs[0]
+	if colDiff.Changes.DataType != nil {
+		t.Errorf("Expected no data type change, got %+v", colDiff.Changes.DataType)
+	}
+	expected := &EnumChange{Added: []string{"'v11'"}}
+	if !reflect.DeepEqual(colDiff.Changes.EnumValues, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, colDiff.Changes.EnumValues)
+	}
+	if colDiff.Conversion != ConversionSafe {
+		t.Errorf("Expected a safe conversion, got %q", colDiff.Conversion)
+	}
+	if sentences := ExplainTableDiff(tableDiff); !slices.Contains(sentences, "Added value 'v11' to `state`") {
+		t.Errorf("Expected the added value to be explained, got %v", sentences)
+	}
+
+	// The reverse diff removes the value again
+	reversed := tableDiff.Reverse().ColumnDiffs[0].Changes.EnumValues
+	if !reflect.DeepEqual(reversed, &EnumChange{Removed: []string{"'v11'"}}) {
+		t.Errorf("Expected the reverse change to remove the value, got %+v", reversed)
+	}
+
+	// Removed and reordered values
+	reorderedTable := parseSingleTable(t, "CREATE TABLE jobs (state ENUM('v2','v1','v3','v4','v5','v6','v7','v8','v9'))")
+	enumChange := analyzer.CompareTables(oldTable, reorderedTable).ColumnDiffs[0].Changes.EnumValues
+	if !reflect.DeepEqual(enumChange, &EnumChange{Removed: []string{"'v10'"}, Reordered: true}) {
+		t.Errorf("Expected a removed value and a reorder, got %+v", enumChange)
+	}
+
+	// A different type is still reported as a data type change
+	varcharTable := parseSingleTable(t, "CREATE TABLE jobs (state VARCHAR(20))")
+	if changes := analyzer.CompareTables(oldTable, varcharTable).ColumnDiffs[0].Changes; changes.DataType == nil || changes.EnumValues != nil {
+		t.Errorf("Expected a data type change, got %+v", changes)
+	}
+}
+
+func TestDeterministicDiffOrder(t *testing.T) {
+	oldTable := parseSingleTable(t, `CREATE TABLE orders (id INT, legacy INT, qty INT, note TEXT, region INT,
+		KEY idx_a (qty), KEY idx_b (note(10)), KEY idx_c (region),
+		CONSTRAINT fk_a FOREIGN KEY (qty) REFERENCES a (id), CONSTRAINT fk_b FOREIGN KEY (region) REFERENCES b (id))`)
+	newTable := parseSingleTable(t, `CREATE TABLE orders (id BIGINT, qty BIGINT, zone INT, note VARCHAR(100), amount INT,
+		KEY idx_d (zone), KEY idx_e (amount), KEY idx_a (qty, id),
+		CONSTRAINT fk_c FOREIGN KEY (zone) REFERENCES c (id), CONSTRAINT fk_d FOREIGN KEY (amount) REFERENCES d (id))`)
+
+	names := func(td *TableDiff) []string {
+		var result []string
+		for _, colDiff := range td.ColumnDiffs {
+			result = append(result, string(colDiff.ChangeType)+" "+colDiff.Name)
+		}
+		for _, idxDiff := range td.IndexDiffs {
+			result = append(result, string(idxDiff.ChangeType)+" "+*idxDiff.Name)
+		}
+		for _, fkDiff := range td.ForeignKeyDiffs {
+			result = append(result, string(fkDiff.ChangeType)+" "+*fkDiff.Name)
+		}
+		return result
+	}
+
+	expected := []string{
+		"modified id", "modified qty", "added zone", "modified note", "added amount", "removed legacy", "removed region",
+		"removed idx_a", "removed idx_b", "removed idx_c", "added idx_d", "added idx_e", "added idx_a",
+		"added fk_c", "added fk_d", "removed fk_a", "removed fk_b",
+	}
+	var firstJSON string
+	for i := range 20 {
+		tableDiff := NewTableDiffAnalyzer().CompareTables(oldTable, newTable)
+		if result := names(tableDiff); !reflect.DeepEqual(result, expected) {
+			t.Fatalf("Run %d: expected diff order %v, got %v", i, expected, result)
+		}
+
+		jsonOutput, err := json.Marshal(tableDiff)
+		if err != nil {
+			t.Fatalf("Failed to marshal diff: %v", err)
+		}
+		if i == 0 {
+			firstJSON = string(jsonOutput)
+		} else if string(jsonOutput) != firstJSON {
+			t.Fatalf("Run %d: JSON output differs from the first run", i)
+		}
+	}
+}
+
+func TestIgnoredColumnComment(t *testing.T) {
+	oldTable := parseSingleTable(t, `CREATE TABLE users (id INT, cache TEXT COMMENT 'mysqldiff:ignore', name VARCHAR(50),
+		legacy INT COMMENT 'MySQLDiff:Ignore local only')`)
+	newTable := parseSingleTable(t, `CREATE TABLE users (id INT, cache VARCHAR(255) NOT NULL COMMENT 'mysqldiff:ignore',
+		name VARCHAR(100), legacy BIGINT, debug INT COMMENT 'mysqldiff:ignore')`)
+
+	tableDiff := NewTableDiffAnalyzer().CompareTables(oldTable, newTable)
+	var changed []string
+	for _, colDiff := range tableDiff.ColumnDiffs {
+		changed = append(changed, string(colDiff.ChangeType)+" "+colDiff.Name)
+	}
+	if expected := []string{"modified name", "added debug"}; !reflect.DeepEqual(changed, expected) {
+		t.Errorf("Expected column diffs %v, got %v", expected, changed)
+	}
+}
+
+func TestIgnoredColumnRename(t *testing.T) {
+	oldTable := parseSingleTable(t, `CREATE TABLE users (id INT, cache TEXT COMMENT 'mysqldiff:ignore')`)
+	newTable := parseSingleTable(t, `CREATE TABLE users (id INT, cache_data VARCHAR(255) NOT NULL COMMENT 'mysqldiff:ignore')`)
+
+	analyzer := NewTableDiffAnalyzer()
+	analyzer.ColumnRenames = map[string]map[string]string{"users": {"cache": "cache_data"}}
+	tableDiff := analyzer.CompareTables(oldTable, newTable)
+	if len(tableDiff.ColumnDiffs) != 1 {
+		t.Fatalf("Expected one column diff, got %+v", tableDiff.ColumnDiffs)
+	}
+	changes := tableDiff.ColumnDiffs[0].Changes
+	if !changes.IsRenameOnly() || changes.Name.Old != "cache" || changes.Name.New != "cache_data" {
+		t.Errorf("Expected only the rename of the ignored column, got %+v", changes)
+	}
+}
+
+func TestAnalyzerOptions(t *testing.T) {
+	oldTable := parseSingleTable(t, `CREATE TABLE orders (id INT NOT NULL, qty INT, customer_id INT, PRIMARY KEY (id),
+		CONSTRAINT chk_qty CHECK (qty > 0)) ENGINE=MyISAM PARTITION BY HASH (id) PARTITIONS 4`)
+	newTable := parseSingleTable(t, `CREATE TABLE orders (id INT NOT NULL, qty BIGINT, customer_id INT, PRIMARY KEY (id, qty),
+		KEY idx_qty (qty), CONSTRAINT fk_customer FOREIGN KEY (customer_id) REFERENCES customers (id),
+		CONSTRAINT chk_qty CHECK (qty > 1)) ENGINE=InnoDB PARTITION BY HASH (id) PARTITIONS 8`)
+
+	full := NewTableDiffAnalyzer().CompareTables(oldTable, newTable)
+	if len(full.ColumnDiffs) != 1 || full.PrimaryKeyDiff == nil || len(full.IndexDiffs) != 1 || len(full.ForeignKeyDiffs) != 1 ||
+		len(full.CheckConstraintDiffs) != 1 || full.TableOptionsDiff == nil || full.PartitionDiff == nil {
+		t.Fatalf("Expected the default analyzer to compare every category, got %+v", full)
+	}
+	if len(NewTableDiffAnalyzerWithOptions(DefaultAnalyzerOptions()).CompareTables(oldTable, newTable).ForeignKeyDiffs) != 1 {
+		t.Error("Expected DefaultAnalyzerOptions to compare foreign keys")
+	}
+
+	analyzer := NewTableDiffAnalyzerWithOptions(AnalyzerOptions{CompareColumns: true, CompareIndexes: true})
+	focused := analyzer.CompareTables(oldTable, newTable)
+	if len(focused.ColumnDiffs) != 1 || focused.PrimaryKeyDiff == nil || len(focused.IndexDiffs) != 1 {
+		t.Errorf("Expected column, primary key and index diffs, got %+v", focused)
+	}
+	if len(focused.ForeignKeyDiffs) != 0 || len(focused.CheckConstraintDiffs) != 0 || focused.TableOptionsDiff != nil ||
+		focused.PartitionDiff != nil || focused.TableOptionsChanged {
+		t.Errorf("Expected disabled categories to be skipped, got %+v", focused)
+	}
+	if focused.ForeignKeysAdded != 0 || focused.CheckConstraintsModified != 0 {
+		t.Errorf("Expected no counts for disabled categories, got %d foreign keys and %d checks added",
+			focused.ForeignKeysAdded, focused.CheckConstraintsModified)
+	}
+
+	if NewTableDiffAnalyzerWithOptions(AnalyzerOptions{}).CompareTables(oldTable, newTable).HasChanges() {
+		t.Error("Expected no changes with every category disabled")
+	}
+}
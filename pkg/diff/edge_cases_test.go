@@ -1,6 +1,7 @@
 package diff
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/n0madic/mysql-diff/pkg/parser"
@@ -41,13 +42,115 @@ func TestIdenticalTablesWithDifferentColumnOrder(t *testing.T) {
 	analyzer := NewTableDiffAnalyzer()
 	diff := analyzer.CompareTables(oldTable, newTable)
 
-	// Order changes should not be considered changes for now
-	// (This is a design decision - we could implement column position tracking)
+	// Order changes are only reported when DetectColumnReorder is enabled
 	if diff.HasChanges() {
 		t.Error("Column order changes should not be detected as table changes")
 	}
 }
 
+func TestColumnReorderDetection(t *testing.T) {
+	oldTable := createTestTable("users", []parser.ColumnDefinition{
+		createTestColumn("id", "INT"),
+		createTestColumn("name", "VARCHAR"),
+		createTestColumn("email", "VARCHAR"),
+		createTestColumn("created_at", "DATETIME"),
+	})
+
+	analyzer := NewTableDiffAnalyzer()
+	analyzer.DetectColumnReorder = true
+
+	t.Run("moved column", func(t *testing.T) {
+		newTable := createTestTable("users", []parser.ColumnDefinition{
+			createTestColumn("id", "INT"),
+			createTestColumn("email", "VARCHAR"),
+			createTestColumn("name", "VARCHAR"),
+			createTestColumn("created_at", "DATETIME"),
+		})
+
+		diff := analyzer.CompareTables(oldTable, newTable)
+		if len(diff.ColumnDiffs) != 1 {
+			t.Fatalf("Expected 1 column diff, got %d", len(diff.ColumnDiffs))
+		}
+		colDiff := diff.ColumnDiffs[0]
+		if colDiff.ChangeType != ChangeTypeModified || colDiff.Changes.Position == nil {
+			t.Fatalf("Expected a position change, got %+v", colDiff)
+		}
+		// Of two swapped columns the one listed first in the old table moves
+		if colDiff.Name != "name" || colDiff.Changes.Position.Old != 2 || colDiff.Changes.Position.New != 3 {
+			t.Errorf("Expected name moved from 2 to 3, got %s moved from %d to %d",
+				colDiff.Name, colDiff.Changes.Position.Old, colDiff.Changes.Position.New)
+		}
+		if diff.ColumnsModified != 1 {
+			t.Errorf("Expected 1 modified column, got %d", diff.ColumnsModified)
+		}
+	})
+
+	t.Run("added and removed columns shift positions without moves", func(t *testing.T) {
+		newTable := createTestTable("users", []parser.ColumnDefinition{
+			createTestColumn("id", "INT"),
+			createTestColumn("login", "VARCHAR"),
+			createTestColumn("name", "VARCHAR"),
+			createTestColumn("created_at", "DATETIME"),
+		})
+
+		diff := analyzer.CompareTables(oldTable, newTable)
+		for _, colDiff := range diff.ColumnDiffs {
+			if colDiff.Changes != nil && colDiff.Changes.Position != nil {
+				t.Errorf("Expected no position change, got %s moved", colDiff.Name)
+			}
+		}
+		if diff.ColumnsAdded != 1 || diff.ColumnsRemoved != 1 || diff.ColumnsModified != 0 {
+			t.Errorf("Expected +1 -1 ~0 columns, got +%d -%d ~%d", diff.ColumnsAdded, diff.ColumnsRemoved, diff.ColumnsModified)
+		}
+	})
+
+	t.Run("moved column with other changes", func(t *testing.T) {
+		newTable := createTestTable("users", []parser.ColumnDefinition{
+			createTestColumn("created_at", "TIMESTAMP"),
+			createTestColumn("id", "INT"),
+			createTestColumn("name", "VARCHAR"),
+			createTestColumn("email", "VARCHAR"),
+		})
+
+		diff := analyzer.CompareTables(oldTable, newTable)
+		if len(diff.ColumnDiffs) != 1 {
+			t.Fatalf("Expected 1 column diff, got %d", len(diff.ColumnDiffs))
+		}
+		changes := diff.ColumnDiffs[0].Changes
+		if changes.DataType == nil || changes.Position == nil || changes.Position.New != 1 {
+			t.Errorf("Expected a type and position change, got %+v", changes)
+		}
+	})
+}
+
+func TestColumnReorderDetectionWithoutPK(t *testing.T) {
+	analyzer := NewTableDiffAnalyzer()
+	analyzer.DetectColumnReorderWithoutPK = true
+
+	withPK := parseSingleTable(t, "CREATE TABLE users (id INT, name VARCHAR(50), email VARCHAR(100), PRIMARY KEY (id))")
+	movedWithPK := parseSingleTable(t, "CREATE TABLE users (id INT, email VARCHAR(100), name VARCHAR(50), PRIMARY KEY (id))")
+	if diff := analyzer.CompareTables(withPK, movedWithPK); diff.HasChanges() {
+		t.Errorf("Expected the column order of a table with a primary key to be ignored, got %+v", diff.ColumnDiffs)
+	}
+
+	inlinePK := parseSingleTable(t, "CREATE TABLE users (id INT PRIMARY KEY, name VARCHAR(50), email VARCHAR(100))")
+	movedInlinePK := parseSingleTable(t, "CREATE TABLE users (id INT PRIMARY KEY, email VARCHAR(100), name VARCHAR(50))")
+	if diff := analyzer.CompareTables(inlinePK, movedInlinePK); diff.HasChanges() {
+		t.Errorf("Expected the column order of a table with an inline primary key to be ignored, got %+v", diff.ColumnDiffs)
+	}
+
+	withoutPK := parseSingleTable(t, "CREATE TABLE logs (ts DATETIME, level VARCHAR(10), message TEXT)")
+	movedWithoutPK := parseSingleTable(t, "CREATE TABLE logs (ts DATETIME, message TEXT, level VARCHAR(10))")
+	diff := analyzer.CompareTables(withoutPK, movedWithoutPK)
+	if len(diff.ColumnDiffs) != 1 || diff.ColumnDiffs[0].Changes.Position == nil {
+		t.Fatalf("Expected a position change for the table without a primary key, got %+v", diff.ColumnDiffs)
+	}
+	if colDiff := diff.ColumnDiffs[0]; colDiff.Name != "level" || colDiff.Changes.Position.Old != 2 || colDiff.Changes.Position.New != 3 {
+		t.Errorf("Expected level moved from 2 to 3, got %s moved from %d to %d",
+			colDiff.Name, colDiff.Changes.Position.Old, colDiff.Changes.Position.New)
+	}
+}
+
 func TestComplexDataTypeChanges(t *testing.T) {
 	oldColumn := parser.ColumnDefinition{
 		Name: "amount",
@@ -424,6 +527,74 @@ func TestTableOptionsChanges(t *testing.T) {
 	}
 }
 
+// TestStorageTableOptionsChanges tests that every storage option of TableOptions is compared
+func TestStorageTableOptionsChanges(t *testing.T) {
+	str := func(s string) *string { return &s }
+	num := func(n int) *int { return &n }
+
+	oldOptions := &parser.TableOptions{
+		RowFormat:      str("DYNAMIC"),
+		MaxRows:        num(1000),
+		Tablespace:     str("ts1"),
+		DataDirectory:  str("/data/a"),
+		IndexDirectory: str("/index/a"),
+		Compression:    str("zlib"),
+		Union:          []string{"t1"},
+		InsertMethod:   str("FIRST"),
+	}
+	newOptions := &parser.TableOptions{
+		RowFormat:      str("COMPRESSED"),
+		KeyBlockSize:   num(8),
+		MinRows:        num(10),
+		Tablespace:     str("ts2"),
+		DataDirectory:  str("/data/b"),
+		IndexDirectory: str("/index/b"),
+		Encryption:     str("Y"),
+		Compression:    str("lz4"),
+		Union:          []string{"t1", "t2"},
+		InsertMethod:   str("LAST"),
+	}
+
+	oldTable := createTestTable("logs", []parser.ColumnDefinition{createTestColumn("id", "INT")})
+	oldTable.TableOptions = oldOptions
+	newTable := createTestTable("logs", []parser.ColumnDefinition{createTestColumn("id", "INT")})
+	newTable.TableOptions = newOptions
+
+	diff := NewTableDiffAnalyzer().CompareTables(oldTable, newTable)
+	if diff.TableOptionsDiff == nil {
+		t.Fatal("Expected table options diff")
+	}
+
+	changes := diff.TableOptionsDiff.Changes
+	for name, change := range map[string]*FieldChange[any]{
+		"RowFormat":      changes.RowFormat,
+		"KeyBlockSize":   changes.KeyBlockSize,
+		"MaxRows":        changes.MaxRows,
+		"MinRows":        changes.MinRows,
+		"Tablespace":     changes.Tablespace,
+		"DataDirectory":  changes.DataDirectory,
+		"IndexDirectory": changes.IndexDirectory,
+		"Encryption":     changes.Encryption,
+		"Compression":    changes.Compression,
+		"Union":          changes.Union,
+		"InsertMethod":   changes.InsertMethod,
+	} {
+		if change == nil {
+			t.Errorf("Expected %s change", name)
+		}
+	}
+	if changes.Encryption.Old != nil || changes.Encryption.New != "Y" {
+		t.Errorf("Expected encryption none -> Y, got %v -> %v", changes.Encryption.Old, changes.Encryption.New)
+	}
+
+	// Keyword options compare case-insensitively when normalizing
+	newTable.TableOptions = &parser.TableOptions{RowFormat: str("dynamic")}
+	oldTable.TableOptions = &parser.TableOptions{RowFormat: str("DYNAMIC")}
+	if diff := NewTableDiffAnalyzer().CompareTables(oldTable, newTable); diff.TableOptionsDiff != nil {
+		t.Errorf("Expected no change for ROW_FORMAT letter case, got %+v", diff.TableOptionsDiff.Changes)
+	}
+}
+
 func TestEmptyTablesComparisonEdgeCase(t *testing.T) {
 	// Tables with no columns
 	oldTable := &parser.CreateTableStatement{
@@ -475,3 +646,64 @@ func TestNilTablesHandling(t *testing.T) {
 		t.Error("Both tables should be nil")
 	}
 }
+
+// TestFindRedundantIndexes tests that left-prefix and duplicate indexes are reported with the
+// index covering them, while unique and fulltext indexes keep their meaning
+func TestFindRedundantIndexes(t *testing.T) {
+	table := parseSingleTable(t, `CREATE TABLE orders (
+		id INT NOT NULL,
+		user_id INT,
+		status VARCHAR(20),
+		note TEXT,
+		PRIMARY KEY (id),
+		KEY idx_user_status (user_id, status),
+		KEY idx_user (user_id),
+		UNIQUE KEY uq_user (user_id),
+		KEY idx_id (id),
+		KEY idx_status_prefix (status(5)),
+		KEY idx_user_status_copy (user_id, status),
+		FULLTEXT KEY ft_note (note)
+	)`)
+
+	redundant := make(map[string]string)
+	for _, finding := range FindRedundantIndexes(table) {
+		redundant[*finding.Index.Name] = finding.CoveredBy
+	}
+
+	expected := map[string]string{
+		"idx_user":             "idx_user_status",
+		"idx_id":               "PRIMARY",
+		"idx_user_status_copy": "idx_user_status",
+	}
+	if len(redundant) != len(expected) {
+		t.Errorf("Expected redundant indexes %v, got %v", expected, redundant)
+	}
+	for name, coveredBy := range expected {
+		if redundant[name] != coveredBy {
+			t.Errorf("Expected %s to be covered by %s, got %q", name, coveredBy, redundant[name])
+		}
+	}
+}
+
+// TestRedundantAddedIndex tests that an added index already covered by an existing index is flagged
+func TestRedundantAddedIndex(t *testing.T) {
+	oldTable := parseSingleTable(t, "CREATE TABLE orders (id INT, user_id INT, status INT, KEY idx_user_status (user_id, status))")
+	newTable := parseSingleTable(t, "CREATE TABLE orders (id INT, user_id INT, status INT, KEY idx_user_status (user_id, status), KEY idx_user (user_id), KEY idx_status (status))")
+
+	tableDiff := NewTableDiffAnalyzer().CompareTables(oldTable, newTable)
+	for _, idxDiff := range tableDiff.IndexDiffs {
+		expected := ""
+		if *idxDiff.NewIndex.Name == "idx_user" {
+			expected = "idx_user_status"
+		}
+		if idxDiff.RedundantWith != expected {
+			t.Errorf("Expected %s RedundantWith=%q, got %q", *idxDiff.NewIndex.Name, expected, idxDiff.RedundantWith)
+		}
+	}
+
+	expected := "Added index `idx_user` on (user_id) to table `orders`; it is redundant with index `idx_user_status`"
+	if sentences := ExplainTableDiff(tableDiff); !slices.Contains(sentences, expected) {
+		t.Errorf("Expected sentence %q, got %v", expected, sentences)
+	}
+
+}
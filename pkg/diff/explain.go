@@ -0,0 +1,467 @@
+package diff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/n0madic/mysql-diff/pkg/parser"
+)
+
+// ExplainTableDiff describes each change of a table diff as a plain English sentence
+func ExplainTableDiff(diff *TableDiff) []string {
+	sentences := []string{}
+	if diff == nil {
+		return sentences
+	}
+
+	tableName := ""
+	if diff.NewTable != nil {
+		tableName = diff.NewTable.TableName
+	} else if diff.OldTable != nil {
+		tableName = diff.OldTable.TableName
+	}
+
+	if diff.TableNameChanged && diff.OldTable != nil && diff.NewTable != nil {
+		sentences = append(sentences, fmt.Sprintf("Renamed table `%s` to `%s`", diff.OldTable.TableName, diff.NewTable.TableName))
+	}
+
+	for _, colDiff := range diff.ColumnDiffs {
+		sentences = append(sentences, explainColumnDiff(tableName, colDiff)...)
+	}
+
+	if diff.PrimaryKeyDiff != nil {
+		sentences = append(sentences, explainPrimaryKeyDiff(tableName, diff.PrimaryKeyDiff))
+	}
+
+	for _, idxDiff := range diff.IndexDiffs {
+		sentences = append(sentences, explainIndexDiff(tableName, idxDiff))
+	}
+
+	for _, fkDiff := range diff.ForeignKeyDiffs {
+		sentences = append(sentences, explainForeignKeyDiff(tableName, fkDiff))
+	}
+
+	for _, checkDiff := range diff.CheckConstraintDiffs {
+		sentences = append(sentences, explainCheckConstraintDiff(tableName, checkDiff)...)
+	}
+
+	if diff.TableOptionsDiff != nil {
+		sentences = append(sentences, explainTableOptionsDiff(tableName, diff.TableOptionsDiff)...)
+	}
+
+	if diff.PartitionDiff != nil {
+		switch diff.PartitionDiff.ChangeType {
+		case ChangeTypeAdded:
+			sentences = append(sentences, fmt.Sprintf("Added partitioning to table `%s`", tableName))
+		case ChangeTypeRemoved:
+			sentences = append(sentences, fmt.Sprintf("Removed partitioning from table `%s`", tableName))
+		case ChangeTypeModified:
+			sentences = append(sentences, fmt.Sprintf("Changed partitioning of table `%s`", tableName))
+		}
+	}
+
+	return sentences
+}
+
+// explainColumnDiff describes a single column change
+func explainColumnDiff(tableName string, colDiff ColumnDiff) []string {
+	switch colDiff.ChangeType {
+	case ChangeTypeAdded:
+		col := colDiff.NewColumn
+		nullability := "nullable"
+		if col.Nullable != nil && !*col.Nullable {
+			nullability = "NOT NULL"
+		}
+		sentence := fmt.Sprintf("Added %s column `%s` (%s) to table `%s`", nullability, col.Name, formatDataType(col.DataType), tableName)
+		if col.DefaultValue != nil {
+			sentence += fmt.Sprintf(" with default %s", *col.DefaultValue)
+		}
+		return []string{sentence}
+
+	case ChangeTypeRemoved:
+		col := colDiff.OldColumn
+		return []string{fmt.Sprintf("Removed column `%s` (%s) from table `%s`", col.Name, formatDataType(col.DataType), tableName)}
+
+	case ChangeTypeModified:
+		return explainColumnChanges(colDiff)
+	}
+
+	return nil
+}
+
+// explainColumnChanges describes the individual attribute changes of a modified column
+func explainColumnChanges(colDiff ColumnDiff) []string {
+	sentences := []string{}
+	changes := colDiff.Changes
+	name := colDiff.Name
+
+	if changes.Name != nil {
+		sentences = append(sentences, fmt.Sprintf("Renamed column `%s` to `%s`", changes.Name.Old, changes.Name.New))
+	}
+
+	if changes.DataType != nil {
+		sentence := fmt.Sprintf("Changed `%s` from %s to %s", name, changes.DataType.Old, changes.DataType.New)
+		risk := colDiff.Conversion
+		if risk == "" {
+			risk = NewTableDiffAnalyzer().ClassifyTypeChange(colDiff.OldColumn.DataType, colDiff.NewColumn.DataType)
+		}
+		qualifiers := []string{}
+		if qualifier := dataTypeChangeQualifier(colDiff.OldColumn.DataType, colDiff.NewColumn.DataType, risk); qualifier != "" {
+			qualifiers = append(qualifiers, qualifier)
+		}
+		if risk == ConversionUnsafe {
+			qualifiers = append(qualifiers, "existing values may be truncated or rejected")
+		}
+		if len(qualifiers) > 0 {
+			sentence += fmt.Sprintf(" (%s)", strings.Join(qualifiers, "; "))
+		}
+		sentences = append(sentences, sentence)
+	}
+
+	if enumChange := changes.EnumValues; enumChange != nil {
+		if len(enumChange.Added) > 0 {
+			sentences = append(sentences, fmt.Sprintf("Added %s %s to `%s`",
+				valuesNoun(enumChange.Added), strings.Join(enumChange.Added, ", "), name))
+		}
+		if len(enumChange.Removed) > 0 {
+			sentences = append(sentences, fmt.Sprintf("Removed %s %s from `%s` (existing values may be truncated or rejected)",
+				valuesNoun(enumChange.Removed), strings.Join(enumChange.Removed, ", "), name))
+		}
+		if enumChange.Reordered {
+			sentences = append(sentences, fmt.Sprintf("Reordered the values of `%s`", name))
+		}
+	}
+
+	if changes.Nullable != nil {
+		if changes.Nullable.New == false {
+			sentences = append(sentences, fmt.Sprintf("Made `%s` NOT NULL", name))
+		} else {
+			sentences = append(sentences, fmt.Sprintf("Made `%s` nullable", name))
+		}
+	}
+
+	if changes.DefaultValue != nil {
+		switch {
+		case changes.DefaultValue.Old == nil:
+			sentences = append(sentences, fmt.Sprintf("Set default of `%s` to %v", name, changes.DefaultValue.New))
+		case changes.DefaultValue.New == nil:
+			sentences = append(sentences, fmt.Sprintf("Removed default of `%s`", name))
+		default:
+			sentences = append(sentences, fmt.Sprintf("Changed default of `%s` from %v to %v", name, changes.DefaultValue.Old, changes.DefaultValue.New))
+		}
+	}
+
+	if changes.OnUpdate != nil {
+		switch {
+		case changes.OnUpdate.Old == nil:
+			sentences = append(sentences, fmt.Sprintf("Made `%s` update to %v on every row change", name, changes.OnUpdate.New))
+		case changes.OnUpdate.New == nil:
+			sentences = append(sentences, fmt.Sprintf("Removed ON UPDATE from `%s`", name))
+		default:
+			sentences = append(sentences, fmt.Sprintf("Changed ON UPDATE of `%s` from %v to %v", name, changes.OnUpdate.Old, changes.OnUpdate.New))
+		}
+	}
+
+	if changes.AutoIncrement != nil {
+		if changes.AutoIncrement.New {
+			sentences = append(sentences, fmt.Sprintf("Made `%s` AUTO_INCREMENT", name))
+		} else {
+			sentences = append(sentences, fmt.Sprintf("Removed AUTO_INCREMENT from `%s`", name))
+		}
+	}
+
+	if changes.Unique != nil {
+		if changes.Unique.New {
+			sentences = append(sentences, fmt.Sprintf("Made `%s` unique", name))
+		} else {
+			sentences = append(sentences, fmt.Sprintf("Removed unique constraint from `%s`", name))
+		}
+	}
+
+	if changes.PrimaryKey != nil {
+		if changes.PrimaryKey.New {
+			sentences = append(sentences, fmt.Sprintf("Made `%s` the primary key", name))
+		} else {
+			sentences = append(sentences, fmt.Sprintf("`%s` is no longer the primary key", name))
+		}
+	}
+
+	attributeChanges := []struct {
+		label  string
+		change *FieldChange[any]
+	}{
+		{"comment", changes.Comment},
+		{"collation", changes.Collation},
+		{"character set", changes.CharacterSet},
+		{"visibility", changes.Visible},
+		{"column format", changes.ColumnFormat},
+		{"storage", changes.Storage},
+	}
+	for _, attr := range attributeChanges {
+		if attr.change != nil {
+			sentences = append(sentences, fmt.Sprintf("Changed %s of `%s` from %s to %s",
+				attr.label, name, explainValue(attr.change.Old), explainValue(attr.change.New)))
+		}
+	}
+
+	if changes.Generated != nil {
+		switch {
+		case changes.Generated.Old == nil:
+			sentences = append(sentences, fmt.Sprintf("Made `%s` a %s generated column (%s)", name, changes.Generated.New.Type, changes.Generated.New.Expression))
+		case changes.Generated.New == nil:
+			sentences = append(sentences, fmt.Sprintf("Made `%s` a regular (non-generated) column", name))
+		default:
+			sentences = append(sentences, fmt.Sprintf("Changed generated expression of `%s` to %s (%s)", name, changes.Generated.New.Expression, changes.Generated.New.Type))
+		}
+	}
+
+	if changes.Checks != nil {
+		if len(changes.Checks.New) == 0 {
+			sentences = append(sentences, fmt.Sprintf("Removed the CHECK constraints of `%s`", name))
+		} else {
+			sentences = append(sentences, fmt.Sprintf("Changed the CHECK constraints of `%s` to %s", name, strings.Join(changes.Checks.New, ", ")))
+		}
+	}
+
+	if changes.Position != nil {
+		sentences = append(sentences, fmt.Sprintf("Moved `%s` from position %d to %d", name, changes.Position.Old, changes.Position.New))
+	}
+
+	return sentences
+}
+
+// dataTypeChangeQualifier explains whether a same-type change widens or narrows the column. The
+// direction is only given when it agrees with the ClassifyTypeChange risk, so a change classified
+// as safe is never described as decreased and an unsafe one never as increased.
+func dataTypeChangeQualifier(oldDT, newDT parser.DataType, risk ConversionRisk) string {
+	if !strings.EqualFold(oldDT.Name, newDT.Name) {
+		return ""
+	}
+	if len(oldDT.Parameters) == 0 || len(oldDT.Parameters) != len(newDT.Parameters) {
+		return ""
+	}
+
+	increased, decreased := false, false
+	for i := range oldDT.Parameters {
+		oldValue, oldErr := strconv.Atoi(oldDT.Parameters[i])
+		newValue, newErr := strconv.Atoi(newDT.Parameters[i])
+		if oldErr != nil || newErr != nil {
+			return ""
+		}
+		if newValue > oldValue {
+			increased = true
+		} else if newValue < oldValue {
+			decreased = true
+		}
+	}
+
+	measure := "length"
+	switch strings.ToUpper(oldDT.Name) {
+	case "DECIMAL", "NUMERIC", "FLOAT", "DOUBLE":
+		measure = "precision"
+	case "INT", "TINYINT", "SMALLINT", "MEDIUMINT", "BIGINT":
+		measure = "display width"
+	}
+
+	switch {
+	case increased && !decreased && risk == ConversionSafe:
+		return "increased " + measure
+	case decreased && !increased && risk == ConversionUnsafe:
+		return "decreased " + measure
+	}
+	return ""
+}
+
+// explainPrimaryKeyDiff describes a primary key change
+func explainPrimaryKeyDiff(tableName string, pkDiff *PrimaryKeyDiff) string {
+	switch pkDiff.ChangeType {
+	case ChangeTypeAdded:
+		return fmt.Sprintf("Added primary key (%s) to table `%s`", indexColumnNames(pkDiff.NewPK.Columns), tableName)
+	case ChangeTypeRemoved:
+		return fmt.Sprintf("Removed primary key (%s) from table `%s`", indexColumnNames(pkDiff.OldPK.Columns), tableName)
+	default:
+		return fmt.Sprintf("Changed primary key of table `%s` from (%s) to (%s)",
+			tableName, indexColumnNames(pkDiff.OldPK.Columns), indexColumnNames(pkDiff.NewPK.Columns))
+	}
+}
+
+// explainIndexDiff describes an index change
+func explainIndexDiff(tableName string, idxDiff IndexDiff) string {
+	switch idxDiff.ChangeType {
+	case ChangeTypeAdded:
+		sentence := fmt.Sprintf("Added %s `%s` on (%s) to table `%s`",
+			indexKind(idxDiff.NewIndex), indexName(idxDiff.NewIndex), indexColumnNames(idxDiff.NewIndex.Columns), tableName)
+		if idxDiff.RedundantWith != "" {
+			sentence += fmt.Sprintf("; it is redundant with index `%s`", idxDiff.RedundantWith)
+		}
+		return sentence
+	case ChangeTypeRemoved:
+		return fmt.Sprintf("Removed %s `%s` from table `%s`", indexKind(idxDiff.OldIndex), indexName(idxDiff.OldIndex), tableName)
+	default:
+		if idxDiff.Changes != nil && idxDiff.Changes.Name != nil {
+			return fmt.Sprintf("Renamed index `%s` to `%s` on table `%s`", indexName(idxDiff.OldIndex), indexName(idxDiff.NewIndex), tableName)
+		}
+		return fmt.Sprintf("Modified %s `%s` on table `%s`", indexKind(idxDiff.NewIndex), indexName(idxDiff.NewIndex), tableName)
+	}
+}
+
+// explainForeignKeyDiff describes a foreign key change
+func explainForeignKeyDiff(tableName string, fkDiff ForeignKeyDiff) string {
+	switch fkDiff.ChangeType {
+	case ChangeTypeAdded:
+		sentence := fmt.Sprintf("Added foreign key%s (%s) referencing `%s` (%s) to table `%s`",
+			foreignKeyName(fkDiff.NewFK), strings.Join(fkDiff.NewFK.Columns, ", "),
+			fkDiff.NewFK.Reference.TableName, strings.Join(fkDiff.NewFK.Reference.Columns, ", "), tableName)
+		if fkDiff.ImplicitIndex {
+			sentence += "; MySQL will create an index for its columns"
+		}
+		return sentence
+	case ChangeTypeRemoved:
+		return fmt.Sprintf("Removed foreign key%s (%s) referencing `%s` from table `%s`",
+			foreignKeyName(fkDiff.OldFK), strings.Join(fkDiff.OldFK.Columns, ", "), fkDiff.OldFK.Reference.TableName, tableName)
+	default:
+		return fmt.Sprintf("Modified foreign key%s on table `%s`", foreignKeyName(fkDiff.NewFK), tableName)
+	}
+}
+
+// explainCheckConstraintDiff describes a check constraint change
+func explainCheckConstraintDiff(tableName string, checkDiff CheckConstraintDiff) []string {
+	switch checkDiff.ChangeType {
+	case ChangeTypeAdded:
+		return []string{fmt.Sprintf("Added check constraint%s (%s) to table `%s`",
+			checkConstraintName(checkDiff.NewCheck), checkDiff.NewCheck.Expression, tableName)}
+	case ChangeTypeRemoved:
+		return []string{fmt.Sprintf("Removed check constraint%s (%s) from table `%s`",
+			checkConstraintName(checkDiff.OldCheck), checkDiff.OldCheck.Expression, tableName)}
+	}
+
+	sentences := []string{}
+	name := checkConstraintName(checkDiff.NewCheck)
+	if change := checkDiff.Changes.Expression; change != nil {
+		sentences = append(sentences, fmt.Sprintf("Changed check constraint%s on table `%s` from (%s) to (%s)",
+			name, tableName, change.Old, change.New))
+	}
+	if change := checkDiff.Changes.Enforced; change != nil {
+		state := "NOT ENFORCED"
+		if change.New {
+			state = "ENFORCED"
+		}
+		sentences = append(sentences, fmt.Sprintf("Made check constraint%s on table `%s` %s", name, tableName, state))
+	}
+	return sentences
+}
+
+// explainTableOptionsDiff describes table option changes
+func explainTableOptionsDiff(tableName string, optionsDiff *TableOptionsDiff) []string {
+	switch optionsDiff.ChangeType {
+	case ChangeTypeAdded:
+		return []string{fmt.Sprintf("Added table options to table `%s`", tableName)}
+	case ChangeTypeRemoved:
+		return []string{fmt.Sprintf("Removed table options from table `%s`", tableName)}
+	}
+
+	sentences := []string{}
+	changes := optionsDiff.Changes
+	optionChanges := []struct {
+		label  string
+		change *FieldChange[any]
+	}{
+		{"engine", changes.Engine},
+		{"auto-increment value", changes.AutoIncrement},
+		{"character set", changes.CharacterSet},
+		{"collation", changes.Collate},
+		{"comment", changes.Comment},
+		{"PACK_KEYS", changes.PackKeys},
+		{"CHECKSUM", changes.Checksum},
+		{"DELAY_KEY_WRITE", changes.DelayKeyWrite},
+		{"STATS_PERSISTENT", changes.StatsPersistent},
+		{"STATS_AUTO_RECALC", changes.StatsAutoRecalc},
+		{"STATS_SAMPLE_PAGES", changes.StatsSamplePages},
+		{"AVG_ROW_LENGTH", changes.AvgRowLength},
+		{"AUTOEXTEND_SIZE", changes.AutoextendSize},
+		{"connection string", changes.Connection},
+		{"ROW_FORMAT", changes.RowFormat},
+		{"KEY_BLOCK_SIZE", changes.KeyBlockSize},
+		{"MAX_ROWS", changes.MaxRows},
+		{"MIN_ROWS", changes.MinRows},
+		{"tablespace", changes.Tablespace},
+		{"DATA DIRECTORY", changes.DataDirectory},
+		{"INDEX DIRECTORY", changes.IndexDirectory},
+		{"encryption", changes.Encryption},
+		{"compression", changes.Compression},
+		{"UNION tables", changes.Union},
+		{"INSERT_METHOD", changes.InsertMethod},
+	}
+	for _, opt := range optionChanges {
+		if opt.change != nil {
+			sentences = append(sentences, fmt.Sprintf("Changed %s of table `%s` from %s to %s",
+				opt.label, tableName, explainValue(opt.change.Old), explainValue(opt.change.New)))
+		}
+	}
+
+	return sentences
+}
+
+// valuesNoun returns "value" or "values" to match the number of ENUM/SET values
+func valuesNoun(values []string) string {
+	if len(values) == 1 {
+		return "value"
+	}
+	return "values"
+}
+
+// explainValue formats an optional value for a sentence
+func explainValue(value any) string {
+	if value == nil {
+		return "none"
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// indexColumnNames joins the column names of an index
+func indexColumnNames(columns []parser.IndexColumn) string {
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// indexName returns the index name or a placeholder for unnamed indexes
+func indexName(idx *parser.IndexDefinition) string {
+	if idx == nil || idx.Name == nil {
+		return "UNNAMED"
+	}
+	return *idx.Name
+}
+
+// indexKind returns a readable kind of index
+func indexKind(idx *parser.IndexDefinition) string {
+	switch idx.IndexType {
+	case "UNIQUE":
+		return "unique index"
+	case "FULLTEXT":
+		return "fulltext index"
+	case "SPATIAL":
+		return "spatial index"
+	default:
+		return "index"
+	}
+}
+
+// foreignKeyName formats the optional foreign key name for a sentence
+func foreignKeyName(fk *parser.ForeignKeyDefinition) string {
+	if fk == nil || fk.Name == nil {
+		return ""
+	}
+	return fmt.Sprintf(" `%s`", *fk.Name)
+}
+
+// checkConstraintName formats the optional check constraint name for a sentence
+func checkConstraintName(check *parser.CheckConstraint) string {
+	if check == nil || check.Name == nil {
+		return ""
+	}
+	return fmt.Sprintf(" `%s`", *check.Name)
+}
@@ -0,0 +1,107 @@
+package diff
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/n0madic/mysql-diff/pkg/parser"
+)
+
+func TestExplainTableDiff(t *testing.T) {
+	sql1 := `
+		CREATE TABLE users (
+			id INT NOT NULL,
+			amount DECIMAL(10,2),
+			name VARCHAR(50),
+			legacy VARCHAR(10),
+			PRIMARY KEY (id)
+		)
+	`
+	sql2 := `
+		CREATE TABLE users (
+			id INT NOT NULL,
+			amount DECIMAL(12,4),
+			name VARCHAR(50) NOT NULL,
+			phone VARCHAR(20),
+			PRIMARY KEY (id),
+			KEY idx_phone (phone)
+		)
+	`
+
+	oldTables, err := parser.ParseSQLDump(sql1)
+	if err != nil {
+		t.Fatalf("Failed to parse old SQL: %v", err)
+	}
+	newTables, err := parser.ParseSQLDump(sql2)
+	if err != nil {
+		t.Fatalf("Failed to parse new SQL: %v", err)
+	}
+
+	sentences := ExplainTableDiff(CompareTables(oldTables[0], newTables[0]))
+
+	expected := []string{
+		"Added nullable column `phone` (VARCHAR(20)) to table `users`",
+		"Changed `amount` from DECIMAL(10,2) to DECIMAL(12,4) (increased precision)",
+		"Made `name` NOT NULL",
+		"Removed column `legacy` (VARCHAR(10)) from table `users`",
+		"Added index `idx_phone` on (phone) to table `users`",
+	}
+
+	if len(sentences) != len(expected) {
+		t.Errorf("Expected %d sentences, got %d: %v", len(expected), len(sentences), sentences)
+	}
+	for _, sentence := range expected {
+		if !slices.Contains(sentences, sentence) {
+			t.Errorf("Expected sentence %q, got %v", sentence, sentences)
+		}
+	}
+}
+
+func TestExplainDataTypeQualifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldDT    parser.DataType
+		newDT    parser.DataType
+		risk     ConversionRisk
+		expected string
+	}{
+		{"decimal precision increase", parser.DataType{Name: "DECIMAL", Parameters: []string{"10", "2"}}, parser.DataType{Name: "DECIMAL", Parameters: []string{"12", "4"}}, ConversionSafe, "increased precision"},
+		{"varchar length decrease", parser.DataType{Name: "VARCHAR", Parameters: []string{"255"}}, parser.DataType{Name: "VARCHAR", Parameters: []string{"100"}}, ConversionUnsafe, "decreased length"},
+		{"mixed change", parser.DataType{Name: "DECIMAL", Parameters: []string{"10", "4"}}, parser.DataType{Name: "DECIMAL", Parameters: []string{"12", "2"}}, ConversionUnsafe, ""},
+		{"type change", parser.DataType{Name: "INT"}, parser.DataType{Name: "BIGINT"}, ConversionSafe, ""},
+		{"safe decrease", parser.DataType{Name: "VARCHAR", Parameters: []string{"255"}}, parser.DataType{Name: "VARCHAR", Parameters: []string{"100"}}, ConversionSafe, ""},
+		{"unsafe increase", parser.DataType{Name: "INT", Parameters: []string{"10"}, Unsigned: true}, parser.DataType{Name: "INT", Parameters: []string{"11"}}, ConversionUnsafe, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := dataTypeChangeQualifier(tt.oldDT, tt.newDT, tt.risk); result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestExplainDefaultAndTableChanges(t *testing.T) {
+	oldEngine := "MyISAM"
+	newEngine := "InnoDB"
+	newDefault := "'active'"
+
+	oldTable := createTestTable("accounts", []parser.ColumnDefinition{createTestColumn("status", "VARCHAR")})
+	oldTable.TableOptions = &parser.TableOptions{Engine: &oldEngine}
+
+	newColumn := createTestColumn("status", "VARCHAR")
+	newColumn.DefaultValue = &newDefault
+	newTable := createTestTable("accounts", []parser.ColumnDefinition{newColumn})
+	newTable.TableOptions = &parser.TableOptions{Engine: &newEngine}
+
+	sentences := ExplainTableDiff(CompareTables(oldTable, newTable))
+
+	expected := []string{
+		"Set default of `status` to 'active'",
+		"Changed engine of table `accounts` from MyISAM to InnoDB",
+	}
+	if !slices.Equal(sentences, expected) {
+		t.Errorf("Expected %v, got %v", expected, sentences)
+	}
+}
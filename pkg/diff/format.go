@@ -0,0 +1,227 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/n0madic/mysql-diff/pkg/output"
+)
+
+// Formatter writes a schema diff to w in an output format
+type Formatter func(w io.Writer, sd *SchemaDiff) error
+
+var (
+	formattersMu sync.RWMutex
+	formatters   = make(map[string]Formatter)
+)
+
+func init() {
+	RegisterFormatter("json", formatJSON)
+	RegisterFormatter("json-compact", formatJSONCompact)
+	RegisterFormatter("detailed", formatDetailed)
+	RegisterFormatter("explain", formatExplain)
+	RegisterFormatter("markdown", formatMarkdown)
+}
+
+// RegisterFormatter makes a formatter available by name to FormatSchemaDiff and the --format flag.
+// It panics if the formatter is nil or a formatter of that name is already registered.
+func RegisterFormatter(name string, formatter Formatter) {
+	formattersMu.Lock()
+	defer formattersMu.Unlock()
+
+	if formatter == nil {
+		panic("diff: RegisterFormatter formatter is nil")
+	}
+	if _, exists := formatters[name]; exists {
+		panic("diff: RegisterFormatter called twice for formatter " + name)
+	}
+	formatters[name] = formatter
+}
+
+// FormatterNames returns the sorted names of the registered formatters
+func FormatterNames() []string {
+	formattersMu.RLock()
+	defer formattersMu.RUnlock()
+	return slices.Sorted(maps.Keys(formatters))
+}
+
+// FormatSchemaDiff writes a schema diff to w with the formatter registered under name
+func FormatSchemaDiff(w io.Writer, name string, sd *SchemaDiff) error {
+	formattersMu.RLock()
+	formatter, ok := formatters[name]
+	formattersMu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("unknown format '%s' (expected %s)", name, strings.Join(FormatterNames(), ", "))
+	}
+	return formatter(w, sd)
+}
+
+// tableDiffs returns a table diff for every added, removed and modified table, ordered by table
+// name. Added and removed tables are diffs with only a new or an old table.
+func (sd *SchemaDiff) tableDiffs() []*TableDiff {
+	var diffs []*TableDiff
+	for _, table := range sd.AddedTables {
+		diffs = append(diffs, &TableDiff{NewTable: table})
+	}
+	for _, table := range sd.RemovedTables {
+		diffs = append(diffs, &TableDiff{OldTable: table})
+	}
+	diffs = append(diffs, sd.ModifiedTables...)
+
+	slices.SortStableFunc(diffs, func(a, b *TableDiff) int {
+		return strings.Compare(schemaTableName(a), schemaTableName(b))
+	})
+	return diffs
+}
+
+// schemaTableName returns the name a table of a schema diff is reported under: the new name,
+// or the old name of a removed table
+func schemaTableName(td *TableDiff) string {
+	if td.NewTable != nil {
+		return td.NewTable.TableName
+	}
+	return td.OldTable.TableName
+}
+
+// jsonTableDiff is a table diff in the JSON output, with the statements generated for it and the
+// notes about them
+type jsonTableDiff struct {
+	*TableDiff
+	AlterStatements []string `json:"alter_statements,omitempty"`
+	Notes           []string `json:"notes,omitempty"`
+}
+
+// formatJSON writes the table diffs as a JSON object keyed by table name. When statements are
+// attached to the schema diff, every table carries its alter_statements and the object also has
+// the created_tables and dropped_tables arrays under the reserved key jsonMigrationKey.
+func formatJSON(w io.Writer, sd *SchemaDiff) error {
+	jsonOutput, err := json.MarshalIndent(sd.jsonResults(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("generating JSON output: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(jsonOutput))
+	return err
+}
+
+// formatJSONCompact writes the same JSON object as formatJSON on a single line, for log ingestion
+func formatJSONCompact(w io.Writer, sd *SchemaDiff) error {
+	jsonOutput, err := json.Marshal(sd.jsonResults())
+	if err != nil {
+		return fmt.Errorf("generating JSON output: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(jsonOutput))
+	return err
+}
+
+// jsonMigrationKey holds the created_tables and dropped_tables arrays in the JSON output. The @
+// cannot start an unquoted MySQL identifier, and table names starting with @ are written with a
+// second @ in front, so no table is ever reported under this key.
+const jsonMigrationKey = "@migration"
+
+// jsonResults returns the object written by the JSON formatters
+func (sd *SchemaDiff) jsonResults() map[string]any {
+	results := make(map[string]any)
+	for _, td := range sd.tableDiffs() {
+		name := schemaTableName(td)
+		key := name
+		if strings.HasPrefix(key, "@") {
+			key = "@" + key
+		}
+		results[key] = jsonTableDiff{TableDiff: td, AlterStatements: sd.TableStatements[name], Notes: sd.TableNotes[name]}
+	}
+	if sd.CreatedTables != nil || sd.DroppedTables != nil {
+		results[jsonMigrationKey] = struct {
+			CreatedTables []string `json:"created_tables"`
+			DroppedTables []string `json:"dropped_tables"`
+		}{sd.CreatedTables, sd.DroppedTables}
+	}
+	return results
+}
+
+// formatDetailed writes the detailed report of every changed table followed by an overall summary
+func formatDetailed(w io.Writer, sd *SchemaDiff) error {
+	if !sd.HasChanges() {
+		_, err := fmt.Fprintln(w, "No differences found between schemas.")
+		return err
+	}
+
+	for _, td := range sd.tableDiffs() {
+		tableName := schemaTableName(td)
+		switch {
+		case td.OldTable == nil:
+			fmt.Fprintf(w, "\n%s\n", strings.Repeat("=", 60))
+			fmt.Fprintf(w, "TABLE ADDED: %s\n", tableName)
+			fmt.Fprintf(w, "%s\n", strings.Repeat("=", 60))
+			fmt.Fprintf(w, "✅ Table '%s' was added to the schema\n", tableName)
+		case td.NewTable == nil:
+			fmt.Fprintf(w, "\n%s\n", strings.Repeat("=", 60))
+			fmt.Fprintf(w, "TABLE REMOVED: %s\n", tableName)
+			fmt.Fprintf(w, "%s\n", strings.Repeat("=", 60))
+			fmt.Fprintf(w, "❌ Table '%s' was removed from the schema\n", tableName)
+		default:
+			fprintTableDiff(w, td, true, sd.OnlyModifiedColumns)
+		}
+	}
+
+	fmt.Fprintf(w, "\n%s\n", strings.Repeat("=", 60))
+	fmt.Fprintf(w, "SUMMARY\n")
+	fmt.Fprintf(w, "%s\n", strings.Repeat("=", 60))
+	fmt.Fprintf(w, "Tables analyzed: %d\n", sd.TablesCompared)
+	_, err := fmt.Fprintf(w, "Tables with changes: %d\n", len(sd.ModifiedTables))
+	return err
+}
+
+// formatExplain writes a plain English sentence per change, grouped by table
+func formatExplain(w io.Writer, sd *SchemaDiff) error {
+	sentenceCount := 0
+	for _, td := range sd.tableDiffs() {
+		tableName := schemaTableName(td)
+
+		var sentences []string
+		switch {
+		case td.OldTable == nil:
+			sentences = []string{fmt.Sprintf("Added table `%s`", tableName)}
+		case td.NewTable == nil:
+			sentences = []string{fmt.Sprintf("Removed table `%s`", tableName)}
+		default:
+			sentences = ExplainTableDiff(td)
+		}
+		if len(sentences) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(w, "%s:\n", output.ColorizeTableName(tableName))
+		for _, sentence := range sentences {
+			fmt.Fprintf(w, "  - %s\n", sentence)
+		}
+		sentenceCount += len(sentences)
+	}
+
+	if sentenceCount == 0 {
+		_, err := fmt.Fprintln(w, "No differences found between schemas.")
+		return err
+	}
+	return nil
+}
+
+// formatMarkdown writes a Markdown section for every added, removed or changed table
+func formatMarkdown(w io.Writer, sd *SchemaDiff) error {
+	if !sd.HasChanges() {
+		_, err := fmt.Fprintln(w, "No differences found between schemas.")
+		return err
+	}
+
+	for i, td := range sd.tableDiffs() {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		FprintMarkdownTableDiff(w, td)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRegisterFormatter(t *testing.T) {
+	defer func() {
+		formattersMu.Lock()
+		delete(formatters, "table-count")
+		formattersMu.Unlock()
+	}()
+	RegisterFormatter("table-count", func(w io.Writer, sd *SchemaDiff) error {
+		_, err := fmt.Fprintf(w, "+%d -%d ~%d\n", len(sd.AddedTables), len(sd.RemovedTables), len(sd.ModifiedTables))
+		return err
+	})
+	if !slices.Contains(FormatterNames(), "table-count") {
+		t.Fatalf("Expected table-count to be registered, got %v", FormatterNames())
+	}
+
+	oldTables := mustParseDump(t, "CREATE TABLE users (id INT); CREATE TABLE logs (id INT);")
+	newTables := mustParseDump(t, "CREATE TABLE users (id BIGINT); CREATE TABLE orders (id INT); CREATE TABLE items (id INT);")
+
+	var buf bytes.Buffer
+	if err := FormatSchemaDiff(&buf, "table-count", CompareSchemas(oldTables, newTables)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if buf.String() != "+2 -1 ~1\n" {
+		t.Errorf("Expected the custom formatter output, got %q", buf.String())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected registering a formatter name twice to panic")
+		}
+	}()
+	RegisterFormatter("table-count", formatJSON)
+}
+
+func TestFormatSchemaDiffUnknownFormat(t *testing.T) {
+	err := FormatSchemaDiff(io.Discard, "yaml", &SchemaDiff{})
+	if err == nil || !strings.Contains(err.Error(), "unknown format 'yaml'") {
+		t.Errorf("Expected an unknown format error, got %v", err)
+	}
+}
+
+func TestBuiltInFormatters(t *testing.T) {
+	oldTables := mustParseDump(t, "CREATE TABLE users (id INT); CREATE TABLE logs (id INT);")
+	newTables := mustParseDump(t, "CREATE TABLE users (id BIGINT); CREATE TABLE orders (id INT);")
+	schemaDiff := CompareSchemas(oldTables, newTables)
+
+	var buf bytes.Buffer
+	if err := FormatSchemaDiff(&buf, "json", schemaDiff); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var results map[string]*TableDiff
+	if err := json.Unmarshal(buf.Bytes(), &results); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+	if len(results) != 3 || results["orders"].OldTable != nil || results["logs"].NewTable != nil || results["users"].ColumnsModified != 1 {
+		t.Errorf("Expected the added, removed and modified tables keyed by name, got %s", buf.String())
+	}
+
+	buf.Reset()
+	if err := FormatSchemaDiff(&buf, "explain", schemaDiff); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, expected := range []string{"  - Removed table `logs`", "  - Added table `orders`", "  - Changed `id` from INT to BIGINT"} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("Expected %q in explain output:\n%s", expected, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := FormatSchemaDiff(&buf, "detailed", schemaDiff); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Tables analyzed: 1\nTables with changes: 1\n") {
+		t.Errorf("Expected the overall summary in detailed output:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := FormatSchemaDiff(&buf, "markdown", &SchemaDiff{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if buf.String() != "No differences found between schemas.\n" {
+		t.Errorf("Expected no differences, got %q", buf.String())
+	}
+}
+
+func TestJSONCompactFormatter(t *testing.T) {
+	oldTables := mustParseDump(t, "CREATE TABLE users (id INT, name VARCHAR(50)); CREATE TABLE logs (id INT);")
+	newTables := mustParseDump(t, "CREATE TABLE users (id BIGINT, name VARCHAR(50) COMMENT 'line\\nbreak');")
+	schemaDiff := CompareSchemas(oldTables, newTables)
+
+	var compact, pretty bytes.Buffer
+	if err := FormatSchemaDiff(&compact, "json-compact", schemaDiff); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := FormatSchemaDiff(&pretty, "json", schemaDiff); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	body, found := strings.CutSuffix(compact.String(), "\n")
+	if !found || strings.Contains(body, "\n") {
+		t.Errorf("Expected a single line of JSON, got %q", compact.String())
+	}
+
+	// Both forms hold the same object
+	var compactResults, prettyResults map[string]any
+	if err := json.Unmarshal(compact.Bytes(), &compactResults); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+	if err := json.Unmarshal(pretty.Bytes(), &prettyResults); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+	if !reflect.DeepEqual(compactResults, prettyResults) {
+		t.Errorf("Expected the compact and pretty JSON to hold the same object")
+	}
+	if len(compactResults) != 2 {
+		t.Errorf("Expected the users and logs tables, got %v", compactResults)
+	}
+}
+
+func TestDetailedOnlyModifiedColumns(t *testing.T) {
+	oldTables := mustParseDump(t, "CREATE TABLE users (id INT, name VARCHAR(50), legacy INT);")
+	newTables := mustParseDump(t, "CREATE TABLE users (id BIGINT, name VARCHAR(50), email VARCHAR(255), phone VARCHAR(20));")
+	schemaDiff := CompareSchemas(oldTables, newTables)
+
+	var full bytes.Buffer
+	if err := FormatSchemaDiff(&full, "detailed", schemaDiff); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, expected := range []string{"+ email", "+ phone", "- legacy", "~ id"} {
+		if !strings.Contains(full.String(), expected) {
+			t.Errorf("Expected %q in the full report:\n%s", expected, full.String())
+		}
+	}
+
+	schemaDiff.OnlyModifiedColumns = true
+	var focused bytes.Buffer
+	if err := FormatSchemaDiff(&focused, "detailed", schemaDiff); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	report := focused.String()
+	for _, unexpected := range []string{"email", "phone", "legacy"} {
+		if strings.Contains(report, unexpected) {
+			t.Errorf("Expected %q to be left out of the report:\n%s", unexpected, report)
+		}
+	}
+	for _, expected := range []string{"~ id", "data_type: INT -> BIGINT", "(3 added or removed columns not shown)", "Columns: +2 -1 ~1"} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("Expected %q in the report:\n%s", expected, report)
+		}
+	}
+}
+
+func TestJSONTableNameCollision(t *testing.T) {
+	oldTables := mustParseDump(t, "CREATE TABLE created_tables (id INT); CREATE TABLE `@migration` (id INT);")
+	newTables := mustParseDump(t, "CREATE TABLE created_tables (id BIGINT); CREATE TABLE `@migration` (id BIGINT);")
+	schemaDiff := CompareSchemas(oldTables, newTables)
+	schemaDiff.CreatedTables = []string{"orders"}
+	schemaDiff.DroppedTables = []string{}
+
+	for _, format := range []string{"json", "json-compact"} {
+		var buf bytes.Buffer
+		if err := FormatSchemaDiff(&buf, format, schemaDiff); err != nil {
+			t.Fatalf("Unexpected %s error: %v", format, err)
+		}
+
+		var results map[string]struct {
+			NewTable      struct{ TableName string } `json:"new_table"`
+			CreatedTables []string                   `json:"created_tables"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &results); err != nil {
+			t.Fatalf("Expected valid %s, got %v", format, err)
+		}
+		if results["created_tables"].NewTable.TableName != "created_tables" {
+			t.Errorf("Expected the created_tables table in %s, got %+v", format, results["created_tables"])
+		}
+		if results["@@migration"].NewTable.TableName != "@migration" {
+			t.Errorf("Expected the @migration table under @@migration in %s, got %+v", format, results)
+		}
+		if created := results["@migration"].CreatedTables; !slices.Equal(created, []string{"orders"}) {
+			t.Errorf("Expected created_tables [orders] under @migration in %s, got %v", format, created)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// FprintMarkdownTableDiff writes a table diff to w as a Markdown section that can be pasted into a
+// pull request description: a summary of change counts followed by tables of the added, removed
+// and modified columns, indexes and foreign keys. A diff without an old or new table is reported as
+// an added or removed table. Colors should be disabled, since definitions are formatted like the
+// detailed report.
+func FprintMarkdownTableDiff(w io.Writer, diff *TableDiff) {
+	switch {
+	case diff.OldTable == nil && diff.NewTable != nil:
+		fmt.Fprintf(w, "## `%s`\n\nTable added.\n", diff.NewTable.TableName)
+		return
+	case diff.OldTable != nil && diff.NewTable == nil:
+		fmt.Fprintf(w, "## `%s`\n\nTable removed.\n", diff.OldTable.TableName)
+		return
+	case diff.OldTable == nil:
+		return
+	}
+
+	fmt.Fprintf(w, "## `%s`\n", diff.NewTable.TableName)
+	if !diff.HasChanges() {
+		fmt.Fprintln(w, "\nNo changes detected.")
+		return
+	}
+	if diff.TableNameChanged {
+		fmt.Fprintf(w, "\nRenamed from `%s`.\n", diff.OldTable.TableName)
+	}
+
+	summary := diff.GetSummary()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| | Added | Removed | Modified |")
+	fmt.Fprintln(w, "|---|---|---|---|")
+	fmt.Fprintf(w, "| Columns | %d | %d | %d |\n", summary.Columns.Added, summary.Columns.Removed, summary.Columns.Modified)
+	fmt.Fprintf(w, "| Indexes | %d | %d | %d |\n", summary.Indexes.Added, summary.Indexes.Removed, summary.Indexes.Modified)
+	fmt.Fprintf(w, "| Foreign keys | %d | %d | %d |\n", summary.ForeignKeys.Added, summary.ForeignKeys.Removed, summary.ForeignKeys.Modified)
+	if len(diff.CheckConstraintDiffs) > 0 {
+		fmt.Fprintf(w, "| Check constraints | %d | %d | %d |\n", summary.CheckConstraints.Added, summary.CheckConstraints.Removed, summary.CheckConstraints.Modified)
+	}
+
+	if len(diff.ColumnDiffs) > 0 {
+		var rows []markdownRow
+		for _, colDiff := range diff.ColumnDiffs {
+			rows = append(rows, markdownRow{colDiff.ChangeType, colDiff.Name, formatColumn(colDiff.OldColumn), formatColumn(colDiff.NewColumn)})
+		}
+		fprintMarkdownChanges(w, "Columns", "Column", rows)
+	}
+
+	if len(diff.IndexDiffs) > 0 {
+		var rows []markdownRow
+		for _, idxDiff := range diff.IndexDiffs {
+			idx := idxDiff.NewIndex
+			if idx == nil {
+				idx = idxDiff.OldIndex
+			}
+			rows = append(rows, markdownRow{idxDiff.ChangeType, indexName(idx), formatIndex(idxDiff.OldIndex), formatIndex(idxDiff.NewIndex)})
+		}
+		fprintMarkdownChanges(w, "Indexes", "Index", rows)
+	}
+
+	if len(diff.ForeignKeyDiffs) > 0 {
+		var rows []markdownRow
+		for _, fkDiff := range diff.ForeignKeyDiffs {
+			fk := fkDiff.NewFK
+			if fk == nil {
+				fk = fkDiff.OldFK
+			}
+			name := "UNNAMED"
+			if fk.Name != nil {
+				name = *fk.Name
+			}
+			rows = append(rows, markdownRow{fkDiff.ChangeType, name, formatForeignKey(fkDiff.OldFK), formatForeignKey(fkDiff.NewFK)})
+		}
+		fprintMarkdownChanges(w, "Foreign keys", "Foreign key", rows)
+	}
+
+	// Changes without their own table are listed as plain sentences
+	var other []string
+	if diff.PrimaryKeyDiff != nil {
+		other = append(other, explainPrimaryKeyDiff(diff.NewTable.TableName, diff.PrimaryKeyDiff))
+	}
+	for _, checkDiff := range diff.CheckConstraintDiffs {
+		other = append(other, explainCheckConstraintDiff(diff.NewTable.TableName, checkDiff)...)
+	}
+	if diff.TableOptionsDiff != nil {
+		other = append(other, explainTableOptionsDiff(diff.NewTable.TableName, diff.TableOptionsDiff)...)
+	}
+	if summary.PartitioningChanged {
+		other = append(other, fmt.Sprintf("Changed partitioning of table `%s`", diff.NewTable.TableName))
+	}
+	if len(other) > 0 {
+		fmt.Fprintln(w, "\n### Other changes")
+		fmt.Fprintln(w)
+		for _, sentence := range other {
+			fmt.Fprintf(w, "- %s\n", sentence)
+		}
+	}
+}
+
+// markdownRow is a changed column, index or foreign key with its old and new definitions
+type markdownRow struct {
+	changeType ChangeType
+	name       string
+	old        string
+	new        string
+}
+
+// fprintMarkdownChanges writes a Markdown table with a row per change
+func fprintMarkdownChanges(w io.Writer, title, item string, rows []markdownRow) {
+	fmt.Fprintf(w, "\n### %s\n\n", title)
+	fmt.Fprintf(w, "| Change | %s | Old | New |\n", item)
+	fmt.Fprintln(w, "|---|---|---|---|")
+	for _, row := range rows {
+		fmt.Fprintf(w, "| %s | `%s` | %s | %s |\n", markdownChangeType(row.changeType),
+			escapeMarkdownCell(row.name), escapeMarkdownCell(row.old), escapeMarkdownCell(row.new))
+	}
+}
+
+// markdownChangeType returns the capitalized label of a change type
+func markdownChangeType(changeType ChangeType) string {
+	switch changeType {
+	case ChangeTypeAdded:
+		return "Added"
+	case ChangeTypeRemoved:
+		return "Removed"
+	case ChangeTypeModified:
+		return "Modified"
+	}
+	return string(changeType)
+}
+
+// escapeMarkdownCell escapes the characters that would break a Markdown table cell
+func escapeMarkdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	return strings.ReplaceAll(value, "\n", " ")
+}
@@ -0,0 +1,53 @@
+package diff
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFprintMarkdownTableDiff(t *testing.T) {
+	oldTable := parseSingleTable(t, "CREATE TABLE users (id INT NOT NULL, name VARCHAR(50), age INT, KEY idx_name (name)) ENGINE=MyISAM")
+	newTable := parseSingleTable(t, `CREATE TABLE users (id INT NOT NULL, name VARCHAR(100), email VARCHAR(255) NOT NULL,
+		KEY idx_name (name, email), CONSTRAINT fk_org FOREIGN KEY (id) REFERENCES orgs (id)) ENGINE=InnoDB`)
+
+	var buf bytes.Buffer
+	FprintMarkdownTableDiff(&buf, NewTableDiffAnalyzer().CompareTables(oldTable, newTable))
+	result := buf.String()
+
+	expectedLines := []string{
+		"## `users`",
+		"| Columns | 1 | 1 | 1 |",
+		"| Indexes | 1 | 1 | 0 |",
+		"| Foreign keys | 1 | 0 | 0 |",
+		"| Change | Column | Old | New |",
+		"| Modified | `name` | VARCHAR(50) | VARCHAR(100) |",
+		"| Added | `email` |  | VARCHAR(255) NOT NULL |",
+		"| Removed | `age` | INT |  |",
+		"| Removed | `idx_name` | INDEX idx_name (name) |  |",
+		"| Added | `idx_name` |  | INDEX idx_name (name, email) |",
+		"| Added | `fk_org` |  | FK fk_org: (id) -> orgs(id) |",
+		"- Changed engine of table `users` from MyISAM to InnoDB",
+	}
+	lines := strings.Split(result, "\n")
+	for _, expected := range expectedLines {
+		if !slices.Contains(lines, expected) {
+			t.Errorf("Expected line %q in report:\n%s", expected, result)
+		}
+	}
+}
+
+func TestFprintMarkdownTableDiffAddedTable(t *testing.T) {
+	var buf bytes.Buffer
+	FprintMarkdownTableDiff(&buf, &TableDiff{NewTable: parseSingleTable(t, "CREATE TABLE orders (id INT)")})
+	if expected := "## `orders`\n\nTable added.\n"; buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestEscapeMarkdownCell(t *testing.T) {
+	if result := escapeMarkdownCell("a|b\nc"); result != `a\|b c` {
+		t.Errorf("Expected escaped pipe and newline, got %q", result)
+	}
+}
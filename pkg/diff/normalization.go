@@ -0,0 +1,126 @@
+package diff
+
+import (
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/n0madic/mysql-diff/pkg/parser"
+)
+
+// NormalizationConfig holds user-supplied equivalence rules applied when comparing tables.
+// Names are matched case-insensitively.
+type NormalizationConfig struct {
+	// TypeSynonyms maps a data type name to the canonical type it is equivalent to, e.g. "INTEGER": "INT"
+	TypeSynonyms map[string]string
+
+	// CharsetSynonyms maps a character set name to the canonical character set it is equivalent to, e.g. "utf8": "utf8mb3"
+	CharsetSynonyms map[string]string
+}
+
+// builtinTypeSynonyms maps the data type synonyms MySQL accepts to the type it stores.
+// REAL is a DOUBLE unless the REAL_AS_FLOAT SQL mode is enabled.
+var builtinTypeSynonyms = map[string]string{
+	"INTEGER":          "INT",
+	"DEC":              "DECIMAL",
+	"NUMERIC":          "DECIMAL",
+	"FIXED":            "DECIMAL",
+	"REAL":             "DOUBLE",
+	"DOUBLE PRECISION": "DOUBLE",
+	"BOOL":             "TINYINT",
+	"BOOLEAN":          "TINYINT",
+}
+
+// canonicalDataType returns a data type with custom type synonyms applied and, when normalization
+// is enabled, MySQL's own synonyms replaced by the type they stand for, e.g. BOOL by TINYINT(1)
+func (a *TableDiffAnalyzer) canonicalDataType(dt parser.DataType) parser.DataType {
+	dt.Name = a.Normalization.canonicalType(dt.Name)
+	if !a.Normalize {
+		return dt
+	}
+
+	upper := strings.ToUpper(dt.Name)
+	if a.KeepBooleanType && (upper == "BOOL" || upper == "BOOLEAN") {
+		return dt
+	}
+	if canonical, ok := builtinTypeSynonyms[upper]; ok {
+		if canonical == "TINYINT" && len(dt.Parameters) == 0 {
+			dt.Parameters = []string{"1"}
+		}
+		dt.Name = canonical
+	}
+	return dt
+}
+
+// numericTypes lists the canonical numeric data types whose defaults are compared as numbers.
+// BOOL and BOOLEAN remain when KeepBooleanType skips their canonicalization.
+var numericTypes = []string{"TINYINT", "SMALLINT", "MEDIUMINT", "INT", "BIGINT", "DECIMAL", "FLOAT", "DOUBLE", "BOOL", "BOOLEAN"}
+
+// columnDefaultEqual compares the defaults of two columns. When normalization is enabled and both
+// columns are numeric, defaults are compared as numbers, so DEFAULT 0, DEFAULT '0' and DEFAULT 0.0 are
+// equal, as are DEFAULT TRUE and DEFAULT 1.
+func (a *TableDiffAnalyzer) columnDefaultEqual(oldCol, newCol parser.ColumnDefinition) bool {
+	isNumeric := func(dt parser.DataType) bool {
+		return slices.Contains(numericTypes, strings.ToUpper(a.canonicalDataType(dt).Name))
+	}
+	if a.Normalize && oldCol.DefaultValue != nil && newCol.DefaultValue != nil &&
+		isNumeric(oldCol.DataType) && isNumeric(newCol.DataType) {
+		oldNumber, oldOK := numericDefault(*oldCol.DefaultValue)
+		newNumber, newOK := numericDefault(*newCol.DefaultValue)
+		if oldOK && newOK {
+			return oldNumber.Cmp(newNumber) == 0
+		}
+	}
+	return a.defaultValueEqual(oldCol.DefaultValue, newCol.DefaultValue)
+}
+
+// numericDefault parses a default value that is a number, optionally quoted as a string, or one of
+// the literals TRUE and FALSE, which MySQL stores as 1 and 0
+func numericDefault(value string) (*big.Rat, bool) {
+	switch strings.ToUpper(value) {
+	case "TRUE":
+		return big.NewRat(1, 1), true
+	case "FALSE":
+		return new(big.Rat), true
+	}
+	if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+	return new(big.Rat).SetString(strings.TrimSpace(value))
+}
+
+// canonicalType returns the canonical name for a data type
+func (c *NormalizationConfig) canonicalType(name string) string {
+	return lookupSynonym(c.TypeSynonyms, name)
+}
+
+// canonicalCharset returns the canonical name for a character set
+func (c *NormalizationConfig) canonicalCharset(name string) string {
+	return lookupSynonym(c.CharsetSynonyms, name)
+}
+
+// lookupSynonym returns the value registered for name, or name itself if there is none
+func lookupSynonym(synonyms map[string]string, name string) string {
+	if canonical, ok := synonyms[name]; ok {
+		return canonical
+	}
+	for synonym, canonical := range synonyms {
+		if strings.EqualFold(synonym, name) {
+			return canonical
+		}
+	}
+	return name
+}
+
+// charsetEqual compares character sets after applying charset synonyms
+func (a *TableDiffAnalyzer) charsetEqual(oldCharset, newCharset *string) bool {
+	if oldCharset != nil {
+		canonical := a.Normalization.canonicalCharset(*oldCharset)
+		oldCharset = &canonical
+	}
+	if newCharset != nil {
+		canonical := a.Normalization.canonicalCharset(*newCharset)
+		newCharset = &canonical
+	}
+	return a.optionValueEqual(oldCharset, newCharset)
+}
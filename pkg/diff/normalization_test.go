@@ -0,0 +1,432 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/n0madic/mysql-diff/pkg/parser"
+)
+
+func parseSingleTable(t *testing.T, sql string) *parser.CreateTableStatement {
+	t.Helper()
+	tables, err := parser.ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("Failed to parse SQL: %v", err)
+	}
+	if len(tables) != 1 {
+		t.Fatalf("Expected 1 table, got %d", len(tables))
+	}
+	return tables[0]
+}
+
+// TestCustomTypeSynonyms tests that registered type synonyms compare equal and others still differ
+func TestCustomTypeSynonyms(t *testing.T) {
+	oldTable := createTestTable("docs", []parser.ColumnDefinition{
+		createTestColumn("id", "INTEGER"),
+		createTestColumn("body", "MEDIUMTEXT"),
+	})
+	newTable := createTestTable("docs", []parser.ColumnDefinition{
+		createTestColumn("id", "INT"),
+		createTestColumn("body", "TEXT"),
+	})
+
+	// Without normalization only the registered synonyms apply
+	analyzer := NewTableDiffAnalyzer()
+	analyzer.Normalize = false
+	if diff := analyzer.CompareTables(oldTable, newTable); diff.ColumnsModified != 2 {
+		t.Fatalf("Expected 2 modified columns without synonyms, got %d", diff.ColumnsModified)
+	}
+
+	analyzer.Normalization = NormalizationConfig{
+		TypeSynonyms: map[string]string{"integer": "INT"},
+	}
+	diff := analyzer.CompareTables(oldTable, newTable)
+
+	if diff.ColumnsModified != 1 || len(diff.ColumnDiffs) != 1 {
+		t.Fatalf("Expected only body to be modified, got %d modified columns", diff.ColumnsModified)
+	}
+	if diff.ColumnDiffs[0].Name != "body" {
+		t.Errorf("Expected MEDIUMTEXT -> TEXT to remain a change, got change on %s", diff.ColumnDiffs[0].Name)
+	}
+}
+
+// TestBuiltinTypeSynonyms tests that MySQL's data type synonyms compare equal to the types they
+// stand for, and are compared literally without normalization
+func TestBuiltinTypeSynonyms(t *testing.T) {
+	oldTable := parseSingleTable(t, `CREATE TABLE orders (
+		id INTEGER UNSIGNED,
+		total DEC(10,2),
+		tax NUMERIC(10,2),
+		rate REAL,
+		ratio DOUBLE PRECISION,
+		paid BOOL,
+		shipped BOOLEAN,
+		amount FIXED(8,2)
+	)`)
+	newTable := parseSingleTable(t, `CREATE TABLE orders (
+		id INT UNSIGNED,
+		total DECIMAL(10,2),
+		tax DECIMAL(10,2),
+		rate DOUBLE,
+		ratio DOUBLE,
+		paid TINYINT(1),
+		shipped tinyint(1),
+		amount DECIMAL(8,2)
+	)`)
+
+	analyzer := NewTableDiffAnalyzer()
+	if diff := analyzer.CompareTables(oldTable, newTable); diff.HasChanges() {
+		for _, colDiff := range diff.ColumnDiffs {
+			t.Errorf("Expected %s to equal its synonym, got %s -> %s", colDiff.Name, colDiff.Changes.DataType.Old, colDiff.Changes.DataType.New)
+		}
+	}
+
+	// Parameters still count: BOOL is TINYINT(1), not TINYINT(4)
+	wider := parseSingleTable(t, "CREATE TABLE orders (paid TINYINT(4), total NUMERIC(12,2))")
+	narrower := parseSingleTable(t, "CREATE TABLE orders (paid BOOL, total DECIMAL(10,2))")
+	if diff := analyzer.CompareTables(wider, narrower); diff.ColumnsModified != 2 {
+		t.Errorf("Expected 2 modified columns, got %d", diff.ColumnsModified)
+	}
+
+	analyzer.Normalize = false
+	if diff := analyzer.CompareTables(oldTable, newTable); diff.ColumnsModified != 8 {
+		t.Errorf("Expected every synonym to differ without normalization, got %d modified columns", diff.ColumnsModified)
+	}
+}
+
+// TestCustomCharsetSynonyms tests that registered charset synonyms apply to table and column charsets
+func TestCustomCharsetSynonyms(t *testing.T) {
+	oldTable := parseSingleTable(t, "CREATE TABLE docs (title VARCHAR(100) CHARACTER SET utf8) DEFAULT CHARSET=utf8")
+	newTable := parseSingleTable(t, "CREATE TABLE docs (title VARCHAR(100) CHARACTER SET utf8mb3) DEFAULT CHARSET=utf8mb3")
+
+	analyzer := NewTableDiffAnalyzer()
+	if diff := analyzer.CompareTables(oldTable, newTable); !diff.HasChanges() {
+		t.Fatal("Expected charset change without synonyms")
+	}
+
+	analyzer.Normalization.CharsetSynonyms = map[string]string{"utf8": "utf8mb3"}
+	if diff := analyzer.CompareTables(oldTable, newTable); diff.HasChanges() {
+		t.Errorf("Expected no changes with utf8 registered as utf8mb3, got columns=%+v options=%+v",
+			diff.ColumnDiffs, diff.TableOptionsDiff)
+	}
+}
+
+// TestCurrentTimestampDefaultEquivalence tests that CURRENT_TIMESTAMP family defaults compare equal under normalization
+func TestCurrentTimestampDefaultEquivalence(t *testing.T) {
+	base := parseSingleTable(t, "CREATE TABLE events (created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP)")
+
+	equivalent := []string{
+		"CREATE TABLE events (created_at TIMESTAMP DEFAULT current_timestamp)",
+		"CREATE TABLE events (created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP())",
+		"CREATE TABLE events (created_at TIMESTAMP DEFAULT NOW())",
+		"CREATE TABLE events (created_at TIMESTAMP DEFAULT now())",
+		"CREATE TABLE events (created_at TIMESTAMP DEFAULT LOCALTIMESTAMP)",
+	}
+
+	analyzer := NewTableDiffAnalyzer()
+	for _, sql := range equivalent {
+		if diff := analyzer.CompareTables(base, parseSingleTable(t, sql)); diff.HasChanges() {
+			t.Errorf("Expected no changes for %q, got %+v", sql, diff.ColumnDiffs[0].Changes.DefaultValue)
+		}
+	}
+
+	different := []string{
+		"CREATE TABLE events (created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP(6))",
+		"CREATE TABLE events (created_at TIMESTAMP DEFAULT '2020-01-01 00:00:00')",
+	}
+	for _, sql := range different {
+		if diff := analyzer.CompareTables(base, parseSingleTable(t, sql)); diff.ColumnsModified != 1 {
+			t.Errorf("Expected a default value change for %q", sql)
+		}
+	}
+
+	// Fractional seconds precision must match, whichever synonym is used
+	precise := parseSingleTable(t, "CREATE TABLE events (created_at TIMESTAMP(6) DEFAULT CURRENT_TIMESTAMP(6))")
+	if diff := analyzer.CompareTables(precise, parseSingleTable(t, "CREATE TABLE events (created_at TIMESTAMP(6) DEFAULT NOW(6))")); diff.HasChanges() {
+		t.Error("Expected CURRENT_TIMESTAMP(6) and NOW(6) to be equal")
+	}
+
+	analyzer.Normalize = false
+	if diff := analyzer.CompareTables(base, parseSingleTable(t, equivalent[2])); diff.ColumnsModified != 1 {
+		t.Error("Expected NOW() to differ from CURRENT_TIMESTAMP without normalization")
+	}
+}
+
+func TestNumericDefaultEquivalence(t *testing.T) {
+	base := parseSingleTable(t, "CREATE TABLE t (n INT DEFAULT 0, price DECIMAL(10,2) DEFAULT 1.5, code VARCHAR(10) DEFAULT 0)")
+
+	analyzer := NewTableDiffAnalyzer()
+	quoted := parseSingleTable(t, "CREATE TABLE t (n INT DEFAULT '0', price DECIMAL(10,2) DEFAULT '1.50', code VARCHAR(10) DEFAULT 0)")
+	if diff := analyzer.CompareTables(base, quoted); diff.HasChanges() {
+		t.Errorf("Expected quoted numeric defaults to be equal, got %+v", diff.ColumnDiffs)
+	}
+
+	// String columns keep comparing the literal, and different numbers still differ
+	different := parseSingleTable(t, "CREATE TABLE t (n INT DEFAULT '1', price DECIMAL(10,2) DEFAULT 1.5, code VARCHAR(10) DEFAULT '0')")
+	if diff := analyzer.CompareTables(base, different); diff.ColumnsModified != 2 {
+		t.Errorf("Expected changes of n and code, got %d modified columns", diff.ColumnsModified)
+	}
+
+	analyzer.Normalize = false
+	if diff := analyzer.CompareTables(base, quoted); diff.ColumnsModified != 2 {
+		t.Errorf("Expected quoted defaults to differ without normalization, got %d modified columns", diff.ColumnsModified)
+	}
+}
+
+// TestBooleanColumns tests that BOOLEAN columns compare as TINYINT(1) with TRUE and FALSE as 1 and 0
+func TestBooleanColumns(t *testing.T) {
+	boolTable := parseSingleTable(t, "CREATE TABLE flags (active BOOLEAN DEFAULT TRUE, deleted BOOL NOT NULL DEFAULT false)")
+	active := boolTable.Columns[0]
+	if active.DataType.Name != "BOOLEAN" || active.DefaultValue == nil || *active.DefaultValue != "TRUE" {
+		t.Fatalf("Expected a BOOLEAN column with a TRUE default, got %+v", active)
+	}
+
+	analyzer := NewTableDiffAnalyzer()
+	tinyintTable := parseSingleTable(t, "CREATE TABLE flags (active TINYINT(1) DEFAULT 1, deleted TINYINT(1) NOT NULL DEFAULT '0')")
+	if diff := analyzer.CompareTables(boolTable, tinyintTable); diff.HasChanges() {
+		t.Errorf("Expected BOOLEAN DEFAULT TRUE to equal TINYINT(1) DEFAULT 1, got %+v", diff.ColumnDiffs)
+	}
+
+	flipped := parseSingleTable(t, "CREATE TABLE flags (active TINYINT(1) DEFAULT 0, deleted TINYINT(1) NOT NULL DEFAULT 0)")
+	diff := analyzer.CompareTables(boolTable, flipped)
+	if diff.ColumnsModified != 1 || diff.ColumnDiffs[0].Name != "active" || diff.ColumnDiffs[0].Changes.DefaultValue == nil {
+		t.Errorf("Expected only the default of active to change, got %+v", diff.ColumnDiffs)
+	}
+
+	analyzer.KeepBooleanType = true
+	diff = analyzer.CompareTables(boolTable, tinyintTable)
+	if diff.ColumnsModified != 2 {
+		t.Fatalf("Expected both columns to change type with KeepBooleanType, got %d modified columns", diff.ColumnsModified)
+	}
+	for _, colDiff := range diff.ColumnDiffs {
+		if colDiff.Changes.DataType == nil || colDiff.Changes.DefaultValue != nil {
+			t.Errorf("Expected only a type change of %s, got %+v", colDiff.Name, colDiff.Changes)
+		}
+	}
+}
+
+// TestIgnoreOrder tests that reordered columns and ENUM values are not reported under IgnoreOrder
+func TestSerialColumns(t *testing.T) {
+	serial := parseSingleTable(t, "CREATE TABLE orders (id SERIAL, total INT)")
+	expanded := parseSingleTable(t, "CREATE TABLE orders (id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT UNIQUE, total INT)")
+	if tableDiff := NewTableDiffAnalyzer().CompareTables(serial, expanded); tableDiff.HasChanges() {
+		t.Errorf("Expected SERIAL to equal BIGINT UNSIGNED NOT NULL AUTO_INCREMENT UNIQUE, got %+v", tableDiff.ColumnDiffs)
+	}
+
+	signed := parseSingleTable(t, "CREATE TABLE orders (id BIGINT NOT NULL AUTO_INCREMENT UNIQUE, total INT)")
+	if tableDiff := NewTableDiffAnalyzer().CompareTables(serial, signed); len(tableDiff.ColumnDiffs) != 1 {
+		t.Errorf("Expected a signed BIGINT to differ from SERIAL, got %+v", tableDiff.ColumnDiffs)
+	}
+}
+
+func TestIgnoreOrder(t *testing.T) {
+	oldTable := parseSingleTable(t, "CREATE TABLE orders (id INT, status ENUM('new','paid','shipped'), total DECIMAL(10,2))")
+	reordered := parseSingleTable(t, "CREATE TABLE orders (total DECIMAL(10,2), id INT, status ENUM('shipped','new','paid'))")
+
+	analyzer := NewTableDiffAnalyzer()
+	if diff := analyzer.CompareTables(oldTable, reordered); diff.ColumnsModified != 1 {
+		t.Errorf("Expected the ENUM reorder to be reported by default, got %d modified columns", diff.ColumnsModified)
+	}
+
+	analyzer.IgnoreOrder = true
+	if diff := analyzer.CompareTables(oldTable, reordered); diff.HasChanges() {
+		t.Errorf("Expected no changes under IgnoreOrder, got %d modified columns", diff.ColumnsModified)
+	}
+
+	// IgnoreOrder wins over column reorder detection
+	analyzer.DetectColumnReorder = true
+	analyzer.DetectColumnReorderWithoutPK = true
+	if diff := analyzer.CompareTables(oldTable, reordered); diff.HasChanges() || diff.ColumnOrderTracked {
+		t.Errorf("Expected no moved columns under IgnoreOrder, got %d modified columns", diff.ColumnsModified)
+	}
+	analyzer.DetectColumnReorder = false
+	analyzer.DetectColumnReorderWithoutPK = false
+
+	// Real changes are still reported
+	changed := parseSingleTable(t, "CREATE TABLE orders (total DECIMAL(12,2), id INT, status ENUM('shipped','new','refunded'))")
+	if diff := analyzer.CompareTables(oldTable, changed); diff.ColumnsModified != 2 {
+		t.Errorf("Expected 2 modified columns under IgnoreOrder, got %d", diff.ColumnsModified)
+	}
+}
+
+// TestDataTypeParameterOrder tests that DECIMAL precision and scale are compared in order, also
+// under IgnoreOrder, while ENUM and SET values only ignore their order under IgnoreOrder
+func TestDataTypeParameterOrder(t *testing.T) {
+	oldTable := parseSingleTable(t, "CREATE TABLE prices (amount DECIMAL(10,2), status ENUM('a','b'), flags SET('x','y'))")
+	swapped := parseSingleTable(t, "CREATE TABLE prices (amount DECIMAL(2,10), status ENUM('b','a'), flags SET('y','x'))")
+
+	for _, ignoreOrder := range []bool{false, true} {
+		analyzer := NewTableDiffAnalyzer()
+		analyzer.IgnoreOrder = ignoreOrder
+		diff := analyzer.CompareTables(oldTable, swapped)
+
+		modified := make(map[string]*ColumnChanges)
+		for _, colDiff := range diff.ColumnDiffs {
+			modified[colDiff.Name] = colDiff.Changes
+		}
+
+		amount, ok := modified["amount"]
+		if !ok || amount.DataType == nil {
+			t.Errorf("IgnoreOrder=%v: expected DECIMAL(10,2) -> DECIMAL(2,10) to be reported", ignoreOrder)
+		} else if amount.DataType.Old != "DECIMAL(10,2)" || amount.DataType.New != "DECIMAL(2,10)" {
+			t.Errorf("IgnoreOrder=%v: unexpected data type change %s -> %s", ignoreOrder, amount.DataType.Old, amount.DataType.New)
+		}
+
+		for _, name := range []string{"status", "flags"} {
+			if _, reported := modified[name]; reported == ignoreOrder {
+				t.Errorf("IgnoreOrder=%v: expected reordered %s values reported=%v", ignoreOrder, name, !ignoreOrder)
+			}
+		}
+	}
+
+	// Values are compared exactly, not just as a set of the same size
+	renamed := parseSingleTable(t, "CREATE TABLE prices (amount DECIMAL(10,2), status ENUM('a','c'), flags SET('x','y'))")
+	analyzer := NewTableDiffAnalyzer()
+	analyzer.IgnoreOrder = true
+	if diff := analyzer.CompareTables(oldTable, renamed); diff.ColumnsModified != 1 || diff.ColumnDiffs[0].Name != "status" {
+		t.Errorf("Expected only the changed ENUM value to be reported, got %d modified columns", diff.ColumnsModified)
+	}
+}
+
+// TestIgnoreAutoIncrement tests that AUTO_INCREMENT counter changes are not reported under
+// IgnoreAutoIncrement while other table option changes still are
+func TestIgnoreAutoIncrement(t *testing.T) {
+	oldTable := parseSingleTable(t, "CREATE TABLE users (id INT AUTO_INCREMENT, PRIMARY KEY (id)) ENGINE=InnoDB AUTO_INCREMENT=100")
+	advanced := parseSingleTable(t, "CREATE TABLE users (id INT AUTO_INCREMENT, PRIMARY KEY (id)) ENGINE=InnoDB AUTO_INCREMENT=2500")
+	counterOnly := parseSingleTable(t, "CREATE TABLE users (id INT AUTO_INCREMENT, PRIMARY KEY (id)) AUTO_INCREMENT=7")
+	withoutOptions := parseSingleTable(t, "CREATE TABLE users (id INT AUTO_INCREMENT, PRIMARY KEY (id))")
+
+	analyzer := NewTableDiffAnalyzer()
+	if diff := analyzer.CompareTables(oldTable, advanced); diff.TableOptionsDiff == nil || diff.TableOptionsDiff.Changes.AutoIncrement == nil {
+		t.Fatal("Expected the AUTO_INCREMENT change to be reported by default")
+	}
+
+	analyzer.IgnoreAutoIncrement = true
+	if diff := analyzer.CompareTables(oldTable, advanced); diff.TableOptionsDiff != nil || diff.HasChanges() {
+		t.Errorf("Expected no table options diff under IgnoreAutoIncrement, got %+v", diff.TableOptionsDiff)
+	}
+	if diff := analyzer.CompareTables(counterOnly, withoutOptions); diff.TableOptionsDiff != nil {
+		t.Errorf("Expected options holding only AUTO_INCREMENT to count as absent, got %+v", diff.TableOptionsDiff)
+	}
+
+	// Other option changes are still reported, without the counter
+	changedEngine := parseSingleTable(t, "CREATE TABLE users (id INT AUTO_INCREMENT, PRIMARY KEY (id)) ENGINE=MyISAM AUTO_INCREMENT=2500")
+	diff := analyzer.CompareTables(oldTable, changedEngine)
+	if diff.TableOptionsDiff == nil || diff.TableOptionsDiff.Changes.Engine == nil {
+		t.Fatal("Expected the engine change to be reported under IgnoreAutoIncrement")
+	}
+	if diff.TableOptionsDiff.Changes.AutoIncrement != nil {
+		t.Error("Expected the AUTO_INCREMENT change to be left out")
+	}
+}
+
+// TestOnUpdateChange tests that adding, removing and changing ON UPDATE is reported
+func TestOnUpdateChange(t *testing.T) {
+	base := parseSingleTable(t, "CREATE TABLE audit (updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP)")
+	withOnUpdate := parseSingleTable(t, "CREATE TABLE audit (updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP)")
+
+	analyzer := NewTableDiffAnalyzer()
+	diff := analyzer.CompareTables(base, withOnUpdate)
+	if diff.ColumnsModified != 1 || diff.ColumnDiffs[0].Changes.OnUpdate == nil {
+		t.Fatal("Expected an ON UPDATE change")
+	}
+	if change := diff.ColumnDiffs[0].Changes.OnUpdate; change.Old != nil || change.New != "CURRENT_TIMESTAMP" {
+		t.Errorf("Expected ON UPDATE nil -> CURRENT_TIMESTAMP, got %v -> %v", change.Old, change.New)
+	}
+
+	if diff := analyzer.CompareTables(withOnUpdate, base); diff.ColumnsModified != 1 {
+		t.Error("Expected removing ON UPDATE to be reported")
+	}
+
+	synonym := parseSingleTable(t, "CREATE TABLE audit (updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE NOW())")
+	if diff := analyzer.CompareTables(withOnUpdate, synonym); diff.HasChanges() {
+		t.Error("Expected ON UPDATE NOW() to equal ON UPDATE CURRENT_TIMESTAMP")
+	}
+}
+
+// TestInlinePrimaryKeyEquivalence tests that inline and table-level primary keys match once normalized
+func TestInlinePrimaryKeyEquivalence(t *testing.T) {
+	inline := parseSingleTable(t, "CREATE TABLE users (id INT PRIMARY KEY, name VARCHAR(50))")
+	tableLevel := parseSingleTable(t, "CREATE TABLE users (id INT, name VARCHAR(50), PRIMARY KEY (id))")
+
+	analyzer := NewTableDiffAnalyzer()
+	if diff := analyzer.CompareTables(inline, tableLevel); !diff.HasChanges() {
+		t.Fatal("Expected the two primary key forms to differ without normalization")
+	}
+
+	parser.MoveInlinePrimaryKey(inline)
+	parser.MoveInlinePrimaryKey(tableLevel)
+	if diff := analyzer.CompareTables(inline, tableLevel); diff.HasChanges() {
+		t.Errorf("Expected no changes after normalization, got %d modified columns, PK diff %+v", diff.ColumnsModified, diff.PrimaryKeyDiff)
+	}
+
+	// A primary key on a different column is still reported
+	other := parseSingleTable(t, "CREATE TABLE users (id INT, name VARCHAR(50) PRIMARY KEY)")
+	parser.MoveInlinePrimaryKey(other)
+	if diff := analyzer.CompareTables(inline, other); diff.PrimaryKeyDiff == nil {
+		t.Error("Expected a primary key change")
+	}
+}
+
+// TestJSONExpressionDefaultChange tests that a JSON column gaining an expression default is reported
+func TestJSONExpressionDefaultChange(t *testing.T) {
+	base := parseSingleTable(t, "CREATE TABLE docs (body JSON)")
+	withDefault := parseSingleTable(t, "CREATE TABLE docs (body JSON DEFAULT (JSON_OBJECT()))")
+
+	analyzer := NewTableDiffAnalyzer()
+	diff := analyzer.CompareTables(base, withDefault)
+	if diff.ColumnsModified != 1 || diff.ColumnDiffs[0].Changes.DefaultValue == nil {
+		t.Fatal("Expected a default value change")
+	}
+	if change := diff.ColumnDiffs[0].Changes.DefaultValue; change.Old != nil || change.New != "(JSON_OBJECT())" {
+		t.Errorf("Expected default nil -> (JSON_OBJECT()), got %v -> %v", change.Old, change.New)
+	}
+
+	respelled := parseSingleTable(t, "CREATE TABLE docs (body JSON DEFAULT ((json_object( ))))")
+	if diff := analyzer.CompareTables(withDefault, respelled); diff.HasChanges() {
+		t.Error("Expected expression defaults differing in case, spacing and parentheses to be equal")
+	}
+
+	different := parseSingleTable(t, "CREATE TABLE docs (body JSON DEFAULT (JSON_ARRAY()))")
+	if diff := analyzer.CompareTables(withDefault, different); diff.ColumnsModified != 1 {
+		t.Error("Expected a different expression default to be reported")
+	}
+}
+
+// TestBacktickQuotedIdentifiers tests that quoting table, column and index names with backticks is not a change
+func TestBacktickQuotedIdentifiers(t *testing.T) {
+	unquoted := parseSingleTable(t, `CREATE TABLE users (
+  id INT NOT NULL,
+  email VARCHAR(255),
+  status VARCHAR(20),
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_email (email),
+  KEY idx_status (status, email)
+)`)
+	quoted := parseSingleTable(t, "CREATE TABLE `users` (\n"+
+		"  `id` INT NOT NULL,\n"+
+		"  `email` VARCHAR(255),\n"+
+		"  `status` VARCHAR(20),\n"+
+		"  PRIMARY KEY (`id`),\n"+
+		"  UNIQUE KEY `uk_email` (`email`),\n"+
+		"  KEY `idx_status` (`status`, `email`)\n"+
+		")")
+
+	analyzer := NewTableDiffAnalyzer()
+	if tableDiff := analyzer.CompareTables(unquoted, quoted); tableDiff.HasChanges() {
+		t.Errorf("Expected no changes between quoted and unquoted identifiers, got %+v", tableDiff.GetSummary())
+	}
+
+	// Reserved words can only be used as identifiers when quoted; the quotes must not end up in the names
+	reserved := parseSingleTable(t, "CREATE TABLE `order` (`key` INT, `index` INT, KEY `primary_key` (`key`), KEY `table` (`index`))")
+	if reserved.TableName != "order" {
+		t.Errorf("Expected table name order, got %q", reserved.TableName)
+	}
+	for i, name := range []string{"key", "index"} {
+		if reserved.Columns[i].Name != name {
+			t.Errorf("Expected column %d to be named %q, got %q", i, name, reserved.Columns[i].Name)
+		}
+	}
+	if len(reserved.Indexes) != 2 || *reserved.Indexes[1].Name != "table" || reserved.Indexes[1].Columns[0].Name != "index" {
+		t.Errorf("Expected index `table` on column `index`, got %+v", reserved.Indexes)
+	}
+}
@@ -0,0 +1,423 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/n0madic/mysql-diff/pkg/parser"
+)
+
+// PatchOpType represents the kind of a schema patch operation
+type PatchOpType string
+
+const (
+	PatchOpAddTable        PatchOpType = "add_table"
+	PatchOpDropTable       PatchOpType = "drop_table"
+	PatchOpRenameTable     PatchOpType = "rename_table"
+	PatchOpAddColumn       PatchOpType = "add_column"
+	PatchOpDropColumn      PatchOpType = "drop_column"
+	PatchOpModifyColumn    PatchOpType = "modify_column"
+	PatchOpSetPrimaryKey   PatchOpType = "set_primary_key"
+	PatchOpAddIndex        PatchOpType = "add_index"
+	PatchOpDropIndex       PatchOpType = "drop_index"
+	PatchOpAddForeignKey   PatchOpType = "add_foreign_key"
+	PatchOpDropForeignKey  PatchOpType = "drop_foreign_key"
+	PatchOpAddCheck        PatchOpType = "add_check"
+	PatchOpDropCheck       PatchOpType = "drop_check"
+	PatchOpSetTableOptions PatchOpType = "set_table_options"
+	PatchOpSetPartitioning PatchOpType = "set_partitioning"
+)
+
+// PatchOperation is a single SQL-independent schema change. Only the fields relevant to Op are set;
+// for the set_* operations a missing definition means the element is removed.
+type PatchOperation struct {
+	Op    PatchOpType `json:"op"`
+	Table string      `json:"table"`
+
+	// Column name for drop_column and modify_column
+	Column string `json:"column,omitempty"`
+	// Name of the column an added column follows; empty means the first position
+	After string `json:"after,omitempty"`
+	// New table name for rename_table
+	NewName string `json:"new_name,omitempty"`
+
+	Definition   *parser.CreateTableStatement `json:"definition,omitempty"`
+	ColumnDef    *parser.ColumnDefinition     `json:"column_def,omitempty"`
+	PrimaryKey   *parser.PrimaryKeyDefinition `json:"primary_key,omitempty"`
+	Index        *parser.IndexDefinition      `json:"index,omitempty"`
+	ForeignKey   *parser.ForeignKeyDefinition `json:"foreign_key,omitempty"`
+	Check        *parser.CheckConstraint      `json:"check,omitempty"`
+	TableOptions *parser.TableOptions         `json:"table_options,omitempty"`
+	Partitioning *parser.PartitionOptions     `json:"partitioning,omitempty"`
+}
+
+// SchemaPatch is an ordered list of operations that transforms one schema into another
+type SchemaPatch struct {
+	Operations []PatchOperation `json:"operations"`
+}
+
+// NewSchemaPatch builds the patch operations for a schema diff. Removed tables come first,
+// then changes to existing tables and finally added tables, each group ordered by table name.
+func NewSchemaPatch(sd *SchemaDiff) *SchemaPatch {
+	patch := &SchemaPatch{Operations: []PatchOperation{}}
+	if sd == nil {
+		return patch
+	}
+
+	for _, table := range sortedTables(sd.RemovedTables) {
+		patch.Operations = append(patch.Operations, PatchOperation{Op: PatchOpDropTable, Table: table.TableName})
+	}
+
+	modified := slices.Clone(sd.ModifiedTables)
+	slices.SortFunc(modified, func(a, b *TableDiff) int {
+		return strings.Compare(tableDiffName(a), tableDiffName(b))
+	})
+	for _, tableDiff := range modified {
+		patch.Operations = append(patch.Operations, tableDiffOperations(tableDiff)...)
+	}
+
+	for _, table := range sortedTables(sd.AddedTables) {
+		patch.Operations = append(patch.Operations, PatchOperation{Op: PatchOpAddTable, Table: table.TableName, Definition: table})
+	}
+
+	return patch
+}
+
+// MarshalPatch serializes the patch for a schema diff as JSON
+func MarshalPatch(sd *SchemaDiff) ([]byte, error) {
+	return json.MarshalIndent(NewSchemaPatch(sd), "", "  ")
+}
+
+// UnmarshalPatch parses a JSON schema patch produced by MarshalPatch
+func UnmarshalPatch(data []byte) (*SchemaPatch, error) {
+	var patch SchemaPatch
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return nil, fmt.Errorf("invalid schema patch: %w", err)
+	}
+	return &patch, nil
+}
+
+// ApplyPatch applies the patch operations in order. Tables in the slice are modified in place;
+// the returned slice reflects added and dropped tables.
+func ApplyPatch(tables []*parser.CreateTableStatement, patch *SchemaPatch) ([]*parser.CreateTableStatement, error) {
+	for i, op := range patch.Operations {
+		var err error
+		tables, err = applyOperation(tables, op)
+		if err != nil {
+			return tables, fmt.Errorf("operation %d (%s on %s): %w", i+1, op.Op, op.Table, err)
+		}
+	}
+	return tables, nil
+}
+
+// tableDiffOperations converts the changes of one table into patch operations.
+// Removals are emitted before additions so that replaced elements do not collide.
+func tableDiffOperations(td *TableDiff) []PatchOperation {
+	tableName := tableDiffName(td)
+	var ops []PatchOperation
+
+	var droppedFKs, addedFKs []*parser.ForeignKeyDefinition
+	for _, fkDiff := range td.ForeignKeyDiffs {
+		if fkDiff.OldFK != nil && fkDiff.ChangeType != ChangeTypeAdded {
+			droppedFKs = append(droppedFKs, fkDiff.OldFK)
+		}
+		if fkDiff.NewFK != nil && fkDiff.ChangeType != ChangeTypeRemoved {
+			addedFKs = append(addedFKs, fkDiff.NewFK)
+		}
+	}
+
+	var droppedChecks, addedChecks []*parser.CheckConstraint
+	for _, checkDiff := range td.CheckConstraintDiffs {
+		if checkDiff.OldCheck != nil && checkDiff.ChangeType != ChangeTypeAdded {
+			droppedChecks = append(droppedChecks, checkDiff.OldCheck)
+		}
+		if checkDiff.NewCheck != nil && checkDiff.ChangeType != ChangeTypeRemoved {
+			addedChecks = append(addedChecks, checkDiff.NewCheck)
+		}
+	}
+
+	var droppedIndexes, addedIndexes []*parser.IndexDefinition
+	for _, idxDiff := range td.IndexDiffs {
+		if idxDiff.OldIndex != nil && idxDiff.ChangeType != ChangeTypeAdded {
+			droppedIndexes = append(droppedIndexes, idxDiff.OldIndex)
+		}
+		if idxDiff.NewIndex != nil && idxDiff.ChangeType != ChangeTypeRemoved {
+			addedIndexes = append(addedIndexes, idxDiff.NewIndex)
+		}
+	}
+
+	var droppedColumns, modifiedColumns []ColumnDiff
+	addedColumns := make(map[string]*parser.ColumnDefinition)
+	for _, colDiff := range td.ColumnDiffs {
+		switch colDiff.ChangeType {
+		case ChangeTypeRemoved:
+			droppedColumns = append(droppedColumns, colDiff)
+		case ChangeTypeModified:
+			modifiedColumns = append(modifiedColumns, colDiff)
+		case ChangeTypeAdded:
+			addedColumns[colDiff.Name] = colDiff.NewColumn
+		}
+	}
+	byName := func(a, b ColumnDiff) int { return strings.Compare(a.Name, b.Name) }
+	slices.SortFunc(droppedColumns, byName)
+	slices.SortFunc(modifiedColumns, byName)
+
+	byCheckIdentity := func(a, b *parser.CheckConstraint) int {
+		return strings.Compare(checkConstraintIdentity(*a), checkConstraintIdentity(*b))
+	}
+	slices.SortFunc(droppedChecks, byCheckIdentity)
+	for _, check := range droppedChecks {
+		ops = append(ops, PatchOperation{Op: PatchOpDropCheck, Table: tableName, Check: check})
+	}
+
+	slices.SortFunc(droppedFKs, func(a, b *parser.ForeignKeyDefinition) int {
+		return strings.Compare(foreignKeyIdentity(*a), foreignKeyIdentity(*b))
+	})
+	for _, fk := range droppedFKs {
+		ops = append(ops, PatchOperation{Op: PatchOpDropForeignKey, Table: tableName, ForeignKey: fk})
+	}
+
+	slices.SortFunc(droppedIndexes, func(a, b *parser.IndexDefinition) int {
+		return strings.Compare(indexIdentity(*a), indexIdentity(*b))
+	})
+	for _, idx := range droppedIndexes {
+		ops = append(ops, PatchOperation{Op: PatchOpDropIndex, Table: tableName, Index: idx})
+	}
+
+	for _, colDiff := range droppedColumns {
+		ops = append(ops, PatchOperation{Op: PatchOpDropColumn, Table: tableName, Column: colDiff.Name})
+	}
+	for _, colDiff := range modifiedColumns {
+		ops = append(ops, PatchOperation{Op: PatchOpModifyColumn, Table: tableName, Column: colDiff.OldColumn.Name, ColumnDef: colDiff.NewColumn})
+	}
+
+	// Added columns follow the order of the new table so that each AFTER column already exists
+	if td.NewTable != nil {
+		for i, col := range td.NewTable.Columns {
+			column, added := addedColumns[col.Name]
+			if !added {
+				continue
+			}
+			after := ""
+			if i > 0 {
+				after = td.NewTable.Columns[i-1].Name
+			}
+			ops = append(ops, PatchOperation{Op: PatchOpAddColumn, Table: tableName, After: after, ColumnDef: column})
+		}
+	}
+
+	if td.PrimaryKeyDiff != nil {
+		ops = append(ops, PatchOperation{Op: PatchOpSetPrimaryKey, Table: tableName, PrimaryKey: td.PrimaryKeyDiff.NewPK})
+	}
+
+	slices.SortFunc(addedIndexes, func(a, b *parser.IndexDefinition) int {
+		return strings.Compare(indexIdentity(*a), indexIdentity(*b))
+	})
+	for _, idx := range addedIndexes {
+		ops = append(ops, PatchOperation{Op: PatchOpAddIndex, Table: tableName, Index: idx})
+	}
+
+	slices.SortFunc(addedFKs, func(a, b *parser.ForeignKeyDefinition) int {
+		return strings.Compare(foreignKeyIdentity(*a), foreignKeyIdentity(*b))
+	})
+	for _, fk := range addedFKs {
+		ops = append(ops, PatchOperation{Op: PatchOpAddForeignKey, Table: tableName, ForeignKey: fk})
+	}
+
+	slices.SortFunc(addedChecks, byCheckIdentity)
+	for _, check := range addedChecks {
+		ops = append(ops, PatchOperation{Op: PatchOpAddCheck, Table: tableName, Check: check})
+	}
+
+	if td.TableOptionsDiff != nil {
+		ops = append(ops, PatchOperation{Op: PatchOpSetTableOptions, Table: tableName, TableOptions: td.TableOptionsDiff.NewOptions})
+	}
+	if td.PartitionDiff != nil {
+		ops = append(ops, PatchOperation{Op: PatchOpSetPartitioning, Table: tableName, Partitioning: td.PartitionDiff.NewPartition})
+	}
+
+	// Rename last, as the operations above refer to the old name
+	if td.TableNameChanged && td.NewTable != nil {
+		ops = append(ops, PatchOperation{Op: PatchOpRenameTable, Table: tableName, NewName: td.NewTable.TableName})
+	}
+
+	return ops
+}
+
+// applyOperation applies a single patch operation
+func applyOperation(tables []*parser.CreateTableStatement, op PatchOperation) ([]*parser.CreateTableStatement, error) {
+	pos := slices.IndexFunc(tables, func(t *parser.CreateTableStatement) bool { return t.TableName == op.Table })
+
+	if op.Op == PatchOpAddTable {
+		if pos >= 0 {
+			return tables, fmt.Errorf("table already exists")
+		}
+		if op.Definition == nil {
+			return tables, fmt.Errorf("missing table definition")
+		}
+		return append(tables, op.Definition), nil
+	}
+
+	if pos < 0 {
+		return tables, fmt.Errorf("table not found")
+	}
+	table := tables[pos]
+
+	switch op.Op {
+	case PatchOpDropTable:
+		return slices.Delete(tables, pos, pos+1), nil
+
+	case PatchOpRenameTable:
+		if op.NewName == "" {
+			return tables, fmt.Errorf("missing new table name")
+		}
+		table.TableName = op.NewName
+
+	case PatchOpAddColumn:
+		if op.ColumnDef == nil {
+			return tables, fmt.Errorf("missing column definition")
+		}
+		insertAt := 0
+		if op.After != "" {
+			afterPos := columnPosition(table, op.After)
+			if afterPos < 0 {
+				return tables, fmt.Errorf("column %q not found", op.After)
+			}
+			insertAt = afterPos + 1
+		}
+		table.Columns = slices.Insert(table.Columns, insertAt, *op.ColumnDef)
+
+	case PatchOpDropColumn:
+		colPos := columnPosition(table, op.Column)
+		if colPos < 0 {
+			return tables, fmt.Errorf("column %q not found", op.Column)
+		}
+		table.Columns = slices.Delete(table.Columns, colPos, colPos+1)
+
+	case PatchOpModifyColumn:
+		if op.ColumnDef == nil {
+			return tables, fmt.Errorf("missing column definition")
+		}
+		colPos := columnPosition(table, op.Column)
+		if colPos < 0 {
+			return tables, fmt.Errorf("column %q not found", op.Column)
+		}
+		table.Columns[colPos] = *op.ColumnDef
+
+	case PatchOpSetPrimaryKey:
+		table.PrimaryKey = op.PrimaryKey
+
+	case PatchOpAddIndex:
+		if op.Index == nil {
+			return tables, fmt.Errorf("missing index definition")
+		}
+		table.Indexes = append(table.Indexes, *op.Index)
+
+	case PatchOpDropIndex:
+		if op.Index == nil {
+			return tables, fmt.Errorf("missing index definition")
+		}
+		identity := indexIdentity(*op.Index)
+		idxPos := slices.IndexFunc(table.Indexes, func(idx parser.IndexDefinition) bool { return indexIdentity(idx) == identity })
+		if idxPos < 0 {
+			return tables, fmt.Errorf("index %s not found", identity)
+		}
+		table.Indexes = slices.Delete(table.Indexes, idxPos, idxPos+1)
+
+	case PatchOpAddForeignKey:
+		if op.ForeignKey == nil {
+			return tables, fmt.Errorf("missing foreign key definition")
+		}
+		table.ForeignKeys = append(table.ForeignKeys, *op.ForeignKey)
+
+	case PatchOpDropForeignKey:
+		if op.ForeignKey == nil {
+			return tables, fmt.Errorf("missing foreign key definition")
+		}
+		identity := foreignKeyIdentity(*op.ForeignKey)
+		fkPos := slices.IndexFunc(table.ForeignKeys, func(fk parser.ForeignKeyDefinition) bool { return foreignKeyIdentity(fk) == identity })
+		if fkPos < 0 {
+			return tables, fmt.Errorf("foreign key %s not found", identity)
+		}
+		table.ForeignKeys = slices.Delete(table.ForeignKeys, fkPos, fkPos+1)
+
+	case PatchOpAddCheck:
+		if op.Check == nil {
+			return tables, fmt.Errorf("missing check constraint definition")
+		}
+		table.CheckConstraints = append(table.CheckConstraints, *op.Check)
+
+	case PatchOpDropCheck:
+		if op.Check == nil {
+			return tables, fmt.Errorf("missing check constraint definition")
+		}
+		identity := checkConstraintIdentity(*op.Check)
+		checkPos := slices.IndexFunc(table.CheckConstraints, func(check parser.CheckConstraint) bool {
+			return checkConstraintIdentity(check) == identity
+		})
+		if checkPos < 0 {
+			return tables, fmt.Errorf("check constraint %s not found", identity)
+		}
+		table.CheckConstraints = slices.Delete(table.CheckConstraints, checkPos, checkPos+1)
+
+	case PatchOpSetTableOptions:
+		table.TableOptions = op.TableOptions
+
+	case PatchOpSetPartitioning:
+		table.PartitionOptions = op.Partitioning
+
+	default:
+		return tables, fmt.Errorf("unknown operation")
+	}
+
+	return tables, nil
+}
+
+// sortedTables returns the tables ordered by name
+func sortedTables(tables []*parser.CreateTableStatement) []*parser.CreateTableStatement {
+	sorted := slices.Clone(tables)
+	slices.SortFunc(sorted, func(a, b *parser.CreateTableStatement) int {
+		return strings.Compare(a.TableName, b.TableName)
+	})
+	return sorted
+}
+
+// tableDiffName returns the name of the table a diff applies to
+func tableDiffName(td *TableDiff) string {
+	if td.OldTable != nil {
+		return td.OldTable.TableName
+	}
+	if td.NewTable != nil {
+		return td.NewTable.TableName
+	}
+	return ""
+}
+
+// columnPosition returns the index of the named column, or -1
+func columnPosition(table *parser.CreateTableStatement, name string) int {
+	return slices.IndexFunc(table.Columns, func(col parser.ColumnDefinition) bool { return col.Name == name })
+}
+
+// indexIdentity identifies an index by name, columns and type, as the index comparator matches them
+func indexIdentity(idx parser.IndexDefinition) string {
+	cols := make([]string, len(idx.Columns))
+	for i, col := range idx.Columns {
+		cols[i] = col.Name
+	}
+	name := ""
+	if idx.Name != nil {
+		name = *idx.Name
+	}
+	return fmt.Sprintf("%s:%s:%s", name, strings.Join(cols, ":"), idx.IndexType)
+}
+
+// foreignKeyIdentity identifies a foreign key by name, columns and reference, as the foreign key comparator matches them
+func foreignKeyIdentity(fk parser.ForeignKeyDefinition) string {
+	name := ""
+	if fk.Name != nil {
+		name = *fk.Name
+	}
+	return fmt.Sprintf("%s:%s:%s:%s", name, strings.Join(fk.Columns, ":"), fk.Reference.TableName, strings.Join(fk.Reference.Columns, ":"))
+}
@@ -0,0 +1,190 @@
+package diff
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/n0madic/mysql-diff/pkg/parser"
+)
+
+func mustParseDump(t *testing.T, sql string) []*parser.CreateTableStatement {
+	t.Helper()
+	tables, err := parser.ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("Failed to parse SQL: %v", err)
+	}
+	return tables
+}
+
+const patchOldSchema = `
+	CREATE TABLE users (
+		id INT NOT NULL AUTO_INCREMENT,
+		name VARCHAR(50),
+		legacy_flag TINYINT,
+		PRIMARY KEY (id),
+		INDEX idx_name (name)
+	) ENGINE=InnoDB;
+
+	CREATE TABLE orders (
+		id INT NOT NULL,
+		user_id INT,
+		PRIMARY KEY (id),
+		CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+	) ENGINE=InnoDB;
+
+	CREATE TABLE sessions (
+		id INT NOT NULL,
+		PRIMARY KEY (id)
+	);
+`
+
+const patchNewSchema = `
+	CREATE TABLE users (
+		id INT NOT NULL AUTO_INCREMENT,
+		email VARCHAR(100) NOT NULL,
+		name VARCHAR(100),
+		created_at DATETIME,
+		PRIMARY KEY (id),
+		UNIQUE KEY uk_email (email),
+		INDEX idx_name (name, email)
+	) ENGINE=InnoDB COMMENT='Users';
+
+	CREATE TABLE orders (
+		id INT NOT NULL,
+		user_id INT,
+		PRIMARY KEY (id),
+		CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE SET NULL
+	) ENGINE=InnoDB;
+
+	CREATE TABLE audit_log (
+		id BIGINT NOT NULL,
+		message TEXT,
+		PRIMARY KEY (id)
+	);
+`
+
+func TestSchemaPatchRoundTrip(t *testing.T) {
+	oldTables := mustParseDump(t, patchOldSchema)
+	newTables := mustParseDump(t, patchNewSchema)
+
+	data, err := MarshalPatch(CompareSchemas(oldTables, newTables))
+	if err != nil {
+		t.Fatalf("MarshalPatch failed: %v", err)
+	}
+
+	patch, err := UnmarshalPatch(data)
+	if err != nil {
+		t.Fatalf("UnmarshalPatch failed: %v", err)
+	}
+
+	// Apply to a freshly parsed copy of the old schema
+	patched, err := ApplyPatch(mustParseDump(t, patchOldSchema), patch)
+	if err != nil {
+		t.Fatalf("ApplyPatch failed: %v", err)
+	}
+
+	remaining := CompareSchemas(patched, newTables)
+	if len(remaining.AddedTables) != 0 || len(remaining.RemovedTables) != 0 || len(remaining.ModifiedTables) != 0 {
+		t.Errorf("Expected patched schema to match new schema, got %d added, %d removed, %d modified tables",
+			len(remaining.AddedTables), len(remaining.RemovedTables), len(remaining.ModifiedTables))
+		for _, td := range remaining.ModifiedTables {
+			t.Logf("Remaining changes in %s: %+v", td.NewTable.TableName, td.GetSummary())
+		}
+	}
+
+	// Added columns are placed where the new schema has them
+	usersPos := slices.IndexFunc(patched, func(t *parser.CreateTableStatement) bool { return t.TableName == "users" })
+	if usersPos < 0 {
+		t.Fatal("Expected users table in patched schema")
+	}
+	var columnNames []string
+	for _, col := range patched[usersPos].Columns {
+		columnNames = append(columnNames, col.Name)
+	}
+	expectedColumns := []string{"id", "email", "name", "created_at"}
+	if !slices.Equal(columnNames, expectedColumns) {
+		t.Errorf("Expected columns %v, got %v", expectedColumns, columnNames)
+	}
+}
+
+func TestMarshalPatchOperations(t *testing.T) {
+	oldTables := mustParseDump(t, patchOldSchema)
+	newTables := mustParseDump(t, patchNewSchema)
+
+	data, err := MarshalPatch(CompareSchemas(oldTables, newTables))
+	if err != nil {
+		t.Fatalf("MarshalPatch failed: %v", err)
+	}
+
+	var raw struct {
+		Operations []struct {
+			Op    string `json:"op"`
+			Table string `json:"table"`
+		} `json:"operations"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("Patch is not valid JSON: %v", err)
+	}
+
+	var ops []string
+	for _, op := range raw.Operations {
+		ops = append(ops, op.Op+" "+op.Table)
+	}
+	joined := strings.Join(ops, "\n")
+
+	for _, expected := range []string{
+		"drop_table sessions",
+		"add_table audit_log",
+		"drop_column users",
+		"modify_column users",
+		"add_column users",
+		"drop_index users",
+		"add_index users",
+		"drop_foreign_key orders",
+		"add_foreign_key orders",
+		"set_table_options users",
+	} {
+		if !strings.Contains(joined, expected) {
+			t.Errorf("Expected operation %q in patch, got:\n%s", expected, joined)
+		}
+	}
+
+	if ops[0] != "drop_table sessions" || ops[len(ops)-1] != "add_table audit_log" {
+		t.Errorf("Expected table drops first and table additions last, got:\n%s", joined)
+	}
+}
+
+func TestMarshalPatchIsDeterministic(t *testing.T) {
+	oldTables := mustParseDump(t, patchOldSchema)
+	newTables := mustParseDump(t, patchNewSchema)
+
+	first, err := MarshalPatch(CompareSchemas(oldTables, newTables))
+	if err != nil {
+		t.Fatalf("MarshalPatch failed: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		again, err := MarshalPatch(CompareSchemas(oldTables, newTables))
+		if err != nil {
+			t.Fatalf("MarshalPatch failed: %v", err)
+		}
+		if string(again) != string(first) {
+			t.Fatal("Expected identical patches for the same diff")
+		}
+	}
+}
+
+func TestApplyPatchMissingTable(t *testing.T) {
+	patch := &SchemaPatch{Operations: []PatchOperation{
+		{Op: PatchOpDropColumn, Table: "missing", Column: "id"},
+	}}
+
+	_, err := ApplyPatch(mustParseDump(t, patchOldSchema), patch)
+	if err == nil {
+		t.Fatal("Expected error when patching a missing table")
+	}
+	if !strings.Contains(err.Error(), "table not found") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -2,56 +2,75 @@ package diff
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
 
 	"github.com/n0madic/mysql-diff/pkg/output"
 	"github.com/n0madic/mysql-diff/pkg/parser"
 )
 
-// PrintTableDiff prints a human-readable summary of table differences
+// PrintTableDiff prints a human-readable summary of table differences to stdout
 func PrintTableDiff(diff *TableDiff, detailed bool) {
-	fmt.Printf("\n%s\n", output.BoldText(strings.Repeat("=", 60)))
-	fmt.Printf("TABLE DIFF: %s -> %s\n",
+	FprintTableDiff(os.Stdout, diff, detailed)
+}
+
+// FprintTableDiff writes a human-readable summary of table differences to w
+func FprintTableDiff(w io.Writer, diff *TableDiff, detailed bool) {
+	fprintTableDiff(w, diff, detailed, false)
+}
+
+// fprintTableDiff writes the summary of FprintTableDiff, leaving the added and removed columns out
+// of the detailed column changes with onlyModifiedColumns
+func fprintTableDiff(w io.Writer, diff *TableDiff, detailed, onlyModifiedColumns bool) {
+	fmt.Fprintf(w, "\n%s\n", output.BoldText(strings.Repeat("=", 60)))
+	fmt.Fprintf(w, "TABLE DIFF: %s -> %s\n",
 		output.ColorizeTableName(diff.OldTable.TableName),
 		output.ColorizeTableName(diff.NewTable.TableName))
-	fmt.Printf("%s\n", output.BoldText(strings.Repeat("=", 60)))
+	fmt.Fprintf(w, "%s\n", output.BoldText(strings.Repeat("=", 60)))
 
 	if !diff.HasChanges() {
-		fmt.Println("No changes detected.")
+		fmt.Fprintln(w, "No changes detected.")
 		return
 	}
 
 	// Table name change
 	if diff.TableNameChanged {
-		fmt.Printf("✏️  Table renamed: %s -> %s\n",
+		fmt.Fprintf(w, "✏️  Table renamed: %s -> %s\n",
 			output.ColorizeTableName(diff.OldTable.TableName),
 			output.ColorizeTableName(diff.NewTable.TableName))
 	}
 
 	// Summary
 	summary := diff.GetSummary()
-	fmt.Printf("\n%s\n", output.BoldText("SUMMARY:"))
-	fmt.Printf("  Columns: %s %s %s\n",
+	fmt.Fprintf(w, "\n%s\n", output.BoldText("SUMMARY:"))
+	fmt.Fprintf(w, "  Columns: %s %s %s\n",
 		output.GreenText(fmt.Sprintf("+%d", summary.Columns.Added)),
 		output.RedText(fmt.Sprintf("-%d", summary.Columns.Removed)),
 		output.YellowText(fmt.Sprintf("~%d", summary.Columns.Modified)))
-	fmt.Printf("  Indexes: %s %s %s\n",
+	fmt.Fprintf(w, "  Indexes: %s %s %s\n",
 		output.GreenText(fmt.Sprintf("+%d", summary.Indexes.Added)),
 		output.RedText(fmt.Sprintf("-%d", summary.Indexes.Removed)),
 		output.YellowText(fmt.Sprintf("~%d", summary.Indexes.Modified)))
-	fmt.Printf("  Foreign Keys: %s %s %s\n",
+	fmt.Fprintf(w, "  Foreign Keys: %s %s %s\n",
 		output.GreenText(fmt.Sprintf("+%d", summary.ForeignKeys.Added)),
 		output.RedText(fmt.Sprintf("-%d", summary.ForeignKeys.Removed)),
 		output.YellowText(fmt.Sprintf("~%d", summary.ForeignKeys.Modified)))
+	if len(diff.CheckConstraintDiffs) > 0 {
+		fmt.Fprintf(w, "  Check Constraints: %s %s %s\n",
+			output.GreenText(fmt.Sprintf("+%d", summary.CheckConstraints.Added)),
+			output.RedText(fmt.Sprintf("-%d", summary.CheckConstraints.Removed)),
+			output.YellowText(fmt.Sprintf("~%d", summary.CheckConstraints.Modified)))
+	}
 
 	if summary.PrimaryKeyChanged {
-		fmt.Printf("  Primary Key: %s\n", output.YellowText("CHANGED"))
+		fmt.Fprintf(w, "  Primary Key: %s\n", output.YellowText("CHANGED"))
 	}
 	if summary.TableOptionsChanged {
-		fmt.Printf("  Table Options: %s\n", output.YellowText("CHANGED"))
+		fmt.Fprintf(w, "  Table Options: %s\n", output.YellowText("CHANGED"))
 	}
 	if summary.PartitioningChanged {
-		fmt.Printf("  Partitioning: %s\n", output.YellowText("CHANGED"))
+		fmt.Fprintf(w, "  Partitioning: %s\n", output.YellowText("CHANGED"))
 	}
 
 	if !detailed {
@@ -60,94 +79,122 @@ func PrintTableDiff(diff *TableDiff, detailed bool) {
 
 	// Detailed changes
 	if len(diff.ColumnDiffs) > 0 {
-		fmt.Printf("\n%s\n", output.BoldText("COLUMN CHANGES:"))
+		fmt.Fprintf(w, "\n%s\n", output.BoldText("COLUMN CHANGES:"))
+		hidden := 0
 		for _, colDiff := range diff.ColumnDiffs {
+			if onlyModifiedColumns && colDiff.ChangeType != ChangeTypeModified {
+				hidden++
+				continue
+			}
 			switch colDiff.ChangeType {
 			case ChangeTypeAdded:
-				fmt.Printf("  %s %s: %s\n",
+				fmt.Fprintf(w, "  %s %s: %s\n",
 					output.GreenText("+"),
 					output.ColorizeColumnName(colDiff.Name),
 					formatColumn(colDiff.NewColumn))
 			case ChangeTypeRemoved:
-				fmt.Printf("  %s %s: %s\n",
+				fmt.Fprintf(w, "  %s %s: %s\n",
 					output.RedText("-"),
 					output.ColorizeColumnName(colDiff.Name),
 					formatColumn(colDiff.OldColumn))
 			case ChangeTypeModified:
-				fmt.Printf("  %s %s:\n",
+				fmt.Fprintf(w, "  %s %s:\n",
 					output.YellowText("~"),
 					output.ColorizeColumnName(colDiff.Name))
-				printColumnChanges(colDiff.Changes)
+				printColumnChanges(w, colDiff.Changes)
 			}
 		}
+		if hidden == 1 {
+			fmt.Fprintln(w, "  (1 added or removed column not shown)")
+		} else if hidden > 1 {
+			fmt.Fprintf(w, "  (%d added or removed columns not shown)\n", hidden)
+		}
 	}
 
 	if len(diff.IndexDiffs) > 0 {
-		fmt.Println("\nINDEX CHANGES:")
+		fmt.Fprintln(w, "\nINDEX CHANGES:")
 		for _, idxDiff := range diff.IndexDiffs {
 			switch idxDiff.ChangeType {
 			case ChangeTypeAdded:
-				fmt.Printf("  + %s\n", formatIndex(idxDiff.NewIndex))
+				fmt.Fprintf(w, "  + %s\n", formatIndex(idxDiff.NewIndex))
 			case ChangeTypeRemoved:
-				fmt.Printf("  - %s\n", formatIndex(idxDiff.OldIndex))
+				fmt.Fprintf(w, "  - %s\n", formatIndex(idxDiff.OldIndex))
 			case ChangeTypeModified:
-				fmt.Printf("  ~ %s:\n", formatIndex(idxDiff.OldIndex))
-				printIndexChanges(idxDiff.Changes)
+				fmt.Fprintf(w, "  ~ %s:\n", formatIndex(idxDiff.OldIndex))
+				printIndexChanges(w, idxDiff.Changes)
 			}
 		}
 	}
 
 	if len(diff.ForeignKeyDiffs) > 0 {
-		fmt.Println("\nFOREIGN KEY CHANGES:")
+		fmt.Fprintln(w, "\nFOREIGN KEY CHANGES:")
 		for _, fkDiff := range diff.ForeignKeyDiffs {
 			switch fkDiff.ChangeType {
 			case ChangeTypeAdded:
-				fmt.Printf("  + %s\n", formatForeignKey(fkDiff.NewFK))
+				fmt.Fprintf(w, "  + %s\n", formatForeignKey(fkDiff.NewFK))
+			case ChangeTypeRemoved:
+				fmt.Fprintf(w, "  - %s\n", formatForeignKey(fkDiff.OldFK))
+			case ChangeTypeModified:
+				fmt.Fprintf(w, "  ~ %s:\n", formatForeignKey(fkDiff.OldFK))
+				printForeignKeyChanges(w, fkDiff.Changes)
+			}
+		}
+	}
+
+	if len(diff.CheckConstraintDiffs) > 0 {
+		fmt.Fprintln(w, "\nCHECK CONSTRAINT CHANGES:")
+		for _, checkDiff := range diff.CheckConstraintDiffs {
+			switch checkDiff.ChangeType {
+			case ChangeTypeAdded:
+				fmt.Fprintf(w, "  + %s\n", formatCheckConstraint(checkDiff.NewCheck))
 			case ChangeTypeRemoved:
-				fmt.Printf("  - %s\n", formatForeignKey(fkDiff.OldFK))
+				fmt.Fprintf(w, "  - %s\n", formatCheckConstraint(checkDiff.OldCheck))
 			case ChangeTypeModified:
-				fmt.Printf("  ~ %s:\n", formatForeignKey(fkDiff.OldFK))
-				printForeignKeyChanges(fkDiff.Changes)
+				fmt.Fprintf(w, "  ~ %s:\n", formatCheckConstraint(checkDiff.OldCheck))
+				printCheckConstraintChanges(w, checkDiff.Changes)
 			}
 		}
 	}
 
 	if diff.PrimaryKeyDiff != nil {
-		fmt.Println("\nPRIMARY KEY CHANGES:")
+		fmt.Fprintln(w, "\nPRIMARY KEY CHANGES:")
 		switch diff.PrimaryKeyDiff.ChangeType {
 		case ChangeTypeAdded:
-			fmt.Printf("  + %s\n", formatPrimaryKey(diff.PrimaryKeyDiff.NewPK))
+			fmt.Fprintf(w, "  + %s\n", formatPrimaryKey(diff.PrimaryKeyDiff.NewPK))
 		case ChangeTypeRemoved:
-			fmt.Printf("  - %s\n", formatPrimaryKey(diff.PrimaryKeyDiff.OldPK))
+			fmt.Fprintf(w, "  - %s\n", formatPrimaryKey(diff.PrimaryKeyDiff.OldPK))
 		case ChangeTypeModified:
-			fmt.Printf("  ~ %s:\n", formatPrimaryKey(diff.PrimaryKeyDiff.OldPK))
-			printPrimaryKeyChanges(diff.PrimaryKeyDiff.Changes)
+			fmt.Fprintf(w, "  ~ %s:\n", formatPrimaryKey(diff.PrimaryKeyDiff.OldPK))
+			printPrimaryKeyChanges(w, diff.PrimaryKeyDiff.Changes)
 		}
 	}
 
 	if diff.TableOptionsDiff != nil {
-		fmt.Println("\nTABLE OPTIONS CHANGES:")
+		fmt.Fprintln(w, "\nTABLE OPTIONS CHANGES:")
 		switch diff.TableOptionsDiff.ChangeType {
 		case ChangeTypeAdded:
-			fmt.Println("  + Table options added")
+			fmt.Fprintln(w, "  + Table options added")
 		case ChangeTypeRemoved:
-			fmt.Println("  - Table options removed")
+			fmt.Fprintln(w, "  - Table options removed")
 		case ChangeTypeModified:
-			fmt.Println("  ~ Table options modified:")
-			printTableOptionsChanges(diff.TableOptionsDiff.Changes)
+			fmt.Fprintln(w, "  ~ Table options modified:")
+			printTableOptionsChanges(w, diff.TableOptionsDiff.Changes)
+		}
+		for _, warning := range diff.TableOptionsDiff.Warnings {
+			fmt.Fprintf(w, "  %s %s\n", output.YellowText("⚠️  Warning:"), warning)
 		}
 	}
 
 	if diff.PartitionDiff != nil {
-		fmt.Println("\nPARTITION CHANGES:")
+		fmt.Fprintln(w, "\nPARTITION CHANGES:")
 		switch diff.PartitionDiff.ChangeType {
 		case ChangeTypeAdded:
-			fmt.Println("  + Partitioning added")
+			fmt.Fprintln(w, "  + Partitioning added")
 		case ChangeTypeRemoved:
-			fmt.Println("  - Partitioning removed")
+			fmt.Fprintln(w, "  - Partitioning removed")
 		case ChangeTypeModified:
-			fmt.Println("  ~ Partitioning modified:")
-			printPartitionChanges(diff.PartitionDiff.Changes)
+			fmt.Fprintln(w, "  ~ Partitioning modified:")
+			printPartitionChanges(w, diff.PartitionDiff.Changes)
 		}
 	}
 }
@@ -183,9 +230,15 @@ func formatColumn(col *parser.ColumnDefinition) string {
 	if col.DefaultValue != nil {
 		result += fmt.Sprintf(" %s %s", output.BlueText("DEFAULT"), output.ColorizeString(*col.DefaultValue))
 	}
+	if col.OnUpdate != nil {
+		result += fmt.Sprintf(" %s %s", output.BlueText("ON UPDATE"), *col.OnUpdate)
+	}
 	if col.Comment != nil {
 		result += fmt.Sprintf(" %s %s", output.BlueText("COMMENT"), output.ColorizeString("'"+*col.Comment+"'"))
 	}
+	for _, check := range columnCheckDefinitions(col.Checks) {
+		result += " " + check
+	}
 	return result
 }
 
@@ -235,6 +288,25 @@ func formatForeignKey(fk *parser.ForeignKeyDefinition) string {
 	return result
 }
 
+// formatCheckConstraint formats check constraint definition for display
+func formatCheckConstraint(check *parser.CheckConstraint) string {
+	if check == nil {
+		return ""
+	}
+
+	name := "UNNAMED"
+	if check.Name != nil {
+		name = *check.Name
+	}
+
+	result := fmt.Sprintf("CHECK %s: (%s)", name, check.Expression)
+	if !isCheckEnforced(*check) {
+		result += " NOT ENFORCED"
+	}
+
+	return result
+}
+
 // formatPrimaryKey formats primary key definition for display
 func formatPrimaryKey(pk *parser.PrimaryKeyDefinition) string {
 	if pk == nil {
@@ -254,10 +326,15 @@ func formatPrimaryKey(pk *parser.PrimaryKeyDefinition) string {
 	return fmt.Sprintf("PRIMARY KEY%s (%s)", name, strings.Join(cols, ", "))
 }
 
-// PrintDiffSummary prints a concise summary of changes
+// PrintDiffSummary prints a concise summary of changes to stdout
 func PrintDiffSummary(diff *TableDiff) {
+	FprintDiffSummary(os.Stdout, diff)
+}
+
+// FprintDiffSummary writes a concise summary of changes to w
+func FprintDiffSummary(w io.Writer, diff *TableDiff) {
 	if !diff.HasChanges() {
-		fmt.Printf("Table %s: No changes\n", diff.OldTable.TableName)
+		fmt.Fprintf(w, "Table %s: No changes\n", diff.OldTable.TableName)
 		return
 	}
 
@@ -293,6 +370,16 @@ func PrintDiffSummary(diff *TableDiff) {
 		changes = append(changes, fmt.Sprintf("~%d fk", diff.ForeignKeysModified))
 	}
 
+	if diff.CheckConstraintsAdded > 0 {
+		changes = append(changes, fmt.Sprintf("+%d chk", diff.CheckConstraintsAdded))
+	}
+	if diff.CheckConstraintsRemoved > 0 {
+		changes = append(changes, fmt.Sprintf("-%d chk", diff.CheckConstraintsRemoved))
+	}
+	if diff.CheckConstraintsModified > 0 {
+		changes = append(changes, fmt.Sprintf("~%d chk", diff.CheckConstraintsModified))
+	}
+
 	if diff.PrimaryKeyDiff != nil {
 		changes = append(changes, "pk changed")
 	}
@@ -303,160 +390,261 @@ func PrintDiffSummary(diff *TableDiff) {
 		changes = append(changes, "partitions changed")
 	}
 
-	fmt.Printf("Table %s: %s\n", diff.OldTable.TableName, strings.Join(changes, ", "))
+	fmt.Fprintf(w, "Table %s: %s\n", diff.OldTable.TableName, strings.Join(changes, ", "))
 }
 
 // Helper functions for printing typed changes
 
-func printColumnChanges(changes *ColumnChanges) {
+func printColumnChanges(w io.Writer, changes *ColumnChanges) {
+	if changes.Name != nil {
+		fmt.Fprintf(w, "      name: %v -> %v\n", changes.Name.Old, changes.Name.New)
+	}
 	if changes.DataType != nil {
-		fmt.Printf("      data_type: %v -> %v\n", changes.DataType.Old, changes.DataType.New)
+		fmt.Fprintf(w, "      data_type: %v -> %v\n", changes.DataType.Old, changes.DataType.New)
+	}
+	if changes.EnumValues != nil {
+		fmt.Fprintf(w, "      enum_values: added %v, removed %v", changes.EnumValues.Added, changes.EnumValues.Removed)
+		if changes.EnumValues.Reordered {
+			fmt.Fprint(w, ", reordered")
+		}
+		fmt.Fprintln(w)
 	}
 	if changes.Nullable != nil {
-		fmt.Printf("      nullable: %v -> %v\n", changes.Nullable.Old, changes.Nullable.New)
+		fmt.Fprintf(w, "      nullable: %v -> %v\n", changes.Nullable.Old, changes.Nullable.New)
 	}
 	if changes.DefaultValue != nil {
-		fmt.Printf("      default_value: %v -> %v\n", changes.DefaultValue.Old, changes.DefaultValue.New)
+		fmt.Fprintf(w, "      default_value: %v -> %v\n", changes.DefaultValue.Old, changes.DefaultValue.New)
+	}
+	if changes.OnUpdate != nil {
+		fmt.Fprintf(w, "      on_update: %v -> %v\n", changes.OnUpdate.Old, changes.OnUpdate.New)
 	}
 	if changes.AutoIncrement != nil {
-		fmt.Printf("      auto_increment: %v -> %v\n", changes.AutoIncrement.Old, changes.AutoIncrement.New)
+		fmt.Fprintf(w, "      auto_increment: %v -> %v\n", changes.AutoIncrement.Old, changes.AutoIncrement.New)
 	}
 	if changes.Unique != nil {
-		fmt.Printf("      unique: %v -> %v\n", changes.Unique.Old, changes.Unique.New)
+		fmt.Fprintf(w, "      unique: %v -> %v\n", changes.Unique.Old, changes.Unique.New)
 	}
 	if changes.PrimaryKey != nil {
-		fmt.Printf("      primary_key: %v -> %v\n", changes.PrimaryKey.Old, changes.PrimaryKey.New)
+		fmt.Fprintf(w, "      primary_key: %v -> %v\n", changes.PrimaryKey.Old, changes.PrimaryKey.New)
 	}
 	if changes.Comment != nil {
-		fmt.Printf("      comment: %v -> %v\n", changes.Comment.Old, changes.Comment.New)
+		fmt.Fprintf(w, "      comment: %v -> %v\n", changes.Comment.Old, changes.Comment.New)
 	}
 	if changes.Collation != nil {
-		fmt.Printf("      collation: %v -> %v\n", changes.Collation.Old, changes.Collation.New)
+		fmt.Fprintf(w, "      collation: %v -> %v\n", changes.Collation.Old, changes.Collation.New)
 	}
 	if changes.CharacterSet != nil {
-		fmt.Printf("      character_set: %v -> %v\n", changes.CharacterSet.Old, changes.CharacterSet.New)
+		fmt.Fprintf(w, "      character_set: %v -> %v\n", changes.CharacterSet.Old, changes.CharacterSet.New)
 	}
 	if changes.Visible != nil {
-		fmt.Printf("      visible: %v -> %v\n", changes.Visible.Old, changes.Visible.New)
+		fmt.Fprintf(w, "      visible: %v -> %v\n", changes.Visible.Old, changes.Visible.New)
 	}
 	if changes.ColumnFormat != nil {
-		fmt.Printf("      column_format: %v -> %v\n", changes.ColumnFormat.Old, changes.ColumnFormat.New)
+		fmt.Fprintf(w, "      column_format: %v -> %v\n", changes.ColumnFormat.Old, changes.ColumnFormat.New)
 	}
 	if changes.Storage != nil {
-		fmt.Printf("      storage: %v -> %v\n", changes.Storage.Old, changes.Storage.New)
+		fmt.Fprintf(w, "      storage: %v -> %v\n", changes.Storage.Old, changes.Storage.New)
 	}
 	if changes.Generated != nil {
-		fmt.Printf("      generated: %v -> %v\n", changes.Generated.Old, changes.Generated.New)
+		fmt.Fprintf(w, "      generated: %v -> %v\n", changes.Generated.Old, changes.Generated.New)
+	}
+	if changes.Checks != nil {
+		fmt.Fprintf(w, "      checks: %v -> %v\n", changes.Checks.Old, changes.Checks.New)
+	}
+	if changes.Position != nil {
+		fmt.Fprintf(w, "      position: %v -> %v\n", changes.Position.Old, changes.Position.New)
 	}
 }
 
-func printIndexChanges(changes *IndexChanges) {
+func printIndexChanges(w io.Writer, changes *IndexChanges) {
 	if changes.Name != nil {
-		fmt.Printf("      name: %v -> %v\n", changes.Name.Old, changes.Name.New)
+		fmt.Fprintf(w, "      name: %v -> %v\n", changes.Name.Old, changes.Name.New)
 	}
 	if changes.IndexType != nil {
-		fmt.Printf("      index_type: %v -> %v\n", changes.IndexType.Old, changes.IndexType.New)
+		fmt.Fprintf(w, "      index_type: %v -> %v\n", changes.IndexType.Old, changes.IndexType.New)
 	}
 	if changes.Columns != nil {
-		fmt.Printf("      columns: %v -> %v\n", changes.Columns.Old, changes.Columns.New)
+		fmt.Fprintf(w, "      columns: %v -> %v\n", changes.Columns.Old, changes.Columns.New)
 	}
 	if changes.KeyBlockSize != nil {
-		fmt.Printf("      key_block_size: %v -> %v\n", changes.KeyBlockSize.Old, changes.KeyBlockSize.New)
+		fmt.Fprintf(w, "      key_block_size: %v -> %v\n", changes.KeyBlockSize.Old, changes.KeyBlockSize.New)
 	}
 	if changes.Using != nil {
-		fmt.Printf("      using: %v -> %v\n", changes.Using.Old, changes.Using.New)
+		fmt.Fprintf(w, "      using: %v -> %v\n", changes.Using.Old, changes.Using.New)
 	}
 	if changes.Comment != nil {
-		fmt.Printf("      comment: %v -> %v\n", changes.Comment.Old, changes.Comment.New)
+		fmt.Fprintf(w, "      comment: %v -> %v\n", changes.Comment.Old, changes.Comment.New)
 	}
 	if changes.Visible != nil {
-		fmt.Printf("      visible: %v -> %v\n", changes.Visible.Old, changes.Visible.New)
+		fmt.Fprintf(w, "      visible: %v -> %v\n", changes.Visible.Old, changes.Visible.New)
 	}
 	if changes.Parser != nil {
-		fmt.Printf("      parser: %v -> %v\n", changes.Parser.Old, changes.Parser.New)
+		fmt.Fprintf(w, "      parser: %v -> %v\n", changes.Parser.Old, changes.Parser.New)
 	}
 	if changes.Algorithm != nil {
-		fmt.Printf("      algorithm: %v -> %v\n", changes.Algorithm.Old, changes.Algorithm.New)
+		fmt.Fprintf(w, "      algorithm: %v -> %v\n", changes.Algorithm.Old, changes.Algorithm.New)
 	}
 	if changes.Lock != nil {
-		fmt.Printf("      lock: %v -> %v\n", changes.Lock.Old, changes.Lock.New)
+		fmt.Fprintf(w, "      lock: %v -> %v\n", changes.Lock.Old, changes.Lock.New)
 	}
 	if changes.EngineAttribute != nil {
-		fmt.Printf("      engine_attribute: %v -> %v\n", changes.EngineAttribute.Old, changes.EngineAttribute.New)
+		fmt.Fprintf(w, "      engine_attribute: %v -> %v\n", changes.EngineAttribute.Old, changes.EngineAttribute.New)
 	}
 }
 
-func printForeignKeyChanges(changes *ForeignKeyChanges) {
+func printForeignKeyChanges(w io.Writer, changes *ForeignKeyChanges) {
 	if changes.Name != nil {
-		fmt.Printf("      name: %v -> %v\n", changes.Name.Old, changes.Name.New)
+		fmt.Fprintf(w, "      name: %v -> %v\n", changes.Name.Old, changes.Name.New)
 	}
 	if changes.Columns != nil {
-		fmt.Printf("      columns: %v -> %v\n", changes.Columns.Old, changes.Columns.New)
+		fmt.Fprintf(w, "      columns: %v -> %v\n", changes.Columns.Old, changes.Columns.New)
 	}
 	if changes.ReferenceTable != nil {
-		fmt.Printf("      reference_table: %v -> %v\n", changes.ReferenceTable.Old, changes.ReferenceTable.New)
+		fmt.Fprintf(w, "      reference_table: %v -> %v\n", changes.ReferenceTable.Old, changes.ReferenceTable.New)
 	}
 	if changes.ReferenceColumns != nil {
-		fmt.Printf("      reference_columns: %v -> %v\n", changes.ReferenceColumns.Old, changes.ReferenceColumns.New)
+		fmt.Fprintf(w, "      reference_columns: %v -> %v\n", changes.ReferenceColumns.Old, changes.ReferenceColumns.New)
 	}
 	if changes.OnDelete != nil {
-		fmt.Printf("      on_delete: %v -> %v\n", changes.OnDelete.Old, changes.OnDelete.New)
+		fmt.Fprintf(w, "      on_delete: %v -> %v\n", changes.OnDelete.Old, changes.OnDelete.New)
 	}
 	if changes.OnUpdate != nil {
-		fmt.Printf("      on_update: %v -> %v\n", changes.OnUpdate.Old, changes.OnUpdate.New)
+		fmt.Fprintf(w, "      on_update: %v -> %v\n", changes.OnUpdate.Old, changes.OnUpdate.New)
+	}
+}
+
+func printCheckConstraintChanges(w io.Writer, changes *CheckConstraintChanges) {
+	if changes.Expression != nil {
+		fmt.Fprintf(w, "      expression: %v -> %v\n", changes.Expression.Old, changes.Expression.New)
+	}
+	if changes.Enforced != nil {
+		fmt.Fprintf(w, "      enforced: %v -> %v\n", changes.Enforced.Old, changes.Enforced.New)
 	}
 }
 
-func printPrimaryKeyChanges(changes *PrimaryKeyChanges) {
+func printPrimaryKeyChanges(w io.Writer, changes *PrimaryKeyChanges) {
 	if changes.Columns != nil {
-		fmt.Printf("      columns: %v -> %v\n", changes.Columns.Old, changes.Columns.New)
+		fmt.Fprintf(w, "      columns: %v -> %v\n", changes.Columns.Old, changes.Columns.New)
 	}
 	if changes.Name != nil {
-		fmt.Printf("      name: %v -> %v\n", changes.Name.Old, changes.Name.New)
+		fmt.Fprintf(w, "      name: %v -> %v\n", changes.Name.Old, changes.Name.New)
 	}
 	if changes.Using != nil {
-		fmt.Printf("      using: %v -> %v\n", changes.Using.Old, changes.Using.New)
+		fmt.Fprintf(w, "      using: %v -> %v\n", changes.Using.Old, changes.Using.New)
 	}
 	if changes.Comment != nil {
-		fmt.Printf("      comment: %v -> %v\n", changes.Comment.Old, changes.Comment.New)
+		fmt.Fprintf(w, "      comment: %v -> %v\n", changes.Comment.Old, changes.Comment.New)
 	}
 }
 
-func printTableOptionsChanges(changes *TableOptionsChanges) {
+func printTableOptionsChanges(w io.Writer, changes *TableOptionsChanges) {
 	if changes.Engine != nil {
-		fmt.Printf("      engine: %v -> %v\n", changes.Engine.Old, changes.Engine.New)
+		fmt.Fprintf(w, "      engine: %v -> %v\n", changes.Engine.Old, changes.Engine.New)
 	}
 	if changes.AutoIncrement != nil {
-		fmt.Printf("      auto_increment: %v -> %v\n", changes.AutoIncrement.Old, changes.AutoIncrement.New)
+		fmt.Fprintf(w, "      auto_increment: %v -> %v\n", changes.AutoIncrement.Old, changes.AutoIncrement.New)
 	}
 	if changes.CharacterSet != nil {
-		fmt.Printf("      character_set: %v -> %v\n", changes.CharacterSet.Old, changes.CharacterSet.New)
+		fmt.Fprintf(w, "      character_set: %v -> %v\n", changes.CharacterSet.Old, changes.CharacterSet.New)
 	}
 	if changes.Collate != nil {
-		fmt.Printf("      collate: %v -> %v\n", changes.Collate.Old, changes.Collate.New)
+		fmt.Fprintf(w, "      collate: %v -> %v\n", changes.Collate.Old, changes.Collate.New)
 	}
 	if changes.Comment != nil {
-		fmt.Printf("      comment: %v -> %v\n", changes.Comment.Old, changes.Comment.New)
+		fmt.Fprintf(w, "      comment: %v -> %v\n", changes.Comment.Old, changes.Comment.New)
+	}
+	if changes.PackKeys != nil {
+		fmt.Fprintf(w, "      pack_keys: %v -> %v\n", changes.PackKeys.Old, changes.PackKeys.New)
+	}
+	if changes.Checksum != nil {
+		fmt.Fprintf(w, "      checksum: %v -> %v\n", changes.Checksum.Old, changes.Checksum.New)
+	}
+	if changes.DelayKeyWrite != nil {
+		fmt.Fprintf(w, "      delay_key_write: %v -> %v\n", changes.DelayKeyWrite.Old, changes.DelayKeyWrite.New)
+	}
+	if changes.StatsPersistent != nil {
+		fmt.Fprintf(w, "      stats_persistent: %v -> %v\n", changes.StatsPersistent.Old, changes.StatsPersistent.New)
+	}
+	if changes.StatsAutoRecalc != nil {
+		fmt.Fprintf(w, "      stats_auto_recalc: %v -> %v\n", changes.StatsAutoRecalc.Old, changes.StatsAutoRecalc.New)
+	}
+	if changes.StatsSamplePages != nil {
+		fmt.Fprintf(w, "      stats_sample_pages: %v -> %v\n", changes.StatsSamplePages.Old, changes.StatsSamplePages.New)
+	}
+	if changes.AvgRowLength != nil {
+		fmt.Fprintf(w, "      avg_row_length: %v -> %v\n", changes.AvgRowLength.Old, changes.AvgRowLength.New)
+	}
+	if changes.AutoextendSize != nil {
+		fmt.Fprintf(w, "      autoextend_size: %v -> %v\n", changes.AutoextendSize.Old, changes.AutoextendSize.New)
+	}
+	if changes.Connection != nil {
+		fmt.Fprintf(w, "      connection: %v -> %v\n", changes.Connection.Old, changes.Connection.New)
+	}
+	if changes.RowFormat != nil {
+		fmt.Fprintf(w, "      row_format: %v -> %v\n", changes.RowFormat.Old, changes.RowFormat.New)
+	}
+	if changes.KeyBlockSize != nil {
+		fmt.Fprintf(w, "      key_block_size: %v -> %v\n", changes.KeyBlockSize.Old, changes.KeyBlockSize.New)
+	}
+	if changes.MaxRows != nil {
+		fmt.Fprintf(w, "      max_rows: %v -> %v\n", changes.MaxRows.Old, changes.MaxRows.New)
+	}
+	if changes.MinRows != nil {
+		fmt.Fprintf(w, "      min_rows: %v -> %v\n", changes.MinRows.Old, changes.MinRows.New)
+	}
+	if changes.Tablespace != nil {
+		fmt.Fprintf(w, "      tablespace: %v -> %v\n", changes.Tablespace.Old, changes.Tablespace.New)
+	}
+	if changes.DataDirectory != nil {
+		fmt.Fprintf(w, "      data_directory: %v -> %v\n", changes.DataDirectory.Old, changes.DataDirectory.New)
+	}
+	if changes.IndexDirectory != nil {
+		fmt.Fprintf(w, "      index_directory: %v -> %v\n", changes.IndexDirectory.Old, changes.IndexDirectory.New)
+	}
+	if changes.Encryption != nil {
+		fmt.Fprintf(w, "      encryption: %v -> %v\n", changes.Encryption.Old, changes.Encryption.New)
+	}
+	if changes.Compression != nil {
+		fmt.Fprintf(w, "      compression: %v -> %v\n", changes.Compression.Old, changes.Compression.New)
+	}
+	if changes.Union != nil {
+		fmt.Fprintf(w, "      union: %v -> %v\n", changes.Union.Old, changes.Union.New)
+	}
+	if changes.InsertMethod != nil {
+		fmt.Fprintf(w, "      insert_method: %v -> %v\n", changes.InsertMethod.Old, changes.InsertMethod.New)
 	}
 }
 
-func printPartitionChanges(changes *PartitionChanges) {
+func printPartitionChanges(w io.Writer, changes *PartitionChanges) {
 	if changes.Type != nil {
-		fmt.Printf("      type: %v -> %v\n", changes.Type.Old, changes.Type.New)
+		fmt.Fprintf(w, "      type: %v -> %v\n", changes.Type.Old, changes.Type.New)
 	}
 	if changes.Linear != nil {
-		fmt.Printf("      linear: %v -> %v\n", changes.Linear.Old, changes.Linear.New)
+		fmt.Fprintf(w, "      linear: %v -> %v\n", changes.Linear.Old, changes.Linear.New)
 	}
 	if changes.Expression != nil {
-		fmt.Printf("      expression: %v -> %v\n", changes.Expression.Old, changes.Expression.New)
+		fmt.Fprintf(w, "      expression: %v -> %v\n", changes.Expression.Old, changes.Expression.New)
 	}
 	if changes.Columns != nil {
-		fmt.Printf("      columns: %v -> %v\n", changes.Columns.Old, changes.Columns.New)
+		fmt.Fprintf(w, "      columns: %v -> %v\n", changes.Columns.Old, changes.Columns.New)
 	}
 	if changes.PartitionsCount != nil {
-		fmt.Printf("      partitions_count: %v -> %v\n", changes.PartitionsCount.Old, changes.PartitionsCount.New)
+		fmt.Fprintf(w, "      partitions_count: %v -> %v\n", changes.PartitionsCount.Old, changes.PartitionsCount.New)
 	}
 	if changes.PartitionDefinitions != nil {
-		fmt.Printf("      partition_definitions: %v -> %v\n", changes.PartitionDefinitions.Old, changes.PartitionDefinitions.New)
+		fmt.Fprintf(w, "      partition_definitions: %s -> %s\n",
+			formatPartitionDefinitions(changes.PartitionDefinitions.Old), formatPartitionDefinitions(changes.PartitionDefinitions.New))
+	}
+	if changes.SubPartitionsCount != nil {
+		fmt.Fprintf(w, "      subpartitions_count: %v -> %v\n", changes.SubPartitionsCount.Old, changes.SubPartitionsCount.New)
+	}
+}
+
+// formatPartitionDefinitions formats partition definition summaries as a parenthesized list
+func formatPartitionDefinitions(value any) string {
+	definitions, ok := value.([]string)
+	if !ok {
+		return "none"
 	}
+	return "(" + strings.Join(definitions, ", ") + ")"
 }
@@ -0,0 +1,157 @@
+package diff
+
+import (
+	"reflect"
+)
+
+// Reverse returns the diff that undoes td: added objects become removed, removed objects
+// become added and every field change swaps its old and new values. td itself is not modified.
+func (td *TableDiff) Reverse() *TableDiff {
+	reversed := &TableDiff{
+		OldTable:            td.NewTable,
+		NewTable:            td.OldTable,
+		TableNameChanged:    td.TableNameChanged,
+		TableOptionsChanged: td.TableOptionsChanged,
+		ColumnOrderTracked:  td.ColumnOrderTracked,
+
+		ColumnDiffs:     []ColumnDiff{},
+		IndexDiffs:      []IndexDiff{},
+		ForeignKeyDiffs: []ForeignKeyDiff{},
+
+		CheckConstraintDiffs: []CheckConstraintDiff{},
+
+		ColumnsAdded:        td.ColumnsRemoved,
+		ColumnsRemoved:      td.ColumnsAdded,
+		ColumnsModified:     td.ColumnsModified,
+		IndexesAdded:        td.IndexesRemoved,
+		IndexesRemoved:      td.IndexesAdded,
+		IndexesModified:     td.IndexesModified,
+		ForeignKeysAdded:    td.ForeignKeysRemoved,
+		ForeignKeysRemoved:  td.ForeignKeysAdded,
+		ForeignKeysModified: td.ForeignKeysModified,
+
+		CheckConstraintsAdded:    td.CheckConstraintsRemoved,
+		CheckConstraintsRemoved:  td.CheckConstraintsAdded,
+		CheckConstraintsModified: td.CheckConstraintsModified,
+	}
+
+	for _, colDiff := range td.ColumnDiffs {
+		name := colDiff.Name
+		if colDiff.Changes != nil && colDiff.Changes.Name != nil {
+			name = colDiff.Changes.Name.Old
+		}
+		reversed.ColumnDiffs = append(reversed.ColumnDiffs, ColumnDiff{
+			Name:       name,
+			ChangeType: reverseChangeType(colDiff.ChangeType),
+			OldColumn:  colDiff.NewColumn,
+			NewColumn:  colDiff.OldColumn,
+			Changes:    reverseChanges(colDiff.Changes),
+		})
+	}
+
+	sortColumnMoves(reversed.ColumnDiffs)
+
+	if pkDiff := td.PrimaryKeyDiff; pkDiff != nil {
+		reversed.PrimaryKeyDiff = &PrimaryKeyDiff{
+			ChangeType: reverseChangeType(pkDiff.ChangeType),
+			OldPK:      pkDiff.NewPK,
+			NewPK:      pkDiff.OldPK,
+			Changes:    reverseChanges(pkDiff.Changes),
+		}
+	}
+
+	for _, idxDiff := range td.IndexDiffs {
+		reversed.IndexDiffs = append(reversed.IndexDiffs, IndexDiff{
+			Name:       idxDiff.Name,
+			ChangeType: reverseChangeType(idxDiff.ChangeType),
+			OldIndex:   idxDiff.NewIndex,
+			NewIndex:   idxDiff.OldIndex,
+			Changes:    reverseChanges(idxDiff.Changes),
+		})
+	}
+	markRedundantIndexes(reversed.IndexDiffs, reversed.NewTable)
+
+	// The index MySQL created for a foreign key stays when the key is dropped, so restoring an
+	// old foreign key never creates one
+	for _, fkDiff := range td.ForeignKeyDiffs {
+		reversed.ForeignKeyDiffs = append(reversed.ForeignKeyDiffs, ForeignKeyDiff{
+			Name:       fkDiff.Name,
+			ChangeType: reverseChangeType(fkDiff.ChangeType),
+			OldFK:      fkDiff.NewFK,
+			NewFK:      fkDiff.OldFK,
+			Changes:    reverseChanges(fkDiff.Changes),
+		})
+	}
+
+	for _, checkDiff := range td.CheckConstraintDiffs {
+		reversed.CheckConstraintDiffs = append(reversed.CheckConstraintDiffs, CheckConstraintDiff{
+			Name:       checkDiff.Name,
+			ChangeType: reverseChangeType(checkDiff.ChangeType),
+			OldCheck:   checkDiff.NewCheck,
+			NewCheck:   checkDiff.OldCheck,
+			Changes:    reverseChanges(checkDiff.Changes),
+		})
+	}
+
+	if optionsDiff := td.TableOptionsDiff; optionsDiff != nil {
+		reversed.TableOptionsDiff = &TableOptionsDiff{
+			ChangeType: reverseChangeType(optionsDiff.ChangeType),
+			OldOptions: optionsDiff.NewOptions,
+			NewOptions: optionsDiff.OldOptions,
+			Changes:    reverseChanges(optionsDiff.Changes),
+		}
+		if changes := reversed.TableOptionsDiff.Changes; changes != nil && changes.Engine != nil {
+			reversed.TableOptionsDiff.Warnings = []string{
+				engineChangeWarning(optionsDiff.NewOptions.Engine, optionsDiff.OldOptions.Engine),
+			}
+		}
+	}
+
+	if partitionDiff := td.PartitionDiff; partitionDiff != nil {
+		reversed.PartitionDiff = &PartitionDiff{
+			ChangeType:   reverseChangeType(partitionDiff.ChangeType),
+			OldPartition: partitionDiff.NewPartition,
+			NewPartition: partitionDiff.OldPartition,
+			Changes:      reverseChanges(partitionDiff.Changes),
+		}
+	}
+
+	return reversed
+}
+
+// reverseChangeType swaps added and removed, leaving other change types as they are
+func reverseChangeType(changeType ChangeType) ChangeType {
+	switch changeType {
+	case ChangeTypeAdded:
+		return ChangeTypeRemoved
+	case ChangeTypeRemoved:
+		return ChangeTypeAdded
+	}
+	return changeType
+}
+
+// reverseChanges returns a copy of a *Changes struct in which every non-nil FieldChange
+// has its Old and New values swapped and every EnumChange is reversed
+func reverseChanges[T any](changes *T) *T {
+	if changes == nil {
+		return nil
+	}
+
+	reversed := *changes
+	value := reflect.ValueOf(&reversed).Elem()
+	for i := range value.NumField() {
+		field := value.Field(i)
+		if field.Kind() != reflect.Pointer || field.IsNil() {
+			continue
+		}
+		if enumChange, ok := field.Interface().(*EnumChange); ok {
+			field.Set(reflect.ValueOf(enumChange.Reverse()))
+			continue
+		}
+		swapped := reflect.New(field.Elem().Type())
+		swapped.Elem().FieldByName("Old").Set(field.Elem().FieldByName("New"))
+		swapped.Elem().FieldByName("New").Set(field.Elem().FieldByName("Old"))
+		field.Set(swapped)
+	}
+	return &reversed
+}
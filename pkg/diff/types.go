@@ -1,6 +1,8 @@
 package diff
 
 import (
+	"strings"
+
 	"github.com/n0madic/mysql-diff/pkg/parser"
 )
 
@@ -22,9 +24,12 @@ type FieldChange[T any] struct {
 
 // ColumnChanges represents specific field changes for columns
 type ColumnChanges struct {
+	Name          *FieldChange[string]                  `json:"name,omitempty"`
 	DataType      *FieldChange[string]                  `json:"data_type,omitempty"`
+	EnumValues    *EnumChange                           `json:"enum_values,omitempty"` // set instead of DataType when only ENUM/SET values change
 	Nullable      *FieldChange[any]                     `json:"nullable,omitempty"`
 	DefaultValue  *FieldChange[any]                     `json:"default_value,omitempty"`
+	OnUpdate      *FieldChange[any]                     `json:"on_update,omitempty"`
 	AutoIncrement *FieldChange[bool]                    `json:"auto_increment,omitempty"`
 	Unique        *FieldChange[bool]                    `json:"unique,omitempty"`
 	PrimaryKey    *FieldChange[bool]                    `json:"primary_key,omitempty"`
@@ -35,15 +40,44 @@ type ColumnChanges struct {
 	ColumnFormat  *FieldChange[any]                     `json:"column_format,omitempty"`
 	Storage       *FieldChange[any]                     `json:"storage,omitempty"`
 	Generated     *FieldChange[*parser.GeneratedColumn] `json:"generated,omitempty"`
+	Checks        *FieldChange[[]string]                `json:"checks,omitempty"`   // inline CHECK constraints as SQL
+	Position      *FieldChange[int]                     `json:"position,omitempty"` // 1-based ordinal, set with DetectColumnReorder
 }
 
 // HasChanges returns true if there are any changes in the column
 func (c *ColumnChanges) HasChanges() bool {
-	return c.DataType != nil || c.Nullable != nil || c.DefaultValue != nil ||
-		c.AutoIncrement != nil || c.Unique != nil || c.PrimaryKey != nil ||
+	return c.Name != nil || c.DataType != nil || c.EnumValues != nil || c.Nullable != nil || c.DefaultValue != nil ||
+		c.OnUpdate != nil || c.AutoIncrement != nil || c.Unique != nil || c.PrimaryKey != nil ||
 		c.Comment != nil || c.Collation != nil || c.CharacterSet != nil ||
 		c.Visible != nil || c.ColumnFormat != nil || c.Storage != nil ||
-		c.Generated != nil
+		c.Generated != nil || c.Checks != nil || c.Position != nil
+}
+
+// IsCommentOnly returns true if the comment is the only changed attribute of the column
+func (c *ColumnChanges) IsCommentOnly() bool {
+	withoutComment := *c
+	withoutComment.Comment = nil
+	return c.Comment != nil && !withoutComment.HasChanges()
+}
+
+// IsRenameOnly returns true if the name is the only changed attribute of the column
+func (c *ColumnChanges) IsRenameOnly() bool {
+	withoutName := *c
+	withoutName.Name = nil
+	return c.Name != nil && !withoutName.HasChanges()
+}
+
+// EnumChange describes the values added to and removed from an ENUM or SET column
+type EnumChange struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	// Reordered is true when the values kept by the change are in a different order
+	Reordered bool `json:"reordered,omitempty"`
+}
+
+// Reverse returns the change that undoes c
+func (c *EnumChange) Reverse() *EnumChange {
+	return &EnumChange{Added: c.Removed, Removed: c.Added, Reordered: c.Reordered}
 }
 
 // IndexChanges represents specific field changes for indexes
@@ -69,6 +103,13 @@ func (c *IndexChanges) HasChanges() bool {
 		c.Lock != nil || c.EngineAttribute != nil
 }
 
+// IsCommentOnly returns true if the comment is the only changed attribute of the index
+func (c *IndexChanges) IsCommentOnly() bool {
+	withoutComment := *c
+	withoutComment.Comment = nil
+	return c.Comment != nil && !withoutComment.HasChanges()
+}
+
 // PrimaryKeyChanges represents specific field changes for primary keys
 type PrimaryKeyChanges struct {
 	Columns *FieldChange[[]string] `json:"columns,omitempty"`
@@ -98,19 +139,63 @@ func (c *ForeignKeyChanges) HasChanges() bool {
 		c.ReferenceColumns != nil || c.OnDelete != nil || c.OnUpdate != nil
 }
 
+// CheckConstraintChanges represents specific field changes for check constraints
+type CheckConstraintChanges struct {
+	Expression *FieldChange[string] `json:"expression,omitempty"`
+	Enforced   *FieldChange[bool]   `json:"enforced,omitempty"`
+}
+
+// HasChanges returns true if there are any changes in the check constraint
+func (c *CheckConstraintChanges) HasChanges() bool {
+	return c.Expression != nil || c.Enforced != nil
+}
+
 // TableOptionsChanges represents specific field changes for table options
 type TableOptionsChanges struct {
-	Engine        *FieldChange[any] `json:"engine,omitempty"`
-	AutoIncrement *FieldChange[any] `json:"auto_increment,omitempty"`
-	CharacterSet  *FieldChange[any] `json:"character_set,omitempty"`
-	Collate       *FieldChange[any] `json:"collate,omitempty"`
-	Comment       *FieldChange[any] `json:"comment,omitempty"`
+	Engine           *FieldChange[any] `json:"engine,omitempty"`
+	AutoIncrement    *FieldChange[any] `json:"auto_increment,omitempty"`
+	CharacterSet     *FieldChange[any] `json:"character_set,omitempty"`
+	Collate          *FieldChange[any] `json:"collate,omitempty"`
+	Comment          *FieldChange[any] `json:"comment,omitempty"`
+	PackKeys         *FieldChange[any] `json:"pack_keys,omitempty"`
+	Checksum         *FieldChange[any] `json:"checksum,omitempty"`
+	DelayKeyWrite    *FieldChange[any] `json:"delay_key_write,omitempty"`
+	StatsPersistent  *FieldChange[any] `json:"stats_persistent,omitempty"`
+	StatsAutoRecalc  *FieldChange[any] `json:"stats_auto_recalc,omitempty"`
+	StatsSamplePages *FieldChange[any] `json:"stats_sample_pages,omitempty"`
+	AvgRowLength     *FieldChange[any] `json:"avg_row_length,omitempty"`
+	AutoextendSize   *FieldChange[any] `json:"autoextend_size,omitempty"`
+	Connection       *FieldChange[any] `json:"connection,omitempty"`
+	RowFormat        *FieldChange[any] `json:"row_format,omitempty"`
+	KeyBlockSize     *FieldChange[any] `json:"key_block_size,omitempty"`
+	MaxRows          *FieldChange[any] `json:"max_rows,omitempty"`
+	MinRows          *FieldChange[any] `json:"min_rows,omitempty"`
+	Tablespace       *FieldChange[any] `json:"tablespace,omitempty"`
+	DataDirectory    *FieldChange[any] `json:"data_directory,omitempty"`
+	IndexDirectory   *FieldChange[any] `json:"index_directory,omitempty"`
+	Encryption       *FieldChange[any] `json:"encryption,omitempty"`
+	Compression      *FieldChange[any] `json:"compression,omitempty"`
+	Union            *FieldChange[any] `json:"union,omitempty"`
+	InsertMethod     *FieldChange[any] `json:"insert_method,omitempty"`
 }
 
 // HasChanges returns true if there are any changes in the table options
 func (c *TableOptionsChanges) HasChanges() bool {
 	return c.Engine != nil || c.AutoIncrement != nil || c.CharacterSet != nil ||
-		c.Collate != nil || c.Comment != nil
+		c.Collate != nil || c.Comment != nil || c.PackKeys != nil ||
+		c.Checksum != nil || c.DelayKeyWrite != nil || c.StatsPersistent != nil ||
+		c.StatsAutoRecalc != nil || c.StatsSamplePages != nil || c.AvgRowLength != nil ||
+		c.AutoextendSize != nil || c.Connection != nil || c.RowFormat != nil ||
+		c.KeyBlockSize != nil || c.MaxRows != nil || c.MinRows != nil ||
+		c.Tablespace != nil || c.DataDirectory != nil || c.IndexDirectory != nil ||
+		c.Encryption != nil || c.Compression != nil || c.Union != nil || c.InsertMethod != nil
+}
+
+// IsCommentOnly returns true if the comment is the only changed table option
+func (c *TableOptionsChanges) IsCommentOnly() bool {
+	withoutComment := *c
+	withoutComment.Comment = nil
+	return c.Comment != nil && !withoutComment.HasChanges()
 }
 
 // PartitionChanges represents specific field changes for partitions
@@ -121,12 +206,14 @@ type PartitionChanges struct {
 	Columns              *FieldChange[[]string] `json:"columns,omitempty"`
 	PartitionsCount      *FieldChange[any]      `json:"partitions_count,omitempty"`
 	PartitionDefinitions *FieldChange[any]      `json:"partition_definitions,omitempty"`
+	SubPartitionsCount   *FieldChange[any]      `json:"subpartitions_count,omitempty"`
 }
 
 // HasChanges returns true if there are any changes in the partitions
 func (c *PartitionChanges) HasChanges() bool {
 	return c.Type != nil || c.Linear != nil || c.Expression != nil ||
-		c.Columns != nil || c.PartitionsCount != nil || c.PartitionDefinitions != nil
+		c.Columns != nil || c.PartitionsCount != nil || c.PartitionDefinitions != nil ||
+		c.SubPartitionsCount != nil
 }
 
 // ColumnDiff represents differences in a column definition
@@ -136,6 +223,8 @@ type ColumnDiff struct {
 	OldColumn  *parser.ColumnDefinition `json:"old_column,omitempty"`
 	NewColumn  *parser.ColumnDefinition `json:"new_column,omitempty"`
 	Changes    *ColumnChanges           `json:"changes,omitempty"`
+	// Conversion classifies a data type change; empty when the data type is unchanged
+	Conversion ConversionRisk `json:"conversion,omitempty"`
 }
 
 // IndexDiff represents differences in an index definition
@@ -145,6 +234,15 @@ type IndexDiff struct {
 	OldIndex   *parser.IndexDefinition `json:"old_index,omitempty"`
 	NewIndex   *parser.IndexDefinition `json:"new_index,omitempty"`
 	Changes    *IndexChanges           `json:"changes,omitempty"`
+
+	// RedundantWith names the index of the new table that already covers an added index
+	RedundantWith string `json:"redundant_with,omitempty"`
+}
+
+// RedundantIndex is an index that another index of the same table already covers
+type RedundantIndex struct {
+	Index     *parser.IndexDefinition `json:"index"`
+	CoveredBy string                  `json:"covered_by"` // name of the covering index, PRIMARY for the primary key
 }
 
 // ForeignKeyDiff represents differences in a foreign key definition
@@ -154,6 +252,19 @@ type ForeignKeyDiff struct {
 	OldFK      *parser.ForeignKeyDefinition `json:"old_fk,omitempty"`
 	NewFK      *parser.ForeignKeyDefinition `json:"new_fk,omitempty"`
 	Changes    *ForeignKeyChanges           `json:"changes,omitempty"`
+
+	// ImplicitIndex is set for an added foreign key whose columns no index of the new table covers;
+	// MySQL creates an index for them when the foreign key is added
+	ImplicitIndex bool `json:"implicit_index,omitempty"`
+}
+
+// CheckConstraintDiff represents differences in a check constraint
+type CheckConstraintDiff struct {
+	Name       *string                 `json:"name"`
+	ChangeType ChangeType              `json:"change_type"`
+	OldCheck   *parser.CheckConstraint `json:"old_check,omitempty"`
+	NewCheck   *parser.CheckConstraint `json:"new_check,omitempty"`
+	Changes    *CheckConstraintChanges `json:"changes,omitempty"`
 }
 
 // PrimaryKeyDiff represents differences in primary key definition
@@ -170,6 +281,7 @@ type TableOptionsDiff struct {
 	OldOptions *parser.TableOptions `json:"old_options,omitempty"`
 	NewOptions *parser.TableOptions `json:"new_options,omitempty"`
 	Changes    *TableOptionsChanges `json:"changes,omitempty"`
+	Warnings   []string             `json:"warnings,omitempty"` // advisory notes about the impact of the changes
 }
 
 // PartitionDiff represents differences in partition options
@@ -190,12 +302,17 @@ type TableDiff struct {
 	TableOptionsChanged bool `json:"table_options_changed"`
 
 	// Component differences
-	ColumnDiffs      []ColumnDiff      `json:"column_diffs"`
-	PrimaryKeyDiff   *PrimaryKeyDiff   `json:"primary_key_diff,omitempty"`
-	IndexDiffs       []IndexDiff       `json:"index_diffs"`
-	ForeignKeyDiffs  []ForeignKeyDiff  `json:"foreign_key_diffs"`
-	TableOptionsDiff *TableOptionsDiff `json:"table_options_diff,omitempty"`
-	PartitionDiff    *PartitionDiff    `json:"partition_diff,omitempty"`
+	ColumnDiffs          []ColumnDiff          `json:"column_diffs"`
+	PrimaryKeyDiff       *PrimaryKeyDiff       `json:"primary_key_diff,omitempty"`
+	IndexDiffs           []IndexDiff           `json:"index_diffs"`
+	ForeignKeyDiffs      []ForeignKeyDiff      `json:"foreign_key_diffs"`
+	CheckConstraintDiffs []CheckConstraintDiff `json:"check_constraint_diffs"`
+	TableOptionsDiff     *TableOptionsDiff     `json:"table_options_diff,omitempty"`
+	PartitionDiff        *PartitionDiff        `json:"partition_diff,omitempty"`
+
+	// ColumnOrderTracked is set when the analyzer compared the column order of the tables, so
+	// that the generated statements also place added columns with FIRST or AFTER
+	ColumnOrderTracked bool `json:"-"`
 
 	// Summary counters
 	ColumnsAdded        int `json:"columns_added"`
@@ -207,6 +324,10 @@ type TableDiff struct {
 	ForeignKeysAdded    int `json:"foreign_keys_added"`
 	ForeignKeysRemoved  int `json:"foreign_keys_removed"`
 	ForeignKeysModified int `json:"foreign_keys_modified"`
+
+	CheckConstraintsAdded    int `json:"check_constraints_added"`
+	CheckConstraintsRemoved  int `json:"check_constraints_removed"`
+	CheckConstraintsModified int `json:"check_constraints_modified"`
 }
 
 // HasChanges returns true if there are any changes between the tables
@@ -217,6 +338,7 @@ func (td *TableDiff) HasChanges() bool {
 		td.PrimaryKeyDiff != nil ||
 		len(td.IndexDiffs) > 0 ||
 		len(td.ForeignKeyDiffs) > 0 ||
+		len(td.CheckConstraintDiffs) > 0 ||
 		td.TableOptionsDiff != nil ||
 		td.PartitionDiff != nil
 }
@@ -227,6 +349,7 @@ type TableSummary struct {
 	Columns             ChangesSummary `json:"columns"`
 	Indexes             ChangesSummary `json:"indexes"`
 	ForeignKeys         ChangesSummary `json:"foreign_keys"`
+	CheckConstraints    ChangesSummary `json:"check_constraints"`
 	PrimaryKeyChanged   bool           `json:"primary_key_changed"`
 	TableOptionsChanged bool           `json:"table_options_changed"`
 	PartitioningChanged bool           `json:"partitioning_changed"`
@@ -258,8 +381,143 @@ func (td *TableDiff) GetSummary() TableSummary {
 			Removed:  td.ForeignKeysRemoved,
 			Modified: td.ForeignKeysModified,
 		},
+		CheckConstraints: ChangesSummary{
+			Added:    td.CheckConstraintsAdded,
+			Removed:  td.CheckConstraintsRemoved,
+			Modified: td.CheckConstraintsModified,
+		},
 		PrimaryKeyChanged:   td.PrimaryKeyDiff != nil,
 		TableOptionsChanged: td.TableOptionsDiff != nil,
 		PartitioningChanged: td.PartitionDiff != nil,
 	}
 }
+
+// AddedColumns returns the definitions of the columns added to the table
+func (td *TableDiff) AddedColumns() []*parser.ColumnDefinition {
+	var columns []*parser.ColumnDefinition
+	for _, colDiff := range td.ColumnDiffs {
+		if colDiff.ChangeType == ChangeTypeAdded {
+			columns = append(columns, colDiff.NewColumn)
+		}
+	}
+	return columns
+}
+
+// RemovedColumns returns the definitions of the columns removed from the table
+func (td *TableDiff) RemovedColumns() []*parser.ColumnDefinition {
+	var columns []*parser.ColumnDefinition
+	for _, colDiff := range td.ColumnDiffs {
+		if colDiff.ChangeType == ChangeTypeRemoved {
+			columns = append(columns, colDiff.OldColumn)
+		}
+	}
+	return columns
+}
+
+// ModifiedColumns returns the diffs of the columns present in both tables whose definition changed
+func (td *TableDiff) ModifiedColumns() []ColumnDiff {
+	var diffs []ColumnDiff
+	for _, colDiff := range td.ColumnDiffs {
+		if colDiff.ChangeType == ChangeTypeModified {
+			diffs = append(diffs, colDiff)
+		}
+	}
+	return diffs
+}
+
+// Additions returns a diff holding only the added columns, indexes, foreign keys and check
+// constraints of td, and the primary key when the old table had none. Removals, modifications,
+// renames, table options and partitioning are left out, as are additions that replace a removed
+// object of the same name (such as an index whose columns changed). td itself is not modified.
+func (td *TableDiff) Additions() *TableDiff {
+	additions := &TableDiff{
+		OldTable:           td.OldTable,
+		NewTable:           td.NewTable,
+		ColumnOrderTracked: td.ColumnOrderTracked,
+
+		ColumnDiffs:     []ColumnDiff{},
+		IndexDiffs:      []IndexDiff{},
+		ForeignKeyDiffs: []ForeignKeyDiff{},
+
+		CheckConstraintDiffs: []CheckConstraintDiff{},
+	}
+
+	for _, colDiff := range td.ColumnDiffs {
+		if colDiff.ChangeType == ChangeTypeAdded {
+			additions.ColumnDiffs = append(additions.ColumnDiffs, colDiff)
+		}
+	}
+	additions.ColumnsAdded = len(additions.ColumnDiffs)
+
+	if td.PrimaryKeyDiff != nil && td.PrimaryKeyDiff.ChangeType == ChangeTypeAdded {
+		additions.PrimaryKeyDiff = td.PrimaryKeyDiff
+	}
+
+	removed := make(map[string]bool)
+	for _, idxDiff := range td.IndexDiffs {
+		if idxDiff.ChangeType == ChangeTypeRemoved && idxDiff.Name != nil {
+			removed[strings.ToLower(*idxDiff.Name)] = true
+		}
+	}
+	for _, idxDiff := range td.IndexDiffs {
+		if idxDiff.ChangeType == ChangeTypeAdded && (idxDiff.Name == nil || !removed[strings.ToLower(*idxDiff.Name)]) {
+			additions.IndexDiffs = append(additions.IndexDiffs, idxDiff)
+		}
+	}
+	additions.IndexesAdded = len(additions.IndexDiffs)
+
+	removed = make(map[string]bool)
+	for _, fkDiff := range td.ForeignKeyDiffs {
+		if fkDiff.ChangeType == ChangeTypeRemoved && fkDiff.Name != nil {
+			removed[strings.ToLower(*fkDiff.Name)] = true
+		}
+	}
+	for _, fkDiff := range td.ForeignKeyDiffs {
+		if fkDiff.ChangeType == ChangeTypeAdded && (fkDiff.Name == nil || !removed[strings.ToLower(*fkDiff.Name)]) {
+			additions.ForeignKeyDiffs = append(additions.ForeignKeyDiffs, fkDiff)
+		}
+	}
+	additions.ForeignKeysAdded = len(additions.ForeignKeyDiffs)
+
+	removed = make(map[string]bool)
+	for _, checkDiff := range td.CheckConstraintDiffs {
+		if checkDiff.ChangeType == ChangeTypeRemoved && checkDiff.Name != nil {
+			removed[strings.ToLower(*checkDiff.Name)] = true
+		}
+	}
+	for _, checkDiff := range td.CheckConstraintDiffs {
+		if checkDiff.ChangeType == ChangeTypeAdded && (checkDiff.Name == nil || !removed[strings.ToLower(*checkDiff.Name)]) {
+			additions.CheckConstraintDiffs = append(additions.CheckConstraintDiffs, checkDiff)
+		}
+	}
+	additions.CheckConstraintsAdded = len(additions.CheckConstraintDiffs)
+
+	return additions
+}
+
+// SchemaDiff represents the differences between two complete schemas
+type SchemaDiff struct {
+	AddedTables    []*parser.CreateTableStatement `json:"added_tables"`
+	RemovedTables  []*parser.CreateTableStatement `json:"removed_tables"`
+	ModifiedTables []*TableDiff                   `json:"modified_tables"`
+
+	// TablesCompared counts the tables present in both schemas, whether changed or not
+	TablesCompared int `json:"tables_compared"`
+
+	// Generated SQL, set by alter.StatementGenerator.AttachStatements and included in the JSON
+	// output: the names of the tables to create and drop, and the statements of each table by name.
+	// The -- Note and -- Warning comments of a table are kept apart from its statements.
+	CreatedTables   []string            `json:"created_tables,omitempty"`
+	DroppedTables   []string            `json:"dropped_tables,omitempty"`
+	TableStatements map[string][]string `json:"-"`
+	TableNotes      map[string][]string `json:"-"`
+
+	// OnlyModifiedColumns leaves added and removed columns out of the column changes of the
+	// detailed report, to focus the review of wide tables on the columns changed in place
+	OnlyModifiedColumns bool `json:"-"`
+}
+
+// HasChanges returns true if any table was added, removed or modified
+func (sd *SchemaDiff) HasChanges() bool {
+	return len(sd.AddedTables) > 0 || len(sd.RemovedTables) > 0 || len(sd.ModifiedTables) > 0
+}
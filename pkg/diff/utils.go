@@ -1,6 +1,9 @@
 package diff
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/n0madic/mysql-diff/pkg/parser"
 )
 
@@ -14,6 +17,14 @@ func ptrToValue[T any](ptr *T) any {
 	return *ptr
 }
 
+// sliceToValue converts a slice to any, returning nil for an empty slice
+func sliceToValue[T any](values []T) any {
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
 // ptrEqual compares two pointers for equality
 func ptrEqual[T comparable](a, b *T) bool {
 	if a == nil && b == nil {
@@ -25,6 +36,64 @@ func ptrEqual[T comparable](a, b *T) bool {
 	return *a == *b
 }
 
+// ptrEqualFold compares two string pointers for equality ignoring case
+func ptrEqualFold(a, b *string) bool {
+	if a == nil && b == nil {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return strings.EqualFold(*a, *b)
+}
+
+// normalizeReferentialAction maps a foreign key action to its canonical form.
+// An omitted action and NO ACTION both behave as RESTRICT in MySQL.
+func normalizeReferentialAction(action *string) string {
+	if action == nil {
+		return "RESTRICT"
+	}
+	normalized := strings.ToUpper(strings.Join(strings.Fields(*action), " "))
+	if normalized == "" || normalized == "NO ACTION" {
+		return "RESTRICT"
+	}
+	return normalized
+}
+
+// IsDefaultReferentialAction reports whether a foreign key action is equivalent to MySQL's default (RESTRICT)
+func IsDefaultReferentialAction(action *string) bool {
+	return normalizeReferentialAction(action) == "RESTRICT"
+}
+
+// currentTimestampSynonyms lists the functions MySQL treats as CURRENT_TIMESTAMP in column defaults
+var currentTimestampSynonyms = []string{"CURRENT_TIMESTAMP", "NOW", "LOCALTIME", "LOCALTIMESTAMP"}
+
+// normalizeTimestampDefault maps a CURRENT_TIMESTAMP family default such as now() or
+// LOCALTIMESTAMP(3) to CURRENT_TIMESTAMP with its fractional seconds precision, if any.
+// The second result is false if the value is not a CURRENT_TIMESTAMP synonym.
+func normalizeTimestampDefault(value string) (string, bool) {
+	upper := strings.ToUpper(strings.Join(strings.Fields(value), ""))
+	for _, synonym := range currentTimestampSynonyms {
+		if !strings.HasPrefix(upper, synonym) {
+			continue
+		}
+		args := strings.TrimPrefix(upper, synonym)
+		switch {
+		case args == "" || args == "()" || args == "(0)":
+			return "CURRENT_TIMESTAMP", true
+		case strings.HasPrefix(args, "(") && strings.HasSuffix(args, ")"):
+			return "CURRENT_TIMESTAMP" + args, true
+		}
+	}
+	return "", false
+}
+
+// IsCurrentTimestampDefault reports whether a default value is CURRENT_TIMESTAMP or one of its synonyms
+func IsCurrentTimestampDefault(value string) bool {
+	_, ok := normalizeTimestampDefault(value)
+	return ok
+}
+
 // generatedColumnEqual compares two GeneratedColumn pointers
 func generatedColumnEqual(a, b *parser.GeneratedColumn) bool {
 	if a == nil && b == nil {
@@ -35,3 +104,18 @@ func generatedColumnEqual(a, b *parser.GeneratedColumn) bool {
 	}
 	return a.Expression == b.Expression && a.Type == b.Type
 }
+
+// formatDataType converts DataType to its SQL representation
+func formatDataType(dt parser.DataType) string {
+	result := dt.Name
+	if len(dt.Parameters) > 0 {
+		result += fmt.Sprintf("(%s)", strings.Join(dt.Parameters, ","))
+	}
+	if dt.Unsigned {
+		result += " UNSIGNED"
+	}
+	if dt.Zerofill {
+		result += " ZEROFILL"
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+package diff
+
+import (
+	"context"
+	"time"
+
+	"github.com/n0madic/mysql-diff/pkg/parser"
+)
+
+// SchemaLoader loads the current tables of a schema, e.g. from a live database
+type SchemaLoader interface {
+	LoadSchema(ctx context.Context) ([]*parser.CreateTableStatement, error)
+}
+
+// SchemaLoaderFunc adapts a function to the SchemaLoader interface
+type SchemaLoaderFunc func(ctx context.Context) ([]*parser.CreateTableStatement, error)
+
+// LoadSchema calls f(ctx)
+func (f SchemaLoaderFunc) LoadSchema(ctx context.Context) ([]*parser.CreateTableStatement, error) {
+	return f(ctx)
+}
+
+// DriftWatcher periodically loads a schema and compares it with a target schema
+type DriftWatcher struct {
+	// Loader loads the watched schema on every poll
+	Loader SchemaLoader
+	// Target is the schema the watched one is expected to match
+	Target []*parser.CreateTableStatement
+	// Interval is the time between polls
+	Interval time.Duration
+	// Analyzer compares the schemas; nil uses NewTableDiffAnalyzer()
+	Analyzer *TableDiffAnalyzer
+
+	// OnDrift is called with the diff from the watched to the target schema whenever
+	// the watched schema drifts or its drift changes
+	OnDrift func(sd *SchemaDiff)
+	// OnSync is called when the watched schema matches the target again after a drift
+	OnSync func()
+	// OnError is called when loading the schema fails; polling continues with the next interval
+	OnError func(err error)
+
+	lastDrift string
+	drifted   bool
+}
+
+// Run polls until ctx is done, checking once immediately. It returns ctx.Err().
+func (w *DriftWatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check loads the watched schema once and returns its diff to the target schema
+func (w *DriftWatcher) Check(ctx context.Context) (*SchemaDiff, error) {
+	current, err := w.Loader.LoadSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	analyzer := w.Analyzer
+	if analyzer == nil {
+		analyzer = NewTableDiffAnalyzer()
+	}
+	return analyzer.CompareSchemas(current, w.Target), nil
+}
+
+// poll runs one check and reports drift changes and errors
+func (w *DriftWatcher) poll(ctx context.Context) {
+	sd, err := w.Check(ctx)
+	if err != nil {
+		if w.OnError != nil && ctx.Err() == nil {
+			w.OnError(err)
+		}
+		return
+	}
+
+	if !sd.HasChanges() {
+		if w.drifted && w.OnSync != nil {
+			w.OnSync()
+		}
+		w.drifted = false
+		w.lastDrift = ""
+		return
+	}
+
+	// The patch is ordered deterministically, so equal drifts serialize identically
+	data, err := MarshalPatch(sd)
+	if err != nil {
+		return
+	}
+	if w.drifted && string(data) == w.lastDrift {
+		return
+	}
+	w.drifted = true
+	w.lastDrift = string(data)
+	if w.OnDrift != nil {
+		w.OnDrift(sd)
+	}
+}
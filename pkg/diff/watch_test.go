@@ -0,0 +1,93 @@
+package diff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/n0madic/mysql-diff/pkg/parser"
+)
+
+// TestDriftWatcher tests that drift is reported once per distinct drift, that the return to the
+// target schema is reported, and that loader errors do not stop polling
+func TestDriftWatcher(t *testing.T) {
+	target := mustParseDump(t, "CREATE TABLE users (id INT, name VARCHAR(50));")
+	driftA := mustParseDump(t, "CREATE TABLE users (id INT);")
+	driftB := mustParseDump(t, "CREATE TABLE users (id INT, name VARCHAR(50), age INT);")
+	errTransient := errors.New("connection refused")
+
+	type snapshot struct {
+		tables []*parser.CreateTableStatement
+		err    error
+	}
+	snapshots := []snapshot{
+		{tables: target},
+		{tables: driftA},
+		{tables: driftA},
+		{err: errTransient},
+		{tables: driftA},
+		{tables: driftB},
+		{tables: target},
+		{tables: target},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	polls := 0
+	var events []string
+	var drifts []*SchemaDiff
+	watcher := &DriftWatcher{
+		Loader: SchemaLoaderFunc(func(ctx context.Context) ([]*parser.CreateTableStatement, error) {
+			current := snapshots[polls]
+			polls++
+			if polls == len(snapshots) {
+				cancel()
+			}
+			return current.tables, current.err
+		}),
+		Target:   target,
+		Interval: time.Millisecond,
+		OnDrift: func(sd *SchemaDiff) {
+			events = append(events, "drift")
+			drifts = append(drifts, sd)
+		},
+		OnSync: func() {
+			events = append(events, "sync")
+		},
+		OnError: func(err error) {
+			if !errors.Is(err, errTransient) {
+				t.Errorf("Expected transient error, got %v", err)
+			}
+			events = append(events, "error")
+		},
+	}
+
+	if err := watcher.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	if polls != len(snapshots) {
+		t.Errorf("Expected %d polls, got %d", len(snapshots), polls)
+	}
+
+	expected := []string{"drift", "error", "drift", "sync"}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected events %v, got %v", expected, events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Errorf("Expected events %v, got %v", expected, events)
+			break
+		}
+	}
+
+	// Diffs go from the watched schema to the target
+	if len(drifts[0].ModifiedTables) != 1 || drifts[0].ModifiedTables[0].ColumnsAdded != 1 {
+		t.Errorf("Expected first drift to add the missing column, got %+v", drifts[0].ModifiedTables)
+	}
+	if len(drifts[1].ModifiedTables) != 1 || drifts[1].ModifiedTables[0].ColumnsRemoved != 1 {
+		t.Errorf("Expected second drift to remove the extra column, got %+v", drifts[1].ModifiedTables)
+	}
+}
@@ -0,0 +1,82 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"time"
+)
+
+// Profiler records the duration of named stages and the peak heap usage seen at the end of each
+// stage. A disabled profiler records and prints nothing.
+type Profiler struct {
+	enabled  bool
+	w        io.Writer
+	stages   []profileStage
+	peakHeap uint64
+}
+
+type profileStage struct {
+	name     string
+	duration time.Duration
+}
+
+// NewProfiler creates a profiler that writes its report to w when enabled
+func NewProfiler(w io.Writer, enabled bool) *Profiler {
+	return &Profiler{enabled: enabled, w: w}
+}
+
+// Stage starts timing a stage and returns the function that ends it
+func (p *Profiler) Stage(name string) func() {
+	if !p.enabled {
+		return func() {}
+	}
+
+	start := time.Now()
+	return func() {
+		p.stages = append(p.stages, profileStage{name: name, duration: time.Since(start)})
+		p.sampleMemory()
+	}
+}
+
+// Report writes the duration of each finished stage, the total, and memory statistics
+func (p *Profiler) Report() {
+	if !p.enabled {
+		return
+	}
+
+	stats := p.sampleMemory()
+
+	var total time.Duration
+	fmt.Fprintf(p.w, "-- Profile:\n")
+	for _, stage := range p.stages {
+		fmt.Fprintf(p.w, "--   %-24s %v\n", stage.name, stage.duration)
+		total += stage.duration
+	}
+	fmt.Fprintf(p.w, "--   %-24s %v\n", "total", total)
+	fmt.Fprintf(p.w, "--   %-24s %s\n", "peak heap", formatBytes(p.peakHeap))
+	fmt.Fprintf(p.w, "--   %-24s %s\n", "memory from OS", formatBytes(stats.Sys))
+	fmt.Fprintf(p.w, "--   %-24s %d\n", "allocations", stats.Mallocs)
+}
+
+// sampleMemory updates the peak heap usage and returns the current memory statistics
+func (p *Profiler) sampleMemory() runtime.MemStats {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	p.peakHeap = max(p.peakHeap, stats.HeapAlloc)
+	return stats
+}
+
+// formatBytes formats a byte count with a binary unit, e.g. 1.5 MiB
+func formatBytes(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
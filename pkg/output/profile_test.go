@@ -0,0 +1,48 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestProfilerEnabled(t *testing.T) {
+	var buf bytes.Buffer
+	profiler := NewProfiler(&buf, true)
+
+	profiler.Stage("parse old.sql")()
+	profiler.Stage("comparing")()
+	profiler.Report()
+
+	report := buf.String()
+	for _, expected := range []string{"-- Profile:", "parse old.sql", "comparing", "total", "peak heap", "allocations"} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("Expected profile report to contain %q, got:\n%s", expected, report)
+		}
+	}
+}
+
+func TestProfilerDisabled(t *testing.T) {
+	var buf bytes.Buffer
+	profiler := NewProfiler(&buf, false)
+
+	profiler.Stage("parse old.sql")()
+	profiler.Report()
+
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output from a disabled profiler, got:\n%s", buf.String())
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[uint64]string{
+		512:             "512 B",
+		1536:            "1.5 KiB",
+		5 * 1024 * 1024: "5.0 MiB",
+	}
+	for input, expected := range tests {
+		if got := formatBytes(input); got != expected {
+			t.Errorf("formatBytes(%d) = %q, want %q", input, got, expected)
+		}
+	}
+}
@@ -25,6 +25,7 @@ type ColumnDefinition struct {
 	DataType      DataType
 	Nullable      *bool // nil = not specified, true = NULL, false = NOT NULL
 	DefaultValue  *string
+	OnUpdate      *string // ON UPDATE expression, e.g. CURRENT_TIMESTAMP(3)
 	AutoIncrement bool
 	Unique        bool
 	PrimaryKey    bool
@@ -36,6 +37,14 @@ type ColumnDefinition struct {
 	ColumnFormat  *string
 	Storage       *string
 	Reference     *ForeignKeyReference
+	Checks        []CheckConstraint // inline [CONSTRAINT name] CHECK (expr) constraints
+	Position      *ColumnPosition   // FIRST / AFTER placement, nil when not specified
+}
+
+// ColumnPosition represents the FIRST or AFTER col placement of a column
+type ColumnPosition struct {
+	First bool
+	After string // column this column follows; empty when First
 }
 
 // IndexColumn represents a column reference in an index
@@ -88,6 +97,9 @@ type CheckConstraint struct {
 	Name       *string
 	Expression string
 	Enforced   *bool
+
+	// NormalizedExpression is Expression re-serialized by NormalizeExpression, used for comparison
+	NormalizedExpression string
 }
 
 // TableOptions represents table-level options
@@ -101,6 +113,8 @@ type TableOptions struct {
 	KeyBlockSize     *int
 	MaxRows          *int
 	MinRows          *int
+	AvgRowLength     *int
+	AutoextendSize   *string // size with optional K/M/G suffix, e.g. 4M
 	Tablespace       *string
 	DataDirectory    *string
 	IndexDirectory   *string
@@ -114,6 +128,7 @@ type TableOptions struct {
 	DelayKeyWrite    *int
 	Union            []string
 	InsertMethod     *string
+	Connection       *string // quoted connection string of FEDERATED tables
 }
 
 // PartitionDefinition represents a single partition
@@ -138,6 +153,12 @@ type PartitionOptions struct {
 	Linear         bool
 	Partitions     []PartitionDefinition
 	PartitionCount *int // For HASH/KEY without explicit partition definitions
+
+	SubPartitionType       string // HASH, KEY
+	SubPartitionLinear     bool
+	SubPartitionExpression *string  // For SUBPARTITION BY HASH
+	SubPartitionColumns    []string // For SUBPARTITION BY KEY
+	SubPartitionCount      *int     // SUBPARTITIONS n
 }
 
 // CreateTableStatement represents a complete CREATE TABLE statement
@@ -152,4 +173,5 @@ type CreateTableStatement struct {
 	CheckConstraints []CheckConstraint
 	TableOptions     *TableOptions
 	PartitionOptions *PartitionOptions
+	RawSQL           string // original text of the statement when parsed from a dump
 }
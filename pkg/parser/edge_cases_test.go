@@ -1,6 +1,9 @@
 package parser
 
 import (
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -234,6 +237,77 @@ func TestParseComplexDefaults(t *testing.T) {
 	}
 }
 
+// TestParseSignedNumericDefaults tests that signed and scientific notation defaults are kept exactly as written
+func TestParseSignedNumericDefaults(t *testing.T) {
+	sql := `CREATE TABLE signed_defaults (
+		negative_int INT DEFAULT -1,
+		negative_decimal DECIMAL(10,2) DEFAULT -99.99,
+		scientific DOUBLE DEFAULT 1.5e3,
+		negative_exponent DOUBLE DEFAULT 2E-4,
+		positive_exponent DOUBLE DEFAULT -1.5e+3,
+		spaced_sign INT DEFAULT - 5,
+		plus_sign INT DEFAULT +7 NOT NULL
+	);`
+
+	tables, err := ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"negative_int":      "-1",
+		"negative_decimal":  "-99.99",
+		"scientific":        "1.5e3",
+		"negative_exponent": "2E-4",
+		"positive_exponent": "-1.5e+3",
+		"spaced_sign":       "-5",
+		"plus_sign":         "+7",
+	}
+
+	columns := tables[0].Columns
+	if len(columns) != len(expected) {
+		t.Fatalf("Expected %d columns, got %d", len(expected), len(columns))
+	}
+	for _, col := range columns {
+		if col.DefaultValue == nil {
+			t.Errorf("Column '%s' should have default value", col.Name)
+		} else if *col.DefaultValue != expected[col.Name] {
+			t.Errorf("Column '%s' expected default '%s', got '%s'", col.Name, expected[col.Name], *col.DefaultValue)
+		}
+	}
+	if nullable := columns[6].Nullable; nullable == nil || *nullable {
+		t.Error("Expected plus_sign to stay NOT NULL")
+	}
+}
+
+// TestParseOnUpdate tests that ON UPDATE expressions are kept, including a fractional seconds precision
+func TestParseOnUpdate(t *testing.T) {
+	sql := `CREATE TABLE audit (
+		id INT,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+		changed_at DATETIME(3) ON UPDATE CURRENT_TIMESTAMP(3) NOT NULL
+	);`
+
+	tables, err := ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+
+	columns := tables[0].Columns
+	if columns[0].OnUpdate != nil {
+		t.Errorf("Expected no ON UPDATE for id, got %q", *columns[0].OnUpdate)
+	}
+	if columns[1].OnUpdate == nil || *columns[1].OnUpdate != "CURRENT_TIMESTAMP" {
+		t.Errorf("Expected ON UPDATE CURRENT_TIMESTAMP, got %v", columns[1].OnUpdate)
+	}
+	if columns[2].OnUpdate == nil || *columns[2].OnUpdate != "CURRENT_TIMESTAMP(3)" {
+		t.Errorf("Expected ON UPDATE CURRENT_TIMESTAMP(3), got %v", columns[2].OnUpdate)
+	}
+	if columns[2].Nullable == nil || *columns[2].Nullable {
+		t.Error("Expected NOT NULL after ON UPDATE to be parsed")
+	}
+}
+
 func TestParseMalformedSQL(t *testing.T) {
 	testCases := []struct {
 		name string
@@ -292,3 +366,298 @@ func TestParseWithMySQLDirectives(t *testing.T) {
 		t.Errorf("Expected table name 'test', got '%s'", tables[0].TableName)
 	}
 }
+
+func TestParseSQLDumpTolerant_CollectsErrors(t *testing.T) {
+	sql := `
+		CREATE TABLE users (id INT);
+		CREATE TABLE (broken INT);
+		CREATE TABLE orders (id INT);
+	`
+
+	tables, errs, err := ParseSQLDumpTolerant(sql, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(tables) != 2 {
+		t.Errorf("Expected 2 parsed tables, got %d", len(tables))
+	}
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 statement error, got %d: %v", len(errs), errs)
+	}
+
+	var stmtErr *StatementError
+	if !errors.As(errs[0], &stmtErr) || stmtErr.Line != 3 {
+		t.Errorf("Expected statement error at line 3, got %v", errs[0])
+	}
+}
+
+func TestParseSQLDumpTolerant_StopsAtMaxErrors(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("CREATE TABLE users (id INT);\n")
+	for i := 0; i < 10; i++ {
+		sb.WriteString("CREATE TABLE (broken INT);\n")
+	}
+	sb.WriteString("CREATE TABLE orders (id INT);\n")
+
+	tables, errs, err := ParseSQLDumpTolerant(sb.String(), 3)
+	if !errors.Is(err, ErrTooManyErrors) {
+		t.Fatalf("Expected ErrTooManyErrors, got %v", err)
+	}
+	if len(errs) != 3 {
+		t.Errorf("Expected parsing to stop after 3 errors, got %d", len(errs))
+	}
+	if len(tables) != 1 || tables[0].TableName != "users" {
+		t.Errorf("Expected only tables before the limit to be parsed, got %d tables", len(tables))
+	}
+}
+
+func TestInterleavedColumnsAndConstraints(t *testing.T) {
+	sql := `CREATE TABLE test (
+		id INT,
+		PRIMARY KEY (id),
+		name VARCHAR(50),
+		KEY idx (name),
+		email VARCHAR(100),
+		CONSTRAINT fk_user FOREIGN KEY (id) REFERENCES users (id),
+		age INT
+	)`
+
+	tables, err := ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+	if len(tables) != 1 {
+		t.Fatalf("Expected 1 table, got %d", len(tables))
+	}
+
+	table := tables[0]
+	expectedColumns := []string{"id", "name", "email", "age"}
+	if len(table.Columns) != len(expectedColumns) {
+		t.Fatalf("Expected %d columns, got %d", len(expectedColumns), len(table.Columns))
+	}
+	for i, name := range expectedColumns {
+		if table.Columns[i].Name != name {
+			t.Errorf("Expected column %d to be %s, got %s", i, name, table.Columns[i].Name)
+		}
+	}
+	if table.Columns[1].DataType.Name != "VARCHAR" || len(table.Columns[1].DataType.Parameters) != 1 ||
+		table.Columns[1].DataType.Parameters[0] != "50" {
+		t.Errorf("Expected name to be VARCHAR(50), got %+v", table.Columns[1].DataType)
+	}
+
+	if table.PrimaryKey == nil || len(table.PrimaryKey.Columns) != 1 || table.PrimaryKey.Columns[0].Name != "id" {
+		t.Errorf("Expected primary key on id, got %+v", table.PrimaryKey)
+	}
+	if len(table.Indexes) != 1 || table.Indexes[0].Name == nil || *table.Indexes[0].Name != "idx" ||
+		table.Indexes[0].Columns[0].Name != "name" {
+		t.Errorf("Expected index idx on name, got %+v", table.Indexes)
+	}
+	if len(table.ForeignKeys) != 1 || table.ForeignKeys[0].Reference.TableName != "users" {
+		t.Errorf("Expected foreign key referencing users, got %+v", table.ForeignKeys)
+	}
+}
+
+// TestParseTrailingCommaInTableBody tests that a trailing comma before the closing paren is tolerated
+func TestParseTrailingCommaInTableBody(t *testing.T) {
+	sqls := []string{
+		"CREATE TABLE t (a INT, b INT,)",
+		"CREATE TABLE t (a INT, b INT, PRIMARY KEY (a),) ENGINE=InnoDB",
+	}
+
+	for _, sql := range sqls {
+		tables, err := ParseSQLDump(sql)
+		if err != nil {
+			t.Fatalf("ParseSQLDump failed for %q: %v", sql, err)
+		}
+		if len(tables) != 1 {
+			t.Fatalf("Expected 1 table for %q, got %d", sql, len(tables))
+		}
+		if len(tables[0].Columns) != 2 {
+			t.Errorf("Expected 2 columns for %q, got %d", sql, len(tables[0].Columns))
+		}
+	}
+
+	tables, _ := ParseSQLDump(sqls[1])
+	if tables[0].PrimaryKey == nil || tables[0].TableOptions == nil || tables[0].TableOptions.Engine == nil {
+		t.Error("Expected primary key and table options after a trailing comma to be parsed")
+	}
+}
+
+// TestParseErrorPosition tests that parse errors carry their position in the whole dump
+func TestParseErrorPosition(t *testing.T) {
+	sql := "CREATE TABLE users (id INT);\n" +
+		"\n" +
+		"CREATE TABLE orders (id INT, total MONEY);\n"
+
+	_, errs, err := ParseSQLDumpTolerant(sql, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 statement error, got %d: %v", len(errs), errs)
+	}
+
+	var parseErr *ParseError
+	if !errors.As(errs[0], &parseErr) {
+		t.Fatalf("Expected a ParseError, got %T: %v", errs[0], errs[0])
+	}
+	if parseErr.Line != 3 || parseErr.Column != 36 {
+		t.Errorf("Expected error at line 3, column 36, got line %d, column %d", parseErr.Line, parseErr.Column)
+	}
+	if parseErr.Token != "MONEY" || parseErr.Message != "expected data type, got IDENTIFIER" {
+		t.Errorf("Unexpected error details: token %q, message %q", parseErr.Token, parseErr.Message)
+	}
+	if got := parseErr.Error(); got != "expected data type, got IDENTIFIER at line 3, column 36" {
+		t.Errorf("Unexpected error string: %q", got)
+	}
+
+	// Errors at the end of input point at the last token
+	_, errs, _ = ParseSQLDumpTolerant("CREATE TABLE broken (id INT", 0)
+	if len(errs) != 1 || !errors.As(errs[0], &parseErr) || parseErr.Line != 1 || parseErr.Token != "" {
+		t.Errorf("Expected an end-of-input ParseError on line 1, got %v", errs)
+	}
+}
+
+// TestParseSQLDumpErrorPosition tests that ParseSQLDump stops at a broken statement and positions
+// the error in the whole dump
+func TestParseSQLDumpErrorPosition(t *testing.T) {
+	tests := []struct {
+		name   string
+		sql    string
+		line   int
+		column int
+	}{
+		{"second line", "CREATE TABLE a (id INT);\nCREATE TABLE b (\n  id INT,\n  total MONEY\n);\nCREATE TABLE c (id INT);", 4, 9},
+		{"same line", "CREATE TABLE a (id INT); CREATE TABLE b (id INT, total MONEY);\nCREATE TABLE c (id INT);", 1, 56},
+		{"after a byte order mark", "\uFEFFCREATE TABLE a (id INT); CREATE TABLE b (id INT, total MONEY);", 1, 56},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables, err := ParseSQLDump(tt.sql)
+			if err == nil {
+				t.Fatalf("Expected an error, got %d tables", len(tables))
+			}
+
+			var stmtErr *StatementError
+			var parseErr *ParseError
+			if !errors.As(err, &stmtErr) || !errors.As(err, &parseErr) {
+				t.Fatalf("Expected a StatementError wrapping a ParseError, got %T: %v", err, err)
+			}
+			if parseErr.Line != tt.line || parseErr.Column != tt.column || parseErr.Token != "MONEY" {
+				t.Errorf("Expected MONEY at line %d, column %d, got %q at line %d, column %d",
+					tt.line, tt.column, parseErr.Token, parseErr.Line, parseErr.Column)
+			}
+
+			// The position matches the one ParseSQLDumpTolerant reports from lexing the whole dump
+			_, errs, _ := ParseSQLDumpTolerant(tt.sql, 0)
+			if len(errs) != 1 || errs[0].Error() != err.Error() {
+				t.Errorf("Expected the error %v, got %v", errs, err)
+			}
+		})
+	}
+}
+
+// TestParseExpressionDefaultsForJSONAndSpatial tests parenthesized defaults on JSON and spatial columns
+func TestParseExpressionDefaultsForJSONAndSpatial(t *testing.T) {
+	sql := `CREATE TABLE places (
+		id INT,
+		tags JSON DEFAULT (JSON_ARRAY()),
+		meta JSON NOT NULL DEFAULT (JSON_OBJECT('source', 'import')),
+		location POINT DEFAULT (ST_GeomFromText('POINT(0 0)')) NOT NULL
+	);`
+
+	tables, err := ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+	if len(tables) != 1 || len(tables[0].Columns) != 4 {
+		t.Fatalf("Expected 1 table with 4 columns, got %v", tables)
+	}
+
+	expected := map[string]string{
+		"tags":     "(JSON_ARRAY())",
+		"meta":     "(JSON_OBJECT('source', 'import'))",
+		"location": "(ST_GeomFromText('POINT(0 0)'))",
+	}
+	for _, column := range tables[0].Columns[1:] {
+		if column.DefaultValue == nil || *column.DefaultValue != expected[column.Name] {
+			t.Errorf("Expected default %s for %s, got %v", expected[column.Name], column.Name, column.DefaultValue)
+		}
+	}
+	if location := tables[0].Columns[3]; location.Nullable == nil || *location.Nullable {
+		t.Error("Expected NOT NULL after the expression default to be parsed")
+	}
+}
+
+// TestParseFunctionAndArithmeticExpressionDefaults tests that function calls and multi-token
+// arithmetic inside parenthesized defaults are captured entirely
+func TestParseFunctionAndArithmeticExpressionDefaults(t *testing.T) {
+	sql := `CREATE TABLE items (
+		uuid VARCHAR(36) DEFAULT (UUID()),
+		id BINARY(16) DEFAULT (UUID_TO_BIN(UUID())) NOT NULL,
+		price DECIMAL(10,2),
+		quantity INT,
+		total DECIMAL(10,2) DEFAULT ((price * quantity) + 1.5) COMMENT 'computed'
+	);`
+
+	tables, err := ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+	columns := tables[0].Columns
+	if len(columns) != 5 {
+		t.Fatalf("Expected 5 columns, got %d", len(columns))
+	}
+
+	expected := map[string]string{
+		"uuid":  "(UUID())",
+		"id":    "(UUID_TO_BIN(UUID()))",
+		"total": "((price * quantity) + 1.5)",
+	}
+	for _, column := range columns {
+		want, ok := expected[column.Name]
+		if !ok {
+			continue
+		}
+		if column.DefaultValue == nil || *column.DefaultValue != want {
+			t.Errorf("Expected default %s for %s, got %v", want, column.Name, column.DefaultValue)
+		}
+		if !IsExpressionDefault(*column.DefaultValue) {
+			t.Errorf("Expected default of %s to be an expression default", column.Name)
+		}
+	}
+	if id := columns[1]; id.Nullable == nil || *id.Nullable {
+		t.Error("Expected NOT NULL after the expression default to be parsed")
+	}
+	if total := columns[4]; total.Comment == nil || *total.Comment != "'computed'" {
+		t.Errorf("Expected comment after the expression default to be parsed, got %v", total.Comment)
+	}
+}
+
+func TestParseColumnAttributeOrder(t *testing.T) {
+	orders := []string{
+		"name VARCHAR(10) DEFAULT 'x' NOT NULL",
+		"name VARCHAR(10) NOT NULL DEFAULT 'x'",
+	}
+
+	var columns []ColumnDefinition
+	for _, order := range orders {
+		tables, err := ParseSQLDump("CREATE TABLE t (" + order + ");")
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", order, err)
+		}
+		columns = append(columns, tables[0].Columns[0])
+	}
+
+	column := columns[0]
+	if column.Nullable == nil || *column.Nullable {
+		t.Errorf("Expected NOT NULL after DEFAULT to be parsed")
+	}
+	if column.DefaultValue == nil || *column.DefaultValue != "'x'" {
+		t.Errorf("Expected default 'x', got %v", column.DefaultValue)
+	}
+	if !reflect.DeepEqual(columns[0], columns[1]) {
+		t.Errorf("Expected the same column for both attribute orders, got %+v and %+v", columns[0], columns[1])
+	}
+}
@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// byteOrderMark is the UTF-8 encoded byte order mark some Windows tools write at the start of a file
+const byteOrderMark = "\uFEFF"
+
+// cp1252Specials maps the bytes 0x80-0x9F of Windows-1252 to their characters; unassigned bytes map
+// to the C1 control characters like in ISO-8859-1
+var cp1252Specials = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8D, 'Ž', 0x8F,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9D, 'ž', 'Ÿ',
+}
+
+// DecodeSQL converts a SQL dump read in the given encoding to a UTF-8 string without a byte order mark.
+// Supported encodings are utf8 (also utf-8 and utf8mb4; the default when encoding is empty), latin1
+// (MySQL's latin1, which is Windows-1252; also cp1252 and windows-1252), iso-8859-1 and utf-16
+// (also utf-16le and utf-16be; a byte order mark overrides the byte order, little-endian by default).
+func DecodeSQL(data []byte, encoding string) (string, error) {
+	switch strings.ToLower(encoding) {
+	case "", "utf8", "utf-8", "utf8mb4":
+		return strings.TrimPrefix(string(data), byteOrderMark), nil
+
+	case "latin1", "cp1252", "windows-1252":
+		var sb strings.Builder
+		sb.Grow(len(data))
+		for _, b := range data {
+			if b >= 0x80 && b <= 0x9F {
+				sb.WriteRune(cp1252Specials[b-0x80])
+			} else {
+				sb.WriteRune(rune(b))
+			}
+		}
+		return sb.String(), nil
+
+	case "iso-8859-1":
+		var sb strings.Builder
+		sb.Grow(len(data))
+		for _, b := range data {
+			sb.WriteRune(rune(b))
+		}
+		return sb.String(), nil
+
+	case "utf-16", "utf16", "utf-16le", "utf-16be":
+		if len(data)%2 != 0 {
+			return "", fmt.Errorf("input is not valid UTF-16: odd number of bytes")
+		}
+		bigEndian := strings.HasSuffix(strings.ToLower(encoding), "be")
+		switch {
+		case len(data) >= 2 && data[0] == 0xFE && data[1] == 0xFF:
+			bigEndian = true
+		case len(data) >= 2 && data[0] == 0xFF && data[1] == 0xFE:
+			bigEndian = false
+		}
+		units := make([]uint16, len(data)/2)
+		for i := range units {
+			if bigEndian {
+				units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+			} else {
+				units[i] = uint16(data[2*i+1])<<8 | uint16(data[2*i])
+			}
+		}
+		return strings.TrimPrefix(string(utf16.Decode(units)), byteOrderMark), nil
+	}
+
+	return "", fmt.Errorf("unsupported encoding '%s'", encoding)
+}
@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"strings"
+)
+
+// NormalizeExpression re-serializes a SQL expression from its tokens so that expressions
+// differing only in whitespace, letter case or redundant outer parentheses compare equal,
+// e.g. "(a > 0)", "(a>0)" and "a > 0" all normalize to "A > 0"
+func NormalizeExpression(expr string) string {
+	var tokens []Token
+	for _, token := range NewMySQLLexer(expr).Tokenize() {
+		if token.Type == EOF || token.Type == SQL_COMMENT {
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+
+	for isWrappedInParens(tokens) {
+		tokens = tokens[1 : len(tokens)-1]
+	}
+
+	var sb strings.Builder
+	for i, token := range tokens {
+		if i > 0 && needsSpaceBetween(tokens[i-1], token) {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(normalizedTokenValue(token))
+	}
+	return sb.String()
+}
+
+// IsExpressionDefault reports whether a column default is a parenthesized expression
+// such as (UUID()) or (JSON_OBJECT()), which MySQL 8 allows in place of a literal
+func IsExpressionDefault(value string) bool {
+	return strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")")
+}
+
+// joinExpressionTokens serializes expression tokens as written, with single spaces and
+// function arguments attached to the function name, e.g. "(JSON_ARRAY(1, 2))"
+func joinExpressionTokens(tokens []Token) string {
+	var sb strings.Builder
+	for i, token := range tokens {
+		if i > 0 && needsSpaceBetween(tokens[i-1], token) &&
+			!(tokens[i-1].Type == IDENTIFIER && token.Type == LPAREN) {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(token.Value)
+	}
+	return sb.String()
+}
+
+// isWrappedInParens reports whether the first token opens a parenthesis that is closed by the last token
+func isWrappedInParens(tokens []Token) bool {
+	if len(tokens) < 2 || tokens[0].Type != LPAREN || tokens[len(tokens)-1].Type != RPAREN {
+		return false
+	}
+
+	depth := 0
+	for i, token := range tokens {
+		switch token.Type {
+		case LPAREN:
+			depth++
+		case RPAREN:
+			depth--
+			if depth == 0 && i < len(tokens)-1 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
+
+// needsSpaceBetween decides the separator between two adjacent tokens in a normalized expression
+func needsSpaceBetween(prev, next Token) bool {
+	switch {
+	case prev.Type == LPAREN, prev.Type == DOT:
+		return false
+	case next.Type == RPAREN, next.Type == COMMA, next.Type == DOT:
+		return false
+	}
+	return true
+}
+
+// normalizedTokenValue returns the canonical spelling of a token. Keywords, identifiers and
+// function names are case-insensitive in MySQL and are upper-cased; string literals are kept as written
+func normalizedTokenValue(token Token) string {
+	if token.Type == STRING {
+		return token.Value
+	}
+	return strings.ToUpper(token.Value)
+}
@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestNormalizeExpression(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain", "a > 0", "A > 0"},
+		{"no whitespace", "a>0", "A > 0"},
+		{"outer parens", "(a > 0)", "A > 0"},
+		{"double outer parens", "((a>0))", "A > 0"},
+		{"extra whitespace", "  a   >\n0 ", "A > 0"},
+		{"inner parens kept", "(a > 0) and (b < 10)", "(A > 0) AND (B < 10)"},
+		{"keyword case", "a is not null and b in (1,2)", "A IS NOT NULL AND B IN (1, 2)"},
+		{"function call", "char_length( name ) >= 3", "CHAR_LENGTH (NAME) >= 3"},
+		{"string literal kept", "status <> 'Active'", "STATUS <> 'Active'"},
+		{"quoted identifier", "(`price`>=0)", "PRICE >= 0"},
+		{"multi-char operators", "a<=b OR a!=c", "A <= B OR A != C"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeExpression(tt.input); got != tt.expected {
+				t.Errorf("NormalizeExpression(%q) = %q, expected %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckConstraintNormalizedExpression(t *testing.T) {
+	variants := []string{
+		"CREATE TABLE t (a INT, CHECK (a > 0));",
+		"CREATE TABLE t (a INT, CHECK (a>0));",
+		"CREATE TABLE t (a INT, CHECK ((a > 0)));",
+		"CREATE TABLE t (a INT, CHECK (  a  >  0  ));",
+	}
+
+	var expected string
+	for i, sql := range variants {
+		tables, err := ParseSQLDump(sql)
+		if err != nil {
+			t.Fatalf("ParseSQLDump failed: %v", err)
+		}
+		if len(tables) != 1 || len(tables[0].CheckConstraints) != 1 {
+			t.Fatalf("Variant %d: expected 1 table with 1 check constraint", i)
+		}
+
+		check := tables[0].CheckConstraints[0]
+		if i == 0 {
+			expected = check.NormalizedExpression
+			if expected != "A > 0" {
+				t.Errorf("Expected normalized expression 'A > 0', got %q", expected)
+			}
+			continue
+		}
+		if check.NormalizedExpression != expected {
+			t.Errorf("Variant %d: normalized expression %q differs from %q (original %q)",
+				i, check.NormalizedExpression, expected, check.Expression)
+		}
+	}
+}
+
+func TestCheckConstraintNormalizedExpressionDistinguishesOperators(t *testing.T) {
+	tables, err := ParseSQLDump(`
+		CREATE TABLE a (x INT, CHECK (x > 0));
+		CREATE TABLE b (x INT, CHECK (x < 0));
+	`)
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+	if len(tables) != 2 {
+		t.Fatalf("Expected 2 tables, got %d", len(tables))
+	}
+
+	gt := tables[0].CheckConstraints[0].NormalizedExpression
+	lt := tables[1].CheckConstraints[0].NormalizedExpression
+	if gt == lt {
+		t.Errorf("Expected different normalized expressions, both are %q", gt)
+	}
+}
@@ -15,9 +15,9 @@ type MySQLLexer struct {
 	keywords    map[string]TokenType
 }
 
-// NewMySQLLexer creates a new lexer instance
+// NewMySQLLexer creates a new lexer instance; a leading byte order mark is skipped
 func NewMySQLLexer(text string) *MySQLLexer {
-	runes := []rune(text)
+	runes := []rune(strings.TrimPrefix(text, byteOrderMark))
 	lexer := &MySQLLexer{
 		text:   runes,
 		pos:    0,
@@ -106,6 +106,8 @@ func NewMySQLLexer(text string) *MySQLLexer {
 		"KEY_BLOCK_SIZE":     KEY_BLOCK_SIZE,
 		"MAX_ROWS":           MAX_ROWS,
 		"MIN_ROWS":           MIN_ROWS,
+		"AVG_ROW_LENGTH":     AVG_ROW_LENGTH,
+		"AUTOEXTEND_SIZE":    AUTOEXTEND_SIZE,
 		"STATS_PERSISTENT":   STATS_PERSISTENT,
 		"STATS_AUTO_RECALC":  STATS_AUTO_RECALC,
 		"STATS_SAMPLE_PAGES": STATS_SAMPLE_PAGES,
@@ -114,6 +116,7 @@ func NewMySQLLexer(text string) *MySQLLexer {
 		"DELAY_KEY_WRITE":    DELAY_KEY_WRITE,
 		"UNION":              UNION,
 		"INSERT_METHOD":      INSERT_METHOD,
+		"CONNECTION":         CONNECTION,
 		"PARTITION":          PARTITION,
 		"BY":                 BY,
 		"HASH":               HASH,
@@ -126,6 +129,9 @@ func NewMySQLLexer(text string) *MySQLLexer {
 		"IN":                 IN,
 		"MAXVALUE":           MAXVALUE,
 		"LINEAR":             LINEAR,
+		"PARTITIONS":         PARTITIONS,
+		"SUBPARTITION":       SUBPARTITION,
+		"SUBPARTITIONS":      SUBPARTITIONS,
 		"ON":                 ON,
 		"DELETE":             DELETE,
 		"UPDATE":             UPDATE,
@@ -144,6 +150,7 @@ func NewMySQLLexer(text string) *MySQLLexer {
 		"NONE":               NONE,
 		"FIRST":              FIRST,
 		"LAST":               LAST,
+		"AFTER":              AFTER,
 		"COLUMN_FORMAT":      COLUMN_FORMAT,
 		"FIXED":              FIXED,
 		"DYNAMIC":            DYNAMIC,
@@ -337,6 +344,24 @@ func (l *MySQLLexer) readNumber() string {
 		value += string(*l.currentChar)
 		l.advance()
 	}
+
+	// Scientific notation exponent such as 1.5e3 or 2E-4
+	if l.currentChar != nil && (*l.currentChar == 'e' || *l.currentChar == 'E') {
+		digitAt := 1
+		if next := l.peek(); next != nil && (*next == '+' || *next == '-') {
+			digitAt = 2
+		}
+		if digit := l.peek(digitAt); digit != nil && unicode.IsDigit(*digit) {
+			for range digitAt {
+				value += string(*l.currentChar)
+				l.advance()
+			}
+			for l.currentChar != nil && unicode.IsDigit(*l.currentChar) {
+				value += string(*l.currentChar)
+				l.advance()
+			}
+		}
+	}
 	return value
 }
 
@@ -367,6 +392,30 @@ func (l *MySQLLexer) readQuotedIdentifier() string {
 	return value
 }
 
+// readOperator reads an expression operator, preferring the longest match; returns "" if there is none
+func (l *MySQLLexer) readOperator() string {
+	for _, operator := range []string{"<=>", "<=", ">=", "<>", "!=", "<<", ">>", "&&", "||", "<", ">", "!", "+", "*", "/", "%", "&", "|", "^", "~"} {
+		if l.hasPrefix(operator) {
+			for range operator {
+				l.advance()
+			}
+			return operator
+		}
+	}
+	return ""
+}
+
+// hasPrefix reports whether the input at the current position starts with s
+func (l *MySQLLexer) hasPrefix(s string) bool {
+	for i, r := range []rune(s) {
+		c := l.peek(i)
+		if c == nil || *c != r {
+			return false
+		}
+	}
+	return true
+}
+
 // GetNextToken returns the next token from the input
 func (l *MySQLLexer) GetNextToken() Token {
 	for l.currentChar != nil {
@@ -380,6 +429,9 @@ func (l *MySQLLexer) GetNextToken() Token {
 			continue
 		}
 
+		// Tokens are positioned at their first character
+		position, line, column := l.pos, l.line, l.column
+
 		// Handle MySQL directives
 		if *l.currentChar == '/' {
 			next1 := l.peek(1)
@@ -388,9 +440,9 @@ func (l *MySQLLexer) GetNextToken() Token {
 				return Token{
 					Type:     MYSQL_DIRECTIVE,
 					Value:    l.readMySQLDirective(),
-					Position: l.pos,
-					Line:     l.line,
-					Column:   l.column,
+					Position: position,
+					Line:     line,
+					Column:   column,
 				}
 			}
 		}
@@ -399,9 +451,9 @@ func (l *MySQLLexer) GetNextToken() Token {
 			return Token{
 				Type:     STRING,
 				Value:    l.readString(),
-				Position: l.pos,
-				Line:     l.line,
-				Column:   l.column,
+				Position: position,
+				Line:     line,
+				Column:   column,
 			}
 		}
 
@@ -409,9 +461,9 @@ func (l *MySQLLexer) GetNextToken() Token {
 			return Token{
 				Type:     IDENTIFIER,
 				Value:    l.readQuotedIdentifier(),
-				Position: l.pos,
-				Line:     l.line,
-				Column:   l.column,
+				Position: position,
+				Line:     line,
+				Column:   column,
 			}
 		}
 
@@ -419,9 +471,9 @@ func (l *MySQLLexer) GetNextToken() Token {
 			return Token{
 				Type:     NUMBER,
 				Value:    l.readNumber(),
-				Position: l.pos,
-				Line:     l.line,
-				Column:   l.column,
+				Position: position,
+				Line:     line,
+				Column:   column,
 			}
 		}
 
@@ -433,9 +485,9 @@ func (l *MySQLLexer) GetNextToken() Token {
 				return Token{
 					Type:     NUMBER,
 					Value:    l.readNumber(),
-					Position: l.pos,
-					Line:     l.line,
-					Column:   l.column,
+					Position: position,
+					Line:     line,
+					Column:   column,
 				}
 			}
 		}
@@ -449,9 +501,20 @@ func (l *MySQLLexer) GetNextToken() Token {
 			return Token{
 				Type:     tokenType,
 				Value:    value,
-				Position: l.pos,
-				Line:     l.line,
-				Column:   l.column,
+				Position: position,
+				Line:     line,
+				Column:   column,
+			}
+		}
+
+		// Comparison, arithmetic and logical operators used in expressions
+		if operator := l.readOperator(); operator != "" {
+			return Token{
+				Type:     OPERATOR,
+				Value:    operator,
+				Position: position,
+				Line:     line,
+				Column:   column,
 			}
 		}
 
@@ -470,9 +533,9 @@ func (l *MySQLLexer) GetNextToken() Token {
 			token := Token{
 				Type:     tokenType,
 				Value:    string(*l.currentChar),
-				Position: l.pos,
-				Line:     l.line,
-				Column:   l.column,
+				Position: position,
+				Line:     line,
+				Column:   column,
 			}
 			l.advance()
 			return token
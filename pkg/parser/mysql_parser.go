@@ -1,19 +1,108 @@
 package parser
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 )
 
-// ParseSQLDump parses a SQL dump containing multiple CREATE TABLE statements
+// ErrTooManyErrors is returned by ParseSQLDumpTolerant when the error limit is reached
+var ErrTooManyErrors = errors.New("too many parse errors")
+
+// ParseError describes a syntax error at a position of the parsed SQL.
+// The position is relative to the whole dump, not to the failing statement.
+type ParseError struct {
+	Line    int
+	Column  int
+	Token   string // value of the offending token, empty at end of input
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s at line %d, column %d", e.Message, e.Line, e.Column)
+}
+
+// StatementError describes a CREATE TABLE statement that could not be parsed
+type StatementError struct {
+	Line int
+	Err  error
+}
+
+func (e *StatementError) Error() string {
+	return fmt.Sprintf("statement at line %d: %v", e.Line, e.Err)
+}
+
+func (e *StatementError) Unwrap() error {
+	return e.Err
+}
+
+// ParseSQLDump parses a SQL dump containing multiple CREATE TABLE statements.
+// It stops at the first CREATE TABLE statement that cannot be parsed and returns a *StatementError
+// wrapping the *ParseError positioned within the whole dump; ParseSQLDumpTolerant skips such
+// statements and reports all of them.
 func ParseSQLDump(sql string) ([]*CreateTableStatement, error) {
+	return ParseSQLReader(strings.NewReader(sql))
+}
+
+// ParseSQLDumpTolerant parses a SQL dump like ParseSQLDump, but collects an error for every
+// CREATE TABLE statement that could not be parsed and skips it instead of stopping.
+// Each error is a *StatementError wrapping the *ParseError positioned within the whole dump.
+// Parsing stops with ErrTooManyErrors once maxErrors errors have accumulated; maxErrors <= 0 means no limit.
+func ParseSQLDumpTolerant(sql string, maxErrors int) ([]*CreateTableStatement, []error, error) {
+	var tables []*CreateTableStatement
+	var errs []error
+
+	for _, statement := range splitCreateTableStatements(sql) {
+		table, err := NewMySQLCreateTableParser(statement.tokens).Parse()
+		if err != nil {
+			errs = append(errs, &StatementError{Line: statement.tokens[0].Line, Err: err})
+			if maxErrors > 0 && len(errs) >= maxErrors {
+				return tables, errs, fmt.Errorf("%w: stopped after %d errors", ErrTooManyErrors, len(errs))
+			}
+			continue
+		}
+		table.RawSQL = statement.raw
+		tables = append(tables, table)
+	}
+
+	return tables, errs, nil
+}
+
+// sqlStatement is a CREATE TABLE statement of a SQL dump
+type sqlStatement struct {
+	tokens []Token
+	raw    string // source text from CREATE through the terminating semicolon
+}
+
+// splitCreateTableStatements splits a SQL dump into its CREATE TABLE statements
+func splitCreateTableStatements(sql string) []sqlStatement {
+	// The lexer skips a byte order mark, which would shift the token positions in text
+	sql = strings.TrimPrefix(sql, byteOrderMark)
 	lexer := NewMySQLLexer(sql)
 	tokens := lexer.Tokenize()
+	// Token positions count runes
+	text := []rune(sql)
 
-	var tables []*CreateTableStatement
+	var statements []sqlStatement
 	var currentTokens []Token
 
+	// end is the position just past the current statement's last token; the lexer does not record
+	// where a token ends, so it is taken from the start of the following token
+	end := 0
+	flush := func() {
+		if len(currentTokens) > 0 && isCreateTable(currentTokens) {
+			raw := strings.TrimSpace(string(text[currentTokens[0].Position:end]))
+			statements = append(statements, sqlStatement{tokens: currentTokens, raw: raw})
+		}
+		currentTokens = nil
+	}
+
 	// Process all tokens
 	for _, token := range tokens {
+		if end < 0 {
+			end = min(token.Position, len(text))
+		}
+
 		// Skip MySQL directives and comments
 		if token.Type == MYSQL_DIRECTIVE || token.Type == SQL_COMMENT {
 			continue
@@ -22,42 +111,32 @@ func ParseSQLDump(sql string) ([]*CreateTableStatement, error) {
 		// Start new statement on CREATE
 		if token.Type == CREATE {
 			// Finish previous statement if exists
-			if len(currentTokens) > 0 {
-				if isCreateTable(currentTokens) {
-					if table := parseTokens(currentTokens); table != nil {
-						tables = append(tables, table)
-					}
-				}
-				currentTokens = nil
-			}
+			flush()
 		}
 
 		// Add non-EOF tokens to current statement
 		if token.Type != EOF {
 			currentTokens = append(currentTokens, token)
+			end = -1
 		}
 
-		// End statement on semicolon or EOF
+		// End statement on semicolon or EOF. Other statements of a full dump (LOCK TABLES,
+		// INSERT INTO, UNLOCK TABLES, ...) are collected up to their semicolon and dropped by flush.
+		if token.Type == SEMICOLON {
+			end = token.Position + 1
+		}
 		if token.Type == SEMICOLON || token.Type == EOF {
-			if len(currentTokens) > 0 {
-				if isCreateTable(currentTokens) {
-					if table := parseTokens(currentTokens); table != nil {
-						tables = append(tables, table)
-					}
-				}
-				currentTokens = nil
-			}
+			flush()
 		}
 	}
 
 	// Handle remaining tokens
-	if len(currentTokens) > 0 && isCreateTable(currentTokens) {
-		if table := parseTokens(currentTokens); table != nil {
-			tables = append(tables, table)
-		}
+	if end < 0 {
+		end = len(text)
 	}
+	flush()
 
-	return tables, nil
+	return statements
 }
 
 func isCreateTable(tokens []Token) bool {
@@ -77,15 +156,6 @@ func isCreateTable(tokens []Token) bool {
 	return false
 }
 
-func parseTokens(tokens []Token) *CreateTableStatement {
-	parser := NewMySQLCreateTableParser(tokens)
-	table, err := parser.Parse()
-	if err != nil {
-		return nil
-	}
-	return table
-}
-
 // Helper function to clean table name (remove backticks)
 func cleanTableName(name string) string {
 	return strings.Trim(name, "`")
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -491,3 +492,902 @@ func TestFulltextAndSpatialIndexes(t *testing.T) {
 		t.Errorf("Expected SPATIAL index type, got %s", spIndex.IndexType)
 	}
 }
+
+func TestPartitionByKey(t *testing.T) {
+	sql := `
+	CREATE TABLE sessions (
+		id INT,
+		user_id INT
+	) ENGINE=InnoDB PARTITION BY KEY (id, user_id) PARTITIONS 4
+	`
+
+	tables, err := ParseSQLDump(sql)
+
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+
+	table := tables[0]
+
+	if table.PartitionOptions == nil {
+		t.Fatalf("Expected partition options to be defined")
+	}
+
+	if table.PartitionOptions.Type != "KEY" {
+		t.Errorf("Expected KEY partition type, got %s", table.PartitionOptions.Type)
+	}
+
+	if len(table.PartitionOptions.Columns) != 2 ||
+		table.PartitionOptions.Columns[0] != "id" || table.PartitionOptions.Columns[1] != "user_id" {
+		t.Errorf("Expected partition columns [id user_id], got %v", table.PartitionOptions.Columns)
+	}
+
+	if table.TableOptions == nil || table.TableOptions.Engine == nil || *table.TableOptions.Engine != "InnoDB" {
+		t.Errorf("Expected ENGINE=InnoDB to be parsed before partitioning")
+	}
+}
+
+func TestPartitionByList(t *testing.T) {
+	sql := `
+	CREATE TABLE stores (
+		id INT,
+		region_id INT
+	) PARTITION BY LIST (region_id) (
+		PARTITION p_north VALUES IN (1, 2),
+		PARTITION p_south VALUES IN (3, 4)
+	)
+	`
+
+	tables, err := ParseSQLDump(sql)
+
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+
+	table := tables[0]
+
+	if table.PartitionOptions == nil {
+		t.Fatalf("Expected partition options to be defined")
+	}
+
+	if table.PartitionOptions.Type != "LIST" {
+		t.Errorf("Expected LIST partition type, got %s", table.PartitionOptions.Type)
+	}
+
+	if table.PartitionOptions.Expression == nil || *table.PartitionOptions.Expression != "region_id" {
+		t.Errorf("Expected partition expression 'region_id', got %v", table.PartitionOptions.Expression)
+	}
+
+	// LIST COLUMNS stores columns instead of an expression
+	sql = "CREATE TABLE stores (id INT, city VARCHAR(20)) PARTITION BY LIST COLUMNS (city) (PARTITION p0 VALUES IN ('Oslo'))"
+	tables, err = ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+
+	partOptions := tables[0].PartitionOptions
+	if partOptions == nil || partOptions.Type != "LIST" {
+		t.Fatalf("Expected LIST partition options, got %+v", partOptions)
+	}
+	if partOptions.Expression != nil {
+		t.Errorf("Expected no expression for LIST COLUMNS, got %s", *partOptions.Expression)
+	}
+	if len(partOptions.Columns) != 1 || partOptions.Columns[0] != "city" {
+		t.Errorf("Expected partition columns [city], got %v", partOptions.Columns)
+	}
+}
+
+func TestRangePartitionDefinitions(t *testing.T) {
+	sql := `
+	CREATE TABLE events (
+		id INT,
+		created DATE
+	) ENGINE=InnoDB PARTITION BY RANGE (TO_DAYS(created)) (
+		PARTITION p2023 VALUES LESS THAN (TO_DAYS('2024-01-01')) COMMENT = 'archive' ENGINE = InnoDB,
+		PARTITION p2024 VALUES LESS THAN (TO_DAYS('2025-01-01')) MAX_ROWS = 1000 DATA DIRECTORY = '/data/p2024',
+		PARTITION pmax VALUES LESS THAN MAXVALUE
+	);
+	`
+
+	tables, err := ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+
+	partOptions := tables[0].PartitionOptions
+	if partOptions == nil || partOptions.Type != "RANGE" {
+		t.Fatalf("Expected RANGE partition options, got %+v", partOptions)
+	}
+	if len(partOptions.Partitions) != 3 {
+		t.Fatalf("Expected 3 partitions, got %d", len(partOptions.Partitions))
+	}
+
+	expected := []struct {
+		name   string
+		values string
+	}{
+		{"p2023", "TO_DAYS('2024-01-01')"},
+		{"p2024", "TO_DAYS('2025-01-01')"},
+		{"pmax", "MAXVALUE"},
+	}
+	for i, partition := range partOptions.Partitions {
+		if partition.Name != expected[i].name || partition.Type != "RANGE" ||
+			len(partition.Values) != 1 || partition.Values[0] != expected[i].values {
+			t.Errorf("Expected partition %s LESS THAN %s, got %+v", expected[i].name, expected[i].values, partition)
+		}
+	}
+
+	if comment := partOptions.Partitions[0].Comment; comment == nil || *comment != "archive" {
+		t.Errorf("Expected comment 'archive', got %v", comment)
+	}
+	if maxRows := partOptions.Partitions[1].MaxRows; maxRows == nil || *maxRows != 1000 {
+		t.Errorf("Expected MAX_ROWS 1000, got %v", maxRows)
+	}
+	if dir := partOptions.Partitions[1].DataDirectory; dir == nil || *dir != "/data/p2024" {
+		t.Errorf("Expected DATA DIRECTORY '/data/p2024', got %v", dir)
+	}
+}
+
+func TestListColumnsPartitionDefinitions(t *testing.T) {
+	sql := `
+	CREATE TABLE stores (
+		id INT,
+		country CHAR(2),
+		city VARCHAR(20)
+	) PARTITION BY LIST COLUMNS (country, city) (
+		PARTITION p_nordic VALUES IN (('NO', 'Oslo'), ('SE', 'Stockholm')),
+		PARTITION p_other VALUES IN (('DE', 'Berlin'))
+	)
+	`
+
+	tables, err := ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+
+	partOptions := tables[0].PartitionOptions
+	if partOptions == nil || partOptions.Type != "LIST" || len(partOptions.Columns) != 2 {
+		t.Fatalf("Expected LIST COLUMNS (country, city), got %+v", partOptions)
+	}
+	if len(partOptions.Partitions) != 2 {
+		t.Fatalf("Expected 2 partitions, got %d", len(partOptions.Partitions))
+	}
+
+	nordic := partOptions.Partitions[0]
+	if nordic.Name != "p_nordic" || !reflect.DeepEqual(nordic.Values, []string{"('NO', 'Oslo')", "('SE', 'Stockholm')"}) {
+		t.Errorf("Expected p_nordic with two value tuples, got %+v", nordic)
+	}
+	other := partOptions.Partitions[1]
+	if other.Name != "p_other" || !reflect.DeepEqual(other.Values, []string{"('DE', 'Berlin')"}) {
+		t.Errorf("Expected p_other with one value tuple, got %+v", other)
+	}
+}
+
+func TestLinearPartitioning(t *testing.T) {
+	tests := []struct {
+		name         string
+		sql          string
+		expectedType string
+	}{
+		{"linear hash", "CREATE TABLE t (id INT) PARTITION BY LINEAR HASH (id) PARTITIONS 4", "HASH"},
+		{"linear key", "CREATE TABLE t (id INT) PARTITION BY LINEAR KEY (id) PARTITIONS 4", "KEY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables, err := ParseSQLDump(tt.sql)
+			if err != nil {
+				t.Fatalf("ParseSQLDump failed: %v", err)
+			}
+
+			partOptions := tables[0].PartitionOptions
+			if partOptions == nil {
+				t.Fatalf("Expected partition options to be defined")
+			}
+			if !partOptions.Linear {
+				t.Errorf("Expected Linear to be true")
+			}
+			if partOptions.Type != tt.expectedType {
+				t.Errorf("Expected %s partition type, got %s", tt.expectedType, partOptions.Type)
+			}
+		})
+	}
+
+	tables, err := ParseSQLDump("CREATE TABLE t (id INT) PARTITION BY HASH (id)")
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+	if tables[0].PartitionOptions.Linear {
+		t.Errorf("Expected Linear to be false for plain HASH partitioning")
+	}
+}
+
+func TestTableOptionsWithPartitioning(t *testing.T) {
+	tables, err := ParseSQLDump(`CREATE TABLE events (id INT, created DATE) ENGINE=InnoDB PARTITION BY HASH(id) PARTITIONS 4;
+	CREATE TABLE logs (id INT) ENGINE=InnoDB ROW_FORMAT=COMPRESSED DEFAULT CHARSET=utf8mb4 COMMENT='archive'
+	PARTITION BY RANGE (id) (PARTITION p0 VALUES LESS THAN (10) ENGINE = MyISAM, PARTITION p1 VALUES LESS THAN MAXVALUE);
+	CREATE TABLE users (id INT) ROW_FORMAT=DYNAMIC;`)
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+	if len(tables) != 3 {
+		t.Fatalf("Expected 3 tables, got %d", len(tables))
+	}
+
+	events := tables[0]
+	if events.TableOptions == nil || events.TableOptions.Engine == nil || *events.TableOptions.Engine != "InnoDB" {
+		t.Errorf("Expected engine InnoDB, got %+v", events.TableOptions)
+	}
+	if events.PartitionOptions == nil || events.PartitionOptions.Type != "HASH" {
+		t.Fatalf("Expected HASH partitioning, got %+v", events.PartitionOptions)
+	}
+	if count := events.PartitionOptions.PartitionCount; count == nil || *count != 4 {
+		t.Errorf("Expected 4 partitions, got %v", count)
+	}
+
+	// Options before PARTITION BY belong to the table, ENGINE inside a partition definition does not
+	logs := tables[1]
+	opts := logs.TableOptions
+	if opts == nil || opts.Engine == nil || *opts.Engine != "InnoDB" || opts.RowFormat == nil || *opts.RowFormat != "COMPRESSED" ||
+		opts.CharacterSet == nil || *opts.CharacterSet != "utf8mb4" || opts.Comment == nil {
+		t.Errorf("Expected engine, row format, charset and comment to be parsed, got %+v", opts)
+	}
+	if logs.PartitionOptions == nil || logs.PartitionOptions.Type != "RANGE" || len(logs.PartitionOptions.Partitions) != 2 {
+		t.Fatalf("Expected RANGE partitioning with 2 partitions, got %+v", logs.PartitionOptions)
+	}
+
+	// The statement after the partitioning is parsed on its own
+	if users := tables[2]; users.PartitionOptions != nil || users.TableOptions == nil || users.TableOptions.RowFormat == nil {
+		t.Errorf("Expected only ROW_FORMAT for the following table, got %+v and %+v", users.TableOptions, users.PartitionOptions)
+	}
+}
+
+func TestSubPartitioning(t *testing.T) {
+	sql := `
+	CREATE TABLE sales (
+		id INT,
+		sold DATE
+	) PARTITION BY RANGE (YEAR(sold)) PARTITIONS 2
+	SUBPARTITION BY LINEAR KEY (id) SUBPARTITIONS 3 (
+		PARTITION p0 VALUES LESS THAN (2020),
+		PARTITION p1 VALUES LESS THAN MAXVALUE
+	)
+	`
+
+	tables, err := ParseSQLDump(sql)
+
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+
+	partOptions := tables[0].PartitionOptions
+	if partOptions == nil {
+		t.Fatalf("Expected partition options to be defined")
+	}
+
+	if partOptions.Type != "RANGE" {
+		t.Errorf("Expected RANGE partition type, got %s", partOptions.Type)
+	}
+	if partOptions.PartitionCount == nil || *partOptions.PartitionCount != 2 {
+		t.Errorf("Expected PARTITIONS 2, got %v", partOptions.PartitionCount)
+	}
+	if partOptions.SubPartitionType != "KEY" || !partOptions.SubPartitionLinear {
+		t.Errorf("Expected LINEAR KEY subpartitioning, got linear=%v type=%s", partOptions.SubPartitionLinear, partOptions.SubPartitionType)
+	}
+	if len(partOptions.SubPartitionColumns) != 1 || partOptions.SubPartitionColumns[0] != "id" {
+		t.Errorf("Expected subpartition columns [id], got %v", partOptions.SubPartitionColumns)
+	}
+	if partOptions.SubPartitionCount == nil || *partOptions.SubPartitionCount != 3 {
+		t.Errorf("Expected SUBPARTITIONS 3, got %v", partOptions.SubPartitionCount)
+	}
+}
+
+func TestTableCharsetSpellings(t *testing.T) {
+	spellings := []string{
+		"DEFAULT CHARSET=utf8mb4",
+		"CHARSET=utf8mb4",
+		"CHARACTER SET=utf8mb4",
+		"CHARACTER SET utf8mb4",
+		"DEFAULT CHARACTER SET = utf8mb4",
+	}
+
+	for _, spelling := range spellings {
+		t.Run(spelling, func(t *testing.T) {
+			tables, err := ParseSQLDump("CREATE TABLE test (id INT) ENGINE=InnoDB " + spelling + " DEFAULT COLLATE=utf8mb4_bin")
+			if err != nil {
+				t.Fatalf("ParseSQLDump failed: %v", err)
+			}
+
+			options := tables[0].TableOptions
+			if options == nil || options.CharacterSet == nil || *options.CharacterSet != "utf8mb4" {
+				t.Errorf("Expected character set utf8mb4, got %+v", options)
+			}
+			if options.Collate == nil || *options.Collate != "utf8mb4_bin" {
+				t.Errorf("Expected collation utf8mb4_bin, got %v", options.Collate)
+			}
+		})
+	}
+}
+
+func TestMyISAMAndStatsTableOptions(t *testing.T) {
+	sql := `CREATE TABLE test (id INT) ENGINE=MyISAM PACK_KEYS=1 CHECKSUM=1 DELAY_KEY_WRITE=0
+		STATS_PERSISTENT=0 STATS_AUTO_RECALC=DEFAULT STATS_SAMPLE_PAGES=25`
+
+	tables, err := ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+
+	options := tables[0].TableOptions
+	if options.PackKeys == nil || *options.PackKeys != 1 {
+		t.Errorf("Expected PACK_KEYS=1, got %v", options.PackKeys)
+	}
+	if options.Checksum == nil || *options.Checksum != 1 {
+		t.Errorf("Expected CHECKSUM=1, got %v", options.Checksum)
+	}
+	if options.DelayKeyWrite == nil || *options.DelayKeyWrite != 0 {
+		t.Errorf("Expected DELAY_KEY_WRITE=0, got %v", options.DelayKeyWrite)
+	}
+	if options.StatsPersistent == nil || *options.StatsPersistent != 0 {
+		t.Errorf("Expected STATS_PERSISTENT=0, got %v", options.StatsPersistent)
+	}
+	if options.StatsAutoRecalc != nil {
+		t.Errorf("Expected STATS_AUTO_RECALC=DEFAULT to leave option unset, got %v", *options.StatsAutoRecalc)
+	}
+	if options.StatsSamplePages == nil || *options.StatsSamplePages != 25 {
+		t.Errorf("Expected STATS_SAMPLE_PAGES=25, got %v", options.StatsSamplePages)
+	}
+}
+
+func TestStorageTableOptions(t *testing.T) {
+	sql := `CREATE TABLE test (id INT) ENGINE=InnoDB ROW_FORMAT=COMPRESSED KEY_BLOCK_SIZE=8 ENCRYPTION='Y'
+		COMPRESSION='zlib' MAX_ROWS=1000000 MIN_ROWS=10 TABLESPACE ts1 STORAGE DISK
+		DATA DIRECTORY='/data' INDEX DIRECTORY='/index' COMMENT='archive'`
+
+	tables, err := ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+
+	options := tables[0].TableOptions
+	if options.RowFormat == nil || *options.RowFormat != "COMPRESSED" {
+		t.Errorf("Expected ROW_FORMAT=COMPRESSED, got %v", options.RowFormat)
+	}
+	if options.KeyBlockSize == nil || *options.KeyBlockSize != 8 {
+		t.Errorf("Expected KEY_BLOCK_SIZE=8, got %v", options.KeyBlockSize)
+	}
+	if options.Encryption == nil || *options.Encryption != "Y" {
+		t.Errorf("Expected ENCRYPTION='Y', got %v", options.Encryption)
+	}
+	if options.Compression == nil || *options.Compression != "zlib" {
+		t.Errorf("Expected COMPRESSION='zlib', got %v", options.Compression)
+	}
+	if options.MaxRows == nil || *options.MaxRows != 1000000 {
+		t.Errorf("Expected MAX_ROWS=1000000, got %v", options.MaxRows)
+	}
+	if options.MinRows == nil || *options.MinRows != 10 {
+		t.Errorf("Expected MIN_ROWS=10, got %v", options.MinRows)
+	}
+	if options.Tablespace == nil || *options.Tablespace != "ts1" {
+		t.Errorf("Expected TABLESPACE ts1, got %v", options.Tablespace)
+	}
+	if options.DataDirectory == nil || *options.DataDirectory != "/data" {
+		t.Errorf("Expected DATA DIRECTORY='/data', got %v", options.DataDirectory)
+	}
+	if options.IndexDirectory == nil || *options.IndexDirectory != "/index" {
+		t.Errorf("Expected INDEX DIRECTORY='/index', got %v", options.IndexDirectory)
+	}
+	if options.Comment == nil || *options.Comment != "'archive'" {
+		t.Errorf("Expected the options after TABLESPACE to be parsed, got comment %v", options.Comment)
+	}
+}
+
+func TestMergeTableOptions(t *testing.T) {
+	sql := "CREATE TABLE total (id INT) ENGINE=MERGE UNION=(t1, `t2`) INSERT_METHOD=last ROW_FORMAT=DEFAULT"
+
+	tables, err := ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+
+	options := tables[0].TableOptions
+	if len(options.Union) != 2 || options.Union[0] != "t1" || options.Union[1] != "t2" {
+		t.Errorf("Expected UNION=(t1, t2), got %v", options.Union)
+	}
+	if options.InsertMethod == nil || *options.InsertMethod != "LAST" {
+		t.Errorf("Expected INSERT_METHOD=LAST, got %v", options.InsertMethod)
+	}
+	if options.RowFormat != nil {
+		t.Errorf("Expected ROW_FORMAT=DEFAULT to leave option unset, got %v", *options.RowFormat)
+	}
+}
+
+func TestIndexAlgorithmAndLock(t *testing.T) {
+	sql := `CREATE TABLE test (
+		id INT,
+		email VARCHAR(100),
+		INDEX idx_email (email) ALGORITHM=INPLACE LOCK=NONE,
+		UNIQUE KEY uk_id (id) ALGORITHM = copy
+	)`
+
+	tables, err := ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+
+	indexes := tables[0].Indexes
+	if len(indexes) != 2 {
+		t.Fatalf("Expected 2 indexes, got %d", len(indexes))
+	}
+	if indexes[0].Algorithm == nil || *indexes[0].Algorithm != "INPLACE" {
+		t.Errorf("Expected ALGORITHM=INPLACE, got %v", indexes[0].Algorithm)
+	}
+	if indexes[0].Lock == nil || *indexes[0].Lock != "NONE" {
+		t.Errorf("Expected LOCK=NONE, got %v", indexes[0].Lock)
+	}
+	if indexes[1].Algorithm == nil || *indexes[1].Algorithm != "COPY" {
+		t.Errorf("Expected ALGORITHM=COPY, got %v", indexes[1].Algorithm)
+	}
+	if indexes[1].Lock != nil {
+		t.Errorf("Expected no LOCK, got %v", *indexes[1].Lock)
+	}
+}
+
+// TestIndexOptions tests that USING, KEY_BLOCK_SIZE, COMMENT, VISIBLE and WITH PARSER are parsed for inline indexes
+func TestIndexOptions(t *testing.T) {
+	sql := `CREATE TABLE test (
+		id INT,
+		email VARCHAR(100),
+		body TEXT,
+		location GEOMETRY NOT NULL,
+		PRIMARY KEY (id) USING BTREE COMMENT 'row id',
+		INDEX idx_email (email) USING BTREE KEY_BLOCK_SIZE=4 COMMENT 'lookup by email' INVISIBLE,
+		UNIQUE KEY uk_email (email) USING HASH VISIBLE,
+		FULLTEXT KEY ft_body (body) WITH PARSER ngram COMMENT 'search',
+		SPATIAL INDEX sp_location (location) KEY_BLOCK_SIZE 8
+	)`
+
+	tables, err := ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+	if len(tables) != 1 {
+		t.Fatalf("Expected 1 table, got %d", len(tables))
+	}
+
+	pk := tables[0].PrimaryKey
+	if pk == nil || pk.Using == nil || *pk.Using != "BTREE" || pk.Comment == nil || *pk.Comment != "row id" {
+		t.Errorf("Expected primary key USING BTREE COMMENT 'row id', got %+v", pk)
+	}
+
+	indexes := tables[0].Indexes
+	if len(indexes) != 4 {
+		t.Fatalf("Expected 4 indexes, got %d", len(indexes))
+	}
+
+	idx := indexes[0]
+	if idx.Using == nil || *idx.Using != "BTREE" {
+		t.Errorf("Expected USING BTREE, got %v", idx.Using)
+	}
+	if idx.KeyBlockSize == nil || *idx.KeyBlockSize != 4 {
+		t.Errorf("Expected KEY_BLOCK_SIZE=4, got %v", idx.KeyBlockSize)
+	}
+	if idx.Comment == nil || *idx.Comment != "lookup by email" {
+		t.Errorf("Expected COMMENT 'lookup by email', got %v", idx.Comment)
+	}
+	if idx.Visible == nil || *idx.Visible {
+		t.Errorf("Expected INVISIBLE, got %v", idx.Visible)
+	}
+
+	if idx := indexes[1]; idx.Using == nil || *idx.Using != "HASH" || idx.Visible == nil || !*idx.Visible {
+		t.Errorf("Expected USING HASH VISIBLE, got %+v", idx)
+	}
+	if idx := indexes[2]; idx.Parser == nil || *idx.Parser != "ngram" || idx.Comment == nil || *idx.Comment != "search" {
+		t.Errorf("Expected WITH PARSER ngram COMMENT 'search', got %+v", idx)
+	}
+	if idx := indexes[3]; idx.KeyBlockSize == nil || *idx.KeyBlockSize != 8 {
+		t.Errorf("Expected KEY_BLOCK_SIZE 8, got %v", idx.KeyBlockSize)
+	}
+}
+
+func TestForeignKeyReferentialActions(t *testing.T) {
+	tests := []struct {
+		clause   string
+		onDelete string
+		onUpdate string
+	}{
+		{"ON DELETE SET NULL", "SET NULL", ""},
+		{"ON DELETE NO ACTION ON UPDATE CASCADE", "NO ACTION", "CASCADE"},
+		{"ON UPDATE SET DEFAULT", "", "SET DEFAULT"},
+		{"ON DELETE RESTRICT ON UPDATE RESTRICT", "RESTRICT", "RESTRICT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.clause, func(t *testing.T) {
+			sql := "CREATE TABLE orders (id INT, user_id INT, FOREIGN KEY (user_id) REFERENCES users (id) " + tt.clause + ", note INT)"
+			tables, err := ParseSQLDump(sql)
+			if err != nil {
+				t.Fatalf("ParseSQLDump failed: %v", err)
+			}
+			if len(tables) != 1 {
+				t.Fatalf("Expected 1 table, got %d", len(tables))
+			}
+			if len(tables[0].Columns) != 3 {
+				t.Errorf("Expected parsing to continue after the foreign key, got %d columns", len(tables[0].Columns))
+			}
+
+			ref := tables[0].ForeignKeys[0].Reference
+			if got := valueOrEmpty(ref.OnDelete); got != tt.onDelete {
+				t.Errorf("Expected ON DELETE %q, got %q", tt.onDelete, got)
+			}
+			if got := valueOrEmpty(ref.OnUpdate); got != tt.onUpdate {
+				t.Errorf("Expected ON UPDATE %q, got %q", tt.onUpdate, got)
+			}
+		})
+	}
+}
+
+func valueOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// TestNormalizeInlinePrimaryKey tests that an inline PRIMARY KEY parses like the table-level form
+func TestNormalizeInlinePrimaryKey(t *testing.T) {
+	parse := func(sql string, normalize bool) *CreateTableStatement {
+		t.Helper()
+		p := NewMySQLCreateTableParser(NewMySQLLexer(sql).Tokenize())
+		p.NormalizeInlinePrimaryKey = normalize
+		table, err := p.Parse()
+		if err != nil {
+			t.Fatalf("Parse failed for %q: %v", sql, err)
+		}
+		return table
+	}
+
+	inline := "CREATE TABLE users (id INT PRIMARY KEY, name VARCHAR(50))"
+
+	table := parse(inline, false)
+	if table.PrimaryKey != nil || !table.Columns[0].PrimaryKey {
+		t.Error("Expected the inline PRIMARY KEY to stay on the column without normalization")
+	}
+
+	table = parse(inline, true)
+	if table.Columns[0].PrimaryKey {
+		t.Error("Expected the column PRIMARY KEY flag to be cleared")
+	}
+	if table.PrimaryKey == nil || len(table.PrimaryKey.Columns) != 1 || table.PrimaryKey.Columns[0].Name != "id" {
+		t.Fatalf("Expected table-level PRIMARY KEY (id), got %+v", table.PrimaryKey)
+	}
+
+	tableLevel := parse("CREATE TABLE users (id INT, name VARCHAR(50), PRIMARY KEY (id))", true)
+	if !reflect.DeepEqual(table.PrimaryKey, tableLevel.PrimaryKey) || !reflect.DeepEqual(table.Columns, tableLevel.Columns) {
+		t.Errorf("Expected inline and table-level primary keys to parse the same, got %+v and %+v", table.PrimaryKey, tableLevel.PrimaryKey)
+	}
+}
+
+func TestNamedConstraints(t *testing.T) {
+	sql := `CREATE TABLE orders (
+		id INT,
+		user_id INT,
+		code VARCHAR(10),
+		total INT,
+		CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users (id),
+		CONSTRAINT uk_code UNIQUE KEY (code),
+		CONSTRAINT chk_total CHECK (total >= 0) NOT ENFORCED,
+		CONSTRAINT chk_code CHECK (code <> '') ENFORCED,
+		CHECK (id > 0)
+	);`
+
+	tables, err := ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+	table := tables[0]
+
+	if len(table.ForeignKeys) != 1 || table.ForeignKeys[0].Name == nil || *table.ForeignKeys[0].Name != "fk_orders_user" {
+		t.Errorf("Expected foreign key named fk_orders_user, got %+v", table.ForeignKeys)
+	}
+	if len(table.Indexes) != 1 || table.Indexes[0].Name == nil || *table.Indexes[0].Name != "uk_code" {
+		t.Errorf("Expected unique key named after its constraint, got %+v", table.Indexes)
+	}
+
+	if len(table.CheckConstraints) != 3 {
+		t.Fatalf("Expected 3 check constraints, got %d", len(table.CheckConstraints))
+	}
+	checks := table.CheckConstraints
+	if checks[0].Name == nil || *checks[0].Name != "chk_total" || checks[0].Enforced == nil || *checks[0].Enforced {
+		t.Errorf("Expected NOT ENFORCED check chk_total, got %+v", checks[0])
+	}
+	if checks[1].Name == nil || *checks[1].Name != "chk_code" || checks[1].Enforced == nil || !*checks[1].Enforced {
+		t.Errorf("Expected ENFORCED check chk_code, got %+v", checks[1])
+	}
+	if checks[2].Name != nil || checks[2].Enforced != nil || checks[2].Expression != "id > 0" {
+		t.Errorf("Expected unnamed check (id > 0), got %+v", checks[2])
+	}
+}
+
+func TestParseFullDumpWithVersionedStatements(t *testing.T) {
+	sql := "-- MySQL dump 10.13  Distrib 8.0.36, for Linux (x86_64)\n" +
+		"/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;\n" +
+		"/*!50503 SET NAMES utf8mb4 */;\n" +
+		"/*!40014 SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0 */;\n" +
+		"DROP TABLE IF EXISTS `users`;\n" +
+		"/*!40101 SET @saved_cs_client     = @@character_set_client */;\n" +
+		"/*!50503 SET character_set_client = utf8mb4 */;\n" +
+		"CREATE TABLE `users` (\n" +
+		"  `id` int NOT NULL AUTO_INCREMENT,\n" +
+		"  `name` varchar(100) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`)\n" +
+		") ENGINE=InnoDB AUTO_INCREMENT=3 DEFAULT CHARSET=utf8mb4;\n" +
+		"/*!40101 SET character_set_client = @saved_cs_client */;\n" +
+		"\n" +
+		"LOCK TABLES `users` WRITE;\n" +
+		"/*!40000 ALTER TABLE `users` DISABLE KEYS */;\n" +
+		"INSERT INTO `users` VALUES (1,'CREATE TABLE fake (a INT);'),(2,'it''s; (unbalanced');\n" +
+		"/*!40000 ALTER TABLE `users` ENABLE KEYS */;\n" +
+		"UNLOCK TABLES;\n" +
+		"\n" +
+		"CREATE TABLE `posts` (`id` int NOT NULL, `user_id` int NOT NULL, PRIMARY KEY (`id`));\n" +
+		"/*!40014 SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS */;\n"
+
+	tables, errs, err := ParseSQLDumpTolerant(sql, 0)
+	if err != nil || len(errs) > 0 {
+		t.Fatalf("Expected the dump to parse without errors, got %v %v", err, errs)
+	}
+
+	if len(tables) != 2 || tables[0].TableName != "users" || tables[1].TableName != "posts" {
+		t.Fatalf("Expected tables users and posts, got %v", tables)
+	}
+	if len(tables[0].Columns) != 2 || tables[0].PrimaryKey == nil {
+		t.Errorf("Expected users to keep its columns and primary key, got %+v", tables[0])
+	}
+	if len(tables[1].Columns) != 2 {
+		t.Errorf("Expected posts to have 2 columns after the data section, got %d", len(tables[1].Columns))
+	}
+}
+
+func TestParseDumpSkipsDataStatements(t *testing.T) {
+	sql := `CREATE TABLE users (id INT NOT NULL, name VARCHAR(50), PRIMARY KEY (id));
+LOCK TABLES users WRITE;
+INSERT INTO users VALUES (1,'Alice'),(2,'Bob; (the builder)'),(3,'CREATE TABLE nope (x INT)');
+INSERT INTO users (id, name) VALUES (4, NULL);
+UNLOCK TABLES;
+CREATE TABLE posts (id INT NOT NULL, title VARCHAR(200), PRIMARY KEY (id));
+INSERT INTO posts VALUES (1,'Hello');`
+
+	tables, err := ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+	if len(tables) != 2 {
+		t.Fatalf("Expected 2 tables, got %d", len(tables))
+	}
+	for i, name := range []string{"users", "posts"} {
+		if tables[i].TableName != name || len(tables[i].Columns) != 2 || tables[i].PrimaryKey == nil {
+			t.Errorf("Expected table %s with 2 columns and a primary key, got %+v", name, tables[i])
+		}
+	}
+}
+
+func TestColumnPositions(t *testing.T) {
+	sql := `CREATE TABLE users (
+		id INT FIRST,
+		name VARCHAR(50) NOT NULL AFTER id,
+		email VARCHAR(100) AFTER ` + "`name`" + `,
+		after INT
+	);`
+
+	tables, err := ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+	columns := tables[0].Columns
+	if len(columns) != 4 {
+		t.Fatalf("Expected 4 columns, got %d", len(columns))
+	}
+
+	if columns[0].Position == nil || !columns[0].Position.First {
+		t.Errorf("Expected id to be placed FIRST, got %+v", columns[0].Position)
+	}
+	if columns[1].Position == nil || columns[1].Position.After != "id" {
+		t.Errorf("Expected name to be placed AFTER id, got %+v", columns[1].Position)
+	}
+	if columns[1].Nullable == nil || *columns[1].Nullable {
+		t.Error("Expected name to stay NOT NULL")
+	}
+	if columns[2].Position == nil || columns[2].Position.After != "name" {
+		t.Errorf("Expected email to be placed AFTER name, got %+v", columns[2].Position)
+	}
+	if columns[3].Name != "after" || columns[3].Position != nil {
+		t.Errorf("Expected column named after without position, got %+v", columns[3])
+	}
+}
+
+func TestColumnCheckConstraints(t *testing.T) {
+	sql := `CREATE TABLE people (
+		age INT CHECK (age >= 0),
+		score INT NOT NULL CONSTRAINT chk_score CHECK (score BETWEEN 0 AND 100) NOT ENFORCED COMMENT 'points',
+		level INT CHECK (level > 0) CHECK (level < 10) ENFORCED,
+		CONSTRAINT chk_table CHECK (age < 150)
+	);`
+
+	tables, err := ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+	table := tables[0]
+	if len(table.Columns) != 3 {
+		t.Fatalf("Expected 3 columns, got %d", len(table.Columns))
+	}
+
+	age := table.Columns[0]
+	if len(age.Checks) != 1 || age.Checks[0].Name != nil || age.Checks[0].Expression != "age >= 0" || age.Checks[0].Enforced != nil {
+		t.Errorf("Expected an anonymous check on age, got %+v", age.Checks)
+	}
+
+	score := table.Columns[1]
+	if len(score.Checks) != 1 || score.Checks[0].Name == nil || *score.Checks[0].Name != "chk_score" {
+		t.Fatalf("Expected the named check chk_score on score, got %+v", score.Checks)
+	}
+	if score.Checks[0].Enforced == nil || *score.Checks[0].Enforced {
+		t.Error("Expected chk_score to be NOT ENFORCED")
+	}
+	if score.Comment == nil || *score.Comment != "'points'" || score.Nullable == nil || *score.Nullable {
+		t.Error("Expected the attributes around the check to be kept")
+	}
+
+	level := table.Columns[2]
+	if len(level.Checks) != 2 || level.Checks[1].Expression != "level < 10" || level.Checks[1].Enforced == nil || !*level.Checks[1].Enforced {
+		t.Errorf("Expected two checks on level, got %+v", level.Checks)
+	}
+
+	if len(table.CheckConstraints) != 1 || *table.CheckConstraints[0].Name != "chk_table" {
+		t.Errorf("Expected the table-level check to stay separate, got %+v", table.CheckConstraints)
+	}
+}
+
+func TestDataTypeSynonyms(t *testing.T) {
+	sql := `CREATE TABLE measures (
+		id INTEGER,
+		total DEC(10,2),
+		tax NUMERIC(8,3),
+		rate REAL,
+		ratio DOUBLE PRECISION(10,4) UNSIGNED,
+		active BOOL,
+		amount FIXED(6,2)
+	);`
+
+	tables, err := ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+
+	// Synonyms keep the spelling of the source; the diff analyzer canonicalizes them
+	expected := []string{"INTEGER", "DEC", "NUMERIC", "REAL", "DOUBLE PRECISION", "BOOL", "FIXED"}
+	columns := tables[0].Columns
+	if len(columns) != len(expected) {
+		t.Fatalf("Expected %d columns, got %d", len(expected), len(columns))
+	}
+	for i, name := range expected {
+		if columns[i].DataType.Name != name {
+			t.Errorf("Expected type %s for %s, got %s", name, columns[i].Name, columns[i].DataType.Name)
+		}
+	}
+	if ratio := columns[4].DataType; len(ratio.Parameters) != 2 || !ratio.Unsigned {
+		t.Errorf("Expected DOUBLE PRECISION(10,4) UNSIGNED, got %+v", ratio)
+	}
+}
+
+func TestSerialDataType(t *testing.T) {
+	tables, err := ParseSQLDump("CREATE TABLE orders (id SERIAL PRIMARY KEY, serial VARCHAR(20), KEY idx_serial (serial));")
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+	if len(tables) != 1 || len(tables[0].Columns) != 2 {
+		t.Fatalf("Expected 1 table with 2 columns, got %+v", tables)
+	}
+
+	// SERIAL expands to the attributes MySQL stores for it
+	id := tables[0].Columns[0]
+	if id.DataType.Name != "BIGINT" || !id.DataType.Unsigned || id.Nullable == nil || *id.Nullable ||
+		!id.AutoIncrement || !id.Unique || !id.PrimaryKey {
+		t.Errorf("Expected BIGINT UNSIGNED NOT NULL AUTO_INCREMENT UNIQUE PRIMARY KEY, got %+v", id)
+	}
+
+	// serial stays usable as a column name
+	if column := tables[0].Columns[1]; column.Name != "serial" || column.DataType.Name != "VARCHAR" {
+		t.Errorf("Expected a VARCHAR column named serial, got %+v", column)
+	}
+}
+
+func TestRawSQL(t *testing.T) {
+	users := "CREATE TABLE `users` (\n" +
+		"  `id` int NOT NULL,\n" +
+		"  `name` varchar(100) COMMENT 'имя; «name»',\n" +
+		"  PRIMARY KEY (`id`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;"
+	posts := "CREATE TABLE posts (id INT, title VARCHAR(200))"
+	sql := "-- schema\n/*!40101 SET NAMES utf8mb4 */;\n" + users + "\n\nDROP TABLE IF EXISTS posts;\n" + posts + "\n"
+
+	tables, err := ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+	if len(tables) != 2 {
+		t.Fatalf("Expected 2 tables, got %d", len(tables))
+	}
+	if tables[0].RawSQL != users {
+		t.Errorf("Expected RawSQL %q, got %q", users, tables[0].RawSQL)
+	}
+	// A statement without a terminating semicolon ends at the end of the dump
+	if tables[1].RawSQL != posts {
+		t.Errorf("Expected RawSQL %q, got %q", posts, tables[1].RawSQL)
+	}
+
+	tolerant, _, err := ParseSQLDumpTolerant(sql, 0)
+	if err != nil || len(tolerant) != 2 || tolerant[0].RawSQL != users {
+		t.Errorf("Expected ParseSQLDumpTolerant to keep RawSQL, got %v %v", tolerant, err)
+	}
+}
+
+func TestByteOrderMark(t *testing.T) {
+	table := "CREATE TABLE users (id INT, name VARCHAR(50));"
+	sql := "\uFEFF" + table
+
+	tables, err := ParseSQLDump(sql)
+	if err != nil || len(tables) != 1 {
+		t.Fatalf("Expected 1 table, got %v %v", tables, err)
+	}
+	if tables[0].TableName != "users" || tables[0].RawSQL != table {
+		t.Errorf("Unexpected table %q with RawSQL %q", tables[0].TableName, tables[0].RawSQL)
+	}
+
+	tolerant, errs, err := ParseSQLDumpTolerant(sql, 0)
+	if err != nil || len(errs) != 0 || len(tolerant) != 1 || tolerant[0].RawSQL != table {
+		t.Errorf("Expected ParseSQLDumpTolerant to skip the byte order mark, got %v %v %v", tolerant, errs, err)
+	}
+
+	// The first token after the byte order mark starts at position 0
+	tokens := NewMySQLLexer("\uFEFFCREATE").Tokenize()
+	if tokens[0].Type != CREATE || tokens[0].Position != 0 {
+		t.Errorf("Expected CREATE at position 0, got %+v", tokens[0])
+	}
+}
+
+func TestDecodeSQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		encoding string
+		expected string
+	}{
+		{"utf-8 with byte order mark", []byte("\xEF\xBB\xBFCREATE TABLE t (c INT)"), "", "CREATE TABLE t (c INT)"},
+		{"latin1", []byte("COMMENT 'caf\xE9 \x80'"), "latin1", "COMMENT 'café €'"},
+		{"iso-8859-1", []byte("caf\xE9 \x80"), "ISO-8859-1", "café \u0080"},
+		{"utf-16le with byte order mark", []byte("\xFF\xFEC\x00\xE9\x00"), "utf-16", "Cé"},
+		{"utf-16be with byte order mark", []byte("\xFE\xFF\x00C\x00\xE9"), "utf-16", "Cé"},
+		{"utf-16be without byte order mark", []byte("\x00C\x00\xE9"), "utf-16be", "Cé"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := DecodeSQL(tt.data, tt.encoding)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+
+	if _, err := DecodeSQL([]byte("x"), "ebcdic"); err == nil {
+		t.Error("Expected an error for an unsupported encoding")
+	}
+	if _, err := DecodeSQL([]byte("x"), "utf-16"); err == nil {
+		t.Error("Expected an error for an odd number of UTF-16 bytes")
+	}
+}
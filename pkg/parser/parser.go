@@ -11,6 +11,10 @@ type MySQLCreateTableParser struct {
 	tokens       []Token
 	pos          int
 	currentToken Token
+
+	// NormalizeInlinePrimaryKey moves a column-level PRIMARY KEY into the table-level
+	// primary key, so `id INT PRIMARY KEY` parses the same as `id INT, PRIMARY KEY (id)`
+	NormalizeInlinePrimaryKey bool
 }
 
 // NewMySQLCreateTableParser creates a new parser instance
@@ -70,8 +74,22 @@ func (p *MySQLCreateTableParser) consume(tokenType TokenType) (Token, error) {
 		p.advance()
 		return token, nil
 	}
-	return Token{}, fmt.Errorf("expected %s, got %s at line %d, column %d",
-		tokenType.String(), p.currentToken.Type.String(), p.currentToken.Line, p.currentToken.Column)
+	return Token{}, p.errorf("expected %s, got %s", tokenType.String(), p.currentToken.Type.String())
+}
+
+// errorf returns a ParseError positioned at the current token, or at the last token once input is exhausted
+func (p *MySQLCreateTableParser) errorf(format string, args ...any) *ParseError {
+	token := p.currentToken
+	if token.Type == EOF && len(p.tokens) > 0 {
+		last := p.tokens[len(p.tokens)-1]
+		token.Line, token.Column = last.Line, last.Column
+	}
+	return &ParseError{
+		Line:    token.Line,
+		Column:  token.Column,
+		Token:   token.Value,
+		Message: fmt.Sprintf(format, args...),
+	}
 }
 
 // Parse parses the tokens into a CREATE TABLE statement
@@ -148,18 +166,50 @@ func (p *MySQLCreateTableParser) parseCreateTable() (*CreateTableStatement, erro
 		stmt.PartitionOptions = partitionOptions
 	}
 
+	if p.NormalizeInlinePrimaryKey {
+		MoveInlinePrimaryKey(stmt)
+	}
+
 	return stmt, nil
 }
 
+// MoveInlinePrimaryKey replaces a single column-level PRIMARY KEY with the equivalent
+// table-level primary key. Tables that already declare a table-level primary key are left as is.
+func MoveInlinePrimaryKey(stmt *CreateTableStatement) {
+	if stmt.PrimaryKey != nil {
+		return
+	}
+
+	inline := -1
+	for i, column := range stmt.Columns {
+		if column.PrimaryKey {
+			if inline >= 0 {
+				return // more than one inline primary key is invalid, keep it visible
+			}
+			inline = i
+		}
+	}
+	if inline < 0 {
+		return
+	}
+
+	stmt.Columns[inline].PrimaryKey = false
+	stmt.PrimaryKey = &PrimaryKeyDefinition{
+		Columns: []IndexColumn{{Name: stmt.Columns[inline].Name}},
+	}
+}
+
 // parseTableElements parses the elements inside the CREATE TABLE parentheses
 func (p *MySQLCreateTableParser) parseTableElements(stmt *CreateTableStatement) error {
 	for !p.match(RPAREN) {
+		// CONSTRAINT [symbol] names the foreign key, check or unique constraint that follows
+		var constraintName *string
 		if p.match(CONSTRAINT) {
-			// Handle named constraints
 			p.advance() // CONSTRAINT
-			// For now, skip to the actual constraint type
 			if p.match(IDENTIFIER) {
-				p.advance() // constraint name
+				name := p.currentToken.Value
+				constraintName = &name
+				p.advance()
 			}
 		}
 
@@ -174,6 +224,9 @@ func (p *MySQLCreateTableParser) parseTableElements(stmt *CreateTableStatement)
 			if err != nil {
 				return err
 			}
+			if index.Name == nil {
+				index.Name = constraintName
+			}
 			stmt.Indexes = append(stmt.Indexes, index)
 		} else if p.match(INDEX, KEY) {
 			index, err := p.parseIndex()
@@ -198,12 +251,16 @@ func (p *MySQLCreateTableParser) parseTableElements(stmt *CreateTableStatement)
 			if err != nil {
 				return err
 			}
+			if constraintName != nil {
+				foreignKey.Name = constraintName
+			}
 			stmt.ForeignKeys = append(stmt.ForeignKeys, foreignKey)
 		} else if p.match(CHECK) {
 			checkConstraint, err := p.parseCheckConstraint()
 			if err != nil {
 				return err
 			}
+			checkConstraint.Name = constraintName
 			stmt.CheckConstraints = append(stmt.CheckConstraints, checkConstraint)
 		} else {
 			// Column definition
@@ -214,6 +271,7 @@ func (p *MySQLCreateTableParser) parseTableElements(stmt *CreateTableStatement)
 			stmt.Columns = append(stmt.Columns, column)
 		}
 
+		// A trailing comma before the closing paren ends the loop at RPAREN
 		if p.match(COMMA) {
 			p.advance()
 		} else if !p.match(RPAREN) {
@@ -235,8 +293,7 @@ func (p *MySQLCreateTableParser) parseColumnDefinition() (ColumnDefinition, erro
 		nameToken = p.currentToken
 		p.advance()
 	} else {
-		return ColumnDefinition{}, fmt.Errorf("expected column name, got %s at line %d, column %d",
-			p.currentToken.Type.String(), p.currentToken.Line, p.currentToken.Column)
+		return ColumnDefinition{}, p.errorf("expected column name, got %s", p.currentToken.Type.String())
 	}
 
 	dataType, err := p.parseDataType()
@@ -249,6 +306,16 @@ func (p *MySQLCreateTableParser) parseColumnDefinition() (ColumnDefinition, erro
 		DataType: dataType,
 	}
 
+	// SERIAL is shorthand for BIGINT UNSIGNED NOT NULL AUTO_INCREMENT UNIQUE, which is how MySQL
+	// stores the column
+	if strings.EqualFold(dataType.Name, "SERIAL") {
+		nullable := false
+		column.DataType = DataType{Name: "BIGINT", Unsigned: true}
+		column.Nullable = &nullable
+		column.AutoIncrement = true
+		column.Unique = true
+	}
+
 	// Parse column attributes
 	for !p.match(COMMA, RPAREN, EOF) {
 		if p.match(NOT) {
@@ -266,9 +333,28 @@ func (p *MySQLCreateTableParser) parseColumnDefinition() (ColumnDefinition, erro
 			p.advance()
 			// Parse default value expression (can be multiple tokens)
 			defaultValue := ""
-			if p.match(STRING, NUMBER, NULL, TRUE, FALSE, IDENTIFIER) {
+			if p.match(IDENTIFIER) {
+				// Function defaults such as CURRENT_TIMESTAMP(6) or NOW() keep their argument list
+				defaultValue = p.currentToken.Value
+				p.advance()
+				if p.match(LPAREN) {
+					defaultValue += p.parseFunctionArguments()
+				}
+			} else if p.match(STRING, NUMBER, NULL, TRUE, FALSE) {
 				defaultValue = p.currentToken.Value
 				p.advance()
+			} else if (p.match(MINUS) || (p.match(OPERATOR) && p.currentToken.Value == "+")) && p.peek().Type == NUMBER {
+				// A sign separated from its number, e.g. DEFAULT - 1 or DEFAULT +1
+				defaultValue = p.currentToken.Value + p.peek().Value
+				p.advance()
+				p.advance()
+			} else if p.match(LPAREN) {
+				// Expression defaults keep their parentheses, e.g. (JSON_OBJECT())
+				expression, err := p.parseDefaultExpression()
+				if err != nil {
+					return ColumnDefinition{}, err
+				}
+				defaultValue = expression
 			}
 			column.DefaultValue = &defaultValue
 		} else if p.match(AUTO_INCREMENT) {
@@ -360,12 +446,44 @@ func (p *MySQLCreateTableParser) parseColumnDefinition() (ColumnDefinition, erro
 			p.advance()
 			if p.match(UPDATE) {
 				p.advance()
-				// Skip the ON UPDATE expression for now
-				// This is typically CURRENT_TIMESTAMP
+				// Typically CURRENT_TIMESTAMP, optionally with a fractional seconds precision
+				if p.match(IDENTIFIER) {
+					onUpdate := p.currentToken.Value
+					p.advance()
+					if p.match(LPAREN) {
+						onUpdate += p.parseFunctionArguments()
+					}
+					column.OnUpdate = &onUpdate
+				}
+			}
+		} else if p.match(CONSTRAINT, CHECK) {
+			// Inline CHECK constraint, optionally named by CONSTRAINT [symbol]
+			var constraintName *string
+			if p.match(CONSTRAINT) {
+				p.advance()
 				if p.match(IDENTIFIER) {
+					name := p.currentToken.Value
+					constraintName = &name
 					p.advance()
 				}
 			}
+			if p.match(CHECK) {
+				check, err := p.parseCheckConstraint()
+				if err != nil {
+					return ColumnDefinition{}, err
+				}
+				check.Name = constraintName
+				column.Checks = append(column.Checks, check)
+			}
+		} else if p.match(FIRST) {
+			p.advance()
+			column.Position = &ColumnPosition{First: true}
+		} else if p.match(AFTER) {
+			p.advance()
+			if p.match(IDENTIFIER) || p.isKeywordUsableAsIdentifier() {
+				column.Position = &ColumnPosition{After: p.currentToken.Value}
+				p.advance()
+			}
 		} else {
 			// Skip unknown attributes
 			p.advance()
@@ -382,13 +500,20 @@ func (p *MySQLCreateTableParser) parseDataType() (DataType, error) {
 	// Data type name
 	if !p.match(INT, TINYINT, SMALLINT, MEDIUMINT, BIGINT, VARCHAR, CHAR, TEXT,
 		DECIMAL, FLOAT, DOUBLE, DATE, DATETIME, TIMESTAMP, TIME, YEAR, BLOB,
-		JSON, ENUM, SET, BINARY, VARBINARY, BIT, BOOLEAN, GEOMETRY, POINT, LINESTRING, POLYGON) {
-		return dataType, fmt.Errorf("expected data type, got %s", p.currentToken.Type.String())
+		JSON, ENUM, SET, BINARY, VARBINARY, BIT, BOOLEAN, GEOMETRY, POINT, LINESTRING, POLYGON, FIXED) &&
+		!(p.match(IDENTIFIER) && isDataTypeSynonym(p.currentToken.Value)) {
+		return dataType, p.errorf("expected data type, got %s", p.currentToken.Type.String())
 	}
 
 	dataType.Name = p.currentToken.Value
 	p.advance()
 
+	// DOUBLE PRECISION is a two-word synonym of DOUBLE
+	if strings.EqualFold(dataType.Name, "DOUBLE") && p.match(IDENTIFIER) && strings.EqualFold(p.currentToken.Value, "PRECISION") {
+		dataType.Name += " " + p.currentToken.Value
+		p.advance()
+	}
+
 	// Parse parameters if present
 	if p.match(LPAREN) {
 		p.advance()
@@ -425,6 +550,15 @@ func (p *MySQLCreateTableParser) parseDataType() (DataType, error) {
 	return dataType, nil
 }
 
+// isDataTypeSynonym reports whether an identifier is a data type synonym without its own token
+func isDataTypeSynonym(name string) bool {
+	switch strings.ToUpper(name) {
+	case "INTEGER", "DEC", "NUMERIC", "REAL", "BOOL", "SERIAL":
+		return true
+	}
+	return false
+}
+
 // parsePrimaryKey parses a primary key definition
 func (p *MySQLCreateTableParser) parsePrimaryKey() (*PrimaryKeyDefinition, error) {
 	if _, err := p.consume(PRIMARY); err != nil {
@@ -488,6 +622,12 @@ func (p *MySQLCreateTableParser) parsePrimaryKey() (*PrimaryKeyDefinition, error
 		return nil, err
 	}
 
+	// USING and COMMENT are the primary key options with a field; the rest are consumed
+	var options IndexDefinition
+	p.parseIndexOptions(&options)
+	pk.Using = options.Using
+	pk.Comment = options.Comment
+
 	return pk, nil
 }
 
@@ -562,9 +702,84 @@ func (p *MySQLCreateTableParser) parseIndex() (IndexDefinition, error) {
 		return index, err
 	}
 
+	p.parseIndexOptions(&index)
+
 	return index, nil
 }
 
+// parseIndexOptions parses the options following an index column list: USING, KEY_BLOCK_SIZE,
+// COMMENT, VISIBLE/INVISIBLE, WITH PARSER, ENGINE_ATTRIBUTE, ALGORITHM and LOCK
+func (p *MySQLCreateTableParser) parseIndexOptions(index *IndexDefinition) {
+	for {
+		switch {
+		case p.match(IDENTIFIER) && strings.EqualFold(p.currentToken.Value, "USING"):
+			p.advance()
+			if !p.match(IDENTIFIER, HASH) {
+				return
+			}
+			using := strings.ToUpper(p.currentToken.Value)
+			index.Using = &using
+			p.advance()
+		case p.match(KEY_BLOCK_SIZE):
+			index.KeyBlockSize = p.parseNumericTableOption()
+		case p.match(COMMENT):
+			p.advance()
+			if !p.match(STRING) {
+				return
+			}
+			comment := unquote(p.currentToken.Value)
+			index.Comment = &comment
+			p.advance()
+		case p.match(VISIBLE, INVISIBLE):
+			visible := p.match(VISIBLE)
+			index.Visible = &visible
+			p.advance()
+		case p.match(WITH):
+			p.advance()
+			if !p.match(PARSER) {
+				return
+			}
+			p.advance()
+			if !p.match(IDENTIFIER) {
+				return
+			}
+			parserName := p.currentToken.Value
+			index.Parser = &parserName
+			p.advance()
+		case p.match(ENGINE_ATTRIBUTE):
+			p.advance()
+			if p.match(EQUALS) {
+				p.advance()
+			}
+			if !p.match(STRING) {
+				return
+			}
+			attribute := unquote(p.currentToken.Value)
+			index.EngineAttribute = &attribute
+			p.advance()
+		case p.match(ALGORITHM, LOCK):
+			isAlgorithm := p.match(ALGORITHM)
+			p.advance()
+			if p.match(EQUALS) {
+				p.advance()
+			}
+			if p.match(COMMA, RPAREN, EOF) {
+				return
+			}
+
+			value := strings.ToUpper(p.currentToken.Value)
+			if isAlgorithm {
+				index.Algorithm = &value
+			} else {
+				index.Lock = &value
+			}
+			p.advance()
+		default:
+			return
+		}
+	}
+}
+
 // parseUniqueIndex parses a unique index definition
 func (p *MySQLCreateTableParser) parseUniqueIndex() (IndexDefinition, error) {
 	if _, err := p.consume(UNIQUE); err != nil {
@@ -638,6 +853,8 @@ func (p *MySQLCreateTableParser) parseUniqueIndex() (IndexDefinition, error) {
 		return index, err
 	}
 
+	p.parseIndexOptions(&index)
+
 	return index, nil
 }
 
@@ -691,6 +908,8 @@ func (p *MySQLCreateTableParser) parseFulltextIndex() (IndexDefinition, error) {
 		return index, err
 	}
 
+	p.parseIndexOptions(&index)
+
 	return index, nil
 }
 
@@ -744,6 +963,8 @@ func (p *MySQLCreateTableParser) parseSpatialIndex() (IndexDefinition, error) {
 		return index, err
 	}
 
+	p.parseIndexOptions(&index)
+
 	return index, nil
 }
 
@@ -831,40 +1052,83 @@ func (p *MySQLCreateTableParser) parseForeignKey() (ForeignKeyDefinition, error)
 
 		if p.match(DELETE) {
 			p.advance()
-			if p.match(CASCADE) {
-				onDelete := "CASCADE"
-				fk.Reference.OnDelete = &onDelete
-				p.advance()
-			} else if p.match(RESTRICT) {
-				onDelete := "RESTRICT"
-				fk.Reference.OnDelete = &onDelete
-				p.advance()
-			} else if p.match(SET_NULL) {
-				onDelete := "SET NULL"
-				fk.Reference.OnDelete = &onDelete
-				p.advance()
-			}
+			fk.Reference.OnDelete = p.parseReferentialAction()
 		} else if p.match(UPDATE) {
 			p.advance()
-			if p.match(CASCADE) {
-				onUpdate := "CASCADE"
-				fk.Reference.OnUpdate = &onUpdate
-				p.advance()
-			} else if p.match(RESTRICT) {
-				onUpdate := "RESTRICT"
-				fk.Reference.OnUpdate = &onUpdate
-				p.advance()
-			} else if p.match(SET_NULL) {
-				onUpdate := "SET NULL"
-				fk.Reference.OnUpdate = &onUpdate
-				p.advance()
-			}
+			fk.Reference.OnUpdate = p.parseReferentialAction()
 		}
 	}
 
 	return fk, nil
 }
 
+// parseReferentialAction parses CASCADE, RESTRICT, SET NULL, SET DEFAULT or NO ACTION
+func (p *MySQLCreateTableParser) parseReferentialAction() *string {
+	var action string
+
+	switch {
+	case p.match(CASCADE):
+		action = "CASCADE"
+	case p.match(RESTRICT):
+		action = "RESTRICT"
+	case p.match(SET_NULL):
+		action = "SET NULL"
+	case p.match(SET) && p.peek().Type == NULL:
+		p.advance()
+		action = "SET NULL"
+	case p.match(SET) && p.peek().Type == DEFAULT:
+		p.advance()
+		action = "SET DEFAULT"
+	case p.match(NO) && p.peek().Type == ACTION:
+		p.advance()
+		action = "NO ACTION"
+	default:
+		return nil
+	}
+
+	p.advance()
+	return &action
+}
+
+// parseFunctionArguments consumes a parenthesized argument list and returns it without spaces, e.g. "(6)"
+func (p *MySQLCreateTableParser) parseFunctionArguments() string {
+	arguments := ""
+	parenCount := 0
+	for !p.match(EOF) {
+		if p.match(LPAREN) {
+			parenCount++
+		} else if p.match(RPAREN) {
+			parenCount--
+		}
+		arguments += p.currentToken.Value
+		p.advance()
+		if parenCount == 0 {
+			break
+		}
+	}
+	return arguments
+}
+
+// parseDefaultExpression consumes a balanced parenthesized default expression and returns it
+// including the outer parentheses
+func (p *MySQLCreateTableParser) parseDefaultExpression() (string, error) {
+	var tokens []Token
+	depth := 0
+	for !p.match(EOF) {
+		if p.match(LPAREN) {
+			depth++
+		} else if p.match(RPAREN) {
+			depth--
+		}
+		tokens = append(tokens, p.currentToken)
+		p.advance()
+		if depth == 0 {
+			return joinExpressionTokens(tokens), nil
+		}
+	}
+	return "", p.errorf("unterminated default expression")
+}
+
 // parseCheckConstraint parses a check constraint
 func (p *MySQLCreateTableParser) parseCheckConstraint() (CheckConstraint, error) {
 	if _, err := p.consume(CHECK); err != nil {
@@ -893,6 +1157,19 @@ func (p *MySQLCreateTableParser) parseCheckConstraint() (CheckConstraint, error)
 	}
 
 	check.Expression = strings.TrimSpace(expression)
+	check.NormalizedExpression = NormalizeExpression(check.Expression)
+
+	// [NOT] ENFORCED
+	if p.match(NOT) && p.peek().Type == IDENTIFIER && strings.EqualFold(p.peek().Value, "ENFORCED") {
+		p.advance()
+		p.advance()
+		enforced := false
+		check.Enforced = &enforced
+	} else if p.match(IDENTIFIER) && strings.EqualFold(p.currentToken.Value, "ENFORCED") {
+		p.advance()
+		enforced := true
+		check.Enforced = &enforced
+	}
 
 	return check, nil
 }
@@ -924,17 +1201,17 @@ func (p *MySQLCreateTableParser) parseTableOptions() (*TableOptions, error) {
 				p.advance()
 			}
 		} else if p.match(DEFAULT) {
+			// DEFAULT is an optional prefix for CHARSET, CHARACTER SET and COLLATE
 			p.advance()
-			if p.match(CHARSET) {
+		} else if p.match(CHARSET) {
+			p.advance()
+			if p.match(EQUALS) {
+				p.advance()
+			}
+			if p.match(IDENTIFIER) {
+				charset := p.currentToken.Value
+				options.CharacterSet = &charset
 				p.advance()
-				if p.match(EQUALS) {
-					p.advance()
-				}
-				if p.match(IDENTIFIER) {
-					charset := p.currentToken.Value
-					options.CharacterSet = &charset
-					p.advance()
-				}
 			}
 		} else if p.match(CHARACTER) {
 			p.advance()
@@ -969,6 +1246,78 @@ func (p *MySQLCreateTableParser) parseTableOptions() (*TableOptions, error) {
 				options.Comment = &comment
 				p.advance()
 			}
+		} else if p.match(CONNECTION) {
+			p.advance()
+			if p.match(EQUALS) {
+				p.advance()
+			}
+			if p.match(STRING) {
+				connection := p.currentToken.Value
+				options.Connection = &connection
+				p.advance()
+			}
+		} else if p.match(PACK_KEYS) {
+			options.PackKeys = p.parseNumericTableOption()
+		} else if p.match(CHECKSUM) {
+			options.Checksum = p.parseNumericTableOption()
+		} else if p.match(DELAY_KEY_WRITE) {
+			options.DelayKeyWrite = p.parseNumericTableOption()
+		} else if p.match(AVG_ROW_LENGTH) {
+			options.AvgRowLength = p.parseNumericTableOption()
+		} else if p.match(AUTOEXTEND_SIZE) {
+			options.AutoextendSize = p.parseSizeTableOption()
+		} else if p.match(STATS_PERSISTENT) {
+			options.StatsPersistent = p.parseNumericTableOption()
+		} else if p.match(STATS_AUTO_RECALC) {
+			options.StatsAutoRecalc = p.parseNumericTableOption()
+		} else if p.match(STATS_SAMPLE_PAGES) {
+			options.StatsSamplePages = p.parseNumericTableOption()
+		} else if p.match(KEY_BLOCK_SIZE) {
+			options.KeyBlockSize = p.parseNumericTableOption()
+		} else if p.match(MAX_ROWS) {
+			options.MaxRows = p.parseNumericTableOption()
+		} else if p.match(MIN_ROWS) {
+			options.MinRows = p.parseNumericTableOption()
+		} else if p.match(ROW_FORMAT) {
+			options.RowFormat = p.parseKeywordTableOption()
+		} else if p.match(INSERT_METHOD) {
+			options.InsertMethod = p.parseKeywordTableOption()
+		} else if p.match(TABLESPACE) {
+			// TABLESPACE takes no equals sign; a trailing STORAGE {DISK|MEMORY} is skipped as unknown
+			p.advance()
+			if p.match(IDENTIFIER) || p.isKeywordUsableAsIdentifier() {
+				tablespace := p.currentToken.Value
+				options.Tablespace = &tablespace
+				p.advance()
+			}
+		} else if p.match(COMPRESSION) {
+			options.Compression = p.parseStringTableOption()
+		} else if p.match(ENCRYPTION) {
+			options.Encryption = p.parseStringTableOption()
+		} else if p.match(DATA) && p.peek().Type == DIRECTORY {
+			p.advance()
+			options.DataDirectory = p.parseStringTableOption()
+		} else if p.match(INDEX) && p.peek().Type == DIRECTORY {
+			p.advance()
+			options.IndexDirectory = p.parseStringTableOption()
+		} else if p.match(UNION) {
+			p.advance()
+			if p.match(EQUALS) {
+				p.advance()
+			}
+			if _, err := p.consume(LPAREN); err != nil {
+				return nil, err
+			}
+			for p.match(IDENTIFIER) || p.isKeywordUsableAsIdentifier() {
+				options.Union = append(options.Union, p.currentToken.Value)
+				p.advance()
+				if p.match(COMMA) {
+					p.advance()
+				}
+			}
+			if _, err := p.consume(RPAREN); err != nil {
+				return nil, err
+			}
 		} else {
 			// Skip unknown options
 			p.advance()
@@ -978,6 +1327,83 @@ func (p *MySQLCreateTableParser) parseTableOptions() (*TableOptions, error) {
 	return options, nil
 }
 
+// parseSizeTableOption parses an OPTION [=] size table option, where size is a number
+// with an optional K, M or G suffix (e.g. AUTOEXTEND_SIZE=4M). The suffix is stored upper-cased.
+func (p *MySQLCreateTableParser) parseSizeTableOption() *string {
+	p.advance()
+	if p.match(EQUALS) {
+		p.advance()
+	}
+	if !p.match(NUMBER) {
+		return nil
+	}
+
+	size := p.currentToken.Value
+	p.advance()
+	if p.match(IDENTIFIER) {
+		if suffix := strings.ToUpper(p.currentToken.Value); suffix == "K" || suffix == "M" || suffix == "G" {
+			size += suffix
+			p.advance()
+		}
+	}
+	return &size
+}
+
+// parseStringTableOption parses an OPTION [=] 'string' table option, e.g. ENCRYPTION='Y'.
+// The value is stored without quotes.
+func (p *MySQLCreateTableParser) parseStringTableOption() *string {
+	p.advance()
+	if p.match(EQUALS) {
+		p.advance()
+	}
+	if !p.match(STRING) {
+		return nil
+	}
+	value := unquote(p.currentToken.Value)
+	p.advance()
+	return &value
+}
+
+// parseKeywordTableOption parses an OPTION [=] keyword table option, e.g. ROW_FORMAT=COMPRESSED.
+// The keyword is stored upper-cased; DEFAULT leaves the option unset.
+func (p *MySQLCreateTableParser) parseKeywordTableOption() *string {
+	p.advance()
+	if p.match(EQUALS) {
+		p.advance()
+	}
+	if p.match(DEFAULT) {
+		p.advance()
+		return nil
+	}
+	if !p.match(IDENTIFIER, NO) && !p.isKeywordUsableAsIdentifier() {
+		return nil
+	}
+	value := strings.ToUpper(p.currentToken.Value)
+	p.advance()
+	return &value
+}
+
+// parseNumericTableOption parses an OPTION [=] {number|DEFAULT} table option.
+// DEFAULT leaves the option unset.
+func (p *MySQLCreateTableParser) parseNumericTableOption() *int {
+	p.advance()
+	if p.match(EQUALS) {
+		p.advance()
+	}
+	if p.match(DEFAULT) {
+		p.advance()
+		return nil
+	}
+	if p.match(NUMBER) {
+		value, err := strconv.Atoi(p.currentToken.Value)
+		p.advance()
+		if err == nil {
+			return &value
+		}
+	}
+	return nil
+}
+
 // parsePartitionOptions parses partition options (simplified)
 func (p *MySQLCreateTableParser) parsePartitionOptions() (*PartitionOptions, error) {
 	if _, err := p.consume(PARTITION); err != nil {
@@ -989,27 +1415,108 @@ func (p *MySQLCreateTableParser) parsePartitionOptions() (*PartitionOptions, err
 
 	partOptions := &PartitionOptions{}
 
+	// LINEAR applies to HASH and KEY partitioning
+	if p.match(LINEAR) {
+		p.advance()
+		partOptions.Linear = true
+	}
+
 	if p.match(HASH) {
 		p.advance()
 		partOptions.Type = "HASH"
-		// Parse hash expression (simplified)
-		if p.match(LPAREN) {
+		expression, err := p.parseParenthesizedExpression()
+		if err != nil {
+			return nil, err
+		}
+		partOptions.Expression = &expression
+	} else if p.match(KEY) {
+		p.advance()
+		partOptions.Type = "KEY"
+		columns, err := p.parsePartitionColumns()
+		if err != nil {
+			return nil, err
+		}
+		partOptions.Columns = columns
+	} else if p.match(RANGE, LIST) {
+		partOptions.Type = strings.ToUpper(p.currentToken.Value)
+		p.advance()
+		if p.match(COLUMNS) {
 			p.advance()
-			// Skip to closing paren
-			for !p.match(RPAREN) && !p.match(EOF) {
-				p.advance()
+			columns, err := p.parsePartitionColumns()
+			if err != nil {
+				return nil, err
 			}
-			if _, err := p.consume(RPAREN); err != nil {
+			partOptions.Columns = columns
+		} else {
+			expression, err := p.parseParenthesizedExpression()
+			if err != nil {
 				return nil, err
 			}
+			partOptions.Expression = &expression
 		}
-	} else if p.match(RANGE) {
+	}
+
+	// PARTITIONS n
+	if p.match(PARTITIONS) {
 		p.advance()
-		partOptions.Type = "RANGE"
-		// Similar simplified parsing for other partition types
+		if p.match(NUMBER) {
+			if count, err := strconv.Atoi(p.currentToken.Value); err == nil {
+				partOptions.PartitionCount = &count
+			}
+			p.advance()
+		}
 	}
 
-	// Skip remaining partition details for now
+	// SUBPARTITION BY [LINEAR] {HASH(expr) | KEY(columns)} [SUBPARTITIONS n]
+	if p.match(SUBPARTITION) {
+		p.advance()
+		if _, err := p.consume(BY); err != nil {
+			return nil, err
+		}
+
+		if p.match(LINEAR) {
+			p.advance()
+			partOptions.SubPartitionLinear = true
+		}
+
+		if p.match(HASH) {
+			p.advance()
+			partOptions.SubPartitionType = "HASH"
+			expression, err := p.parseParenthesizedExpression()
+			if err != nil {
+				return nil, err
+			}
+			partOptions.SubPartitionExpression = &expression
+		} else if p.match(KEY) {
+			p.advance()
+			partOptions.SubPartitionType = "KEY"
+			columns, err := p.parsePartitionColumns()
+			if err != nil {
+				return nil, err
+			}
+			partOptions.SubPartitionColumns = columns
+		}
+
+		if p.match(SUBPARTITIONS) {
+			p.advance()
+			if p.match(NUMBER) {
+				if count, err := strconv.Atoi(p.currentToken.Value); err == nil {
+					partOptions.SubPartitionCount = &count
+				}
+				p.advance()
+			}
+		}
+	}
+
+	if p.match(LPAREN) {
+		partitions, err := p.parsePartitionDefinitions(partOptions.Type)
+		if err != nil {
+			return nil, err
+		}
+		partOptions.Partitions = partitions
+	}
+
+	// Skip anything after the partition definitions
 	for !p.match(EOF, SEMICOLON) {
 		p.advance()
 	}
@@ -1017,14 +1524,226 @@ func (p *MySQLCreateTableParser) parsePartitionOptions() (*PartitionOptions, err
 	return partOptions, nil
 }
 
+// parsePartitionDefinitions parses a parenthesized list of
+// PARTITION name [VALUES {LESS THAN {(values) | MAXVALUE} | IN (values)}] [options] [(subpartitions)].
+// Subpartition definitions are skipped.
+func (p *MySQLCreateTableParser) parsePartitionDefinitions(partitionType string) ([]PartitionDefinition, error) {
+	if _, err := p.consume(LPAREN); err != nil {
+		return nil, err
+	}
+
+	var partitions []PartitionDefinition
+	for p.match(PARTITION) {
+		p.advance()
+		if !p.match(IDENTIFIER) && !p.isKeywordUsableAsIdentifier() {
+			return nil, p.errorf("expected partition name, got %s", p.currentToken.Type.String())
+		}
+		partition := PartitionDefinition{Name: p.currentToken.Value, Type: partitionType}
+		p.advance()
+
+		if p.match(VALUES) {
+			p.advance()
+			if p.match(LESS) {
+				p.advance()
+				if _, err := p.consume(THAN); err != nil {
+					return nil, err
+				}
+				if p.match(MAXVALUE) {
+					partition.Values = []string{"MAXVALUE"}
+					p.advance()
+				} else {
+					values, err := p.parsePartitionValues()
+					if err != nil {
+						return nil, err
+					}
+					partition.Values = values
+				}
+			} else if p.match(IN) {
+				p.advance()
+				values, err := p.parsePartitionValues()
+				if err != nil {
+					return nil, err
+				}
+				partition.Values = values
+			} else {
+				return nil, p.errorf("expected LESS THAN or IN, got %s", p.currentToken.Type.String())
+			}
+		}
+
+		p.parsePartitionDefinitionOptions(&partition)
+
+		if p.match(LPAREN) {
+			if _, err := p.parseParenthesizedExpression(); err != nil {
+				return nil, err
+			}
+		}
+
+		partitions = append(partitions, partition)
+		if p.match(COMMA) {
+			p.advance()
+		} else {
+			break
+		}
+	}
+
+	if _, err := p.consume(RPAREN); err != nil {
+		return nil, err
+	}
+
+	return partitions, nil
+}
+
+// parsePartitionValues parses the parenthesized value list of VALUES LESS THAN or VALUES IN,
+// returning each top-level value as normalized expression text, e.g. TO_DAYS('2024-01-01') or (1, 'a')
+func (p *MySQLCreateTableParser) parsePartitionValues() ([]string, error) {
+	if _, err := p.consume(LPAREN); err != nil {
+		return nil, err
+	}
+
+	var values []string
+	var current []Token
+	depth := 0
+	for !p.match(EOF) {
+		if p.match(RPAREN) && depth == 0 {
+			break
+		}
+		if p.match(COMMA) && depth == 0 {
+			values = append(values, joinExpressionTokens(current))
+			current = nil
+			p.advance()
+			continue
+		}
+		if p.match(LPAREN) {
+			depth++
+		} else if p.match(RPAREN) {
+			depth--
+		}
+		current = append(current, p.currentToken)
+		p.advance()
+	}
+	if len(current) > 0 {
+		values = append(values, joinExpressionTokens(current))
+	}
+
+	if _, err := p.consume(RPAREN); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// parsePartitionDefinitionOptions parses the options of a partition definition:
+// [STORAGE] ENGINE, COMMENT, DATA DIRECTORY, INDEX DIRECTORY, MAX_ROWS, MIN_ROWS and TABLESPACE.
+// The engine is skipped, since MySQL requires all partitions to use the table's engine.
+func (p *MySQLCreateTableParser) parsePartitionDefinitionOptions(partition *PartitionDefinition) {
+	for {
+		switch {
+		case p.match(STORAGE, ENGINE):
+			if p.match(STORAGE) {
+				p.advance()
+			}
+			p.advance()
+			if p.match(EQUALS) {
+				p.advance()
+			}
+			if p.match(IDENTIFIER) {
+				p.advance()
+			}
+		case p.match(COMMENT):
+			partition.Comment = p.parseStringTableOption()
+		case p.match(DATA, INDEX) && p.peek().Type == DIRECTORY:
+			isData := p.match(DATA)
+			p.advance()
+			directory := p.parseStringTableOption()
+			if isData {
+				partition.DataDirectory = directory
+			} else {
+				partition.IndexDirectory = directory
+			}
+		case p.match(MAX_ROWS):
+			partition.MaxRows = p.parseNumericTableOption()
+		case p.match(MIN_ROWS):
+			partition.MinRows = p.parseNumericTableOption()
+		case p.match(TABLESPACE):
+			p.advance()
+			if p.match(EQUALS) {
+				p.advance()
+			}
+			if p.match(IDENTIFIER) || p.isKeywordUsableAsIdentifier() {
+				tablespace := p.currentToken.Value
+				partition.Tablespace = &tablespace
+				p.advance()
+			}
+		default:
+			return
+		}
+	}
+}
+
+// parsePartitionColumns parses a parenthesized, comma-separated column list used by KEY and COLUMNS partitioning
+func (p *MySQLCreateTableParser) parsePartitionColumns() ([]string, error) {
+	if _, err := p.consume(LPAREN); err != nil {
+		return nil, err
+	}
+
+	var columns []string
+	for !p.match(RPAREN, EOF) {
+		if p.match(IDENTIFIER) || p.isKeywordUsableAsIdentifier() {
+			columns = append(columns, p.currentToken.Value)
+			p.advance()
+		} else {
+			return nil, p.errorf("expected column name, got %s", p.currentToken.Type.String())
+		}
+
+		if p.match(COMMA) {
+			p.advance()
+		} else {
+			break
+		}
+	}
+
+	if _, err := p.consume(RPAREN); err != nil {
+		return nil, err
+	}
+
+	return columns, nil
+}
+
+// parseParenthesizedExpression reads a balanced parenthesized expression and returns its inner text
+func (p *MySQLCreateTableParser) parseParenthesizedExpression() (string, error) {
+	if _, err := p.consume(LPAREN); err != nil {
+		return "", err
+	}
+
+	expression := ""
+	parenCount := 1
+	for parenCount > 0 && !p.match(EOF) {
+		if p.match(LPAREN) {
+			parenCount++
+		} else if p.match(RPAREN) {
+			parenCount--
+		}
+		if parenCount > 0 {
+			expression += p.currentToken.Value + " "
+		}
+		p.advance()
+	}
+
+	if parenCount > 0 {
+		return "", p.errorf("unterminated expression")
+	}
+
+	return strings.TrimSpace(expression), nil
+}
+
 // isKeywordUsableAsIdentifier checks if the current token is a keyword that can be used as an identifier
 func (p *MySQLCreateTableParser) isKeywordUsableAsIdentifier() bool {
 	// List of keywords that can be used as column names in MySQL
 	allowedKeywords := []TokenType{
 		DATA, DIRECTORY, COMPRESSION, ENCRYPTION, TABLESPACE,
 		STATS_PERSISTENT, STATS_AUTO_RECALC, STATS_SAMPLE_PAGES,
-		PACK_KEYS, CHECKSUM, DELAY_KEY_WRITE, MEMORY, DISK,
-		FIXED, DYNAMIC, COMPRESSED, FIRST, LAST, ACTION,
+		PACK_KEYS, CHECKSUM, DELAY_KEY_WRITE, AVG_ROW_LENGTH, AUTOEXTEND_SIZE, CONNECTION, MEMORY, DISK,
+		FIXED, DYNAMIC, COMPRESSED, FIRST, LAST, AFTER, ACTION,
 	}
 
 	for _, keyword := range allowedKeywords {
@@ -0,0 +1,247 @@
+package parser
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// ParseSQLReader parses the CREATE TABLE statements of a SQL dump read from r.
+// The dump is split into statements while it is read and each CREATE statement is parsed on its own,
+// so only the statement being read is held in memory. Other statements, such as the INSERT INTO
+// statements of a full dump, are scanned up to their semicolon without being buffered.
+// Like ParseSQLDump, it returns a *StatementError for the first statement that cannot be parsed.
+func ParseSQLReader(r io.Reader) ([]*CreateTableStatement, error) {
+	var tables []*CreateTableStatement
+	var parseErr error
+
+	err := scanCreateStatements(r, func(statement string, line, column int) {
+		if parseErr != nil {
+			return
+		}
+		for _, stmt := range splitCreateTableStatements(statement) {
+			// The statement is lexed on its own; move its tokens to their position in the dump
+			for i := range stmt.tokens {
+				if stmt.tokens[i].Line == 1 {
+					stmt.tokens[i].Column += column - 1
+				}
+				stmt.tokens[i].Line += line - 1
+			}
+			table, err := NewMySQLCreateTableParser(stmt.tokens).Parse()
+			if err != nil {
+				parseErr = &StatementError{Line: stmt.tokens[0].Line, Err: err}
+				return
+			}
+			table.RawSQL = stmt.raw
+			tables = append(tables, table)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	return tables, nil
+}
+
+// scanCreateStatements reads SQL from r and calls yield with the text of every statement starting
+// with the CREATE keyword and the line and column of that keyword, counted in runes like the lexer does. A statement ends at a semicolon or where the next CREATE keyword starts,
+// the same boundaries splitCreateTableStatements uses. Semicolons and keywords inside strings,
+// quoted identifiers, comments and MySQL directives are ignored.
+func scanCreateStatements(r io.Reader, yield func(statement string, line, column int)) error {
+	reader := bufio.NewReader(r)
+
+	// statement holds the current CREATE statement; capturing is false between CREATE statements,
+	// where the input is only scanned for the next boundary
+	var statement strings.Builder
+	capturing := false
+	startLine, startColumn := 0, 0
+	flush := func() {
+		if capturing {
+			yield(statement.String(), startLine, startColumn)
+		}
+		statement.Reset()
+		capturing = false
+	}
+
+	// word holds the identifier being read. Outside CREATE statements only its first runes are kept,
+	// which is enough to recognize the CREATE keyword.
+	var word []rune
+	wordLength := 0
+	inWord := false
+	wordLine, wordColumn := 0, 0
+	endWord := func() {
+		if wordLength == len("CREATE") && strings.EqualFold(string(word), "CREATE") {
+			flush()
+			capturing = true
+			startLine, startColumn = wordLine, wordColumn
+		}
+		if capturing {
+			statement.WriteString(string(word))
+		}
+		word = word[:0]
+		wordLength = 0
+		inWord = false
+	}
+
+	// line and column are the position of the next rune; lastLine and lastColumn the position of
+	// the rune read last, restored when it is unread
+	line, column := 1, 1
+	lastLine, lastColumn := 1, 1
+
+	// next reads a rune; write adds it to the current statement
+	next := func() (rune, error) {
+		c, _, err := reader.ReadRune()
+		if err != nil {
+			return c, err
+		}
+		lastLine, lastColumn = line, column
+		if c == '\n' {
+			line, column = line+1, 1
+		} else {
+			column++
+		}
+		return c, nil
+	}
+	unread := func() error {
+		line, column = lastLine, lastColumn
+		return reader.UnreadRune()
+	}
+	write := func(c rune) {
+		if capturing {
+			statement.WriteRune(c)
+		}
+	}
+	// peek consumes and writes the next rune if it equals want
+	peek := func(want rune) (bool, error) {
+		c, err := next()
+		if err != nil {
+			return false, err
+		}
+		if c != want {
+			return false, unread()
+		}
+		write(c)
+		return true, nil
+	}
+	// skipUntil writes runes through the end of a comment or quoted identifier
+	skipUntil := func(end string) error {
+		matched := 0
+		for matched < len(end) {
+			c, err := next()
+			if err != nil {
+				return err
+			}
+			write(c)
+			switch {
+			case c == rune(end[matched]):
+				matched++
+			case c == rune(end[0]):
+				matched = 1
+			default:
+				matched = 0
+			}
+		}
+		return nil
+	}
+	// skipString writes runes through the closing quote, honoring backslash escapes and doubled quotes
+	skipString := func(quote rune) error {
+		for {
+			c, err := next()
+			if err != nil {
+				return err
+			}
+			write(c)
+			switch c {
+			case '\\':
+				escaped, err := next()
+				if err != nil {
+					return err
+				}
+				write(escaped)
+			case quote:
+				doubled, err := peek(quote)
+				if err != nil || !doubled {
+					return err
+				}
+			}
+		}
+	}
+
+	err := func() error {
+		// The lexer skips a byte order mark without counting it
+		if c, err := next(); err != nil {
+			return err
+		} else if string(c) == byteOrderMark {
+			line, column = 1, 1
+		} else if err := unread(); err != nil {
+			return err
+		}
+
+		for {
+			c, err := next()
+			if err != nil {
+				return err
+			}
+
+			if inWord {
+				if unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '$' {
+					if capturing || wordLength < len("CREATE") {
+						word = append(word, c)
+					}
+					wordLength++
+					continue
+				}
+				endWord()
+			}
+
+			if unicode.IsLetter(c) || c == '_' {
+				inWord = true
+				wordLine, wordColumn = lastLine, lastColumn
+				word = append(word, c)
+				wordLength = 1
+				continue
+			}
+
+			write(c)
+			switch c {
+			case ';':
+				flush()
+			case '\'', '"':
+				err = skipString(c)
+			case '`':
+				err = skipUntil("`")
+			case '#':
+				err = skipUntil("\n")
+			case '-':
+				var comment bool
+				if comment, err = peek('-'); comment {
+					err = skipUntil("\n")
+				}
+			case '/':
+				// Comments and MySQL directives both extend to the closing */
+				var comment bool
+				if comment, err = peek('*'); comment {
+					err = skipUntil("*/")
+				}
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}()
+
+	if inWord {
+		endWord()
+	}
+	flush()
+
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+const readerTestDump = `-- MySQL dump
+/*!40101 SET NAMES utf8mb4 */;
+/*!50001 CREATE ALGORITHM=UNDEFINED */ /*!50001 VIEW v AS SELECT 1 */;
+DROP TABLE IF EXISTS ` + "`users`" + `;
+CREATE TABLE ` + "`users`" + ` (
+  ` + "`id`" + ` int NOT NULL AUTO_INCREMENT, -- the key; CREATE TABLE nothing
+  ` + "`name`" + ` varchar(50) DEFAULT 'it''s; here' COMMENT "say \"hi\"; CREATE",
+  PRIMARY KEY (` + "`id`" + `)
+) ENGINE=InnoDB /* trailing; comment */;
+LOCK TABLES ` + "`users`" + ` WRITE;
+INSERT INTO ` + "`users`" + ` VALUES (1,'a;b'),(2,'CREATE TABLE fake (x INT);'),(3,0xCREA7E),(4,'back\'slash;');
+UNLOCK TABLES;
+# create table hidden (id int);
+CREATE TEMPORARY TABLE tmp (id INT)
+CREATE TABLE ` + "`orders`" + ` (id INT, note TEXT COMMENT 'ünïcode')`
+
+func TestParseSQLReader(t *testing.T) {
+	// ParseSQLDumpTolerant tokenizes the whole dump at once and serves as the reference
+	expected, errs, err := ParseSQLDumpTolerant(readerTestDump, 0)
+	if err != nil || len(errs) != 0 {
+		t.Fatalf("Unexpected errors: %v %v", err, errs)
+	}
+
+	tables, err := ParseSQLReader(iotest.OneByteReader(strings.NewReader(readerTestDump)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var names []string
+	for _, table := range tables {
+		names = append(names, table.TableName)
+	}
+	if !reflect.DeepEqual(names, []string{"users", "tmp", "orders"}) {
+		t.Fatalf("Expected tables users, tmp and orders, got %v", names)
+	}
+	if !reflect.DeepEqual(tables, expected) {
+		t.Errorf("Expected the same tables as the whole-dump parser")
+	}
+
+	if tables[1].RawSQL != "CREATE TEMPORARY TABLE tmp (id INT)" {
+		t.Errorf("Unexpected raw SQL %q", tables[1].RawSQL)
+	}
+	if !strings.HasPrefix(tables[0].RawSQL, "CREATE TABLE `users`") || !strings.HasSuffix(tables[0].RawSQL, "/* trailing; comment */;") {
+		t.Errorf("Unexpected raw SQL %q", tables[0].RawSQL)
+	}
+}
+
+func TestParseSQLReaderError(t *testing.T) {
+	errRead := errors.New("read failed")
+	_, err := ParseSQLReader(iotest.ErrReader(errRead))
+	if !errors.Is(err, errRead) {
+		t.Errorf("Expected read error, got %v", err)
+	}
+}
+
+// largeDump returns a full dump of tables followed by INSERT INTO statements holding their rows
+func largeDump(tables, rows int) string {
+	var sb strings.Builder
+	for i := range tables {
+		fmt.Fprintf(&sb, "CREATE TABLE `t%d` (\n  `id` int NOT NULL,\n  `payload` varchar(255) DEFAULT NULL,\n  PRIMARY KEY (`id`)\n) ENGINE=InnoDB;\n", i)
+		fmt.Fprintf(&sb, "INSERT INTO `t%d` VALUES ", i)
+		for row := range rows {
+			if row > 0 {
+				sb.WriteByte(',')
+			}
+			fmt.Fprintf(&sb, "(%d,'row %d; some payload text')", row, row)
+		}
+		sb.WriteString(";\n")
+	}
+	return sb.String()
+}
+
+func BenchmarkParseSQLReader(b *testing.B) {
+	dump := largeDump(20, 2000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if _, err := ParseSQLReader(strings.NewReader(dump)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkParseSQLDumpTolerant tokenizes the whole dump, including the rows of every INSERT INTO
+func BenchmarkParseSQLDumpTolerant(b *testing.B) {
+	dump := largeDump(20, 2000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if _, _, err := ParseSQLDumpTolerant(dump, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package parser
+
+// SchemaStats holds aggregated counts for a parsed schema
+type SchemaStats struct {
+	Tables      int            `json:"tables"`
+	Columns     int            `json:"columns"`
+	Indexes     int            `json:"indexes"`
+	ForeignKeys int            `json:"foreign_keys"`
+	Engines     map[string]int `json:"engines"`
+	Charsets    map[string]int `json:"charsets"`
+}
+
+// AverageColumnsPerTable returns the mean number of columns per table
+func (s *SchemaStats) AverageColumnsPerTable() float64 {
+	if s.Tables == 0 {
+		return 0
+	}
+	return float64(s.Columns) / float64(s.Tables)
+}
+
+// CollectSchemaStats counts tables, columns, indexes and foreign keys, and the
+// number of tables per declared storage engine and default character set
+func CollectSchemaStats(tables []*CreateTableStatement) *SchemaStats {
+	stats := &SchemaStats{
+		Engines:  make(map[string]int),
+		Charsets: make(map[string]int),
+	}
+
+	for _, table := range tables {
+		if table == nil {
+			continue
+		}
+
+		stats.Tables++
+		stats.Columns += len(table.Columns)
+		stats.Indexes += len(table.Indexes)
+		stats.ForeignKeys += len(table.ForeignKeys)
+
+		if table.TableOptions == nil {
+			continue
+		}
+		if table.TableOptions.Engine != nil {
+			stats.Engines[*table.TableOptions.Engine]++
+		}
+		if table.TableOptions.CharacterSet != nil {
+			stats.Charsets[*table.TableOptions.CharacterSet]++
+		}
+	}
+
+	return stats
+}
@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestCollectSchemaStats(t *testing.T) {
+	sql := `
+		CREATE TABLE users (
+			id INT NOT NULL,
+			email VARCHAR(100),
+			name VARCHAR(50),
+			PRIMARY KEY (id),
+			UNIQUE KEY uk_email (email),
+			INDEX idx_name (name)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+		CREATE TABLE orders (
+			id INT NOT NULL,
+			user_id INT,
+			PRIMARY KEY (id),
+			INDEX idx_user (user_id),
+			FOREIGN KEY (user_id) REFERENCES users (id)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+		CREATE TABLE logs (
+			id INT,
+			message TEXT
+		) ENGINE=MyISAM DEFAULT CHARSET=latin1;
+
+		CREATE TABLE tmp (id INT);
+	`
+
+	tables, err := ParseSQLDump(sql)
+	if err != nil {
+		t.Fatalf("ParseSQLDump failed: %v", err)
+	}
+
+	stats := CollectSchemaStats(tables)
+
+	if stats.Tables != 4 {
+		t.Errorf("Expected 4 tables, got %d", stats.Tables)
+	}
+	if stats.Columns != 8 {
+		t.Errorf("Expected 8 columns, got %d", stats.Columns)
+	}
+	if stats.Indexes != 3 {
+		t.Errorf("Expected 3 indexes, got %d", stats.Indexes)
+	}
+	if stats.ForeignKeys != 1 {
+		t.Errorf("Expected 1 foreign key, got %d", stats.ForeignKeys)
+	}
+	if stats.AverageColumnsPerTable() != 2.0 {
+		t.Errorf("Expected 2.0 average columns per table, got %.1f", stats.AverageColumnsPerTable())
+	}
+
+	if len(stats.Engines) != 2 || stats.Engines["InnoDB"] != 2 || stats.Engines["MyISAM"] != 1 {
+		t.Errorf("Expected engines InnoDB:2 MyISAM:1, got %v", stats.Engines)
+	}
+	if len(stats.Charsets) != 2 || stats.Charsets["utf8mb4"] != 2 || stats.Charsets["latin1"] != 1 {
+		t.Errorf("Expected charsets utf8mb4:2 latin1:1, got %v", stats.Charsets)
+	}
+}
+
+func TestCollectSchemaStats_Empty(t *testing.T) {
+	stats := CollectSchemaStats(nil)
+
+	if stats.Tables != 0 || stats.Columns != 0 || stats.AverageColumnsPerTable() != 0 {
+		t.Errorf("Expected empty stats, got %+v", stats)
+	}
+}
@@ -94,6 +94,8 @@ const (
 	KEY_BLOCK_SIZE
 	MAX_ROWS
 	MIN_ROWS
+	AVG_ROW_LENGTH
+	AUTOEXTEND_SIZE
 	STATS_PERSISTENT
 	STATS_AUTO_RECALC
 	STATS_SAMPLE_PAGES
@@ -102,6 +104,7 @@ const (
 	DELAY_KEY_WRITE
 	UNION
 	INSERT_METHOD
+	CONNECTION
 
 	// Partition options
 	PARTITION
@@ -116,6 +119,9 @@ const (
 	IN
 	MAXVALUE
 	LINEAR
+	PARTITIONS
+	SUBPARTITION
+	SUBPARTITIONS
 
 	// Reference options
 	ON
@@ -139,6 +145,7 @@ const (
 	NONE
 	FIRST
 	LAST
+	AFTER
 
 	// Column format and storage options
 	COLUMN_FORMAT
@@ -157,6 +164,7 @@ const (
 	EQUALS
 	DOT
 	MINUS
+	OPERATOR
 
 	// Literals
 	IDENTIFIER
@@ -256,6 +264,8 @@ func (t TokenType) String() string {
 		KEY_BLOCK_SIZE:     "KEY_BLOCK_SIZE",
 		MAX_ROWS:           "MAX_ROWS",
 		MIN_ROWS:           "MIN_ROWS",
+		AVG_ROW_LENGTH:     "AVG_ROW_LENGTH",
+		AUTOEXTEND_SIZE:    "AUTOEXTEND_SIZE",
 		STATS_PERSISTENT:   "STATS_PERSISTENT",
 		STATS_AUTO_RECALC:  "STATS_AUTO_RECALC",
 		STATS_SAMPLE_PAGES: "STATS_SAMPLE_PAGES",
@@ -264,6 +274,7 @@ func (t TokenType) String() string {
 		DELAY_KEY_WRITE:    "DELAY_KEY_WRITE",
 		UNION:              "UNION",
 		INSERT_METHOD:      "INSERT_METHOD",
+		CONNECTION:         "CONNECTION",
 		PARTITION:          "PARTITION",
 		BY:                 "BY",
 		HASH:               "HASH",
@@ -276,6 +287,9 @@ func (t TokenType) String() string {
 		IN:                 "IN",
 		MAXVALUE:           "MAXVALUE",
 		LINEAR:             "LINEAR",
+		PARTITIONS:         "PARTITIONS",
+		SUBPARTITION:       "SUBPARTITION",
+		SUBPARTITIONS:      "SUBPARTITIONS",
 		ON:                 "ON",
 		DELETE:             "DELETE",
 		UPDATE:             "UPDATE",
@@ -295,6 +309,7 @@ func (t TokenType) String() string {
 		NONE:               "NONE",
 		FIRST:              "FIRST",
 		LAST:               "LAST",
+		AFTER:              "AFTER",
 		COLUMN_FORMAT:      "COLUMN_FORMAT",
 		FIXED:              "FIXED",
 		DYNAMIC:            "DYNAMIC",
@@ -309,6 +324,7 @@ func (t TokenType) String() string {
 		EQUALS:             "=",
 		DOT:                ".",
 		MINUS:              "-",
+		OPERATOR:           "OPERATOR",
 		IDENTIFIER:         "IDENTIFIER",
 		STRING:             "STRING",
 		NUMBER:             "NUMBER",